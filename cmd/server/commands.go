@@ -15,10 +15,13 @@ import (
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/utils"
 
+	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/service"
 )
 
+const redactedValue = "<redacted>"
+
 func generateKeys(_ *cli.Context) error {
 	apiKey := utils.NewGuid(utils.APIKeyPrefix)
 	secret := utils.RandomSecret()
@@ -67,6 +70,61 @@ func printPorts(c *cli.Context) error {
 	return nil
 }
 
+func validateConfig(c *cli.Context) error {
+	conf, err := loadConfig(c)
+	if err != nil {
+		return err
+	}
+
+	// secrets are not printed
+	redacted := *conf
+	redacted.Keys = redactValues(conf.Keys)
+	// headers usually carry auth tokens
+	redacted.Analytics.HTTP.Headers = redactValues(conf.Analytics.HTTP.Headers)
+	redacted.Tracing.Headers = redactValues(conf.Tracing.Headers)
+	if redacted.Redis.Password != "" {
+		redacted.Redis.Password = redactedValue
+	}
+	if redacted.Redis.SentinelPassword != "" {
+		redacted.Redis.SentinelPassword = redactedValue
+	}
+	redacted.RTC.TURNServers = redactTURNServers(conf.RTC.TURNServers)
+
+	out, err := yaml.Marshal(&redacted)
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// redactTURNServers returns a copy of servers with their credentials redacted, nil when there are none
+func redactTURNServers(servers []config.TURNServer) []config.TURNServer {
+	if len(servers) == 0 {
+		return nil
+	}
+	redacted := make([]config.TURNServer, len(servers))
+	for i, server := range servers {
+		if server.Credential != "" {
+			server.Credential = redactedValue
+		}
+		redacted[i] = server
+	}
+	return redacted
+}
+
+// redactValues returns a copy of values with each value redacted, nil when there are none
+func redactValues(values map[string]string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	redacted := make(map[string]string, len(values))
+	for key := range values {
+		redacted[key] = redactedValue
+	}
+	return redacted
+}
+
 func createToken(c *cli.Context) error {
 	room := c.String("room")
 	identity := c.String("identity")
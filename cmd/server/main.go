@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"math/rand"
@@ -19,6 +20,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 	"github.com/livekit/livekit-server/version"
 )
 
@@ -106,6 +108,11 @@ func main() {
 				Usage:  "generates an API key and secret pair",
 				Action: generateKeys,
 			},
+			{
+				Name:   "validate-config",
+				Usage:  "validates configuration and prints the effective config, merged from config file, environment and flags",
+				Action: validateConfig,
+			},
 			{
 				Name:   "ports",
 				Usage:  "print ports that server is configured to use",
@@ -150,6 +157,16 @@ func main() {
 }
 
 func getConfig(c *cli.Context) (*config.Config, error) {
+	conf, err := loadConfig(c)
+	if err != nil {
+		return nil, err
+	}
+	serverlogger.InitFromConfig(conf.Logging)
+	return conf, nil
+}
+
+// loadConfig reads configuration from flags and config sources, also used when reloading
+func loadConfig(c *cli.Context) (*config.Config, error) {
 	confString, err := getConfigString(c.String("config"), c.String("config-body"))
 	if err != nil {
 		return nil, err
@@ -159,7 +176,6 @@ func getConfig(c *cli.Context) (*config.Config, error) {
 	if err != nil {
 		return nil, err
 	}
-	serverlogger.InitFromConfig(conf.Logging)
 
 	if c.String("config") == "" && c.String("config-body") == "" && conf.Development {
 		// use single port UDP when no config is provided
@@ -216,11 +232,38 @@ func startServer(c *cli.Context) error {
 		return err
 	}
 
+	shutdownTracing, err := tracing.Init(&conf.Tracing, currentNode.Id)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// flush pending spans
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = shutdownTracing(ctx)
+	}()
+
 	server, err := service.InitializeServer(conf, currentNode)
 	if err != nil {
 		return err
 	}
 
+	server.SetConfigLoader(func() (*config.Config, error) {
+		return loadConfig(c)
+	})
+
+	reloadChan := make(chan os.Signal, 1)
+	signal.Notify(reloadChan, syscall.SIGHUP)
+
+	go func() {
+		for range reloadChan {
+			logger.Infow("reload requested, reloading configuration")
+			if _, err := server.ReloadConfig(); err != nil {
+				logger.Errorw("could not reload configuration", err)
+			}
+		}
+	}()
+
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
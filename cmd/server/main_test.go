@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/livekit-server/pkg/config"
 )
 
 type testStruct struct {
@@ -47,3 +49,18 @@ func writeConfigFile(test testStruct, t *testing.T) {
 		require.NoError(t, err)
 	}
 }
+
+func TestRedactValues(t *testing.T) {
+	require.Nil(t, redactValues(nil))
+	require.Equal(t, map[string]string{"Authorization": redactedValue},
+		redactValues(map[string]string{"Authorization": "Bearer secret"}))
+}
+
+func TestRedactTURNServers(t *testing.T) {
+	require.Nil(t, redactTURNServers(nil))
+	servers := []config.TURNServer{{Host: "turn.example.com", Username: "user", Credential: "secret"}}
+	require.Equal(t, []config.TURNServer{{Host: "turn.example.com", Username: "user", Credential: redactedValue}},
+		redactTURNServers(servers))
+	// the loaded config is left intact
+	require.Equal(t, "secret", servers[0].Credential)
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/mitchellh/go-homedir"
@@ -11,6 +12,7 @@ import (
 	"github.com/urfave/cli/v2"
 	"gopkg.in/yaml.v3"
 
+	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 )
 
@@ -38,7 +40,7 @@ type Config struct {
 	Video          VideoConfig        `yaml:"video,omitempty"`
 	Room           RoomConfig         `yaml:"room,omitempty"`
 	TURN           TURNConfig         `yaml:"turn,omitempty"`
-	Ingress        IngressConfig      `yaml:ingress,omitempty"`
+	Ingress        IngressConfig      `yaml:"ingress,omitempty"`
 	WebHook        WebHookConfig      `yaml:"webhook,omitempty"`
 	NodeSelector   NodeSelectorConfig `yaml:"node_selector,omitempty"`
 	KeyFile        string             `yaml:"key_file,omitempty"`
@@ -46,9 +48,14 @@ type Config struct {
 	Region         string             `yaml:"region,omitempty"`
 	Autocert       AutocertConfig     `yaml:"autocert,omitempty"`
 	// LogLevel is deprecated
-	LogLevel string        `yaml:"log_level,omitempty"`
-	Logging  LoggingConfig `yaml:"logging,omitempty"`
-	Limit    LimitConfig   `yaml:"limit,omitempty"`
+	LogLevel    string            `yaml:"log_level,omitempty"`
+	Logging     LoggingConfig     `yaml:"logging,omitempty"`
+	Limit       LimitConfig       `yaml:"limit,omitempty"`
+	Tenancy     TenancyConfig     `yaml:"tenancy,omitempty"`
+	Usage       UsageConfig       `yaml:"usage,omitempty"`
+	Analytics   AnalyticsConfig   `yaml:"analytics,omitempty"`
+	Tracing     TracingConfig     `yaml:"tracing,omitempty"`
+	RoomMetrics RoomMetricsConfig `yaml:"room_metrics,omitempty"`
 
 	Development bool `yaml:"development,omitempty"`
 }
@@ -141,6 +148,117 @@ type RoomConfig struct {
 	EmptyTimeout       uint32      `yaml:"empty_timeout"`
 	EnableRemoteUnmute bool        `yaml:"enable_remote_unmute"`
 	MaxMetadataSize    uint32      `yaml:"max_metadata_size"`
+	// total size of a participant's attribute keys and values, 0 for no limit
+	MaxAttributesSize uint32 `yaml:"max_attributes_size,omitempty"`
+	// total size of keys and values of a room's shared state, 0 for no limit
+	MaxStateSize uint32 `yaml:"max_state_size,omitempty"`
+	// number of seconds a room may stay open after creation, 0 for no limit
+	MaxDuration uint32 `yaml:"max_duration,omitempty"`
+	// reliable data messages retained and replayed to participants joining later
+	DataHistory DataHistoryConfig `yaml:"data_history,omitempty"`
+	// classes of data topics counted on their own in metrics, a topic's class being the part before its first "/"
+	DataTopicClasses []string `yaml:"data_topic_classes,omitempty"`
+	// limits on data packets participants publish
+	DataLimits DataLimitsConfig `yaml:"data_limits,omitempty"`
+	// limits on signal requests participants send
+	SignalLimits SignalLimitsConfig `yaml:"signal_limits,omitempty"`
+	// what happens when a participant joins with the identity of one already in the room: replace, reject or
+	// allow. defaults to replace, disconnecting the participant in the room
+	DuplicateIdentity string `yaml:"duplicate_identity,omitempty"`
+	// number of seconds before a room or participant session ends that clients are warned
+	DurationWarning uint32 `yaml:"duration_warning,omitempty"`
+	// named templates selectable when creating rooms, unset fields fall back to the defaults above
+	Templates map[string]RoomTemplate `yaml:"templates,omitempty"`
+}
+
+type RoomTemplate struct {
+	MaxParticipants uint32      `yaml:"max_participants,omitempty"`
+	EmptyTimeout    uint32      `yaml:"empty_timeout,omitempty"`
+	EnabledCodecs   []CodecSpec `yaml:"enabled_codecs,omitempty"`
+	MaxMetadataSize uint32      `yaml:"max_metadata_size,omitempty"`
+	MaxDuration     uint32      `yaml:"max_duration,omitempty"`
+	// total size of a participant's attribute keys and values
+	MaxAttributesSize uint32 `yaml:"max_attributes_size,omitempty"`
+	// total size of keys and values of the room's shared state
+	MaxStateSize uint32 `yaml:"max_state_size,omitempty"`
+	// max number of participants allowed to publish, 0 for unlimited
+	MaxPublishers uint32                `yaml:"max_publishers,omitempty"`
+	Recording     RoomTemplateRecording `yaml:"recording,omitempty"`
+	// sources of tracks participants start with muted, i.e. microphone or camera
+	StartMuted []string `yaml:"start_muted,omitempty"`
+	// sources of tracks participants may publish: camera, microphone, screen_share or screen_share_audio.
+	// all are allowed when empty
+	PublishSources []string `yaml:"publish_sources,omitempty"`
+	// sources allowed to participants by the role claimed in their token, overriding PublishSources.
+	// a role listing no sources can't publish tracks
+	RolePublishSources map[string][]string `yaml:"role_publish_sources,omitempty"`
+	// retention of data messages, replacing the server wide setting when max_messages is set
+	DataHistory DataHistoryConfig `yaml:"data_history,omitempty"`
+	// policy for duplicate identities, replacing the server wide one when set
+	DuplicateIdentity string `yaml:"duplicate_identity,omitempty"`
+}
+
+type DataLimitsConfig struct {
+	// max payload size of a data packet, 0 for no limit
+	MaxPacketSize uint32 `yaml:"max_packet_size,omitempty"`
+	// data packets and payload bytes per second each participant may publish, 0 for no limit.
+	// bursts of up to a second of either are allowed, larger packets exceed the size limit
+	ParticipantPacketRate uint32 `yaml:"participant_packet_rate,omitempty"`
+	ParticipantByteRate   uint32 `yaml:"participant_byte_rate,omitempty"`
+	// data packets and payload bytes per second all participants of a room may publish, 0 for no limit
+	RoomPacketRate uint32 `yaml:"room_packet_rate,omitempty"`
+	RoomByteRate   uint32 `yaml:"room_byte_rate,omitempty"`
+	// action taken when a packet exceeds the limits: drop, warn or disconnect. packets are dropped in any case,
+	// warn sends the participant a data message as well. defaults to drop
+	Action string `yaml:"action,omitempty"`
+}
+
+type SignalLimitsConfig struct {
+	// requests per second each participant may send, by type of request: offer, answer, trickle, add_track, mute,
+	// subscription, track_setting, update_layers, subscription_permission, sync_state or simulate.
+	// bursts of up to a second of requests are allowed, types not listed aren't limited
+	RequestRates map[string]uint32 `yaml:"request_rates,omitempty"`
+	// tracks each participant may have pending publication, and published including pending ones. 0 for no limit
+	MaxPendingTracks   uint32 `yaml:"max_pending_tracks,omitempty"`
+	MaxPublishedTracks uint32 `yaml:"max_published_tracks,omitempty"`
+	// requests per second each participant may have rejected for exceeding the limits above before it's
+	// disconnected, bursts of up to a second are allowed. 0 to never disconnect
+	MaxViolationRate uint32 `yaml:"max_violation_rate,omitempty"`
+}
+
+// types of signal requests that can be limited. leave requests never are, nor are offers, answers and ICE
+// candidates, which negotiation can't do without
+var signalRequestTypes = map[string]bool{
+	"add_track":               true,
+	"mute":                    true,
+	"subscription":            true,
+	"track_setting":           true,
+	"update_layers":           true,
+	"subscription_permission": true,
+	"sync_state":              true,
+	"simulate":                true,
+	"data_topics":             true,
+	"stage":                   true,
+}
+
+type DataHistoryConfig struct {
+	// number of messages retained per room, history is disabled when 0
+	MaxMessages uint32 `yaml:"max_messages,omitempty"`
+	// total size of retained payloads, the oldest messages are dropped beyond it. 0 for no limit
+	MaxBytes uint32 `yaml:"max_bytes,omitempty"`
+	// also retain messages sent to specific participants, for moderation. they're never replayed,
+	// only messages sent to everyone are
+	IncludeDirect bool `yaml:"include_direct,omitempty"`
+	// retain messages in the object store as well (Redis when configured), keeping them across restarts of
+	// the room's node
+	Persist bool `yaml:"persist,omitempty"`
+}
+
+type RoomTemplateRecording struct {
+	// rejects all egress requests of the room
+	Disabled bool `yaml:"disabled,omitempty"`
+	// room_composite, track_composite or track, all are allowed when empty
+	AllowedTypes []string `yaml:"allowed_types,omitempty"`
 }
 
 type CodecSpec struct {
@@ -192,6 +310,90 @@ type LimitConfig struct {
 	BytesPerSec float32 `yaml:"bytes_per_sec"`
 }
 
+// TenancyConfig isolates rooms, participants, egresses and ingresses of different tenants sharing a cluster
+type TenancyConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// maps API keys to tenants, keys that are not listed are their own tenant
+	KeyTenants map[string]string `yaml:"key_tenants"`
+	// keys that can access resources of every tenant, such as those used by egress and ingress.
+	// tokens signed by these keys may carry a tenant claim to act on behalf of a tenant
+	GlobalKeys []string `yaml:"global_keys"`
+	// default limits for each tenant, 0 for unlimited
+	MaxRooms        int `yaml:"max_rooms"`
+	MaxParticipants int `yaml:"max_participants"`
+	// per-tenant overrides
+	Tenants map[string]TenantConfig `yaml:"tenants"`
+}
+
+type TenantConfig struct {
+	MaxRooms        int `yaml:"max_rooms"`
+	MaxParticipants int `yaml:"max_participants"`
+}
+
+// UsageConfig enables metering of participant minutes, track minutes and bandwidth per room and API key
+type UsageConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// interval at which usage is persisted and exported, defaults to 1 minute
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+	// when set, usage of each interval is appended to daily files in this directory
+	ExportDir string `yaml:"export_dir,omitempty"`
+	// csv or jsonl, defaults to jsonl
+	ExportFormat string `yaml:"export_format,omitempty"`
+}
+
+type AnalyticsConfig struct {
+	// file or http, stats and events are dropped when unset
+	Sink string `yaml:"sink,omitempty"`
+	// max number of stats and events queued each, new items are dropped when full
+	BufferSize int `yaml:"buffer_size,omitempty"`
+	// max number of items written to the sink at once
+	BatchSize int `yaml:"batch_size,omitempty"`
+	// interval at which partial batches are written
+	FlushInterval time.Duration `yaml:"flush_interval,omitempty"`
+
+	File AnalyticsFileConfig `yaml:"file,omitempty"`
+	HTTP AnalyticsHTTPConfig `yaml:"http,omitempty"`
+}
+
+type AnalyticsFileConfig struct {
+	// path of the JSONL file, rotated files are suffixed with a timestamp
+	Path string `yaml:"path"`
+	// size in bytes after which the file is rotated
+	MaxSize int64 `yaml:"max_size,omitempty"`
+	// number of rotated files to keep, 0 to keep all
+	MaxBackups int `yaml:"max_backups,omitempty"`
+}
+
+type AnalyticsHTTPConfig struct {
+	// batches are POSTed to this URL as JSON
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers,omitempty"`
+	Timeout time.Duration     `yaml:"timeout,omitempty"`
+}
+
+// RoomMetricsConfig enables Prometheus metrics labelled by room and participant for selected rooms
+type RoomMetricsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// rooms with names matching any of these glob patterns are measured
+	RoomNames []string `yaml:"room_names,omitempty"`
+	// rooms can opt in by setting this key to true in their JSON metadata
+	MetadataKey string `yaml:"metadata_key,omitempty"`
+	// caps on measured rooms and labelled participants per room, limiting cardinality
+	MaxRooms               int `yaml:"max_rooms,omitempty"`
+	MaxParticipantsPerRoom int `yaml:"max_participants_per_room,omitempty"`
+}
+
+type TracingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// OTLP/HTTP collector address, host:port
+	Endpoint string `yaml:"endpoint"`
+	// send spans over plain HTTP instead of HTTPS
+	Insecure bool              `yaml:"insecure,omitempty"`
+	Headers  map[string]string `yaml:"headers,omitempty"`
+	// fraction of new traces sampled, between 0 and 1
+	SampleRatio float64 `yaml:"sample_ratio,omitempty"`
+}
+
 type IngressConfig struct {
 	RTMPBaseURL string `yaml:"rtmp_base_url"`
 }
@@ -238,7 +440,8 @@ func NewConfig(confString string, c *cli.Context) (*Config, error) {
 				// {Mime: webrtc.MimeTypeAV1},
 				// {Mime: webrtc.MimeTypeVP9},
 			},
-			EmptyTimeout: 5 * 60,
+			EmptyTimeout:    5 * 60,
+			DurationWarning: 60,
 		},
 		Logging: LoggingConfig{
 			PionLevel: "error",
@@ -252,14 +455,37 @@ func NewConfig(confString string, c *cli.Context) (*Config, error) {
 			SysloadLimit: 0.9,
 			CPULoadLimit: 0.9,
 		},
+		Analytics: AnalyticsConfig{
+			BufferSize:    10000,
+			BatchSize:     100,
+			FlushInterval: 5 * time.Second,
+			File: AnalyticsFileConfig{
+				MaxSize:    100 * 1024 * 1024,
+				MaxBackups: 10,
+			},
+			HTTP: AnalyticsHTTPConfig{
+				Timeout: 10 * time.Second,
+			},
+		},
+		Tracing: TracingConfig{
+			SampleRatio: 1,
+		},
+		RoomMetrics: RoomMetricsConfig{
+			MaxRooms:               50,
+			MaxParticipantsPerRoom: 50,
+		},
 		Keys: map[string]string{},
 	}
 	if confString != "" {
-		if err := yaml.Unmarshal([]byte(confString), conf); err != nil {
+		if err := unmarshalStrict(confString, conf); err != nil {
 			return nil, fmt.Errorf("could not parse config: %v", err)
 		}
 	}
 
+	if err := conf.updateFromEnv(); err != nil {
+		return nil, err
+	}
+
 	if c != nil {
 		if err := conf.updateFromCLI(c); err != nil {
 			return nil, err
@@ -310,9 +536,73 @@ func NewConfig(confString string, c *cli.Context) (*Config, error) {
 		conf.Logging.Level = "debug"
 	}
 
+	if err := conf.validate(); err != nil {
+		return nil, err
+	}
+
 	return conf, nil
 }
 
+func (conf *Config) validate() error {
+	if conf.RTC.ICEPortRangeStart > conf.RTC.ICEPortRangeEnd {
+		return fmt.Errorf("invalid rtc port range %d-%d", conf.RTC.ICEPortRangeStart, conf.RTC.ICEPortRangeEnd)
+	}
+	if conf.TURN.RelayPortRangeStart > conf.TURN.RelayPortRangeEnd {
+		return fmt.Errorf("invalid turn relay range %d-%d", conf.TURN.RelayPortRangeStart, conf.TURN.RelayPortRangeEnd)
+	}
+	switch conf.RTC.CongestionControl.ProbeMode {
+	case "", CongestionControlProbeModePadding, CongestionControlProbeModeMedia:
+	default:
+		return fmt.Errorf("invalid congestion control padding_mode %q", conf.RTC.CongestionControl.ProbeMode)
+	}
+	if conf.Tracing.SampleRatio < 0 || conf.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample_ratio must be between 0 and 1")
+	}
+	switch conf.Room.DataLimits.Action {
+	case "", "drop", "warn", "disconnect":
+	default:
+		return fmt.Errorf("invalid data_limits action %q", conf.Room.DataLimits.Action)
+	}
+	if !validDuplicateIdentity(conf.Room.DuplicateIdentity) {
+		return fmt.Errorf("invalid duplicate_identity %q", conf.Room.DuplicateIdentity)
+	}
+	for requestType := range conf.Room.SignalLimits.RequestRates {
+		if !signalRequestTypes[requestType] {
+			return fmt.Errorf("invalid signal_limits request type %q", requestType)
+		}
+	}
+	for name, template := range conf.Room.Templates {
+		for _, egressType := range template.Recording.AllowedTypes {
+			switch egressType {
+			case "room_composite", "track_composite", "track":
+			default:
+				return fmt.Errorf("invalid recording type %q in room template %s", egressType, name)
+			}
+		}
+		sources := append(append([]string{}, template.StartMuted...), template.PublishSources...)
+		for _, roleSources := range template.RolePublishSources {
+			sources = append(sources, roleSources...)
+		}
+		for _, source := range sources {
+			if value, ok := livekit.TrackSource_value[strings.ToUpper(source)]; !ok || value == int32(livekit.TrackSource_UNKNOWN) {
+				return fmt.Errorf("invalid track source %q in room template %s", source, name)
+			}
+		}
+		if !validDuplicateIdentity(template.DuplicateIdentity) {
+			return fmt.Errorf("invalid duplicate_identity %q in room template %s", template.DuplicateIdentity, name)
+		}
+	}
+	return nil
+}
+
+func validDuplicateIdentity(policy string) bool {
+	switch policy {
+	case "", "replace", "reject", "allow":
+		return true
+	}
+	return false
+}
+
 func (conf *Config) HasRedis() bool {
 	return conf.Redis.Address != "" || conf.Redis.SentinelAddresses != nil
 }
@@ -321,6 +611,23 @@ func (conf *Config) UseSentinel() bool {
 	return conf.Redis.SentinelAddresses != nil
 }
 
+// Limits returns the limits that apply to the given tenant
+func (t *TenancyConfig) Limits(tenant string) TenantConfig {
+	limits := TenantConfig{
+		MaxRooms:        t.MaxRooms,
+		MaxParticipants: t.MaxParticipants,
+	}
+	if override, ok := t.Tenants[tenant]; ok {
+		if override.MaxRooms != 0 {
+			limits.MaxRooms = override.MaxRooms
+		}
+		if override.MaxParticipants != 0 {
+			limits.MaxParticipants = override.MaxParticipants
+		}
+	}
+	return limits
+}
+
 func (conf *Config) updateFromCLI(c *cli.Context) error {
 	if c.IsSet("dev") {
 		conf.Development = c.Bool("dev")
@@ -1,7 +1,9 @@
 package config
 
 import (
+	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -22,3 +24,103 @@ func TestConfig_DefaultsKept(t *testing.T) {
 	require.Equal(t, true, conf.Room.AutoCreate)
 	require.Equal(t, uint32(10), conf.Room.EmptyTimeout)
 }
+
+func TestConfig_UnknownKeysRejected(t *testing.T) {
+	_, err := NewConfig(`room:
+  empty_timeot: 10`, nil)
+	require.Error(t, err)
+
+	conf, err := NewConfig(`ingress:
+  rtmp_base_url: rtmp://localhost/live`, nil)
+	require.NoError(t, err)
+	require.Equal(t, "rtmp://localhost/live", conf.Ingress.RTMPBaseURL)
+}
+
+func TestConfig_SampleConfigValid(t *testing.T) {
+	content, err := os.ReadFile("../../config-sample.yaml")
+	require.NoError(t, err)
+	require.NoError(t, unmarshalStrict(string(content), &Config{}))
+}
+
+func TestConfig_EnvOverrides(t *testing.T) {
+	t.Setenv("LIVEKIT_PORT", "7890")
+	t.Setenv("LIVEKIT_RTC_TCP_PORT", "7891")
+	t.Setenv("LIVEKIT_RTC_STUN_SERVERS", "stun1.host:3478, stun2.host:3478")
+	t.Setenv("LIVEKIT_RTC_PLI_THROTTLE_LOW_QUALITY", "2s")
+	t.Setenv("LIVEKIT_ROOM_AUTO_CREATE", "false")
+	t.Setenv("LIVEKIT_ROOM_ENABLED_CODECS", "[{mime: audio/opus}]")
+	t.Setenv("LIVEKIT_NODE_SELECTOR_KIND", "regionaware")
+	t.Setenv("LIVEKIT_NODE_SELECTOR_REGIONS", "[{name: us-west, lat: 37.64, lon: -120.95}]")
+	t.Setenv("LIVEKIT_TURN_ENABLED", "true")
+	t.Setenv("LIVEKIT_LOGGING_LEVEL", "warn")
+	t.Setenv("LIVEKIT_TENANCY_KEY_TENANTS", "key1: tenant1")
+
+	conf, err := NewConfig(`port: 7880
+room:
+  empty_timeout: 10`, nil)
+	require.NoError(t, err)
+	require.Equal(t, uint32(7890), conf.Port)
+	require.Equal(t, uint32(7891), conf.RTC.TCPPort)
+	require.Equal(t, []string{"stun1.host:3478", "stun2.host:3478"}, conf.RTC.STUNServers)
+	require.Equal(t, 2*time.Second, conf.RTC.PLIThrottle.LowQuality)
+	require.False(t, conf.Room.AutoCreate)
+	require.Equal(t, uint32(10), conf.Room.EmptyTimeout)
+	require.Equal(t, []CodecSpec{{Mime: "audio/opus"}}, conf.Room.EnabledCodecs)
+	require.Equal(t, "regionaware", conf.NodeSelector.Kind)
+	require.Equal(t, []RegionConfig{{Name: "us-west", Lat: 37.64, Lon: -120.95}}, conf.NodeSelector.Regions)
+	require.True(t, conf.TURN.Enabled)
+	require.Equal(t, "warn", conf.Logging.Level)
+	require.Equal(t, map[string]string{"key1": "tenant1"}, conf.Tenancy.KeyTenants)
+
+	t.Setenv("LIVEKIT_ROOM_ENABLED_CODECS", "[{mimetype: audio/opus}]")
+	_, err = NewConfig("", nil)
+	require.Error(t, err)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	_, err := NewConfig(`rtc:
+  port_range_start: 60000
+  port_range_end: 50000`, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`tracing:
+  sample_ratio: 2`, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`room:
+  templates:
+    webinar:
+      recording:
+        allowed_types: [room_composite, mixed]`, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`room:
+  templates:
+    webinar:
+      role_publish_sources:
+        audience: [microphone, webcam]`, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`room:
+  data_limits:
+    action: kick`, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`room:
+  signal_limits:
+    request_rates:
+      leave: 1`, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`room:
+  signal_limits:
+    request_rates:
+      trickle: 1`, nil)
+	require.Error(t, err)
+
+	_, err = NewConfig(`room:
+  templates:
+    webinar:
+      duplicate_identity: merge`, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const envPrefix = "LIVEKIT_"
+
+// updateFromEnv sets fields from environment variables named after their yaml path, prefixed by LIVEKIT_,
+// i.e. LIVEKIT_RTC_TCP_PORT for rtc.tcp_port. Lists and maps are given in YAML, lists of strings may also
+// be comma separated
+func (conf *Config) updateFromEnv() error {
+	return updateStructFromEnv(reflect.ValueOf(conf).Elem(), envPrefix)
+}
+
+func updateStructFromEnv(v reflect.Value, prefix string) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("yaml"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+
+		fv := v.Field(i)
+		if len(tag) > 1 && tag[1] == "inline" {
+			if err := updateStructFromEnv(fv, prefix); err != nil {
+				return err
+			}
+			continue
+		}
+
+		envName := prefix + strings.ToUpper(name)
+		if f.Type.Kind() == reflect.Struct {
+			if err := updateStructFromEnv(fv, envName+"_"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := os.LookupEnv(envName)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(fv, value); err != nil {
+			return fmt.Errorf("could not parse %s: %v", envName, err)
+		}
+	}
+	return nil
+}
+
+func setFromEnv(v reflect.Value, value string) error {
+	if v.Kind() == reflect.String {
+		v.SetString(value)
+		return nil
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+		items := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = reflect.Append(items, reflect.ValueOf(item).Convert(v.Type().Elem()))
+			}
+		}
+		v.Set(items)
+		return nil
+	}
+
+	target := reflect.New(v.Type())
+	if err := unmarshalStrict(value, target.Interface()); err != nil {
+		return err
+	}
+	v.Set(target.Elem())
+	return nil
+}
+
+// unmarshalStrict decodes YAML, rejecting keys that don't match a field
+func unmarshalStrict(in string, out interface{}) error {
+	decoder := yaml.NewDecoder(strings.NewReader(in))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(out); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
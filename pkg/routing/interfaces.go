@@ -3,6 +3,7 @@ package routing
 import (
 	"context"
 	"encoding/json"
+	"time"
 
 	"github.com/go-redis/redis/v8"
 	"google.golang.org/protobuf/proto"
@@ -16,6 +17,7 @@ import (
 
 // MessageSink is an abstraction for writing protobuf messages and having them read by a MessageSource,
 // potentially on a different node via a transport
+//
 //counterfeiter:generate . MessageSink
 type MessageSink interface {
 	WriteMessage(msg proto.Message) error
@@ -38,15 +40,50 @@ type ParticipantInit struct {
 	Grants         *auth.ClaimGrants
 	Region         string
 	AdaptiveStream bool
+	// API key that signed the participant's token, and the tenant it acts for
+	APIKey string
+	Tenant string
+	// serialized trace context of the signal connection, continued on the RTC node
+	TraceContext map[string]string
+	// template the participant's token applies to rooms auto created on join
+	RoomTemplate string
+	// time the participant may stay connected, 0 for no limit
+	MaxSessionDuration time.Duration
+	// role claimed by the participant's token
+	Role string
+	// data topics the participant subscribes to on join, and may publish to. any may be published to when nil
+	DataTopics    []string
+	PublishTopics []string
+	// policy for an identity already in the room claimed by the participant's token, the room's when empty
+	DuplicateIdentity string
+	// SID of the session a reconnecting participant resumes, any session of its identity when empty
+	ParticipantID livekit.ParticipantID
 }
 
+// sessionClaims are serialized into StartSession.GrantsJson, carrying server side session details alongside
+// the participant's grants. Fields are flattened so older nodes can still decode the grants
+type sessionClaims struct {
+	auth.ClaimGrants
+	APIKey             string            `json:"apiKey,omitempty"`
+	Tenant             string            `json:"tenant,omitempty"`
+	TraceContext       map[string]string `json:"traceContext,omitempty"`
+	RoomTemplate       string            `json:"roomTemplate,omitempty"`
+	MaxSessionDuration time.Duration     `json:"maxSessionDuration,omitempty"`
+	Role               string            `json:"role,omitempty"`
+	DataTopics         []string          `json:"dataTopics,omitempty"`
+	PublishTopics      []string          `json:"publishTopics,omitempty"`
+	DuplicateIdentity  string            `json:"duplicateIdentity,omitempty"`
+	ParticipantID      string            `json:"participantId,omitempty"`
+}
+
+// NewParticipantCallback starts or resumes the session of a participant, returning its SID
 type NewParticipantCallback func(
 	ctx context.Context,
 	roomName livekit.RoomName,
 	pi ParticipantInit,
 	requestSource MessageSource,
 	responseSink MessageSink,
-) error
+) (livekit.ParticipantID, error)
 
 type RTCMessageCallback func(
 	ctx context.Context,
@@ -55,7 +92,16 @@ type RTCMessageCallback func(
 	msg *livekit.RTCNodeMessage,
 )
 
+// RoomOperationCallback executes an operation on a room hosted on this node, returning its encoded result
+type RoomOperationCallback func(
+	ctx context.Context,
+	roomName livekit.RoomName,
+	op string,
+	req []byte,
+) ([]byte, error)
+
 // Router allows multiple nodes to coordinate the participant session
+//
 //counterfeiter:generate . Router
 type Router interface {
 	MessageRouter
@@ -81,6 +127,9 @@ type Router interface {
 
 	// OnRTCMessage is called to execute actions on the RTC node
 	OnRTCMessage(callback RTCMessageCallback)
+
+	// OnRoomOperation is called to execute operations on rooms hosted on this node
+	OnRoomOperation(callback RoomOperationCallback)
 }
 
 type MessageRouter interface {
@@ -90,6 +139,9 @@ type MessageRouter interface {
 	// Write a message to a participant or room
 	WriteParticipantRTC(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, msg *livekit.RTCNodeMessage) error
 	WriteRoomRTC(ctx context.Context, roomName livekit.RoomName, msg *livekit.RTCNodeMessage) error
+
+	// ExecuteRoomOperation executes an operation on the node hosting the room, waiting for its result
+	ExecuteRoomOperation(ctx context.Context, roomName livekit.RoomName, op string, req []byte) ([]byte, error)
 }
 
 func CreateRouter(rc *redis.Client, node LocalNode) Router {
@@ -103,7 +155,22 @@ func CreateRouter(rc *redis.Client, node LocalNode) Router {
 }
 
 func (pi *ParticipantInit) ToStartSession(roomName livekit.RoomName, connectionID livekit.ConnectionID) (*livekit.StartSession, error) {
-	claims, err := json.Marshal(pi.Grants)
+	sc := sessionClaims{
+		APIKey:             pi.APIKey,
+		Tenant:             pi.Tenant,
+		TraceContext:       pi.TraceContext,
+		RoomTemplate:       pi.RoomTemplate,
+		MaxSessionDuration: pi.MaxSessionDuration,
+		Role:               pi.Role,
+		DataTopics:         pi.DataTopics,
+		PublishTopics:      pi.PublishTopics,
+		DuplicateIdentity:  pi.DuplicateIdentity,
+		ParticipantID:      string(pi.ParticipantID),
+	}
+	if pi.Grants != nil {
+		sc.ClaimGrants = *pi.Grants
+	}
+	claims, err := json.Marshal(&sc)
 	if err != nil {
 		return nil, err
 	}
@@ -123,19 +190,29 @@ func (pi *ParticipantInit) ToStartSession(roomName livekit.RoomName, connectionI
 }
 
 func ParticipantInitFromStartSession(ss *livekit.StartSession, region string) (*ParticipantInit, error) {
-	claims := &auth.ClaimGrants{}
-	if err := json.Unmarshal([]byte(ss.GrantsJson), claims); err != nil {
+	sc := &sessionClaims{}
+	if err := json.Unmarshal([]byte(ss.GrantsJson), sc); err != nil {
 		return nil, err
 	}
 
 	return &ParticipantInit{
-		Identity:       livekit.ParticipantIdentity(ss.Identity),
-		Name:           livekit.ParticipantName(ss.Name),
-		Reconnect:      ss.Reconnect,
-		Client:         ss.Client,
-		AutoSubscribe:  ss.AutoSubscribe,
-		Grants:         claims,
-		Region:         region,
-		AdaptiveStream: ss.AdaptiveStream,
+		Identity:           livekit.ParticipantIdentity(ss.Identity),
+		Name:               livekit.ParticipantName(ss.Name),
+		Reconnect:          ss.Reconnect,
+		Client:             ss.Client,
+		AutoSubscribe:      ss.AutoSubscribe,
+		Grants:             &sc.ClaimGrants,
+		Region:             region,
+		AdaptiveStream:     ss.AdaptiveStream,
+		APIKey:             sc.APIKey,
+		Tenant:             sc.Tenant,
+		TraceContext:       sc.TraceContext,
+		RoomTemplate:       sc.RoomTemplate,
+		MaxSessionDuration: sc.MaxSessionDuration,
+		Role:               sc.Role,
+		DataTopics:         sc.DataTopics,
+		PublishTopics:      sc.PublishTopics,
+		DuplicateIdentity:  sc.DuplicateIdentity,
+		ParticipantID:      livekit.ParticipantID(sc.ParticipantID),
 	}, nil
 }
@@ -10,6 +10,9 @@ import (
 
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 )
 
 // aggregated channel for all participants
@@ -19,23 +22,28 @@ const localRTCChannelSize = 10000
 type LocalRouter struct {
 	currentNode LocalNode
 	lock        sync.RWMutex
-	// channels for each participant
-	requestChannels  map[string]*MessageChannel
-	responseChannels map[string]*MessageChannel
-	isStarted        atomic.Bool
+	// channels for each connection, along with the connection carrying each session, by SID
+	requestChannels    map[string]*MessageChannel
+	responseChannels   map[string]*MessageChannel
+	sessionConnections map[livekit.ParticipantID]livekit.ConnectionID
+	connectionSessions map[livekit.ConnectionID]livekit.ParticipantID
+	isStarted          atomic.Bool
 
 	rtcMessageChan *MessageChannel
 
 	onNewParticipant NewParticipantCallback
 	onRTCMessage     RTCMessageCallback
+	onRoomOperation  RoomOperationCallback
 }
 
 func NewLocalRouter(currentNode LocalNode) *LocalRouter {
 	return &LocalRouter{
-		currentNode:      currentNode,
-		requestChannels:  make(map[string]*MessageChannel),
-		responseChannels: make(map[string]*MessageChannel),
-		rtcMessageChan:   NewMessageChannel(localRTCChannelSize),
+		currentNode:        currentNode,
+		requestChannels:    make(map[string]*MessageChannel),
+		responseChannels:   make(map[string]*MessageChannel),
+		sessionConnections: make(map[livekit.ParticipantID]livekit.ConnectionID),
+		connectionSessions: make(map[livekit.ConnectionID]livekit.ParticipantID),
+		rtcMessageChan:     NewMessageChannel(localRTCChannelSize),
 	}
 }
 
@@ -81,29 +89,25 @@ func (r *LocalRouter) ListNodes() ([]*livekit.Node, error) {
 }
 
 func (r *LocalRouter) StartParticipantSignal(ctx context.Context, roomName livekit.RoomName, pi ParticipantInit) (connectionID livekit.ConnectionID, reqSink MessageSink, resSource MessageSource, err error) {
+	ctx, span := tracing.Start(ctx, "Router.StartParticipantSignal")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// treat it as a new participant connecting
 	if r.onNewParticipant == nil {
 		err = ErrHandlerNotDefined
 		return
 	}
 
-	// index channels by roomName | identity
-	key := participantKey(roomName, pi.Identity)
-
-	// close older channels if one already exists
-	reqChan := r.getMessageChannel(r.requestChannels, string(key))
-	if reqChan != nil {
-		reqChan.Close()
-	}
-	resChan := r.getMessageChannel(r.responseChannels, string(key))
-	if resChan != nil {
-		resChan.Close()
-	}
-	reqChan = r.getOrCreateMessageChannel(r.requestChannels, string(key))
-	resChan = r.getOrCreateMessageChannel(r.responseChannels, string(key))
+	// index channels by connectionID, since the SID of the session isn't known until it starts, and sessions
+	// joining alongside others of their identity have their own connections
+	connectionID = livekit.ConnectionID(utils.NewGuid("CO_"))
+	reqChan := r.getOrCreateRequestChannel(connectionID)
+	resChan := r.getOrCreateMessageChannel(r.responseChannels, string(connectionID))
 
 	go func() {
-		err := r.onNewParticipant(
+		sid, err := r.onNewParticipant(
 			ctx,
 			roomName,
 			pi,
@@ -119,9 +123,31 @@ func (r *LocalRouter) StartParticipantSignal(ctx context.Context, roomName livek
 				"room", roomName,
 				"participant", pi.Identity,
 			)
+			return
 		}
+		r.setSessionConnection(sid, connectionID)
 	}()
-	return livekit.ConnectionID(pi.Identity), reqChan, resChan, nil
+	return connectionID, reqChan, resChan, nil
+}
+
+// setSessionConnection records the connection carrying a session, closing the channels of the connection it
+// supersedes when the session is resumed
+func (r *LocalRouter) setSessionConnection(sid livekit.ParticipantID, connectionID livekit.ConnectionID) {
+	r.lock.Lock()
+	var superseded []*MessageChannel
+	if prev, ok := r.sessionConnections[sid]; ok && prev != connectionID {
+		delete(r.connectionSessions, prev)
+		superseded = append(superseded, r.requestChannels[string(prev)], r.responseChannels[string(prev)])
+	}
+	r.sessionConnections[sid] = connectionID
+	r.connectionSessions[connectionID] = sid
+	r.lock.Unlock()
+
+	for _, mc := range superseded {
+		if mc != nil {
+			mc.Close()
+		}
+	}
 }
 
 func (r *LocalRouter) WriteParticipantRTC(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, msg *livekit.RTCNodeMessage) error {
@@ -148,6 +174,13 @@ func (r *LocalRouter) WriteNodeRTC(_ context.Context, _ string, msg *livekit.RTC
 	return r.writeRTCMessage(r.rtcMessageChan, msg)
 }
 
+func (r *LocalRouter) ExecuteRoomOperation(ctx context.Context, roomName livekit.RoomName, op string, req []byte) ([]byte, error) {
+	if r.onRoomOperation == nil {
+		return nil, ErrHandlerNotDefined
+	}
+	return r.onRoomOperation(ctx, roomName, op, req)
+}
+
 func (r *LocalRouter) writeRTCMessage(sink MessageSink, msg *livekit.RTCNodeMessage) error {
 	defer sink.Close()
 	msg.SenderTime = time.Now().Unix()
@@ -162,6 +195,10 @@ func (r *LocalRouter) OnRTCMessage(callback RTCMessageCallback) {
 	r.onRTCMessage = callback
 }
 
+func (r *LocalRouter) OnRoomOperation(callback RoomOperationCallback) {
+	r.onRoomOperation = callback
+}
+
 func (r *LocalRouter) Start() error {
 	if r.isStarted.Swap(true) {
 		return nil
@@ -234,10 +271,20 @@ func (r *LocalRouter) rtcMessageWorker() {
 	}
 }
 
-func (r *LocalRouter) getMessageChannel(target map[string]*MessageChannel, key string) *MessageChannel {
-	r.lock.RLock()
-	defer r.lock.RUnlock()
-	return target[key]
+// getOrCreateRequestChannel returns the request channel of a connection, which no longer carries its session once
+// closed
+func (r *LocalRouter) getOrCreateRequestChannel(connectionID livekit.ConnectionID) *MessageChannel {
+	mc := r.getOrCreateMessageChannel(r.requestChannels, string(connectionID))
+	mc.OnClose(func() {
+		r.lock.Lock()
+		delete(r.requestChannels, string(connectionID))
+		if sid, ok := r.connectionSessions[connectionID]; ok {
+			delete(r.connectionSessions, connectionID)
+			delete(r.sessionConnections, sid)
+		}
+		r.lock.Unlock()
+	})
+	return mc
 }
 
 func (r *LocalRouter) getOrCreateMessageChannel(target map[string]*MessageChannel, key string) *MessageChannel {
@@ -0,0 +1,56 @@
+package routing_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/routing"
+)
+
+func TestLocalRouterSessionChannels(t *testing.T) {
+	router := routing.NewLocalRouter(&livekit.Node{Id: "node"})
+	// sessions resume by the SID the participant reconnects with, new ones are given the next SID
+	sids := []livekit.ParticipantID{"PA_1", "PA_1#2"}
+	started := make(chan struct{}, 1)
+	router.OnNewParticipantRTC(func(_ context.Context, _ livekit.RoomName, pi routing.ParticipantInit, _ routing.MessageSource, _ routing.MessageSink) (livekit.ParticipantID, error) {
+		defer func() { started <- struct{}{} }()
+		if pi.ParticipantID != "" {
+			return pi.ParticipantID, nil
+		}
+		sid := sids[0]
+		sids = sids[1:]
+		return sid, nil
+	})
+
+	start := func(pi routing.ParticipantInit) routing.MessageSink {
+		_, reqSink, _, err := router.StartParticipantSignal(context.Background(), "room", pi)
+		require.NoError(t, err)
+		select {
+		case <-started:
+		case <-time.After(time.Second):
+			t.Fatal("session did not start")
+		}
+		return reqSink
+	}
+	isClosed := func(sink routing.MessageSink) bool {
+		return sink.(*routing.MessageChannel).IsClosed()
+	}
+
+	first := start(routing.ParticipantInit{Identity: "p"})
+	// a session joining alongside keeps the channels of the first one open
+	duplicate := start(routing.ParticipantInit{Identity: "p"})
+	require.False(t, isClosed(first))
+
+	// resuming a session closes the channels of the connection it supersedes
+	resumed := start(routing.ParticipantInit{Identity: "p", Reconnect: true, ParticipantID: "PA_1"})
+	require.Eventually(t, func() bool {
+		return isClosed(first)
+	}, time.Second, 10*time.Millisecond)
+	require.False(t, isClosed(duplicate))
+	require.False(t, isClosed(resumed))
+}
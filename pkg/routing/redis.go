@@ -38,6 +38,29 @@ func signalNodeChannel(nodeID livekit.NodeID) string {
 	return "signal_channel:" + string(nodeID)
 }
 
+// operations on rooms hosted by the node
+func roomOperationChannel(nodeID livekit.NodeID) string {
+	return "room_op_channel:" + string(nodeID)
+}
+
+// result of a room operation, list popped by the node waiting for it
+func roomOperationResultKey(operationID string) string {
+	return "room_op_result:" + operationID
+}
+
+// roomOperation is published to the node hosting a room, which pushes a roomOperationResult to ResultKey
+type roomOperation struct {
+	ResultKey string           `json:"resultKey"`
+	Room      livekit.RoomName `json:"room"`
+	Op        string           `json:"op"`
+	Request   []byte           `json:"request"`
+}
+
+type roomOperationResult struct {
+	Result []byte `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
 func publishRTCMessage(rc *redis.Client, nodeID livekit.NodeID, participantKey livekit.ParticipantKey, msg proto.Message) error {
 	rm := &livekit.RTCNodeMessage{
 		ParticipantKey: string(participantKey),
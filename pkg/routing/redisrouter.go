@@ -3,6 +3,7 @@ package routing
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"runtime/pprof"
 	"sync"
 	"time"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/routing/selector"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 )
 
 const (
@@ -25,6 +27,8 @@ const (
 	participantMappingTTL = 24 * time.Hour
 	statsUpdateInterval   = 2 * time.Second
 	statsMaxDelaySeconds  = 30
+	// time the node hosting a room has to execute an operation on it, unless the caller's context ends sooner
+	roomOperationTimeout = 5 * time.Second
 )
 
 // RedisRouter uses Redis pub/sub to route signaling messages across different nodes
@@ -140,6 +144,11 @@ func (r *RedisRouter) ListNodes() ([]*livekit.Node, error) {
 
 // StartParticipantSignal signal connection sets up paths to the RTC node, and starts to route messages to that message queue
 func (r *RedisRouter) StartParticipantSignal(ctx context.Context, roomName livekit.RoomName, pi ParticipantInit) (connectionID livekit.ConnectionID, reqSink MessageSink, resSource MessageSource, err error) {
+	ctx, span := tracing.Start(ctx, "Router.StartParticipantSignal")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	// find the node where the room is hosted at
 	rtcNode, err := r.GetNodeForRoom(ctx, roomName)
 	if err != nil {
@@ -157,7 +166,8 @@ func (r *RedisRouter) StartParticipantSignal(ctx context.Context, roomName livek
 
 	sink := NewRTCNodeSink(r.rc, livekit.NodeID(rtcNode.Id), pKey)
 
-	// serialize claims
+	// serialize claims, continuing the trace on the RTC node
+	pi.TraceContext = tracing.Inject(ctx)
 	ss, err := pi.ToStartSession(roomName, connectionID)
 	if err != nil {
 		return
@@ -200,6 +210,73 @@ func (r *RedisRouter) WriteNodeRTC(_ context.Context, rtcNodeID string, msg *liv
 	return r.writeRTCMessage(rtcSink, msg)
 }
 
+func (r *RedisRouter) ExecuteRoomOperation(ctx context.Context, roomName livekit.RoomName, op string, req []byte) ([]byte, error) {
+	node, err := r.GetNodeForRoom(ctx, roomName)
+	if err != nil {
+		return nil, err
+	}
+	if node.Id == r.currentNode.Id {
+		return r.LocalRouter.ExecuteRoomOperation(ctx, roomName, op, req)
+	}
+
+	rop := &roomOperation{
+		ResultKey: roomOperationResultKey(utils.NewGuid("RO_")),
+		Room:      roomName,
+		Op:        op,
+		Request:   req,
+	}
+	data, err := json.Marshal(rop)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.rc.Publish(ctx, roomOperationChannel(livekit.NodeID(node.Id)), data).Err(); err != nil {
+		return nil, err
+	}
+
+	timeout := roomOperationTimeout
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+	res, err := r.rc.BLPop(ctx, timeout, rop.ResultKey).Result()
+	if err == redis.Nil {
+		return nil, ErrOperationTimeout
+	} else if err != nil {
+		return nil, err
+	}
+	result := &roomOperationResult{}
+	if err = json.Unmarshal([]byte(res[1]), result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" {
+		return nil, errors.New(result.Error)
+	}
+	return result.Result, nil
+}
+
+// handleRoomOperation executes an operation published by another node, and pushes its result back
+func (r *RedisRouter) handleRoomOperation(rop *roomOperation) {
+	result := &roomOperationResult{}
+	res, err := r.LocalRouter.ExecuteRoomOperation(r.ctx, rop.Room, rop.Op, rop.Request)
+	if err != nil {
+		result.Error = err.Error()
+	} else {
+		result.Result = res
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorw("could not marshal room operation result", err, "room", rop.Room, "op", rop.Op)
+		return
+	}
+
+	pipe := r.rc.TxPipeline()
+	pipe.RPush(r.ctx, rop.ResultKey, data)
+	// left behind when the caller has given up
+	pipe.Expire(r.ctx, rop.ResultKey, roomOperationTimeout)
+	if _, err = pipe.Exec(r.ctx); err != nil {
+		logger.Errorw("could not push room operation result", err, "room", rop.Room, "op", rop.Op)
+	}
+}
+
 func (r *RedisRouter) startParticipantRTC(ss *livekit.StartSession, participantKey livekit.ParticipantKey) error {
 	// find the node where the room is hosted at
 	rtcNode, err := r.GetNodeForRoom(r.ctx, livekit.RoomName(ss.RoomName))
@@ -250,8 +327,8 @@ func (r *RedisRouter) startParticipantRTC(ss *livekit.StartSession, participantK
 	reqChan := r.getOrCreateMessageChannel(r.requestChannels, string(participantKey))
 	resSink := NewSignalNodeSink(r.rc, livekit.NodeID(signalNode), livekit.ConnectionID(ss.ConnectionId))
 	go func() {
-		err := r.onNewParticipant(
-			r.ctx,
+		_, err := r.onNewParticipant(
+			tracing.Extract(r.ctx, pi.TraceContext),
 			livekit.RoomName(ss.RoomName),
 			*pi,
 			reqChan,
@@ -380,7 +457,8 @@ func (r *RedisRouter) redisWorker(startedChan chan struct{}) {
 
 	sigChannel := signalNodeChannel(livekit.NodeID(r.currentNode.Id))
 	rtcChannel := rtcNodeChannel(livekit.NodeID(r.currentNode.Id))
-	r.pubsub = r.rc.Subscribe(r.ctx, sigChannel, rtcChannel)
+	opChannel := roomOperationChannel(livekit.NodeID(r.currentNode.Id))
+	r.pubsub = r.rc.Subscribe(r.ctx, sigChannel, rtcChannel, opChannel)
 
 	close(startedChan)
 	for msg := range r.pubsub.Channel() {
@@ -414,6 +492,16 @@ func (r *RedisRouter) redisWorker(startedChan chan struct{}) {
 				continue
 			}
 			prometheus.MessageCounter.WithLabelValues("rtc", "success").Add(1)
+		} else if msg.Channel == opChannel {
+			rop := &roomOperation{}
+			if err := json.Unmarshal([]byte(msg.Payload), rop); err != nil {
+				logger.Errorw("could not unmarshal room operation on opchan", err)
+				prometheus.MessageCounter.WithLabelValues("room_op", "failure").Add(1)
+				continue
+			}
+			// operations may take a while, and wait on messages of this worker
+			go r.handleRoomOperation(rop)
+			prometheus.MessageCounter.WithLabelValues("room_op", "success").Add(1)
 		}
 	}
 }
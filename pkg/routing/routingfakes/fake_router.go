@@ -26,6 +26,22 @@ type FakeRouter struct {
 	drainMutex       sync.RWMutex
 	drainArgsForCall []struct {
 	}
+	ExecuteRoomOperationStub        func(context.Context, livekit.RoomName, string, []byte) ([]byte, error)
+	executeRoomOperationMutex       sync.RWMutex
+	executeRoomOperationArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 string
+		arg4 []byte
+	}
+	executeRoomOperationReturns struct {
+		result1 []byte
+		result2 error
+	}
+	executeRoomOperationReturnsOnCall map[int]struct {
+		result1 []byte
+		result2 error
+	}
 	GetNodeForRoomStub        func(context.Context, livekit.RoomName) (*livekit.Node, error)
 	getNodeForRoomMutex       sync.RWMutex
 	getNodeForRoomArgsForCall []struct {
@@ -72,6 +88,11 @@ type FakeRouter struct {
 	onRTCMessageArgsForCall []struct {
 		arg1 routing.RTCMessageCallback
 	}
+	OnRoomOperationStub        func(routing.RoomOperationCallback)
+	onRoomOperationMutex       sync.RWMutex
+	onRoomOperationArgsForCall []struct {
+		arg1 routing.RoomOperationCallback
+	}
 	RegisterNodeStub        func() error
 	registerNodeMutex       sync.RWMutex
 	registerNodeArgsForCall []struct {
@@ -265,6 +286,73 @@ func (fake *FakeRouter) DrainCalls(stub func()) {
 	fake.DrainStub = stub
 }
 
+func (fake *FakeRouter) ExecuteRoomOperation(arg1 context.Context, arg2 livekit.RoomName, arg3 string, arg4 []byte) ([]byte, error) {
+	fake.executeRoomOperationMutex.Lock()
+	ret, specificReturn := fake.executeRoomOperationReturnsOnCall[len(fake.executeRoomOperationArgsForCall)]
+	fake.executeRoomOperationArgsForCall = append(fake.executeRoomOperationArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 string
+		arg4 []byte
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.ExecuteRoomOperationStub
+	fakeReturns := fake.executeRoomOperationReturns
+	fake.recordInvocation("ExecuteRoomOperation", []interface{}{arg1, arg2, arg3, arg4})
+	fake.executeRoomOperationMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeRouter) ExecuteRoomOperationCallCount() int {
+	fake.executeRoomOperationMutex.RLock()
+	defer fake.executeRoomOperationMutex.RUnlock()
+	return len(fake.executeRoomOperationArgsForCall)
+}
+
+func (fake *FakeRouter) ExecuteRoomOperationCalls(stub func(context.Context, livekit.RoomName, string, []byte) ([]byte, error)) {
+	fake.executeRoomOperationMutex.Lock()
+	defer fake.executeRoomOperationMutex.Unlock()
+	fake.ExecuteRoomOperationStub = stub
+}
+
+func (fake *FakeRouter) ExecuteRoomOperationArgsForCall(i int) (context.Context, livekit.RoomName, string, []byte) {
+	fake.executeRoomOperationMutex.RLock()
+	defer fake.executeRoomOperationMutex.RUnlock()
+	argsForCall := fake.executeRoomOperationArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeRouter) ExecuteRoomOperationReturns(result1 []byte, result2 error) {
+	fake.executeRoomOperationMutex.Lock()
+	defer fake.executeRoomOperationMutex.Unlock()
+	fake.ExecuteRoomOperationStub = nil
+	fake.executeRoomOperationReturns = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeRouter) ExecuteRoomOperationReturnsOnCall(i int, result1 []byte, result2 error) {
+	fake.executeRoomOperationMutex.Lock()
+	defer fake.executeRoomOperationMutex.Unlock()
+	fake.ExecuteRoomOperationStub = nil
+	if fake.executeRoomOperationReturnsOnCall == nil {
+		fake.executeRoomOperationReturnsOnCall = make(map[int]struct {
+			result1 []byte
+			result2 error
+		})
+	}
+	fake.executeRoomOperationReturnsOnCall[i] = struct {
+		result1 []byte
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeRouter) GetNodeForRoom(arg1 context.Context, arg2 livekit.RoomName) (*livekit.Node, error) {
 	fake.getNodeForRoomMutex.Lock()
 	ret, specificReturn := fake.getNodeForRoomReturnsOnCall[len(fake.getNodeForRoomArgsForCall)]
@@ -503,6 +591,38 @@ func (fake *FakeRouter) OnRTCMessageArgsForCall(i int) routing.RTCMessageCallbac
 	return argsForCall.arg1
 }
 
+func (fake *FakeRouter) OnRoomOperation(arg1 routing.RoomOperationCallback) {
+	fake.onRoomOperationMutex.Lock()
+	fake.onRoomOperationArgsForCall = append(fake.onRoomOperationArgsForCall, struct {
+		arg1 routing.RoomOperationCallback
+	}{arg1})
+	stub := fake.OnRoomOperationStub
+	fake.recordInvocation("OnRoomOperation", []interface{}{arg1})
+	fake.onRoomOperationMutex.Unlock()
+	if stub != nil {
+		fake.OnRoomOperationStub(arg1)
+	}
+}
+
+func (fake *FakeRouter) OnRoomOperationCallCount() int {
+	fake.onRoomOperationMutex.RLock()
+	defer fake.onRoomOperationMutex.RUnlock()
+	return len(fake.onRoomOperationArgsForCall)
+}
+
+func (fake *FakeRouter) OnRoomOperationCalls(stub func(routing.RoomOperationCallback)) {
+	fake.onRoomOperationMutex.Lock()
+	defer fake.onRoomOperationMutex.Unlock()
+	fake.OnRoomOperationStub = stub
+}
+
+func (fake *FakeRouter) OnRoomOperationArgsForCall(i int) routing.RoomOperationCallback {
+	fake.onRoomOperationMutex.RLock()
+	defer fake.onRoomOperationMutex.RUnlock()
+	argsForCall := fake.onRoomOperationArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeRouter) RegisterNode() error {
 	fake.registerNodeMutex.Lock()
 	ret, specificReturn := fake.registerNodeReturnsOnCall[len(fake.registerNodeArgsForCall)]
@@ -1008,6 +1128,8 @@ func (fake *FakeRouter) Invocations() map[string][][]interface{} {
 	defer fake.clearRoomStateMutex.RUnlock()
 	fake.drainMutex.RLock()
 	defer fake.drainMutex.RUnlock()
+	fake.executeRoomOperationMutex.RLock()
+	defer fake.executeRoomOperationMutex.RUnlock()
 	fake.getNodeForRoomMutex.RLock()
 	defer fake.getNodeForRoomMutex.RUnlock()
 	fake.getRegionMutex.RLock()
@@ -1018,6 +1140,8 @@ func (fake *FakeRouter) Invocations() map[string][][]interface{} {
 	defer fake.onNewParticipantRTCMutex.RUnlock()
 	fake.onRTCMessageMutex.RLock()
 	defer fake.onRTCMessageMutex.RUnlock()
+	fake.onRoomOperationMutex.RLock()
+	defer fake.onRoomOperationMutex.RUnlock()
 	fake.registerNodeMutex.RLock()
 	defer fake.registerNodeMutex.RUnlock()
 	fake.removeDeadNodesMutex.RLock()
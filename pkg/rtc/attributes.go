@@ -0,0 +1,25 @@
+package rtc
+
+import (
+	"github.com/livekit/protocol/livekit"
+)
+
+// UpdateParticipantAttributes updates attributes of a participant, see ParticipantImpl.UpdateAttributes.
+// Participants of the room receive changes as participant updates, the participant's attributes are returned
+func (r *Room) UpdateParticipantAttributes(identity livekit.ParticipantIdentity, update map[string]string, ifVersion *uint32, maxSize uint32) (map[string]string, uint32, error) {
+	participant := r.GetParticipant(identity)
+	if participant == nil {
+		return nil, 0, ErrParticipantNotFound
+	}
+
+	changed, version, err := participant.UpdateAttributes(update, ifVersion, maxSize)
+	if err != nil {
+		return nil, version, err
+	}
+	if len(changed) > 0 {
+		participant.GetLogger().Debugw("updated participant attributes", "changed", changed, "version", version)
+	}
+
+	attributes, version := participant.Attributes()
+	return attributes, version, nil
+}
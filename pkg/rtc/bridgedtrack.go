@@ -0,0 +1,369 @@
+package rtc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pion/webrtc/v3"
+	"go.uber.org/atomic"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/sfu"
+	"github.com/livekit/livekit-server/pkg/sfu/buffer"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/utils"
+)
+
+// BridgedTrack republishes a MediaTrack of another room on the same node.
+// Each of its receivers is attached to a receiver of the source track as a down track, and spreads packets to down
+// tracks of its own subscribers, which keep selecting simulcast layers on their own. The max quality its subscribers
+// want is reported to the source track like that of subscribers on another node, so dynacast keeps working
+// Implements LocalMediaTrack interface
+type BridgedTrack struct {
+	params BridgedTrackParams
+
+	*MediaTrackReceiver
+
+	dynacastManager *DynacastManager
+
+	lock          sync.RWMutex
+	receivers     []*bridgedReceiver
+	onSourceMuted func(muted bool)
+
+	closed atomic.Bool
+}
+
+type BridgedTrackParams struct {
+	Source              *MediaTrack
+	TrackID             livekit.TrackID
+	ParticipantID       livekit.ParticipantID
+	ParticipantIdentity livekit.ParticipantIdentity
+	ParticipantVersion  uint32
+	BufferFactory       *buffer.Factory
+	ReceiverConfig      ReceiverConfig
+	SubscriberConfig    DirectionConfig
+	VideoConfig         config.VideoConfig
+	Telemetry           telemetry.TelemetryService
+	Logger              logger.Logger
+}
+
+func NewBridgedTrack(params BridgedTrackParams) *BridgedTrack {
+	t := &BridgedTrack{
+		params: params,
+		dynacastManager: NewDynacastManager(DynacastManagerParams{
+			DynacastPauseDelay: params.VideoConfig.DynacastPauseDelay,
+			Logger:             params.Logger,
+		}),
+	}
+
+	ti := params.Source.ToProto()
+	ti.Sid = string(params.TrackID)
+	// mids belong to the source publisher's session description
+	ti.Mid = ""
+	for _, c := range ti.Codecs {
+		c.Mid = ""
+		c.Cid = ""
+	}
+
+	t.MediaTrackReceiver = NewMediaTrackReceiver(MediaTrackReceiverParams{
+		TrackInfo:           ti,
+		MediaTrack:          t,
+		ParticipantID:       params.ParticipantID,
+		ParticipantIdentity: params.ParticipantIdentity,
+		ParticipantVersion:  params.ParticipantVersion,
+		BufferFactory:       params.BufferFactory,
+		ReceiverConfig:      params.ReceiverConfig,
+		SubscriberConfig:    params.SubscriberConfig,
+		Telemetry:           params.Telemetry,
+		Logger:              params.Logger,
+	})
+	t.MediaTrackReceiver.SetSimulcast(params.Source.IsSimulcast())
+	t.MediaTrackReceiver.OnSetupReceiver(func(mime string) {
+		t.dynacastManager.AddCodec(mime)
+	})
+	t.MediaTrackReceiver.OnSubscriberMaxQualityChange(func(subscriberID livekit.ParticipantID, codec webrtc.RTPCodecCapability, layer int32) {
+		t.dynacastManager.NotifySubscriberMaxQuality(subscriberID, codec.MimeType, utils.QualityForSpatialLayer(layer))
+	})
+	t.dynacastManager.OnSubscribedMaxQualityChange(func(_ []*livekit.SubscribedCodec, maxSubscribedQualities []types.SubscribedCodecQuality) {
+		if !t.closed.Load() {
+			t.params.Source.NotifySubscriberNodeMaxQuality(t.sourceNodeID(), maxSubscribedQualities)
+		}
+	})
+
+	for priority, source := range params.Source.Receivers() {
+		receiver := newBridgedReceiver(source, t.ID(), params.ParticipantID, t.Kind(), params.Logger)
+		receiver.onClose = t.Close
+		if err := source.AddDownTrack(receiver.sink()); err != nil {
+			params.Logger.Warnw("could not attach to source receiver", err, "mime", source.Codec().MimeType)
+			continue
+		}
+		t.lock.Lock()
+		t.receivers = append(t.receivers, receiver)
+		t.lock.Unlock()
+		t.MediaTrackReceiver.SetupReceiver(receiver, priority, "")
+	}
+
+	params.Source.AddListener(string(params.TrackID), func(muted bool) {
+		if t.closed.Load() {
+			return
+		}
+		t.lock.RLock()
+		onSourceMuted := t.onSourceMuted
+		t.lock.RUnlock()
+		if onSourceMuted != nil {
+			onSourceMuted(muted)
+		}
+	}, t.Close)
+
+	params.Telemetry.TrackPublished(context.Background(), t.PublisherID(), t.PublisherIdentity(), t.ToProto())
+	return t
+}
+
+// SourceTrack returns the track being republished
+func (t *BridgedTrack) SourceTrack() *MediaTrack {
+	return t.params.Source
+}
+
+// OnSourceMuted is called when the source track is muted or unmuted
+func (t *BridgedTrack) OnSourceMuted(f func(muted bool)) {
+	t.lock.Lock()
+	t.onSourceMuted = f
+	t.lock.Unlock()
+}
+
+func (t *BridgedTrack) ToProto() *livekit.TrackInfo {
+	info := t.MediaTrackReceiver.TrackInfo(true)
+	info.Muted = t.IsMuted()
+	info.Simulcast = t.IsSimulcast()
+	return info
+}
+
+func (t *BridgedTrack) Restart() {
+	t.MediaTrackReceiver.Restart()
+
+	t.dynacastManager.Restart()
+}
+
+func (t *BridgedTrack) SignalCid() string {
+	return ""
+}
+
+func (t *BridgedTrack) HasSdpCid(_ string) bool {
+	return false
+}
+
+func (t *BridgedTrack) GetConnectionScore() float32 {
+	return t.params.Source.GetConnectionScore()
+}
+
+func (t *BridgedTrack) NotifySubscriberNodeMaxQuality(nodeID livekit.NodeID, qualities []types.SubscribedCodecQuality) {
+	t.dynacastManager.NotifySubscriberNodeMaxQuality(nodeID, qualities)
+}
+
+func (t *BridgedTrack) NotifySubscriberNodeMediaLoss(_ livekit.NodeID, _ uint8) {
+	// loss is reported to the source publisher by subscribers of the source track
+}
+
+func (t *BridgedTrack) SetMuted(muted bool) {
+	if !muted {
+		t.dynacastManager.ForceUpdate()
+	}
+
+	t.MediaTrackReceiver.SetMuted(muted)
+}
+
+// Close detaches the track from the source track, and unsubscribes its subscribers
+func (t *BridgedTrack) Close() {
+	if t.closed.Swap(true) {
+		return
+	}
+	t.params.Logger.Infow("closing bridged track", "sourceTrackID", t.params.Source.ID())
+
+	// the source track outlives bridges closed by their participant leaving
+	t.params.Source.RemoveListener(string(t.params.TrackID))
+
+	t.RemoveAllSubscribers(false)
+
+	t.lock.Lock()
+	receivers := t.receivers
+	t.receivers = nil
+	t.lock.Unlock()
+
+	qualities := make([]types.SubscribedCodecQuality, 0, len(receivers))
+	for _, receiver := range receivers {
+		receiver.closed.Store(true)
+		receiver.TrackReceiver.DeleteDownTrack(receiver.subscriberID())
+		qualities = append(qualities, types.SubscribedCodecQuality{
+			CodecMime: receiver.Codec().MimeType,
+			Quality:   livekit.VideoQuality_OFF,
+		})
+	}
+	t.params.Source.NotifySubscriberNodeMaxQuality(t.sourceNodeID(), qualities)
+
+	t.MediaTrackReceiver.ClearAllReceivers()
+	t.dynacastManager.Close()
+	t.params.Telemetry.TrackUnpublished(context.Background(), t.PublisherID(), t.PublisherIdentity(), t.ToProto(), 0)
+
+	t.MediaTrackReceiver.Close()
+}
+
+// sourceNodeID identifies the track's subscribers to the source track
+func (t *BridgedTrack) sourceNodeID() livekit.NodeID {
+	return livekit.NodeID(t.ID())
+}
+
+// ---------------------------
+
+// bridgedReceiver is a receiver of a bridged track, forwarding to the receiver of the source track
+// everything but down tracks, which are fed by its sink
+type bridgedReceiver struct {
+	sfu.TrackReceiver
+
+	trackID  livekit.TrackID
+	streamID string
+	kind     livekit.TrackType
+	logger   logger.Logger
+
+	downTrackSpreader *sfu.DownTrackSpreader
+
+	layersLock sync.RWMutex
+	layers     []int32
+
+	closed  atomic.Bool
+	onClose func()
+}
+
+func newBridgedReceiver(source sfu.TrackReceiver, trackID livekit.TrackID, participantID livekit.ParticipantID, kind livekit.TrackType, logger logger.Logger) *bridgedReceiver {
+	return &bridgedReceiver{
+		TrackReceiver: source,
+		trackID:       trackID,
+		streamID:      string(participantID),
+		kind:          kind,
+		logger:        logger,
+		downTrackSpreader: sfu.NewDownTrackSpreader(sfu.DownTrackSpreaderParams{
+			Threshold: 20,
+			Logger:    logger,
+		}),
+	}
+}
+
+func (r *bridgedReceiver) TrackID() livekit.TrackID {
+	return r.trackID
+}
+
+func (r *bridgedReceiver) StreamID() string {
+	return r.streamID
+}
+
+func (r *bridgedReceiver) SetUpTrackPaused(_ bool) {
+	// follows the source track's publisher
+}
+
+func (r *bridgedReceiver) SetMaxExpectedSpatialLayer(_ int32) {
+	// set by dynacast of the source track, which the bridged track's subscribers are reported to
+}
+
+func (r *bridgedReceiver) AddDownTrack(track sfu.TrackSender) error {
+	if r.closed.Load() {
+		return sfu.ErrReceiverClosed
+	}
+
+	if r.kind == livekit.TrackType_VIDEO {
+		// notify added down track of available layers
+		r.layersLock.RLock()
+		layers := r.layers
+		r.layersLock.RUnlock()
+		if len(layers) != 0 {
+			track.UpTrackLayersChange(layers)
+		}
+	}
+
+	r.downTrackSpreader.Store(track)
+	return nil
+}
+
+func (r *bridgedReceiver) DeleteDownTrack(subscriberID livekit.ParticipantID) {
+	r.downTrackSpreader.Free(subscriberID)
+}
+
+func (r *bridgedReceiver) DebugInfo() map[string]interface{} {
+	return map[string]interface{}{
+		"Source":     r.TrackReceiver.DebugInfo(),
+		"DownTracks": r.downTrackSpreader.DownTrackCount(),
+	}
+}
+
+// subscriberID identifies the sink to the source receiver
+func (r *bridgedReceiver) subscriberID() livekit.ParticipantID {
+	return livekit.ParticipantID(r.trackID)
+}
+
+func (r *bridgedReceiver) sink() sfu.TrackSender {
+	return &bridgeSink{receiver: r}
+}
+
+// ---------------------------
+
+// bridgeSink is the down track of a bridgedReceiver on the source receiver
+type bridgeSink struct {
+	receiver *bridgedReceiver
+}
+
+func (s *bridgeSink) UpTrackLayersChange(availableLayers []int32) {
+	r := s.receiver
+	r.layersLock.Lock()
+	r.layers = availableLayers
+	r.layersLock.Unlock()
+
+	for _, dt := range r.downTrackSpreader.GetDownTracks() {
+		dt.UpTrackLayersChange(availableLayers)
+	}
+}
+
+func (s *bridgeSink) UpTrackBitrateAvailabilityChange() {
+	for _, dt := range s.receiver.downTrackSpreader.GetDownTracks() {
+		dt.UpTrackBitrateAvailabilityChange()
+	}
+}
+
+func (s *bridgeSink) WriteRTP(p *buffer.ExtPacket, layer int32) error {
+	r := s.receiver
+	if r.closed.Load() {
+		return nil
+	}
+
+	r.downTrackSpreader.Broadcast(func(dt sfu.TrackSender) {
+		if err := dt.WriteRTP(p, layer); err != nil {
+			r.logger.Errorw("failed writing to down track", err)
+		}
+	})
+	return nil
+}
+
+// Close is called when the source receiver closes
+func (s *bridgeSink) Close() {
+	r := s.receiver
+	if r.closed.Swap(true) {
+		return
+	}
+
+	if r.onClose != nil {
+		r.onClose()
+	}
+}
+
+func (s *bridgeSink) IsClosed() bool {
+	return s.receiver.closed.Load()
+}
+
+func (s *bridgeSink) ID() string {
+	return string(s.receiver.trackID)
+}
+
+func (s *bridgeSink) SubscriberID() livekit.ParticipantID {
+	return s.receiver.subscriberID()
+}
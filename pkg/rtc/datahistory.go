@@ -0,0 +1,163 @@
+package rtc
+
+import (
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// SetDataHistory enables retention of the room's reliable data messages, seeding it with messages retained
+// previously, oldest first. A nil policy disables it
+func (r *Room) SetDataHistory(policy *types.DataHistory, messages []*types.DataMessage) {
+	r.historyLock.Lock()
+	defer r.historyLock.Unlock()
+
+	r.historyPolicy = nil
+	r.history = nil
+	r.historyBytes = 0
+	r.historyEnabled.Store(policy != nil && policy.MaxMessages > 0)
+	if !r.historyEnabled.Load() {
+		return
+	}
+	r.historyPolicy = policy
+	for _, msg := range messages {
+		r.history = append(r.history, msg)
+		r.historyBytes += len(msg.Payload)
+	}
+	r.trimHistoryLocked()
+}
+
+// OnDataRetained is called with every data message retained in the room's history
+func (r *Room) OnDataRetained(f func(msg *types.DataMessage)) {
+	r.historyLock.Lock()
+	r.onDataRetained = f
+	r.historyLock.Unlock()
+}
+
+// DataHistory returns the data messages retained by the room, oldest first
+func (r *Room) DataHistory() []*types.DataMessage {
+	r.historyLock.Lock()
+	defer r.historyLock.Unlock()
+
+	history := make([]*types.DataMessage, 0, len(r.history))
+	for _, msg := range r.history {
+		copied := *msg
+		history = append(history, &copied)
+	}
+	return history
+}
+
+// retainDataPacketLocked adds a reliable data packet to the room's history when it's enabled, returning the
+// retained message
+func (r *Room) retainDataPacketLocked(source types.LocalParticipant, dp *livekit.DataPacket) *types.DataMessage {
+	policy := r.historyPolicy
+	user := dp.GetUser()
+	if policy == nil || user == nil || dp.Kind != livekit.DataPacket_RELIABLE {
+		return nil
+	}
+	topic, dest := dataTopic(user)
+	if len(dest) > 0 && !policy.IncludeDirect {
+		return nil
+	}
+	if policy.MaxBytes > 0 && len(user.Payload) > int(policy.MaxBytes) {
+		return nil
+	}
+
+	msg := &types.DataMessage{
+		SenderSid:       user.ParticipantSid,
+		Topic:           topic,
+		DestinationSids: dest,
+		Payload:         user.Payload,
+		Timestamp:       time.Now().UnixMilli(),
+	}
+	if source != nil {
+		msg.Sender = string(source.Identity())
+		msg.SenderSid = string(source.ID())
+	}
+	r.history = append(r.history, msg)
+	r.historyBytes += len(msg.Payload)
+	r.trimHistoryLocked()
+	return msg
+}
+
+// trimHistoryLocked drops the oldest messages beyond the limits of the history
+func (r *Room) trimHistoryLocked() {
+	drop := 0
+	for drop < len(r.history) {
+		if len(r.history)-drop <= int(r.historyPolicy.MaxMessages) &&
+			(r.historyPolicy.MaxBytes == 0 || r.historyBytes <= int(r.historyPolicy.MaxBytes)) {
+			break
+		}
+		r.historyBytes -= len(r.history[drop].Payload)
+		r.history[drop] = nil
+		drop++
+	}
+	r.history = r.history[drop:]
+}
+
+// replayDataHistory sends a participant that became active the retained messages that were sent to everyone, or to
+// topics it's subscribed to. Packets forwarded meanwhile are held back and sent after the history
+func (r *Room) replayDataHistory(participant types.LocalParticipant) {
+	if !r.historyEnabled.Load() {
+		return
+	}
+
+	r.historyLock.Lock()
+	history := append([]*types.DataMessage{}, r.history...)
+	if r.replaying == nil {
+		r.replaying = make(map[livekit.ParticipantID][]*livekit.DataPacket)
+	}
+	r.replaying[participant.ID()] = nil
+	r.historyLock.Unlock()
+
+	replayed := 0
+	for _, msg := range history {
+		// participants get a new sid each time they join, messages sent to others are never meant for them
+		if len(msg.DestinationSids) > 0 {
+			continue
+		}
+		up := &livekit.UserPacket{
+			ParticipantSid: msg.SenderSid,
+			Payload:        msg.Payload,
+		}
+		if msg.Topic != "" {
+			if !participant.IsSubscribedToDataTopic(msg.Topic) {
+				continue
+			}
+			SetUserPacketTopic(up, msg.Topic)
+		}
+		err := participant.SendDataPacket(&livekit.DataPacket{
+			Kind:  livekit.DataPacket_RELIABLE,
+			Value: &livekit.DataPacket_User{User: up},
+		})
+		if err != nil {
+			r.Logger.Infow("could not replay data history", "error", err, "participant", participant.Identity())
+			break
+		}
+		replayed++
+	}
+	if replayed > 0 {
+		participant.GetLogger().Debugw("replayed data history", "messages", replayed)
+	}
+
+	// send packets held back until none are left
+	for {
+		r.historyLock.Lock()
+		pending := r.replaying[participant.ID()]
+		if len(pending) == 0 {
+			delete(r.replaying, participant.ID())
+			r.historyLock.Unlock()
+			return
+		}
+		r.replaying[participant.ID()] = nil
+		r.historyLock.Unlock()
+
+		for _, dp := range pending {
+			if err := participant.SendDataPacket(dp); err != nil {
+				r.Logger.Infow("send data packet error", "error", err, "participant", participant.Identity())
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package rtc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// DataLimitAction is taken when a participant's data packet exceeds its limits. The packet is always dropped
+type DataLimitAction string
+
+const (
+	DataLimitDrop DataLimitAction = "drop"
+	// sends the participant a DataThrottledMessage as well
+	DataLimitWarn DataLimitAction = "warn"
+	// disconnects the participant
+	DataLimitDisconnect DataLimitAction = "disconnect"
+)
+
+// reasons data packets are dropped
+const (
+	DataLimitReasonSize            = "size"
+	DataLimitReasonParticipantRate = "participant_rate"
+	DataLimitReasonRoomRate        = "room_rate"
+)
+
+// type of the data message participants receive when their data packets are dropped
+const dataThrottledMessageType = "data_throttled"
+
+// participants are considered throttled again once they haven't exceeded their limits for this long
+const dataThrottleResetInterval = time.Second
+
+// DataThrottledMessage is the payload of the data message a participant receives as its data packets start being
+// dropped, when limits are configured to warn
+type DataThrottledMessage struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+// DataLimits limits the data packets a participant publishes
+type DataLimits struct {
+	// max payload size of a packet, 0 for no limit
+	MaxPacketSize uint32
+	// rate of the participant's packets, and of all participants of its room. nil when unlimited
+	Participant *DataRateLimiter
+	Room        *DataRateLimiter
+	Action      DataLimitAction
+}
+
+// DataRateLimiter limits the rate of data packets and of their payload bytes, allowing bursts of up to a second of
+// either
+type DataRateLimiter struct {
+	lock       sync.Mutex
+	packetRate float64
+	byteRate   float64
+	packets    float64
+	bytes      float64
+	updatedAt  time.Time
+}
+
+// NewDataRateLimiter returns a limiter of packets and bytes per second, 0 for no limit. nil when neither is limited
+func NewDataRateLimiter(packetRate uint32, byteRate uint32) *DataRateLimiter {
+	if packetRate == 0 && byteRate == 0 {
+		return nil
+	}
+	return &DataRateLimiter{
+		packetRate: float64(packetRate),
+		byteRate:   float64(byteRate),
+		packets:    float64(packetRate),
+		bytes:      float64(byteRate),
+		updatedAt:  time.Now(),
+	}
+}
+
+// Allow returns whether a packet of the given size is within the limits, counting it when it is
+func (l *DataRateLimiter) Allow(size int) bool {
+	if l == nil {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if !l.availableLocked(size) {
+		return false
+	}
+	l.takeLocked(size)
+	return true
+}
+
+// fits returns whether a packet of the given size is within a second of the byte rate. larger packets would never be
+// allowed
+func (l *DataRateLimiter) fits(size int) bool {
+	return l == nil || l.byteRate == 0 || float64(size) <= l.byteRate
+}
+
+// availableLocked refills the limiter, returning whether a packet of the given size is within the limits
+func (l *DataRateLimiter) availableLocked(size int) bool {
+	now := time.Now()
+	elapsed := now.Sub(l.updatedAt).Seconds()
+	l.updatedAt = now
+	l.packets = refill(l.packets, l.packetRate, elapsed)
+	l.bytes = refill(l.bytes, l.byteRate, elapsed)
+
+	return (l.packetRate == 0 || l.packets >= 1) && (l.byteRate == 0 || l.bytes >= float64(size))
+}
+
+// takeLocked counts a packet of the given size against the limits
+func (l *DataRateLimiter) takeLocked(size int) {
+	l.packets--
+	l.bytes -= float64(size)
+}
+
+func refill(tokens float64, rate float64, elapsed float64) float64 {
+	tokens += rate * elapsed
+	if tokens > rate {
+		return rate
+	}
+	return tokens
+}
+
+// check returns the reason a packet of the given size exceeds the limits, empty when it's within them
+func (l *DataLimits) check(size int) string {
+	if l.MaxPacketSize > 0 && size > int(l.MaxPacketSize) {
+		return DataLimitReasonSize
+	}
+	if !l.Participant.fits(size) || !l.Room.fits(size) {
+		return DataLimitReasonSize
+	}
+
+	// the packet is counted against neither limiter unless it's within both. a participant's limiter is locked
+	// before the room's, which participants share
+	participant, room := l.Participant, l.Room
+	if participant != nil {
+		participant.lock.Lock()
+		defer participant.lock.Unlock()
+		if !participant.availableLocked(size) {
+			return DataLimitReasonParticipantRate
+		}
+	}
+	if room != nil {
+		room.lock.Lock()
+		defer room.lock.Unlock()
+		if !room.availableLocked(size) {
+			return DataLimitReasonRoomRate
+		}
+		room.takeLocked(size)
+	}
+	if participant != nil {
+		participant.takeLocked(size)
+	}
+	return ""
+}
+
+// SetDataLimiter sets the limiter of data packets shared by participants of the room
+func (r *Room) SetDataLimiter(limiter *DataRateLimiter) {
+	r.lock.Lock()
+	r.dataLimiter = limiter
+	r.lock.Unlock()
+}
+
+// DataLimiter returns the limiter of data packets shared by participants of the room, nil when unlimited
+func (r *Room) DataLimiter() *DataRateLimiter {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.dataLimiter
+}
+
+func (r *Room) onDataThrottled(participant types.LocalParticipant, reason string) {
+	r.telemetry.DataThrottled(context.Background(), r.ToProto(), participant.ToProto(), reason)
+}
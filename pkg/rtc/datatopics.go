@@ -0,0 +1,65 @@
+package rtc
+
+import (
+	"strings"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+// class of data topics that aren't configured to be counted on their own
+const otherDataTopicClass = "other"
+
+// dataTopic returns the topic of a data packet, empty when it has none, and the participants it's sent to.
+// Packets with a topic are only forwarded to participants subscribed to it, destination sids restricting them further
+func dataTopic(up *livekit.UserPacket) (string, []string) {
+	if up == nil {
+		return "", nil
+	}
+	return UserPacketTopic(up), up.DestinationSids
+}
+
+// dataTopicMatches returns whether a topic matches a pattern, which is either a topic or a prefix of topics ending
+// with a wildcard, i.e. "chat/*"
+func dataTopicMatches(pattern string, topic string) bool {
+	if strings.HasSuffix(pattern, "*") {
+		return strings.HasPrefix(topic, strings.TrimSuffix(pattern, "*"))
+	}
+	return pattern == topic
+}
+
+// SetDataTopicClasses sets the classes data topics are counted by in metrics. The class of a topic is the part before
+// its first "/", topics of other classes are counted together
+func (r *Room) SetDataTopicClasses(classes []string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.dataTopicClasses = make(map[string]bool, len(classes))
+	for _, class := range classes {
+		r.dataTopicClasses[class] = true
+	}
+}
+
+func (r *Room) dataTopicClass(topic string) string {
+	class := topic
+	if i := strings.Index(topic, "/"); i >= 0 {
+		class = topic[:i]
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	if !r.dataTopicClasses[class] {
+		return otherDataTopicClass
+	}
+	return class
+}
+
+// countDataTopicPacket counts a packet published to a topic along with the number of subscribers it was forwarded to
+func (r *Room) countDataTopicPacket(topic string, size int, forwarded int) {
+	class := r.dataTopicClass(topic)
+	prometheus.AddDataTopicPackets(class, prometheus.Incoming, 1, size)
+	if forwarded > 0 {
+		prometheus.AddDataTopicPackets(class, prometheus.Outgoing, forwarded, forwarded*size)
+	}
+}
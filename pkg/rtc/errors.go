@@ -6,12 +6,32 @@ var (
 	ErrRoomClosed              = errors.New("room has already closed")
 	ErrPermissionDenied        = errors.New("no permissions to access the room")
 	ErrMaxParticipantsExceeded = errors.New("room has exceeded its max participants")
+	ErrMaxPublishersExceeded   = errors.New("room has exceeded its max publishers")
+	ErrRoomLocked              = errors.New("room is locked, new participants cannot join")
 	ErrLimitExceeded           = errors.New("node has exceeded its configured limit")
 	ErrAlreadyJoined           = errors.New("a participant with the same identity is already in the room")
+	ErrParticipantNotFound     = errors.New("participant is not in the room")
+	ErrParticipantNotActive    = errors.New("participant is not active")
 	ErrUnexpectedOffer         = errors.New("expected answer SDP, received offer")
 	ErrDataChannelUnavailable  = errors.New("data channel is not available")
 	ErrCannotSubscribe         = errors.New("participant does not have permission to subscribe")
 	ErrEmptyIdentity           = errors.New("participant identity cannot be empty")
 	ErrEmptyParticipantID      = errors.New("participant ID cannot be empty")
 	ErrMissingGrants           = errors.New("VideoGrant is missing")
+	ErrCodecNotAllowed         = errors.New("codec is not allowed in the room")
+	ErrSourceNotAllowed        = errors.New("track source is not allowed to be published")
+	ErrAlreadyPublisher        = errors.New("participant can already publish")
+	ErrNotPublisher            = errors.New("participant cannot publish")
+	ErrNoPublishRequest        = errors.New("participant has not requested to publish")
+	ErrInvalidStageAction      = errors.New("unknown stage action")
+	ErrMetadataChanged         = errors.New("room metadata has changed since the expected version")
+	ErrAttributesChanged       = errors.New("participant attributes have changed since the expected version")
+	ErrAttributesTooLarge      = errors.New("participant attributes exceed the size limit")
+	ErrEmptyAttributeKey       = errors.New("attribute key cannot be empty")
+	ErrEmptyStateKey           = errors.New("room state key cannot be empty")
+	ErrStateKeyNotWritable     = errors.New("room state key is owned by another participant")
+	ErrStateTooLarge           = errors.New("room state exceeds the size limit")
+	ErrEmptyDataTopic          = errors.New("data topic cannot be empty")
+	ErrTopicNotAllowed         = errors.New("participant is not allowed to publish to the data topic")
+	ErrTrackLimitExceeded      = errors.New("participant exceeded its limit of published tracks")
 )
@@ -1,6 +1,7 @@
 package rtc
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/pion/webrtc/v3"
@@ -98,3 +99,51 @@ func isCodecEnabled(codecs []*livekit.Codec, cap webrtc.RTPCodecCapability) bool
 	}
 	return false
 }
+
+// codecMimeForTrack returns the mime type of a codec named by clients in AddTrackRequest, which may omit the kind
+func codecMimeForTrack(trackType livekit.TrackType, codec string) string {
+	if trackType == livekit.TrackType_VIDEO && !strings.HasPrefix(codec, "video/") {
+		return "video/" + codec
+	} else if trackType == livekit.TrackType_AUDIO && !strings.HasPrefix(codec, "audio/") {
+		return "audio/" + codec
+	}
+	return codec
+}
+
+// checkTrackCodecs ensures a track can be published with the room's codecs: every codec requested
+// must be enabled, and when none are requested, the room must enable a codec of the track's kind
+func checkTrackCodecs(codecs []*livekit.Codec, req *livekit.AddTrackRequest) error {
+	if len(codecs) == 0 || (req.Type != livekit.TrackType_AUDIO && req.Type != livekit.TrackType_VIDEO) {
+		return nil
+	}
+
+	if len(req.SimulcastCodecs) > 0 {
+		for _, codec := range req.SimulcastCodecs {
+			if codec.Codec == "" {
+				continue
+			}
+			mime := codecMimeForTrack(req.Type, codec.Codec)
+			if !isMimeEnabled(codecs, mime) {
+				return fmt.Errorf("%w: %s", ErrCodecNotAllowed, mime)
+			}
+		}
+		return nil
+	}
+
+	kind := strings.ToLower(req.Type.String()) + "/"
+	for _, codec := range codecs {
+		if strings.HasPrefix(strings.ToLower(codec.Mime), kind) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no %s codecs are enabled", ErrCodecNotAllowed, strings.ToLower(req.Type.String()))
+}
+
+func isMimeEnabled(codecs []*livekit.Codec, mime string) bool {
+	for _, codec := range codecs {
+		if strings.EqualFold(codec.Mime, mime) {
+			return true
+		}
+	}
+	return false
+}
@@ -99,3 +99,23 @@ func TestGetQualityForDimension(t *testing.T) {
 		require.Equal(t, livekit.VideoQuality_HIGH, mt.GetQualityForDimension(1000, 700))
 	})
 }
+
+func TestTrackListeners(t *testing.T) {
+	mt := NewMediaTrack(MediaTrackParams{TrackInfo: &livekit.TrackInfo{
+		Sid:  "testsid",
+		Type: livekit.TrackType_AUDIO,
+	}})
+
+	var mutes []bool
+	mt.AddListener("bridge", func(muted bool) {
+		mutes = append(mutes, muted)
+	}, nil)
+	mt.SetMuted(true)
+	mt.SetMuted(true)
+	require.Equal(t, []bool{true}, mutes)
+
+	// removed listeners aren't called anymore
+	mt.RemoveListener("bridge")
+	mt.SetMuted(false)
+	require.Equal(t, []bool{true}, mutes)
+}
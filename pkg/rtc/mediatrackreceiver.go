@@ -69,16 +69,24 @@ type MediaTrackReceiver struct {
 	onMediaLossFeedback func(dt *sfu.DownTrack, report *rtcp.ReceiverReport)
 	onVideoLayerUpdate  func(layers []*livekit.VideoLayer)
 	onClose             []func()
+	listeners           map[string]*trackListener
 
 	*MediaTrackSubscriptions
 }
 
+// trackListener is notified of the mute state and closing of a track, until removed
+type trackListener struct {
+	onMuted func(muted bool)
+	onClose func()
+}
+
 func NewMediaTrackReceiver(params MediaTrackReceiverParams) *MediaTrackReceiver {
 	t := &MediaTrackReceiver{
 		params:             params,
 		trackInfo:          proto.Clone(params.TrackInfo).(*livekit.TrackInfo),
 		layerDimensions:    make(map[livekit.VideoQuality]*livekit.VideoLayer),
 		pendingSubscribeOp: make(map[livekit.ParticipantID]int),
+		listeners:          make(map[string]*trackListener),
 	}
 
 	t.MediaTrackSubscriptions = NewMediaTrackSubscriptions(MediaTrackSubscriptionsParams{
@@ -267,11 +275,17 @@ func (t *MediaTrackReceiver) TryClose() bool {
 func (t *MediaTrackReceiver) Close() {
 	t.lock.RLock()
 	onclose := t.onClose
+	listeners := t.listenersLocked()
 	t.lock.RUnlock()
 
 	for _, f := range onclose {
 		f()
 	}
+	for _, l := range listeners {
+		if l.onClose != nil {
+			l.onClose()
+		}
+	}
 }
 
 func (t *MediaTrackReceiver) ID() livekit.TrackID {
@@ -327,16 +341,47 @@ func (t *MediaTrackReceiver) IsMuted() bool {
 }
 
 func (t *MediaTrackReceiver) SetMuted(muted bool) {
-	t.muted.Store(muted)
+	changed := t.muted.Swap(muted) != muted
 
 	t.lock.RLock()
 	receivers := t.receiversShadow
+	listeners := t.listenersLocked()
 	t.lock.RUnlock()
 	for _, receiver := range receivers {
 		receiver.SetUpTrackPaused(muted)
 	}
 
 	t.MediaTrackSubscriptions.SetMuted(muted)
+
+	if changed {
+		for _, l := range listeners {
+			if l.onMuted != nil {
+				l.onMuted(muted)
+			}
+		}
+	}
+}
+
+// AddListener has onMuted called when the track is muted or unmuted, and onClose when it closes,
+// until RemoveListener is called with the same key
+func (t *MediaTrackReceiver) AddListener(key string, onMuted func(muted bool), onClose func()) {
+	t.lock.Lock()
+	t.listeners[key] = &trackListener{onMuted: onMuted, onClose: onClose}
+	t.lock.Unlock()
+}
+
+func (t *MediaTrackReceiver) RemoveListener(key string) {
+	t.lock.Lock()
+	delete(t.listeners, key)
+	t.lock.Unlock()
+}
+
+func (t *MediaTrackReceiver) listenersLocked() []*trackListener {
+	listeners := make([]*trackListener, 0, len(t.listeners))
+	for _, l := range t.listeners {
+		listeners = append(listeners, l)
+	}
+	return listeners
 }
 
 func (t *MediaTrackReceiver) AddOnClose(f func()) {
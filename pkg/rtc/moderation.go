@@ -0,0 +1,110 @@
+package rtc
+
+import (
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// TrackModeration mutes or unpublishes tracks of the given sources, published by participants other than Except
+type TrackModeration struct {
+	Sources   []livekit.TrackSource
+	Except    []livekit.ParticipantIdentity
+	Unpublish bool
+	// tracks published until then are moderated as soon as they're published
+	Until time.Time
+}
+
+func (m *TrackModeration) appliesTo(participant types.LocalParticipant, track types.MediaTrack) bool {
+	for _, identity := range m.Except {
+		if participant.Identity() == identity {
+			return false
+		}
+	}
+	for _, source := range m.Sources {
+		if track.Source() == source {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *TrackModeration) apply(participant types.LocalParticipant, track types.MediaTrack) {
+	if m.Unpublish {
+		participant.UnpublishTrack(track.ID())
+	} else if !track.IsMuted() {
+		participant.SetTrackMuted(track.ID(), true, true)
+	}
+}
+
+// ModerateTracks applies moderation to tracks published in the room, and to tracks published until
+// moderation.Until. Participants joining meanwhile are either seen as already in the room, or have their tracks
+// moderated as they're published. Returns the number of tracks moderated
+func (r *Room) ModerateTracks(moderation *TrackModeration) int {
+	r.lock.Lock()
+	r.entryModeration = moderation
+	r.applyingModeration = true
+	participants := make([]types.LocalParticipant, 0, len(r.participants))
+	for _, p := range r.participants {
+		participants = append(participants, p)
+	}
+	r.lock.Unlock()
+
+	r.Logger.Infow("moderating tracks",
+		"sources", moderation.Sources,
+		"except", moderation.Except,
+		"unpublish", moderation.Unpublish,
+		"until", moderation.Until)
+	moderated := 0
+	for _, p := range participants {
+		for _, track := range p.GetPublishedTracks() {
+			if moderation.appliesTo(p, track) {
+				moderation.apply(p, track)
+				moderated++
+			}
+		}
+	}
+
+	r.lock.Lock()
+	if r.entryModeration == moderation {
+		r.applyingModeration = false
+		if !time.Now().Before(moderation.Until) {
+			r.entryModeration = nil
+		}
+	}
+	r.lock.Unlock()
+	return moderated
+}
+
+// RemoveParticipants removes participants that match, returning their identities
+func (r *Room) RemoveParticipants(match func(participant types.LocalParticipant) bool, reason types.ParticipantCloseReason) []livekit.ParticipantIdentity {
+	var removed []livekit.ParticipantIdentity
+	for _, p := range r.GetParticipants() {
+		if match(p) {
+			r.RemoveParticipantSession(p, reason)
+			removed = append(removed, p.Identity())
+		}
+	}
+	return removed
+}
+
+// moderateOnEntry applies the moderation in effect to a newly published track, returns false when it's unpublished
+func (r *Room) moderateOnEntry(participant types.LocalParticipant, track types.MediaTrack) bool {
+	r.lock.RLock()
+	moderation := r.entryModeration
+	// tracks published while existing tracks are being moderated are moderated too, even past the window
+	applying := r.applyingModeration
+	r.lock.RUnlock()
+	if moderation == nil || (!applying && !time.Now().Before(moderation.Until)) {
+		return true
+	}
+	if !moderation.appliesTo(participant, track) {
+		return true
+	}
+
+	participant.GetLogger().Infow("moderating track on entry", "trackID", track.ID(), "unpublish", moderation.Unpublish)
+	moderation.apply(participant, track)
+	return !moderation.Unpublish
+}
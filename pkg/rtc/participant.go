@@ -3,6 +3,7 @@ package rtc
 import (
 	"context"
 	"io"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +12,8 @@ import (
 	"github.com/pion/rtcp"
 	"github.com/pion/webrtc/v3"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"google.golang.org/protobuf/proto"
 
@@ -22,6 +25,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/twcc"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -84,6 +88,22 @@ type ParticipantParams struct {
 	Region                  string
 	Migration               bool
 	AdaptiveStream          bool
+	// sources of tracks muted by the server as they're published
+	StartMutedSources []livekit.TrackSource
+	// sources of tracks allowed to be published, nil when all are
+	AllowedSources []livekit.TrackSource
+	// data topics the participant starts subscribed to
+	DataTopics []string
+	// data topics the participant may publish to, nil when it may publish to any
+	PublishTopics []string
+	// limits on the data packets the participant publishes
+	DataLimits DataLimits
+	// limits on the signal requests the participant sends
+	SignalLimits SignalLimits
+	// span of the session start, signaling and transport spans are parented to it
+	TraceParent trace.SpanContext
+	// prefix of room names the client isn't aware of, such as the tenant of the room
+	RoomNamePrefix string
 }
 
 type ParticipantImpl struct {
@@ -139,13 +159,21 @@ type ParticipantImpl struct {
 	updateLock sync.Mutex
 	version    atomic.Uint32
 
+	// key-value state of the participant, versioned separately from its info. protected by lock
+	attributes        map[string]string
+	attributesVersion uint32
+	// data topics the participant is subscribed to, protected by lock
+	dataTopics map[string]struct{}
+
 	// callbacks & handlers
 	onTrackPublished    func(types.LocalParticipant, types.MediaTrack)
 	onTrackUpdated      func(types.LocalParticipant, types.MediaTrack)
 	onStateChange       func(p types.LocalParticipant, oldState livekit.ParticipantInfo_State)
 	onParticipantUpdate func(types.LocalParticipant)
+	onAttributesUpdate  func(types.LocalParticipant, map[string]string, uint32)
 	onDataPacket        func(types.LocalParticipant, *livekit.DataPacket)
 	onSubscribedTo      func(types.LocalParticipant, livekit.ParticipantID)
+	onDataThrottled     func(types.LocalParticipant, string)
 
 	migrateState        atomic.Value // types.MigrateState
 	pendingOffer        *webrtc.SessionDescription
@@ -154,6 +182,11 @@ type ParticipantImpl struct {
 	onClaimsChanged     func(participant types.LocalParticipant)
 	onICEConfigChanged  func(participant types.LocalParticipant, iceConfig types.IceConfig)
 
+	// time the participant's data packets were last dropped for exceeding its limits
+	dataThrottledAt atomic.Int64
+	// set once the participant is disconnected for exceeding its signal limits
+	signalLimitExceeded atomic.Bool
+
 	activeCounter  atomic.Int32
 	firstConnected atomic.Bool
 	iceConfig      types.IceConfig
@@ -189,6 +222,12 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 		subscriptionInProgress:    make(map[livekit.TrackID]bool),
 		subscriptionRequestsQueue: make(map[livekit.TrackID][]SubscribeRequest),
 		trackPublisherVersion:     make(map[livekit.TrackID]uint32),
+		dataTopics:                make(map[string]struct{}),
+	}
+	for _, topic := range params.DataTopics {
+		if topic != "" {
+			p.dataTopics[topic] = struct{}{}
+		}
 	}
 	p.version.Store(params.InitialVersion)
 	p.migrateState.Store(types.MigrateStateInit)
@@ -227,6 +266,7 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 		Telemetry:               p.params.Telemetry,
 		EnabledCodecs:           enabledCodecs,
 		Logger:                  LoggerWithPCTarget(params.Logger, livekit.SignalTarget_PUBLISHER),
+		TraceParent:             params.TraceParent,
 		SimTracks:               params.SimTracks,
 	})
 	if err != nil {
@@ -242,6 +282,7 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 		Telemetry:               p.params.Telemetry,
 		EnabledCodecs:           enabledCodecs,
 		Logger:                  LoggerWithPCTarget(params.Logger, livekit.SignalTarget_SUBSCRIBER),
+		TraceParent:             params.TraceParent,
 	})
 	if err != nil {
 		return nil, err
@@ -263,14 +304,14 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 		p.sendIceCandidate(c, livekit.SignalTarget_SUBSCRIBER)
 	})
 
-	primaryPC := p.publisher.pc
-	secondaryPC := p.subscriber.pc
+	primaryPC := p.publisher
+	secondaryPC := p.subscriber
 	// primary connection does not change, canSubscribe can change if permission was updated
 	// after the participant has joined
 	p.subscriberAsPrimary = p.ProtocolVersion().SubscriberAsPrimary() && p.CanSubscribe()
 	if p.SubscriberAsPrimary() {
-		primaryPC = p.subscriber.pc
-		secondaryPC = p.publisher.pc
+		primaryPC = p.subscriber
+		secondaryPC = p.publisher
 		if !params.Migration {
 			if err := p.createDataChannelForSubscriberAsPrimary(nil); err != nil {
 				return nil, err
@@ -287,8 +328,8 @@ func NewParticipant(params ParticipantParams) (*ParticipantImpl, error) {
 		p.handleICEStateChange(false, state)
 	})
 
-	primaryPC.OnConnectionStateChange(p.handlePrimaryStateChange)
-	secondaryPC.OnConnectionStateChange(p.handleSecondaryStateChange)
+	primaryPC.pc.OnConnectionStateChange(p.handlePrimaryStateChange)
+	secondaryPC.pc.OnConnectionStateChange(p.handleSecondaryStateChange)
 
 	p.publisher.pc.OnTrack(p.onMediaTrack)
 	p.publisher.pc.OnDataChannel(p.onDataChannel)
@@ -391,6 +432,145 @@ func (p *ParticipantImpl) ConnectedAt() time.Time {
 	return p.connectedAt
 }
 
+// Attributes returns a copy of the participant's attributes, along with their version
+func (p *ParticipantImpl) Attributes() (map[string]string, uint32) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	attributes := make(map[string]string, len(p.attributes))
+	for key, value := range p.attributes {
+		attributes[key] = value
+	}
+	return attributes, p.attributesVersion
+}
+
+// UpdateAttributes sets attributes key by key, removing keys set to an empty value. When ifVersion is set, the update
+// is rejected unless attributes are at that version. maxSize limits the total size of keys and values, 0 for no limit.
+// Returns the keys that changed, with an empty value for removed ones, and the resulting version. Only the changed
+// keys are sent to the room
+func (p *ParticipantImpl) UpdateAttributes(update map[string]string, ifVersion *uint32, maxSize uint32) (map[string]string, uint32, error) {
+	changed, version, err := p.updateAttributes(update, ifVersion, maxSize)
+	if err != nil || len(changed) == 0 {
+		return changed, version, err
+	}
+
+	p.lock.RLock()
+	onAttributesUpdate := p.onAttributesUpdate
+	p.lock.RUnlock()
+	if onAttributesUpdate != nil {
+		onAttributesUpdate(p, changed, version)
+	}
+	return changed, version, nil
+}
+
+func (p *ParticipantImpl) updateAttributes(update map[string]string, ifVersion *uint32, maxSize uint32) (map[string]string, uint32, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if ifVersion != nil && *ifVersion != p.attributesVersion {
+		return nil, p.attributesVersion, ErrAttributesChanged
+	}
+
+	size := 0
+	for key, value := range p.attributes {
+		if _, ok := update[key]; !ok {
+			size += len(key) + len(value)
+		}
+	}
+	changed := make(map[string]string)
+	for key, value := range update {
+		if key == "" {
+			return nil, p.attributesVersion, ErrEmptyAttributeKey
+		}
+		if value != "" {
+			size += len(key) + len(value)
+		}
+		// stored values are never empty, removing a missing key isn't a change
+		if p.attributes[key] == value {
+			continue
+		}
+		changed[key] = value
+	}
+	if maxSize > 0 && size > int(maxSize) {
+		return nil, p.attributesVersion, ErrAttributesTooLarge
+	}
+	if len(changed) == 0 {
+		return changed, p.attributesVersion, nil
+	}
+
+	if p.attributes == nil {
+		p.attributes = make(map[string]string, len(changed))
+	}
+	for key, value := range changed {
+		if value == "" {
+			delete(p.attributes, key)
+		} else {
+			p.attributes[key] = value
+		}
+	}
+	p.attributesVersion++
+	return changed, p.attributesVersion, nil
+}
+
+// DataTopics returns the data topics the participant is subscribed to, sorted
+func (p *ParticipantImpl) DataTopics() []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	topics := make([]string, 0, len(p.dataTopics))
+	for topic := range p.dataTopics {
+		topics = append(topics, topic)
+	}
+	sort.Strings(topics)
+	return topics
+}
+
+// UpdateDataTopics subscribes the participant to data topics and unsubscribes it from others. Topics may end with
+// a wildcard, i.e. "chat/*"
+func (p *ParticipantImpl) UpdateDataTopics(subscribe []string, unsubscribe []string) error {
+	for _, topic := range append(subscribe, unsubscribe...) {
+		if topic == "" {
+			return ErrEmptyDataTopic
+		}
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for _, topic := range unsubscribe {
+		delete(p.dataTopics, topic)
+	}
+	for _, topic := range subscribe {
+		p.dataTopics[topic] = struct{}{}
+	}
+	return nil
+}
+
+// IsSubscribedToDataTopic returns whether data published to topic should be forwarded to the participant
+func (p *ParticipantImpl) IsSubscribedToDataTopic(topic string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	for pattern := range p.dataTopics {
+		if dataTopicMatches(pattern, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanPublishDataTopic returns whether the participant's token lets it publish data to topic
+func (p *ParticipantImpl) CanPublishDataTopic(topic string) bool {
+	if p.params.PublishTopics == nil {
+		return true
+	}
+	for _, pattern := range p.params.PublishTopics {
+		if dataTopicMatches(pattern, topic) {
+			return true
+		}
+	}
+	return false
+}
+
 // SetMetadata attaches metadata to the participant
 func (p *ParticipantImpl) SetMetadata(metadata string) {
 	p.lock.Lock()
@@ -419,6 +599,19 @@ func (p *ParticipantImpl) ClaimGrants() *auth.ClaimGrants {
 	return p.grants.Clone()
 }
 
+// SetRoomName updates the room the participant's grants are for, once it's been moved to another room
+func (p *ParticipantImpl) SetRoomName(roomName livekit.RoomName) {
+	p.lock.Lock()
+	changed := p.grants.Video.Room != string(roomName)
+	p.grants.Video.Room = string(roomName)
+	onClaimsChanged := p.onClaimsChanged
+	p.lock.Unlock()
+
+	if changed && onClaimsChanged != nil {
+		onClaimsChanged(p)
+	}
+}
+
 func (p *ParticipantImpl) SetPermission(permission *livekit.ParticipantPermission) bool {
 	if permission == nil {
 		return false
@@ -450,13 +643,7 @@ func (p *ParticipantImpl) SetPermission(permission *livekit.ParticipantPermissio
 	// publish permission has been revoked then remove all published tracks
 	if !canPublish {
 		for _, track := range p.GetPublishedTracks() {
-			p.RemovePublishedTrack(track, false)
-			if p.ProtocolVersion().SupportsUnpublish() {
-				p.sendTrackUnpublished(track.ID())
-			} else {
-				// for older clients that don't support unpublish, mute to avoid them sending data
-				p.sendTrackMuted(track.ID(), true)
-			}
+			p.UnpublishTrack(track.ID())
 		}
 	}
 	// update isPublisher attribute
@@ -485,6 +672,7 @@ func (p *ParticipantImpl) ToProto() *livekit.ParticipantInfo {
 		Region:      p.params.Region,
 		IsPublisher: p.IsPublisher(),
 	}
+	SetParticipantAttributes(info, p.attributes, p.attributesVersion)
 	p.lock.RUnlock()
 	info.Tracks = p.UpTrackManager.ToProto()
 
@@ -521,12 +709,26 @@ func (p *ParticipantImpl) OnParticipantUpdate(callback func(types.LocalParticipa
 	p.lock.Unlock()
 }
 
+// OnAttributesUpdate is called with the attributes that changed, an empty value for removed ones, and their version
+func (p *ParticipantImpl) OnAttributesUpdate(callback func(types.LocalParticipant, map[string]string, uint32)) {
+	p.lock.Lock()
+	p.onAttributesUpdate = callback
+	p.lock.Unlock()
+}
+
 func (p *ParticipantImpl) OnDataPacket(callback func(types.LocalParticipant, *livekit.DataPacket)) {
 	p.lock.Lock()
 	p.onDataPacket = callback
 	p.lock.Unlock()
 }
 
+// OnDataThrottled is called with the reason data packets of the participant started being dropped
+func (p *ParticipantImpl) OnDataThrottled(callback func(types.LocalParticipant, string)) {
+	p.lock.Lock()
+	p.onDataThrottled = callback
+	p.lock.Unlock()
+}
+
 func (p *ParticipantImpl) OnSubscribedTo(callback func(types.LocalParticipant, livekit.ParticipantID)) {
 	p.lock.Lock()
 	p.onSubscribedTo = callback
@@ -546,7 +748,12 @@ func (p *ParticipantImpl) OnClaimsChanged(callback func(types.LocalParticipant))
 }
 
 // HandleOffer an offer from remote participant, used when clients make the initial connection
-func (p *ParticipantImpl) HandleOffer(sdp webrtc.SessionDescription) error {
+func (p *ParticipantImpl) HandleOffer(sdp webrtc.SessionDescription) (err error) {
+	_, span := p.startSpan("ParticipantImpl.HandleOffer")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	p.lock.Lock()
 	if p.MigrateState() == types.MigrateStateInit {
 		p.pendingOffer = &sdp
@@ -559,7 +766,7 @@ func (p *ParticipantImpl) HandleOffer(sdp webrtc.SessionDescription) error {
 		// "sdp", sdp.SDP,
 	)
 
-	if err := p.publisher.SetRemoteDescription(sdp); err != nil {
+	if err = p.publisher.SetRemoteDescription(sdp); err != nil {
 		prometheus.ServiceOperationCounter.WithLabelValues("answer", "error", "remote_description").Add(1)
 		return err
 	}
@@ -567,7 +774,12 @@ func (p *ParticipantImpl) HandleOffer(sdp webrtc.SessionDescription) error {
 	return nil
 }
 
-func (p *ParticipantImpl) createPublsiherAnswerAndSend() error {
+func (p *ParticipantImpl) createPublsiherAnswerAndSend() (err error) {
+	_, span := p.startSpan("ParticipantImpl.sendAnswer")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	p.lock.RLock()
 	onParticipantUpdate := p.onParticipantUpdate
 	p.lock.RUnlock()
@@ -656,12 +868,110 @@ func (p *ParticipantImpl) AddTrack(req *livekit.AddTrackRequest) {
 		return
 	}
 
+	if err := checkTrackCodecs(p.params.EnabledCodecs, req); err != nil {
+		p.params.Logger.Warnw("rejecting track publication", err, "cid", req.Cid, "type", req.Type)
+		p.rejectTrack(req)
+		return
+	}
+
+	if p.params.AllowedSources != nil && !containsTrackSource(p.params.AllowedSources, req.Source) {
+		p.params.Logger.Warnw("rejecting track publication", ErrSourceNotAllowed, "cid", req.Cid, "source", req.Source)
+		p.rejectTrack(req)
+		return
+	}
+
+	if reason := p.trackLimitExceeded(req); reason != "" {
+		p.params.Logger.Warnw("rejecting track publication", ErrTrackLimitExceeded, "cid", req.Cid, "reason", reason)
+		p.rejectTrack(req)
+		p.handleSignalLimitExceeded("add_track", reason)
+		return
+	}
+
 	ti := p.addPendingTrackLocked(req)
 	if ti == nil {
 		return
 	}
 
 	p.sendTrackPublished(req.Cid, ti)
+
+	// the client learns the track's ID from the publication, it's muted once that's sent
+	if req.Sid == "" && !ti.Muted && containsTrackSource(p.params.StartMutedSources, ti.Source) {
+		p.params.Logger.Infow("muting track on publication", "trackID", ti.Sid, "source", ti.Source)
+		p.SetTrackMuted(livekit.TrackID(ti.Sid), true, true)
+	}
+}
+
+// rejectTrack refuses a track publication. The protocol has no publication error response, so the client is sent
+// the publication it waits for, under a track ID the server doesn't keep, and is then told to unpublish it
+func (p *ParticipantImpl) rejectTrack(req *livekit.AddTrackRequest) {
+	if req.Sid != "" {
+		// a codec added to a published track, which stays published with its current codecs
+		if track := p.GetPublishedTrack(livekit.TrackID(req.Sid)); track != nil {
+			p.sendTrackPublished(req.Cid, track.ToProto())
+		}
+		return
+	}
+
+	trackID := livekit.TrackID(utils.NewGuid(utils.TrackPrefix))
+	p.sendTrackPublished(req.Cid, &livekit.TrackInfo{
+		Sid:    string(trackID),
+		Type:   req.Type,
+		Name:   req.Name,
+		Source: req.Source,
+		Muted:  req.Muted,
+	})
+	if p.ProtocolVersion().SupportsUnpublish() {
+		p.sendTrackUnpublished(trackID)
+	} else {
+		// older clients are muted instead, media of the track is dropped as it isn't pending
+		p.sendTrackMuted(trackID, true)
+	}
+}
+
+// AllowSignalRequest returns whether a signal request is within the participant's rate limits, counting it when it
+// is. Participants that keep exceeding their limits are disconnected
+func (p *ParticipantImpl) AllowSignalRequest(req *livekit.SignalRequest) bool {
+	requestType := signalRequestType(req)
+	if requestType == "leave" || p.params.SignalLimits.allowRequest(requestType) {
+		return true
+	}
+
+	p.handleSignalLimitExceeded(requestType, SignalLimitReasonRate)
+	return false
+}
+
+// trackLimitExceeded returns the reason publishing a new track exceeds the participant's limits, empty when it
+// doesn't
+func (p *ParticipantImpl) trackLimitExceeded(req *livekit.AddTrackRequest) string {
+	if req.Sid != "" {
+		// adds a codec to a published track
+		return ""
+	}
+
+	p.pendingTracksLock.RLock()
+	pending := len(p.pendingTracks)
+	p.pendingTracksLock.RUnlock()
+	return p.params.SignalLimits.trackLimitExceeded(pending, len(p.GetPublishedTracks()))
+}
+
+// handleSignalLimitExceeded counts a signal request rejected for exceeding the participant's limits, disconnecting
+// the participant once it exceeds them too often
+func (p *ParticipantImpl) handleSignalLimitExceeded(requestType string, reason string) {
+	prometheus.AddSignalRequestDropped(requestType, reason)
+	if p.params.SignalLimits.Violations.Allow(0) {
+		p.params.Logger.Debugw("dropped signal request", "type", requestType, "reason", reason)
+		return
+	}
+
+	if p.signalLimitExceeded.Swap(true) {
+		return
+	}
+	p.params.Logger.Warnw("participant exceeded signal limits", nil, "type", requestType, "reason", reason)
+	prometheus.AddSignalLimitDisconnect()
+	// not closing on the signal goroutine, AddTrack holds the participant's lock
+	go func() {
+		_ = p.Close(true, types.ParticipantCloseReasonSignalLimitExceeded)
+	}()
 }
 
 func (p *ParticipantImpl) SetMigrateInfo(previousAnswer *webrtc.SessionDescription, mediaTracks []*livekit.TrackPublishedResponse, dataChannels []*livekit.DataChannelInfo) {
@@ -683,13 +993,18 @@ func (p *ParticipantImpl) SetMigrateInfo(previousAnswer *webrtc.SessionDescripti
 
 // HandleAnswer handles a client answer response, with subscriber PC, server initiates the
 // offer and client answers
-func (p *ParticipantImpl) HandleAnswer(sdp webrtc.SessionDescription) error {
+func (p *ParticipantImpl) HandleAnswer(sdp webrtc.SessionDescription) (err error) {
+	_, span := p.startSpan("ParticipantImpl.HandleAnswer")
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	if sdp.Type != webrtc.SDPTypeAnswer {
 		return ErrUnexpectedOffer
 	}
 	p.params.Logger.Debugw("setting subPC answer", "answer", sdp)
 
-	if err := p.subscriber.SetRemoteDescription(sdp); err != nil {
+	if err = p.subscriber.SetRemoteDescription(sdp); err != nil {
 		return errors.Wrap(err, "could not set remote description")
 	}
 
@@ -751,6 +1066,13 @@ func (p *ParticipantImpl) Close(sendLeave bool, reason types.ParticipantCloseRea
 
 	p.UpTrackManager.Close(!sendLeave)
 
+	// bridged tracks are not ended by a peer connection
+	for _, t := range p.GetPublishedTracks() {
+		if bt, ok := t.(*BridgedTrack); ok {
+			bt.Close()
+		}
+	}
+
 	p.pendingTracksLock.Lock()
 	p.pendingTracks = make(map[string]*pendingTrackInfo)
 	p.pendingTracksLock.Unlock()
@@ -1224,6 +1546,7 @@ func (p *ParticipantImpl) onOffer(offer webrtc.SessionDescription) {
 		return
 	}
 
+	_, span := p.startSpan("ParticipantImpl.sendOffer")
 	p.params.Logger.Debugw("sending server offer to participant", "offer", offer)
 
 	err := p.writeMessage(&livekit.SignalResponse{
@@ -1236,6 +1559,14 @@ func (p *ParticipantImpl) onOffer(offer webrtc.SessionDescription) {
 	} else {
 		prometheus.ServiceOperationCounter.WithLabelValues("offer", "success", "").Add(1)
 	}
+	tracing.EndSpan(span, err)
+}
+
+func (p *ParticipantImpl) startSpan(spanName string) (context.Context, trace.Span) {
+	return tracing.StartWithParent(p.params.TraceParent, spanName, trace.WithAttributes(
+		attribute.String("participant", string(p.params.Identity)),
+		attribute.String("pID", string(p.params.SID)),
+	))
 }
 
 // when a new remoteTrack is created, creates a Track and adds it to room
@@ -1316,6 +1647,11 @@ func (p *ParticipantImpl) handleDataMessage(kind livekit.DataPacket_Kind, data [
 	// only forward on user payloads
 	switch payload := dp.Value.(type) {
 	case *livekit.DataPacket_User:
+		if reason := p.params.DataLimits.check(len(payload.User.Payload)); reason != "" {
+			p.handleDataLimitExceeded(reason)
+			return
+		}
+
 		p.lock.RLock()
 		onDataPacket := p.onDataPacket
 		p.lock.RUnlock()
@@ -1328,6 +1664,40 @@ func (p *ParticipantImpl) handleDataMessage(kind livekit.DataPacket_Kind, data [
 	}
 }
 
+// handleDataLimitExceeded takes the configured action on a data packet that was dropped for exceeding the
+// participant's limits
+func (p *ParticipantImpl) handleDataLimitExceeded(reason string) {
+	prometheus.AddDataPacketDropped(reason)
+
+	now := time.Now()
+	last := p.dataThrottledAt.Swap(now.UnixNano())
+	if now.Sub(time.Unix(0, last)) < dataThrottleResetInterval {
+		// already throttled
+		return
+	}
+
+	p.params.Logger.Infow("participant data throttled", "reason", reason, "action", p.params.DataLimits.Action)
+	p.lock.RLock()
+	onDataThrottled := p.onDataThrottled
+	p.lock.RUnlock()
+	if onDataThrottled != nil {
+		onDataThrottled(p, reason)
+	}
+
+	switch p.params.DataLimits.Action {
+	case DataLimitWarn:
+		_ = SendJSONMessage(p, &DataThrottledMessage{
+			Type:   dataThrottledMessageType,
+			Reason: reason,
+		})
+	case DataLimitDisconnect:
+		// not closing on the data channel's goroutine
+		go func() {
+			_ = p.Close(true, types.ParticipantCloseReasonDataLimitExceeded)
+		}()
+	}
+}
+
 func (p *ParticipantImpl) getTransport(isPrimary bool) *PCTransport {
 	pcTransport := p.publisher
 	if (isPrimary && p.SubscriberAsPrimary()) || (!isPrimary && !p.SubscriberAsPrimary()) {
@@ -1695,14 +2065,8 @@ func (p *ParticipantImpl) addPendingTrackLocked(req *livekit.AddTrackRequest) *l
 	}
 	p.setStableTrackID(req.Cid, ti)
 	for _, codec := range req.SimulcastCodecs {
-		mime := codec.Codec
-		if req.Type == livekit.TrackType_VIDEO && !strings.HasPrefix(mime, "video/") {
-			mime = "video/" + mime
-		} else if req.Type == livekit.TrackType_AUDIO && !strings.HasPrefix(mime, "audio/") {
-			mime = "audio/" + mime
-		}
 		ti.Codecs = append(ti.Codecs, &livekit.SimulcastCodecInfo{
-			MimeType: mime,
+			MimeType: codecMimeForTrack(req.Type, codec.Codec),
 			Cid:      codec.Cid,
 		})
 	}
@@ -1734,6 +2098,22 @@ func (p *ParticipantImpl) sendTrackPublished(cid string, ti *livekit.TrackInfo)
 	})
 }
 
+// UnpublishTrack removes a published track on behalf of the server, the client is told to stop publishing it
+func (p *ParticipantImpl) UnpublishTrack(trackID livekit.TrackID) {
+	track := p.GetPublishedTrack(trackID)
+	if track == nil {
+		return
+	}
+
+	p.RemovePublishedTrack(track, false)
+	if p.ProtocolVersion().SupportsUnpublish() {
+		p.sendTrackUnpublished(trackID)
+	} else {
+		// for older clients that don't support unpublish, mute to avoid them sending data
+		p.sendTrackMuted(trackID, true)
+	}
+}
+
 func (p *ParticipantImpl) SetTrackMuted(trackID livekit.TrackID, muted bool, fromAdmin bool) {
 	// when request is coming from admin, send message to current participant
 	if fromAdmin {
@@ -1925,6 +2305,32 @@ func (p *ParticipantImpl) addMediaTrack(signalCid string, sdpCid string, ti *liv
 	return mt
 }
 
+// AddBridgedTrack publishes a track republishing source, which is published in another room on the same node.
+// The bridged track follows the mute state of source, and is closed along with it
+func (p *ParticipantImpl) AddBridgedTrack(source *MediaTrack) *BridgedTrack {
+	trackID := livekit.TrackID(utils.NewGuid(utils.TrackPrefix))
+	bt := NewBridgedTrack(BridgedTrackParams{
+		Source:              source,
+		TrackID:             trackID,
+		ParticipantID:       p.params.SID,
+		ParticipantIdentity: p.params.Identity,
+		ParticipantVersion:  p.version.Load(),
+		BufferFactory:       p.params.Config.BufferFactory,
+		ReceiverConfig:      p.params.Config.Receiver,
+		SubscriberConfig:    p.params.Config.Subscriber,
+		VideoConfig:         p.params.VideoConfig,
+		Telemetry:           p.params.Telemetry,
+		Logger:              LoggerWithTrack(p.params.Logger, trackID),
+	})
+	bt.OnSourceMuted(func(muted bool) {
+		p.setTrackMuted(trackID, muted)
+	})
+	p.UpTrackManager.AddPublishedTrack(bt)
+
+	p.handleTrackPublished(bt)
+	return bt
+}
+
 func (p *ParticipantImpl) handleTrackPublished(track types.MediaTrack) {
 	if !p.hasPendingMigratedTrack() {
 		p.SetMigrateState(types.MigrateStateComplete)
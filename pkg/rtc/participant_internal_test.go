@@ -178,6 +178,97 @@ func TestTrackPublishing(t *testing.T) {
 		// check SID is the same
 		require.Equal(t, p.pendingTracks["cid"].trackInfos[0].Sid, p.pendingTracks["cid"].trackInfos[1].Sid)
 	})
+
+	t.Run("rejects codecs that aren't enabled in the room", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.EnabledCodecs = []*livekit.Codec{{Mime: "video/H264"}}
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:             "vp8",
+			Type:            livekit.TrackType_VIDEO,
+			SimulcastCodecs: []*livekit.SimulcastCodec{{Codec: "vp8", Cid: "vp8"}},
+		})
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:  "mic",
+			Type: livekit.TrackType_AUDIO,
+		})
+		require.Equal(t, 4, sink.WriteMessageCallCount())
+		for i, cid := range []string{"vp8", "mic"} {
+			requireTrackRejected(t, sink, 2*i, cid)
+		}
+		require.Empty(t, p.pendingTracks)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:             "h264",
+			Type:            livekit.TrackType_VIDEO,
+			SimulcastCodecs: []*livekit.SimulcastCodec{{Codec: "h264", Cid: "h264"}},
+		})
+		require.Equal(t, 5, sink.WriteMessageCallCount())
+		res := sink.WriteMessageArgsForCall(4).(*livekit.SignalResponse)
+		require.Equal(t, "h264", res.GetTrackPublished().GetCid())
+	})
+
+	t.Run("rejects sources that aren't allowed", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.AllowedSources = []livekit.TrackSource{livekit.TrackSource_SCREEN_SHARE}
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    "camera",
+			Type:   livekit.TrackType_VIDEO,
+			Source: livekit.TrackSource_CAMERA,
+		})
+		require.Equal(t, 2, sink.WriteMessageCallCount())
+		requireTrackRejected(t, sink, 0, "camera")
+		require.Empty(t, p.pendingTracks)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    "screen",
+			Type:   livekit.TrackType_VIDEO,
+			Source: livekit.TrackSource_SCREEN_SHARE,
+		})
+		require.Equal(t, 3, sink.WriteMessageCallCount())
+		res := sink.WriteMessageArgsForCall(2).(*livekit.SignalResponse)
+		require.Equal(t, "screen", res.GetTrackPublished().GetCid())
+
+		// no source may be published
+		p.params.AllowedSources = []livekit.TrackSource{}
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    "mic",
+			Type:   livekit.TrackType_AUDIO,
+			Source: livekit.TrackSource_MICROPHONE,
+		})
+		require.Equal(t, 5, sink.WriteMessageCallCount())
+		requireTrackRejected(t, sink, 3, "mic")
+	})
+
+	t.Run("mutes sources that start muted", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.StartMutedSources = []livekit.TrackSource{livekit.TrackSource_MICROPHONE}
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    "mic",
+			Type:   livekit.TrackType_AUDIO,
+			Source: livekit.TrackSource_MICROPHONE,
+		})
+		require.Equal(t, 2, sink.WriteMessageCallCount())
+		published := sink.WriteMessageArgsForCall(0).(*livekit.SignalResponse).GetTrackPublished()
+		require.Equal(t, "mic", published.GetCid())
+		mute := sink.WriteMessageArgsForCall(1).(*livekit.SignalResponse).GetMute()
+		require.Equal(t, published.GetTrack().GetSid(), mute.GetSid())
+		require.True(t, mute.GetMuted())
+		require.True(t, p.pendingTracks["mic"].trackInfos[0].Muted)
+
+		p.AddTrack(&livekit.AddTrackRequest{
+			Cid:    "camera",
+			Type:   livekit.TrackType_VIDEO,
+			Source: livekit.TrackSource_CAMERA,
+		})
+		require.Equal(t, 3, sink.WriteMessageCallCount())
+		require.False(t, p.pendingTracks["camera"].trackInfos[0].Muted)
+	})
 }
 
 func TestOutOfOrderUpdates(t *testing.T) {
@@ -644,6 +735,235 @@ func TestSetStableTrackID(t *testing.T) {
 	}
 }
 
+func TestUpdateAttributes(t *testing.T) {
+	p := newParticipantForTest("test")
+	var updates []map[string]string
+	p.OnAttributesUpdate(func(_ types.LocalParticipant, changed map[string]string, _ uint32) {
+		updates = append(updates, changed)
+	})
+
+	changed, version, err := p.UpdateAttributes(map[string]string{"hand": "raised", "role": "speaker"}, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"hand": "raised", "role": "speaker"}, changed)
+	require.EqualValues(t, 1, version)
+	require.Equal(t, []map[string]string{{"hand": "raised", "role": "speaker"}}, updates)
+	attributes, version := ParticipantAttributes(p.ToProto())
+	require.Equal(t, map[string]string{"hand": "raised", "role": "speaker"}, attributes)
+	require.EqualValues(t, 1, version)
+
+	// only changed keys are returned, removed ones with an empty value
+	changed, version, err = p.UpdateAttributes(map[string]string{"hand": "", "role": "speaker", "missing": ""}, nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"hand": ""}, changed)
+	require.EqualValues(t, 2, version)
+	attributes, version = p.Attributes()
+	require.Equal(t, map[string]string{"role": "speaker"}, attributes)
+	require.EqualValues(t, 2, version)
+
+	// no changes keep the version
+	changed, version, err = p.UpdateAttributes(map[string]string{"role": "speaker"}, nil, 0)
+	require.NoError(t, err)
+	require.Empty(t, changed)
+	require.EqualValues(t, 2, version)
+	// only changes are sent to the room
+	require.Equal(t, []map[string]string{{"hand": "raised", "role": "speaker"}, {"hand": ""}}, updates)
+
+	// version checks
+	stale := uint32(1)
+	_, _, err = p.UpdateAttributes(map[string]string{"role": "listener"}, &stale, 0)
+	require.ErrorIs(t, err, ErrAttributesChanged)
+	current := uint32(2)
+	_, version, err = p.UpdateAttributes(map[string]string{"role": "listener"}, &current, 0)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, version)
+
+	// size limits apply to the resulting attributes
+	_, _, err = p.UpdateAttributes(map[string]string{"status": "away"}, nil, 16)
+	require.ErrorIs(t, err, ErrAttributesTooLarge)
+	_, _, err = p.UpdateAttributes(map[string]string{"role": "", "status": "away"}, nil, 16)
+	require.NoError(t, err)
+
+	_, _, err = p.UpdateAttributes(map[string]string{"": "value"}, nil, 0)
+	require.ErrorIs(t, err, ErrEmptyAttributeKey)
+	attributes, version = p.Attributes()
+	require.Equal(t, map[string]string{"status": "away"}, attributes)
+	require.EqualValues(t, 4, version)
+}
+
+func TestSignalLimits(t *testing.T) {
+	subscription := &livekit.SignalRequest{
+		Message: &livekit.SignalRequest_Subscription{Subscription: &livekit.UpdateSubscription{}},
+	}
+	leave := &livekit.SignalRequest{
+		Message: &livekit.SignalRequest_Leave{Leave: &livekit.LeaveRequest{}},
+	}
+	mute := &livekit.SignalRequest{
+		Message: &livekit.SignalRequest_Mute{Mute: &livekit.MuteTrackRequest{}},
+	}
+
+	t.Run("requests are limited by type", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.SignalLimits = NewSignalLimits(map[string]uint32{"subscription": 2, "leave": 1}, 0, 0, 0)
+
+		require.True(t, p.AllowSignalRequest(subscription))
+		require.True(t, p.AllowSignalRequest(subscription))
+		require.False(t, p.AllowSignalRequest(subscription))
+		require.True(t, p.AllowSignalRequest(mute))
+		// leaving is never limited
+		require.True(t, p.AllowSignalRequest(leave))
+		require.True(t, p.AllowSignalRequest(leave))
+		require.NotEqual(t, livekit.ParticipantInfo_DISCONNECTED, p.State())
+	})
+
+	t.Run("pending tracks are limited", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.SignalLimits = NewSignalLimits(nil, 1, 0, 0)
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+
+		p.AddTrack(&livekit.AddTrackRequest{Cid: "cid1", Name: "webcam", Type: livekit.TrackType_VIDEO})
+		p.AddTrack(&livekit.AddTrackRequest{Cid: "cid2", Name: "mic", Type: livekit.TrackType_AUDIO})
+		require.Len(t, p.pendingTracks, 1)
+		require.NotNil(t, p.pendingTracks["cid1"])
+		require.Equal(t, 3, sink.WriteMessageCallCount())
+		requireTrackRejected(t, sink, 1, "cid2")
+	})
+
+	t.Run("participants exceeding limits repeatedly are disconnected", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.SignalLimits = NewSignalLimits(map[string]uint32{"subscription": 1}, 0, 0, 1)
+
+		require.True(t, p.AllowSignalRequest(subscription))
+		require.False(t, p.AllowSignalRequest(subscription))
+		require.NotEqual(t, livekit.ParticipantInfo_DISCONNECTED, p.State())
+		require.False(t, p.AllowSignalRequest(subscription))
+		require.Eventually(t, func() bool {
+			return p.State() == livekit.ParticipantInfo_DISCONNECTED
+		}, time.Second, 10*time.Millisecond)
+
+		sink := p.params.Sink.(*routingfakes.FakeMessageSink)
+		var leave *livekit.LeaveRequest
+		for i := 0; i < sink.WriteMessageCallCount(); i++ {
+			if res, ok := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse); ok && res.GetLeave() != nil {
+				leave = res.GetLeave()
+			}
+		}
+		require.NotNil(t, leave)
+		require.Equal(t, types.DisconnectReasonSignalLimitExceeded, leave.Reason)
+	})
+}
+
+func TestDataLimits(t *testing.T) {
+	publish := func(p *ParticipantImpl, payload string) {
+		data, err := proto.Marshal(&livekit.DataPacket{
+			Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Payload: []byte(payload)}},
+		})
+		require.NoError(t, err)
+		p.handleDataMessage(livekit.DataPacket_RELIABLE, data)
+	}
+
+	t.Run("packets exceeding limits are dropped", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.DataLimits = DataLimits{
+			MaxPacketSize: 4,
+			Participant:   NewDataRateLimiter(2, 0),
+			Action:        DataLimitDrop,
+		}
+		forwarded := 0
+		p.OnDataPacket(func(_ types.LocalParticipant, _ *livekit.DataPacket) {
+			forwarded++
+		})
+		var reasons []string
+		p.OnDataThrottled(func(_ types.LocalParticipant, reason string) {
+			reasons = append(reasons, reason)
+		})
+
+		publish(p, "too large")
+		require.Zero(t, forwarded)
+		publish(p, "1")
+		publish(p, "2")
+		publish(p, "3")
+		require.Equal(t, 2, forwarded)
+		// consecutive drops are a single throttling
+		require.Equal(t, []string{DataLimitReasonSize}, reasons)
+	})
+
+	t.Run("room limits are shared by participants", func(t *testing.T) {
+		room := NewDataRateLimiter(0, 10)
+		p1 := newParticipantForTest("p1")
+		p1.params.DataLimits = DataLimits{Room: room, Action: DataLimitDrop}
+		p2 := newParticipantForTest("p2")
+		p2.params.DataLimits = DataLimits{Room: room, Action: DataLimitDrop}
+		var reasons []string
+		p2.OnDataThrottled(func(_ types.LocalParticipant, reason string) {
+			reasons = append(reasons, reason)
+		})
+
+		publish(p1, "12345678")
+		publish(p2, "12345")
+		require.Equal(t, []string{DataLimitReasonRoomRate}, reasons)
+	})
+
+	t.Run("packets larger than a second of the byte rate exceed the size limit", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.DataLimits = DataLimits{
+			Participant: NewDataRateLimiter(0, 100),
+			Room:        NewDataRateLimiter(0, 4),
+			Action:      DataLimitDrop,
+		}
+		var reasons []string
+		p.OnDataThrottled(func(_ types.LocalParticipant, reason string) {
+			reasons = append(reasons, reason)
+		})
+
+		publish(p, "too large")
+		require.Equal(t, []string{DataLimitReasonSize}, reasons)
+		// nor are they counted against the participant's rate
+		require.True(t, p.params.DataLimits.Participant.Allow(100))
+	})
+
+	t.Run("packets dropped by the room limit don't count against the participant's", func(t *testing.T) {
+		room := NewDataRateLimiter(0, 10)
+		p1 := newParticipantForTest("p1")
+		p1.params.DataLimits = DataLimits{Room: room, Action: DataLimitDrop}
+		p2 := newParticipantForTest("p2")
+		p2.params.DataLimits = DataLimits{Participant: NewDataRateLimiter(0, 10), Room: room, Action: DataLimitDrop}
+
+		publish(p1, "12345678")
+		publish(p2, "12345")
+		require.True(t, p2.params.DataLimits.Participant.Allow(10))
+	})
+
+	t.Run("participants exceeding limits are disconnected", func(t *testing.T) {
+		p := newParticipantForTest("test")
+		p.params.DataLimits = DataLimits{MaxPacketSize: 4, Action: DataLimitDisconnect}
+		publish(p, "too large")
+		require.Eventually(t, func() bool {
+			return p.State() == livekit.ParticipantInfo_DISCONNECTED
+		}, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestDataTopicSubscriptions(t *testing.T) {
+	p := newParticipantForTest("test")
+	require.Empty(t, p.DataTopics())
+	require.True(t, p.CanPublishDataTopic("any"))
+
+	require.NoError(t, p.UpdateDataTopics([]string{"chat/*", "cursor"}, nil))
+	require.Equal(t, []string{"chat/*", "cursor"}, p.DataTopics())
+	require.True(t, p.IsSubscribedToDataTopic("chat/lobby"))
+	require.True(t, p.IsSubscribedToDataTopic("cursor"))
+	require.False(t, p.IsSubscribedToDataTopic("cursors"))
+
+	require.NoError(t, p.UpdateDataTopics(nil, []string{"chat/*"}))
+	require.False(t, p.IsSubscribedToDataTopic("chat/lobby"))
+	require.Equal(t, ErrEmptyDataTopic, p.UpdateDataTopics([]string{""}, nil))
+
+	// publishing is limited by the token's topics
+	p.params.PublishTopics = []string{"chat/*"}
+	require.True(t, p.CanPublishDataTopic("chat/lobby"))
+	require.False(t, p.CanPublishDataTopic("cursor"))
+}
+
 func TestDisableCodecs(t *testing.T) {
 	participant := newParticipantForTestWithOpts(livekit.ParticipantIdentity("123"), &participantOpts{
 		publisher: false,
@@ -706,6 +1026,24 @@ type participantOpts struct {
 	clientConf      *livekit.ClientConfiguration
 }
 
+// requireTrackRejected checks the messages sent from i on are the publication of cid under a track ID that's
+// unpublished right away
+func requireTrackRejected(t *testing.T, sink *routingfakes.FakeMessageSink, i int, cid string) {
+	published := sink.WriteMessageArgsForCall(i).(*livekit.SignalResponse).GetTrackPublished()
+	require.Equal(t, cid, published.GetCid())
+	trackID := published.GetTrack().GetSid()
+	require.NotEmpty(t, trackID)
+
+	res := sink.WriteMessageArgsForCall(i + 1).(*livekit.SignalResponse)
+	if unpublished := res.GetTrackUnpublished(); unpublished != nil {
+		require.Equal(t, trackID, unpublished.TrackSid)
+	} else {
+		// clients that can't be told to unpublish are muted
+		require.Equal(t, trackID, res.GetMute().GetSid())
+		require.True(t, res.GetMute().GetMuted())
+	}
+}
+
 func newParticipantForTestWithOpts(identity livekit.ParticipantIdentity, opts *participantOpts) *ParticipantImpl {
 	if opts == nil {
 		opts = &participantOpts{}
@@ -2,6 +2,7 @@ package rtc
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pion/webrtc/v3"
 	"google.golang.org/protobuf/proto"
@@ -9,6 +10,7 @@ import (
 	"github.com/livekit/protocol/livekit"
 
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/version"
 )
 
@@ -34,6 +36,7 @@ func (p *ParticipantImpl) SetResponseSink(sink routing.MessageSink) {
 func (p *ParticipantImpl) SendJoinResponse(
 	roomInfo *livekit.Room,
 	otherParticipants []*livekit.ParticipantInfo,
+	roomState map[string]*types.RoomStateEntry,
 	iceServers []*livekit.ICEServer,
 	region string,
 ) error {
@@ -41,23 +44,26 @@ func (p *ParticipantImpl) SendJoinResponse(
 		p.updateState(livekit.ParticipantInfo_JOINED)
 	}
 
+	join := &livekit.JoinResponse{
+		Room:              p.clientRoom(roomInfo),
+		Participant:       p.ToProto(),
+		OtherParticipants: otherParticipants,
+		ServerVersion:     version.Version,
+		ServerRegion:      region,
+		IceServers:        iceServers,
+		// indicates both server and client support subscriber as primary
+		SubscriberPrimary:   p.SubscriberAsPrimary(),
+		ClientConfiguration: p.params.ClientConf,
+		// sane defaults for ping interval & timeout
+		PingInterval: 10,
+		PingTimeout:  20,
+	}
+	SetJoinRoomState(join, roomState)
+
 	// send Join response
 	return p.writeMessage(&livekit.SignalResponse{
 		Message: &livekit.SignalResponse_Join{
-			Join: &livekit.JoinResponse{
-				Room:              roomInfo,
-				Participant:       p.ToProto(),
-				OtherParticipants: otherParticipants,
-				ServerVersion:     version.Version,
-				ServerRegion:      region,
-				IceServers:        iceServers,
-				// indicates both server and client support subscriber as primary
-				SubscriberPrimary:   p.SubscriberAsPrimary(),
-				ClientConfiguration: p.params.ClientConf,
-				// sane defaults for ping interval & timeout
-				PingInterval: 10,
-				PingTimeout:  20,
-			},
+			Join: join,
 		},
 	})
 }
@@ -159,12 +165,22 @@ func (p *ParticipantImpl) SendRoomUpdate(room *livekit.Room) error {
 	return p.writeMessage(&livekit.SignalResponse{
 		Message: &livekit.SignalResponse_RoomUpdate{
 			RoomUpdate: &livekit.RoomUpdate{
-				Room: room,
+				Room: p.clientRoom(room),
 			},
 		},
 	})
 }
 
+// clientRoom returns the room as known to the participant's client
+func (p *ParticipantImpl) clientRoom(room *livekit.Room) *livekit.Room {
+	if p.params.RoomNamePrefix == "" || room == nil {
+		return room
+	}
+	room = proto.Clone(room).(*livekit.Room)
+	room.Name = strings.TrimPrefix(room.Name, p.params.RoomNamePrefix)
+	return room
+}
+
 func (p *ParticipantImpl) SendConnectionQualityUpdate(update *livekit.ConnectionQualityUpdate) error {
 	return p.writeMessage(&livekit.SignalResponse{
 		Message: &livekit.SignalResponse_ConnectionQuality{
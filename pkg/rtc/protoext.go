@@ -0,0 +1,430 @@
+package rtc
+
+import (
+	"sort"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// Fields this server sends that the protocol doesn't define are carried as unknown fields of its messages.
+// Clients built with a protocol defining them read them as regular fields, others ignore them. Fields are
+// numbered well past the protocol's own, so that they don't collide with fields it adds later, unless they match a
+// field later versions of the protocol define
+const (
+	roomLockedField    protowire.Number = 100
+	roomParentField    protowire.Number = 101
+	roomBreakoutsField protowire.Number = 102
+
+	// map<string, string> attributes = 15, as defined by later versions of the protocol
+	participantAttributesField        protowire.Number = 15
+	participantAttributesVersionField protowire.Number = 100
+	participantAttributesDeltaField   protowire.Number = 101
+
+	joinRoomStateField protowire.Number = 100
+
+	// optional string topic = 4, as defined by later versions of the protocol
+	userPacketTopicField protowire.Number = 4
+
+	signalDataTopicsField protowire.Number = 100
+	signalStageField      protowire.Number = 101
+)
+
+// SetRoomLocked marks whether new identities can join the room
+func SetRoomLocked(room *livekit.Room, locked bool) {
+	setExtensionField(room, roomLockedField, func(b []byte) []byte {
+		if !locked {
+			return b
+		}
+		b = protowire.AppendTag(b, roomLockedField, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(true))
+	})
+}
+
+// RoomLocked returns whether new identities can join the room
+func RoomLocked(room *livekit.Room) bool {
+	locked := false
+	rangeExtensionField(room, roomLockedField, func(typ protowire.Type, b []byte) {
+		if v, n := protowire.ConsumeVarint(b); typ == protowire.VarintType && n > 0 {
+			locked = protowire.DecodeBool(v)
+		}
+	})
+	return locked
+}
+
+// SetRoomBreakoutLinks sets the parent of a breakout room, and the breakout rooms of a parent
+func SetRoomBreakoutLinks(room *livekit.Room, parent string, breakouts []string) {
+	setExtensionField(room, roomParentField, func(b []byte) []byte {
+		if parent == "" {
+			return b
+		}
+		b = protowire.AppendTag(b, roomParentField, protowire.BytesType)
+		return protowire.AppendString(b, parent)
+	})
+	setExtensionField(room, roomBreakoutsField, func(b []byte) []byte {
+		for _, name := range breakouts {
+			b = protowire.AppendTag(b, roomBreakoutsField, protowire.BytesType)
+			b = protowire.AppendString(b, name)
+		}
+		return b
+	})
+}
+
+// RoomBreakoutLinks returns the parent of a breakout room, and the breakout rooms of a parent
+func RoomBreakoutLinks(room *livekit.Room) (parent string, breakouts []string) {
+	rangeExtensionField(room, roomParentField, func(typ protowire.Type, b []byte) {
+		if v, n := protowire.ConsumeString(b); typ == protowire.BytesType && n > 0 {
+			parent = v
+		}
+	})
+	rangeExtensionField(room, roomBreakoutsField, func(typ protowire.Type, b []byte) {
+		if v, n := protowire.ConsumeString(b); typ == protowire.BytesType && n > 0 {
+			breakouts = append(breakouts, v)
+		}
+	})
+	return
+}
+
+// SetParticipantAttributes sets the attributes of a participant, along with their version
+func SetParticipantAttributes(info *livekit.ParticipantInfo, attributes map[string]string, version uint32) {
+	setParticipantAttributes(info, attributes, version, false)
+}
+
+// SetParticipantAttributesDelta sets only the attributes of a participant that changed at version, with an empty
+// value for removed ones. Participants receiving it update the attributes they know of key by key
+func SetParticipantAttributesDelta(info *livekit.ParticipantInfo, changed map[string]string, version uint32) {
+	setParticipantAttributes(info, changed, version, true)
+}
+
+func setParticipantAttributes(info *livekit.ParticipantInfo, attributes map[string]string, version uint32, delta bool) {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	// keeps the encoding stable
+	sort.Strings(keys)
+
+	setExtensionField(info, participantAttributesField, func(b []byte) []byte {
+		for _, key := range keys {
+			var entry []byte
+			entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+			entry = protowire.AppendString(entry, key)
+			entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+			entry = protowire.AppendString(entry, attributes[key])
+			b = protowire.AppendTag(b, participantAttributesField, protowire.BytesType)
+			b = protowire.AppendBytes(b, entry)
+		}
+		return b
+	})
+	setExtensionField(info, participantAttributesVersionField, func(b []byte) []byte {
+		if version == 0 {
+			return b
+		}
+		b = protowire.AppendTag(b, participantAttributesVersionField, protowire.VarintType)
+		return protowire.AppendVarint(b, uint64(version))
+	})
+	setExtensionField(info, participantAttributesDeltaField, func(b []byte) []byte {
+		if !delta {
+			return b
+		}
+		b = protowire.AppendTag(b, participantAttributesDeltaField, protowire.VarintType)
+		return protowire.AppendVarint(b, protowire.EncodeBool(true))
+	})
+}
+
+// ParticipantAttributes returns the attributes of a participant, along with their version
+func ParticipantAttributes(info *livekit.ParticipantInfo) (map[string]string, uint32) {
+	attributes := make(map[string]string)
+	rangeExtensionField(info, participantAttributesField, func(typ protowire.Type, b []byte) {
+		entry, n := protowire.ConsumeBytes(b)
+		if typ != protowire.BytesType || n < 0 {
+			return
+		}
+		var key, value string
+		for len(entry) > 0 {
+			num, fieldType, tagSize := protowire.ConsumeTag(entry)
+			if tagSize < 0 {
+				return
+			}
+			entry = entry[tagSize:]
+			if fieldType != protowire.BytesType {
+				if size := protowire.ConsumeFieldValue(num, fieldType, entry); size >= 0 {
+					entry = entry[size:]
+					continue
+				}
+				return
+			}
+			v, size := protowire.ConsumeString(entry)
+			if size < 0 {
+				return
+			}
+			entry = entry[size:]
+			switch num {
+			case 1:
+				key = v
+			case 2:
+				value = v
+			}
+		}
+		attributes[key] = value
+	})
+
+	var version uint32
+	rangeExtensionField(info, participantAttributesVersionField, func(typ protowire.Type, b []byte) {
+		if v, n := protowire.ConsumeVarint(b); typ == protowire.VarintType && n > 0 {
+			version = uint32(v)
+		}
+	})
+	return attributes, version
+}
+
+// ParticipantAttributesDelta returns whether the attributes of a participant only hold the keys that changed
+func ParticipantAttributesDelta(info *livekit.ParticipantInfo) bool {
+	delta := false
+	rangeExtensionField(info, participantAttributesDeltaField, func(typ protowire.Type, b []byte) {
+		if v, n := protowire.ConsumeVarint(b); typ == protowire.VarintType && n > 0 {
+			delta = protowire.DecodeBool(v)
+		}
+	})
+	return delta
+}
+
+// SetJoinRoomState sets the shared state of the room a participant joins
+func SetJoinRoomState(res *livekit.JoinResponse, state map[string]*types.RoomStateEntry) {
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	setExtensionField(res, joinRoomStateField, func(b []byte) []byte {
+		for _, key := range keys {
+			e := state[key]
+			var entry []byte
+			entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+			entry = protowire.AppendString(entry, key)
+			entry = protowire.AppendTag(entry, 2, protowire.BytesType)
+			entry = protowire.AppendString(entry, e.Value)
+			if e.Owner != "" {
+				entry = protowire.AppendTag(entry, 3, protowire.BytesType)
+				entry = protowire.AppendString(entry, e.Owner)
+			}
+			if e.Shared {
+				entry = protowire.AppendTag(entry, 4, protowire.VarintType)
+				entry = protowire.AppendVarint(entry, protowire.EncodeBool(true))
+			}
+			if e.ExpiresAt != 0 {
+				entry = protowire.AppendTag(entry, 5, protowire.VarintType)
+				entry = protowire.AppendVarint(entry, uint64(e.ExpiresAt))
+			}
+			b = protowire.AppendTag(b, joinRoomStateField, protowire.BytesType)
+			b = protowire.AppendBytes(b, entry)
+		}
+		return b
+	})
+}
+
+// JoinRoomState returns the shared state of the room a participant joins
+func JoinRoomState(res *livekit.JoinResponse) map[string]*types.RoomStateEntry {
+	state := make(map[string]*types.RoomStateEntry)
+	rangeExtensionField(res, joinRoomStateField, func(typ protowire.Type, b []byte) {
+		entry, n := protowire.ConsumeBytes(b)
+		if typ != protowire.BytesType || n < 0 {
+			return
+		}
+		key := ""
+		e := &types.RoomStateEntry{}
+		for len(entry) > 0 {
+			num, fieldType, tagSize := protowire.ConsumeTag(entry)
+			if tagSize < 0 {
+				return
+			}
+			entry = entry[tagSize:]
+			size := protowire.ConsumeFieldValue(num, fieldType, entry)
+			if size < 0 {
+				return
+			}
+			switch {
+			case num == 1 && fieldType == protowire.BytesType:
+				key, _ = protowire.ConsumeString(entry)
+			case num == 2 && fieldType == protowire.BytesType:
+				e.Value, _ = protowire.ConsumeString(entry)
+			case num == 3 && fieldType == protowire.BytesType:
+				e.Owner, _ = protowire.ConsumeString(entry)
+			case num == 4 && fieldType == protowire.VarintType:
+				v, _ := protowire.ConsumeVarint(entry)
+				e.Shared = protowire.DecodeBool(v)
+			case num == 5 && fieldType == protowire.VarintType:
+				v, _ := protowire.ConsumeVarint(entry)
+				e.ExpiresAt = int64(v)
+			}
+			entry = entry[size:]
+		}
+		state[key] = e
+	})
+	return state
+}
+
+// SetUserPacketTopic sets the topic of a data packet, empty for none
+func SetUserPacketTopic(up *livekit.UserPacket, topic string) {
+	setExtensionField(up, userPacketTopicField, func(b []byte) []byte {
+		if topic == "" {
+			return b
+		}
+		b = protowire.AppendTag(b, userPacketTopicField, protowire.BytesType)
+		return protowire.AppendString(b, topic)
+	})
+}
+
+// UserPacketTopic returns the topic of a data packet, empty when it has none
+func UserPacketTopic(up *livekit.UserPacket) string {
+	topic := ""
+	rangeExtensionField(up, userPacketTopicField, func(typ protowire.Type, b []byte) {
+		if v, n := protowire.ConsumeString(b); typ == protowire.BytesType && n >= 0 {
+			topic = v
+		}
+	})
+	return topic
+}
+
+// SetSignalDataTopics makes a signal request change the data topics the participant is subscribed to
+func SetSignalDataTopics(req *livekit.SignalRequest, subscribe []string, unsubscribe []string) {
+	setExtensionField(req, signalDataTopicsField, func(b []byte) []byte {
+		var update []byte
+		for _, topic := range subscribe {
+			update = protowire.AppendTag(update, 1, protowire.BytesType)
+			update = protowire.AppendString(update, topic)
+		}
+		for _, topic := range unsubscribe {
+			update = protowire.AppendTag(update, 2, protowire.BytesType)
+			update = protowire.AppendString(update, topic)
+		}
+		b = protowire.AppendTag(b, signalDataTopicsField, protowire.BytesType)
+		return protowire.AppendBytes(b, update)
+	})
+}
+
+// SignalDataTopics returns the data topics a signal request subscribes the participant to and unsubscribes it from,
+// ok when it's an update of its data topics
+func SignalDataTopics(req *livekit.SignalRequest) (subscribe []string, unsubscribe []string, ok bool) {
+	rangeExtensionField(req, signalDataTopicsField, func(typ protowire.Type, b []byte) {
+		update, n := protowire.ConsumeBytes(b)
+		if typ != protowire.BytesType || n < 0 {
+			return
+		}
+		ok = true
+		for len(update) > 0 {
+			num, fieldType, tagSize := protowire.ConsumeTag(update)
+			if tagSize < 0 {
+				return
+			}
+			update = update[tagSize:]
+			size := protowire.ConsumeFieldValue(num, fieldType, update)
+			if size < 0 {
+				return
+			}
+			if fieldType == protowire.BytesType {
+				topic, _ := protowire.ConsumeString(update)
+				switch num {
+				case 1:
+					subscribe = append(subscribe, topic)
+				case 2:
+					unsubscribe = append(unsubscribe, topic)
+				}
+			}
+			update = update[size:]
+		}
+	})
+	return
+}
+
+// SetSignalStage makes a signal request apply action to the participant identity of the room's stage. identity is
+// ignored by requests and withdrawals, which apply to the participant sending them
+func SetSignalStage(req *livekit.SignalRequest, action types.StageAction, identity livekit.ParticipantIdentity) {
+	setExtensionField(req, signalStageField, func(b []byte) []byte {
+		var update []byte
+		update = protowire.AppendTag(update, 1, protowire.BytesType)
+		update = protowire.AppendString(update, string(action))
+		if identity != "" {
+			update = protowire.AppendTag(update, 2, protowire.BytesType)
+			update = protowire.AppendString(update, string(identity))
+		}
+		b = protowire.AppendTag(b, signalStageField, protowire.BytesType)
+		return protowire.AppendBytes(b, update)
+	})
+}
+
+// SignalStage returns the stage action of a signal request and the identity it applies to, ok when it's a stage
+// request
+func SignalStage(req *livekit.SignalRequest) (action types.StageAction, identity livekit.ParticipantIdentity, ok bool) {
+	rangeExtensionField(req, signalStageField, func(typ protowire.Type, b []byte) {
+		update, n := protowire.ConsumeBytes(b)
+		if typ != protowire.BytesType || n < 0 {
+			return
+		}
+		ok = true
+		for len(update) > 0 {
+			num, fieldType, tagSize := protowire.ConsumeTag(update)
+			if tagSize < 0 {
+				return
+			}
+			update = update[tagSize:]
+			size := protowire.ConsumeFieldValue(num, fieldType, update)
+			if size < 0 {
+				return
+			}
+			if fieldType == protowire.BytesType {
+				value, _ := protowire.ConsumeString(update)
+				switch num {
+				case 1:
+					action = types.StageAction(value)
+				case 2:
+					identity = livekit.ParticipantIdentity(value)
+				}
+			}
+			update = update[size:]
+		}
+	})
+	return
+}
+
+// setExtensionField replaces the unknown fields of m numbered num with the fields appendField appends
+func setExtensionField(m proto.Message, num protowire.Number, appendField func(b []byte) []byte) {
+	var kept []byte
+	unknown := m.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		n, _, size := protowire.ConsumeField(unknown)
+		if size < 0 {
+			// drop what can't be parsed rather than carry it on
+			break
+		}
+		if n != num {
+			kept = append(kept, unknown[:size]...)
+		}
+		unknown = unknown[size:]
+	}
+	m.ProtoReflect().SetUnknown(appendField(kept))
+}
+
+// rangeExtensionField calls f with the type and value of each unknown field of m numbered num, in order
+func rangeExtensionField(m proto.Message, num protowire.Number, f func(typ protowire.Type, b []byte)) {
+	unknown := m.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		n, typ, tagSize := protowire.ConsumeTag(unknown)
+		if tagSize < 0 {
+			return
+		}
+		valueSize := protowire.ConsumeFieldValue(n, typ, unknown[tagSize:])
+		if valueSize < 0 {
+			return
+		}
+		if n == num {
+			f(typ, unknown[tagSize:tagSize+valueSize])
+		}
+		unknown = unknown[tagSize+valueSize:]
+	}
+}
@@ -0,0 +1,153 @@
+package rtc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+func TestRoomLocked(t *testing.T) {
+	room := &livekit.Room{Name: "room"}
+	require.False(t, RoomLocked(room))
+
+	SetRoomLocked(room, true)
+	require.True(t, RoomLocked(room))
+	// setting again replaces the field
+	SetRoomLocked(room, true)
+	require.Len(t, room.ProtoReflect().GetUnknown(), 3)
+
+	// survives encoding
+	data, err := proto.Marshal(room)
+	require.NoError(t, err)
+	decoded := &livekit.Room{}
+	require.NoError(t, proto.Unmarshal(data, decoded))
+	require.Equal(t, "room", decoded.Name)
+	require.True(t, RoomLocked(decoded))
+	require.True(t, RoomLocked(proto.Clone(decoded).(*livekit.Room)))
+
+	SetRoomLocked(room, false)
+	require.False(t, RoomLocked(room))
+	require.Empty(t, room.ProtoReflect().GetUnknown())
+}
+
+func TestRoomBreakoutLinks(t *testing.T) {
+	room := &livekit.Room{Name: "room"}
+	SetRoomLocked(room, true)
+	SetRoomBreakoutLinks(room, "parent", []string{"b1", "b2"})
+
+	data, err := proto.Marshal(room)
+	require.NoError(t, err)
+	decoded := &livekit.Room{}
+	require.NoError(t, proto.Unmarshal(data, decoded))
+	parent, breakouts := RoomBreakoutLinks(decoded)
+	require.Equal(t, "parent", parent)
+	require.Equal(t, []string{"b1", "b2"}, breakouts)
+	require.True(t, RoomLocked(decoded))
+
+	SetRoomBreakoutLinks(decoded, "", nil)
+	parent, breakouts = RoomBreakoutLinks(decoded)
+	require.Empty(t, parent)
+	require.Empty(t, breakouts)
+	require.True(t, RoomLocked(decoded))
+}
+
+func TestParticipantAttributes(t *testing.T) {
+	info := &livekit.ParticipantInfo{Identity: "p"}
+	attributes, version := ParticipantAttributes(info)
+	require.Empty(t, attributes)
+	require.Zero(t, version)
+
+	SetParticipantAttributes(info, map[string]string{"hand": "raised", "role": "speaker"}, 2)
+	data, err := proto.Marshal(info)
+	require.NoError(t, err)
+	decoded := &livekit.ParticipantInfo{}
+	require.NoError(t, proto.Unmarshal(data, decoded))
+	attributes, version = ParticipantAttributes(decoded)
+	require.Equal(t, map[string]string{"hand": "raised", "role": "speaker"}, attributes)
+	require.EqualValues(t, 2, version)
+
+	// setting again replaces them
+	SetParticipantAttributes(decoded, map[string]string{"role": "listener"}, 3)
+	attributes, version = ParticipantAttributes(decoded)
+	require.Equal(t, map[string]string{"role": "listener"}, attributes)
+	require.EqualValues(t, 3, version)
+	require.False(t, ParticipantAttributesDelta(decoded))
+
+	// deltas carry removed keys with an empty value, and are told apart from full attributes
+	SetParticipantAttributesDelta(decoded, map[string]string{"hand": "", "mic": "on"}, 4)
+	data, err = proto.Marshal(decoded)
+	require.NoError(t, err)
+	delta := &livekit.ParticipantInfo{}
+	require.NoError(t, proto.Unmarshal(data, delta))
+	attributes, version = ParticipantAttributes(delta)
+	require.Equal(t, map[string]string{"hand": "", "mic": "on"}, attributes)
+	require.EqualValues(t, 4, version)
+	require.True(t, ParticipantAttributesDelta(delta))
+
+	SetParticipantAttributes(delta, map[string]string{"mic": "on"}, 4)
+	require.False(t, ParticipantAttributesDelta(delta))
+}
+
+func TestJoinRoomState(t *testing.T) {
+	state := map[string]*types.RoomStateEntry{
+		"k1": {Value: "v1", Owner: "alice", Shared: true, ExpiresAt: 1700000000},
+		"k2": {Value: ""},
+	}
+	res := &livekit.JoinResponse{}
+	SetJoinRoomState(res, state)
+
+	data, err := proto.Marshal(res)
+	require.NoError(t, err)
+	decoded := &livekit.JoinResponse{}
+	require.NoError(t, proto.Unmarshal(data, decoded))
+	require.Equal(t, state, JoinRoomState(decoded))
+
+	SetJoinRoomState(decoded, nil)
+	require.Empty(t, JoinRoomState(decoded))
+}
+
+func TestDataTopicFields(t *testing.T) {
+	up := &livekit.UserPacket{Payload: []byte("message"), DestinationSids: []string{"PA_1"}}
+	SetUserPacketTopic(up, "chat/lobby")
+	data, err := proto.Marshal(up)
+	require.NoError(t, err)
+	decodedPacket := &livekit.UserPacket{}
+	require.NoError(t, proto.Unmarshal(data, decodedPacket))
+	require.Equal(t, "chat/lobby", UserPacketTopic(decodedPacket))
+	require.Equal(t, []string{"PA_1"}, decodedPacket.DestinationSids)
+
+	req := &livekit.SignalRequest{}
+	_, _, ok := SignalDataTopics(req)
+	require.False(t, ok)
+	SetSignalDataTopics(req, []string{"chat/*", "cursor"}, []string{"chat/lobby"})
+	data, err = proto.Marshal(req)
+	require.NoError(t, err)
+	decodedRequest := &livekit.SignalRequest{}
+	require.NoError(t, proto.Unmarshal(data, decodedRequest))
+	subscribe, unsubscribe, ok := SignalDataTopics(decodedRequest)
+	require.True(t, ok)
+	require.Equal(t, []string{"chat/*", "cursor"}, subscribe)
+	require.Equal(t, []string{"chat/lobby"}, unsubscribe)
+	require.Equal(t, "data_topics", signalRequestType(decodedRequest))
+}
+
+func TestSignalStageField(t *testing.T) {
+	req := &livekit.SignalRequest{}
+	_, _, ok := SignalStage(req)
+	require.False(t, ok)
+	SetSignalStage(req, types.StageActionApprove, "p1")
+	data, err := proto.Marshal(req)
+	require.NoError(t, err)
+	decoded := &livekit.SignalRequest{}
+	require.NoError(t, proto.Unmarshal(data, decoded))
+	action, identity, ok := SignalStage(decoded)
+	require.True(t, ok)
+	require.Equal(t, types.StageActionApprove, action)
+	require.Equal(t, livekit.ParticipantIdentity("p1"), identity)
+	require.Equal(t, "stage", signalRequestType(decoded))
+}
@@ -2,11 +2,15 @@ package rtc
 
 import (
 	"context"
+	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 	"google.golang.org/protobuf/proto"
 
@@ -20,6 +24,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu/buffer"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 )
 
 const (
@@ -39,16 +44,21 @@ type Room struct {
 	lock sync.RWMutex
 
 	protoRoom *livekit.Room
+	internal  *types.RoomInternal
 	Logger    logger.Logger
 
 	config      WebRTCConfig
 	audioConfig *config.AudioConfig
 	telemetry   telemetry.TelemetryService
 
-	// map of identity -> Participant
-	participants    map[livekit.ParticipantIdentity]types.LocalParticipant
-	participantOpts map[livekit.ParticipantIdentity]*ParticipantOptions
-	bufferFactory   *buffer.Factory
+	// map of SID -> Participant, along with the SIDs of each identity's sessions in the order they joined. an
+	// identity has several sessions when they're allowed to join alongside each other
+	participants    map[livekit.ParticipantID]types.LocalParticipant
+	participantOpts map[livekit.ParticipantID]*ParticipantOptions
+	identities      map[livekit.ParticipantIdentity][]livekit.ParticipantID
+	// identities that joined the room and haven't left it, which may rejoin while the room is locked
+	members       map[livekit.ParticipantIdentity]bool
+	bufferFactory *buffer.Factory
 
 	// batch update participant info for non-publishers
 	batchedUpdates   map[livekit.ParticipantIdentity]*livekit.ParticipantInfo
@@ -61,27 +71,71 @@ type Room struct {
 	leftAt atomic.Int64
 	closed chan struct{}
 
+	// serializes metadata updates, along with their persistence
+	metadataLock    sync.Mutex
+	metadataVersion uint32
+
+	// identities of participants requesting to publish, in order of their requests
+	stageQueue []livekit.ParticipantIdentity
+	stageLock  sync.Mutex
+	// moderation applied to tracks as they're published
+	entryModeration    *TrackModeration
+	applyingModeration bool
+	// shared key-value state, along with timers expiring its keys
+	stateLock   sync.Mutex
+	state       map[string]*types.RoomStateEntry
+	stateTimers map[string]*time.Timer
+	// incremented on every change of the state, and the version each joining participant received
+	stateVersion uint64
+	stateSent    map[livekit.ParticipantID]uint64
+
+	// reliable data messages retained for participants joining later. historyEnabled is set along with the policy,
+	// so that rooms without a history forward data without taking historyLock
+	historyLock    sync.Mutex
+	historyEnabled atomic.Bool
+	historyPolicy  *types.DataHistory
+	history        []*types.DataMessage
+	historyBytes   int
+	// packets held back from participants being replayed the history, sent after it
+	replaying      map[livekit.ParticipantID][]*livekit.DataPacket
+	onDataRetained func(msg *types.DataMessage)
+	// classes data topics are counted by in metrics
+	dataTopicClasses map[string]bool
+	// rate of data packets all participants may publish, nil when unlimited
+	dataLimiter *DataRateLimiter
+
 	onParticipantChanged func(p types.LocalParticipant)
-	onMetadataUpdate     func(metadata string)
+	onMetadataUpdate     func(metadata string, version uint32)
 	onClose              func()
 }
 
 type ParticipantOptions struct {
 	AutoSubscribe bool
+	// Bridge is set for participants publishing tracks bridged from another room, which don't keep the room open
+	Bridge bool
+	// Duplicate is set for sessions allowed to join alongside other sessions of their identity
+	Duplicate bool
 }
 
-func NewRoom(room *livekit.Room, config WebRTCConfig, audioConfig *config.AudioConfig, telemetry telemetry.TelemetryService) *Room {
+func NewRoom(room *livekit.Room, internal *types.RoomInternal, config WebRTCConfig, audioConfig *config.AudioConfig, telemetry telemetry.TelemetryService) *Room {
+	if internal == nil {
+		internal = &types.RoomInternal{}
+	}
 	r := &Room{
 		protoRoom:       proto.Clone(room).(*livekit.Room),
+		internal:        internal,
 		Logger:          LoggerWithRoom(logger.GetDefaultLogger(), livekit.RoomName(room.Name), livekit.RoomID(room.Sid)),
 		config:          config,
 		audioConfig:     audioConfig,
 		telemetry:       telemetry,
-		participants:    make(map[livekit.ParticipantIdentity]types.LocalParticipant),
-		participantOpts: make(map[livekit.ParticipantIdentity]*ParticipantOptions),
+		participants:    make(map[livekit.ParticipantID]types.LocalParticipant),
+		participantOpts: make(map[livekit.ParticipantID]*ParticipantOptions),
+		identities:      make(map[livekit.ParticipantIdentity][]livekit.ParticipantID),
+		members:         make(map[livekit.ParticipantIdentity]bool),
 		bufferFactory:   buffer.NewBufferFactory(config.Receiver.PacketBufferSize),
 		batchedUpdates:  make(map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
 		closed:          make(chan struct{}),
+		metadataVersion: internal.MetadataVersion,
 	}
 	if r.protoRoom.EmptyTimeout == 0 {
 		r.protoRoom.EmptyTimeout = DefaultEmptyTimeout
@@ -89,6 +143,7 @@ func NewRoom(room *livekit.Room, config WebRTCConfig, audioConfig *config.AudioC
 	if r.protoRoom.CreationTime == 0 {
 		r.protoRoom.CreationTime = time.Now().Unix()
 	}
+	SetRoomLocked(r.protoRoom, internal.Locked)
 
 	go r.audioUpdateWorker()
 	go r.connectionQualityWorker()
@@ -97,6 +152,17 @@ func NewRoom(room *livekit.Room, config WebRTCConfig, audioConfig *config.AudioC
 	return r
 }
 
+// numPublishersLocked counts participants allowed to publish, r.lock must be held
+func (r *Room) numPublishersLocked() int {
+	num := 0
+	for _, p := range r.participants {
+		if p.CanPublish() {
+			num++
+		}
+	}
+	return num
+}
+
 func (r *Room) ToProto() *livekit.Room {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -108,29 +174,63 @@ func (r *Room) Name() livekit.RoomName {
 	return livekit.RoomName(r.protoRoom.Name)
 }
 
+// Internal returns the server side settings of the room, which must not be modified
+func (r *Room) Internal() *types.RoomInternal {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.internal
+}
+
+// SetInternal replaces the server side settings of the room after they're updated in the store
+func (r *Room) SetInternal(internal *types.RoomInternal) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.internal = internal
+	if RoomLocked(r.protoRoom) != internal.Locked {
+		SetRoomLocked(r.protoRoom, internal.Locked)
+		r.sendRoomUpdateLocked()
+	}
+}
+
 func (r *Room) ID() livekit.RoomID {
 	return livekit.RoomID(r.protoRoom.Sid)
 }
 
+// GetParticipant returns the session of identity that joined first
 func (r *Room) GetParticipant(identity livekit.ParticipantIdentity) types.LocalParticipant {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
-	return r.participants[identity]
+	return r.participantLocked(identity)
 }
 
-func (r *Room) GetParticipantBySid(participantID livekit.ParticipantID) types.LocalParticipant {
+// GetParticipantSessions returns every session of identity, in the order they joined
+func (r *Room) GetParticipantSessions(identity livekit.ParticipantIdentity) []types.LocalParticipant {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
 
-	for _, p := range r.participants {
-		if p.ID() == participantID {
-			return p
-		}
+	sids := r.identities[identity]
+	participants := make([]types.LocalParticipant, 0, len(sids))
+	for _, sid := range sids {
+		participants = append(participants, r.participants[sid])
 	}
+	return participants
+}
 
+// participantLocked returns the session of identity that joined first, r.lock must be held
+func (r *Room) participantLocked(identity livekit.ParticipantIdentity) types.LocalParticipant {
+	if sids := r.identities[identity]; len(sids) > 0 {
+		return r.participants[sids[0]]
+	}
 	return nil
 }
 
+func (r *Room) GetParticipantBySid(participantID livekit.ParticipantID) types.LocalParticipant {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return r.participants[participantID]
+}
+
 func (r *Room) GetParticipants() []types.LocalParticipant {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -141,6 +241,21 @@ func (r *Room) GetParticipants() []types.LocalParticipant {
 	return participants
 }
 
+// DuplicateParticipantID returns the SID another session of a participant in the room joins with, suffixing the
+// participant's SID with "#<n>" for the first n not taken
+func (r *Room) DuplicateParticipantID(participant types.LocalParticipant) livekit.ParticipantID {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	sid := strings.SplitN(string(participant.ID()), "#", 2)[0]
+	for n := 2; ; n++ {
+		duplicate := livekit.ParticipantID(fmt.Sprintf("%s#%d", sid, n))
+		if r.participants[duplicate] == nil {
+			return duplicate
+		}
+	}
+}
+
 func (r *Room) GetActiveSpeakers() []*livekit.SpeakerInfo {
 	participants := r.GetParticipants()
 	speakers := make([]*livekit.SpeakerInfo, 0, len(participants))
@@ -196,25 +311,148 @@ func (r *Room) Release() {
 	r.holds.Dec()
 }
 
-func (r *Room) Join(participant types.LocalParticipant, opts *ParticipantOptions, iceServers []*livekit.ICEServer, region string) error {
+func (r *Room) Join(ctx context.Context, participant types.LocalParticipant, opts *ParticipantOptions, iceServers []*livekit.ICEServer, region string) (err error) {
+	_, span := tracing.Start(ctx, "Room.Join", trace.WithAttributes(
+		attribute.String("room", string(r.Name())),
+		attribute.String("participant", string(participant.Identity())),
+	))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
+	// taken before the room lock, changes made meanwhile are sent once the participant is active
+	roomState := r.joinState(participant)
+	// deferred before the room lock, so that it runs once the lock is released. state updates take the room lock
+	// while holding the state lock
+	defer func() {
+		if err != nil {
+			r.forgetJoinState(participant)
+		}
+	}()
+
 	r.lock.Lock()
 	defer r.lock.Unlock()
 
+	if reason, err := r.checkJoinLocked(participant, opts); err != nil {
+		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", reason).Add(1)
+		return err
+	}
+
+	// it's important to set this before connection, we don't want to miss out on any publishedTracks
+	r.setParticipantCallbacks(participant)
+	r.Logger.Infow("new participant joined",
+		"pID", participant.ID(),
+		"participant", participant.Identity(),
+		"protocol", participant.ProtocolVersion(),
+		"options", opts)
+
+	r.addParticipantLocked(participant, opts)
+
+	// gather other participants and send join response
+	otherParticipants := make([]*livekit.ParticipantInfo, 0, len(r.participants))
+	for _, p := range r.participants {
+		if p.ID() != participant.ID() && !p.Hidden() {
+			otherParticipants = append(otherParticipants, p.ToProto())
+		}
+	}
+
+	if r.onParticipantChanged != nil {
+		r.onParticipantChanged(participant)
+	}
+
+	time.AfterFunc(time.Minute, func() {
+		state := participant.State()
+		if state == livekit.ParticipantInfo_JOINING || state == livekit.ParticipantInfo_JOINED {
+			r.RemoveParticipantSession(participant, types.ParticipantCloseReasonJoinTimeout)
+		}
+	})
+
+	if err := participant.SendJoinResponse(proto.Clone(r.protoRoom).(*livekit.Room), otherParticipants, roomState, iceServers, region); err != nil {
+		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "send_response").Add(1)
+		return err
+	}
+
+	participant.SetMigrateState(types.MigrateStateComplete)
+
+	if participant.SubscriberAsPrimary() {
+		// initiates sub connection as primary
+		if participant.ProtocolVersion().SupportFastStart() {
+			go func() {
+				r.subscribeToExistingTracks(participant)
+				participant.Negotiate(true)
+			}()
+		} else {
+			participant.Negotiate(true)
+		}
+	}
+
+	prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "success", "").Add(1)
+
+	return nil
+}
+
+// JoinBridge adds a participant without a signal connection, publishing tracks bridged from another room.
+// It does not subscribe to tracks of the room, and stays JOINED without ever becoming ACTIVE
+func (r *Room) JoinBridge(participant types.LocalParticipant) error {
+	r.lock.Lock()
+	if reason, err := r.checkJoinLocked(participant, nil); err != nil {
+		r.lock.Unlock()
+		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", reason).Add(1)
+		return err
+	}
+
+	r.setParticipantCallbacks(participant)
+	r.Logger.Infow("bridge participant joined",
+		"pID", participant.ID(),
+		"participant", participant.Identity())
+
+	r.addParticipantLocked(participant, &ParticipantOptions{AutoSubscribe: false, Bridge: true})
+	if r.onParticipantChanged != nil {
+		r.onParticipantChanged(participant)
+	}
+	protoRoom := proto.Clone(r.protoRoom).(*livekit.Room)
+	r.lock.Unlock()
+
+	if err := participant.SendJoinResponse(protoRoom, nil, nil, nil, ""); err != nil {
+		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "send_response").Add(1)
+		return err
+	}
+	participant.SetMigrateState(types.MigrateStateComplete)
+	participant.Start()
+
+	prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "success", "").Add(1)
+	return nil
+}
+
+// checkJoinLocked returns why the participant cannot join the room, along with the reason's metric label.
+// r.lock must be held
+func (r *Room) checkJoinLocked(participant types.LocalParticipant, opts *ParticipantOptions) (string, error) {
 	if r.IsClosed() {
-		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "room_closed").Add(1)
-		return ErrRoomClosed
+		return "room_closed", ErrRoomClosed
 	}
 
-	if r.participants[participant.Identity()] != nil {
-		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "already_joined").Add(1)
-		return ErrAlreadyJoined
+	if r.participants[participant.ID()] != nil ||
+		(opts == nil || !opts.Duplicate) && len(r.identities[participant.Identity()]) > 0 {
+		return "already_joined", ErrAlreadyJoined
 	}
 
 	if r.protoRoom.MaxParticipants > 0 && len(r.participants) >= int(r.protoRoom.MaxParticipants) {
-		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "max_exceeded").Add(1)
-		return ErrMaxParticipantsExceeded
+		return "max_exceeded", ErrMaxParticipantsExceeded
+	}
+
+	if r.internal.MaxPublishers > 0 && participant.CanPublish() && r.numPublishersLocked() >= int(r.internal.MaxPublishers) {
+		return "max_publishers_exceeded", ErrMaxPublishersExceeded
 	}
 
+	// locked rooms refuse new identities, hidden participants such as recorders may still join
+	if r.internal.Locked && !r.members[participant.Identity()] && !participant.Hidden() && !participant.IsRecorder() {
+		return "room_locked", ErrRoomLocked
+	}
+	return "", nil
+}
+
+// addParticipantLocked adds the participant to the room's state, r.lock must be held
+func (r *Room) addParticipantLocked(participant types.LocalParticipant, opts *ParticipantOptions) {
 	if r.FirstJoinedAt() == 0 {
 		r.joinedAt.Store(time.Now().Unix())
 	}
@@ -222,7 +460,18 @@ func (r *Room) Join(participant types.LocalParticipant, opts *ParticipantOptions
 		r.protoRoom.NumParticipants++
 	}
 
-	// it's important to set this before connection, we don't want to miss out on any publishedTracks
+	if participant.IsRecorder() && !r.protoRoom.ActiveRecording {
+		r.protoRoom.ActiveRecording = true
+		r.sendRoomUpdateLocked()
+	}
+
+	r.participants[participant.ID()] = participant
+	r.participantOpts[participant.ID()] = opts
+	r.identities[participant.Identity()] = append(r.identities[participant.Identity()], participant.ID())
+	r.members[participant.Identity()] = true
+}
+
+func (r *Room) setParticipantCallbacks(participant types.LocalParticipant) {
 	participant.OnTrackPublished(r.onTrackPublished)
 	participant.OnStateChange(func(p types.LocalParticipant, oldState livekit.ParticipantInfo_State) {
 		r.Logger.Debugw("participant state changed",
@@ -239,6 +488,8 @@ func (r *Room) Join(participant types.LocalParticipant, opts *ParticipantOptions
 		if state == livekit.ParticipantInfo_ACTIVE {
 			// subscribe participant to existing publishedTracks
 			r.subscribeToExistingTracks(p)
+			r.sendStateSnapshot(p)
+			r.replayDataHistory(p)
 
 			// start the workers once connectivity is established
 			p.Start()
@@ -246,12 +497,14 @@ func (r *Room) Join(participant types.LocalParticipant, opts *ParticipantOptions
 			r.telemetry.ParticipantActive(context.Background(), r.ToProto(), p.ToProto(), &livekit.AnalyticsClientMeta{ClientConnectTime: uint32(time.Since(p.ConnectedAt()).Milliseconds())})
 		} else if state == livekit.ParticipantInfo_DISCONNECTED {
 			// remove participant from room
-			go r.RemoveParticipant(p.Identity(), types.ParticipantCloseReasonStateDisconnected)
+			go r.RemoveParticipantSession(p, types.ParticipantCloseReasonStateDisconnected)
 		}
 	})
 	participant.OnTrackUpdated(r.onTrackUpdated)
 	participant.OnParticipantUpdate(r.onParticipantUpdate)
+	participant.OnAttributesUpdate(r.onAttributesUpdate)
 	participant.OnDataPacket(r.onDataPacket)
+	participant.OnDataThrottled(r.onDataThrottled)
 	participant.OnSubscribedTo(func(p types.LocalParticipant, publisherID livekit.ParticipantID) {
 		go func() {
 			// when a participant subscribes to another participant,
@@ -273,61 +526,6 @@ func (r *Room) Join(participant types.LocalParticipant, opts *ParticipantOptions
 			}
 		}()
 	})
-	r.Logger.Infow("new participant joined",
-		"pID", participant.ID(),
-		"participant", participant.Identity(),
-		"protocol", participant.ProtocolVersion(),
-		"options", opts)
-
-	if participant.IsRecorder() && !r.protoRoom.ActiveRecording {
-		r.protoRoom.ActiveRecording = true
-		r.sendRoomUpdateLocked()
-	}
-
-	r.participants[participant.Identity()] = participant
-	r.participantOpts[participant.Identity()] = opts
-
-	// gather other participants and send join response
-	otherParticipants := make([]*livekit.ParticipantInfo, 0, len(r.participants))
-	for _, p := range r.participants {
-		if p.ID() != participant.ID() && !p.Hidden() {
-			otherParticipants = append(otherParticipants, p.ToProto())
-		}
-	}
-
-	if r.onParticipantChanged != nil {
-		r.onParticipantChanged(participant)
-	}
-
-	time.AfterFunc(time.Minute, func() {
-		state := participant.State()
-		if state == livekit.ParticipantInfo_JOINING || state == livekit.ParticipantInfo_JOINED {
-			r.RemoveParticipant(participant.Identity(), types.ParticipantCloseReasonJoinTimeout)
-		}
-	})
-
-	if err := participant.SendJoinResponse(proto.Clone(r.protoRoom).(*livekit.Room), otherParticipants, iceServers, region); err != nil {
-		prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "error", "send_response").Add(1)
-		return err
-	}
-
-	participant.SetMigrateState(types.MigrateStateComplete)
-
-	if participant.SubscriberAsPrimary() {
-		// initiates sub connection as primary
-		if participant.ProtocolVersion().SupportFastStart() {
-			go func() {
-				r.subscribeToExistingTracks(participant)
-				participant.Negotiate(true)
-			}()
-		} else {
-			participant.Negotiate(true)
-		}
-	}
-
-	prometheus.ServiceOperationCounter.WithLabelValues("participant_join", "success", "").Add(1)
-
-	return nil
 }
 
 func (r *Room) ResumeParticipant(p types.LocalParticipant, responseSink routing.MessageSink) error {
@@ -348,36 +546,34 @@ func (r *Room) ResumeParticipant(p types.LocalParticipant, responseSink routing.
 	return nil
 }
 
+// RemoveParticipant removes every session of identity
 func (r *Room) RemoveParticipant(identity livekit.ParticipantIdentity, reason types.ParticipantCloseReason) {
-	r.lock.Lock()
-	p, ok := r.participants[identity]
-	if ok {
-		delete(r.participants, identity)
-		delete(r.participantOpts, identity)
-		if !p.Hidden() {
-			r.protoRoom.NumParticipants--
-		}
+	for _, p := range r.GetParticipantSessions(identity) {
+		r.RemoveParticipantSession(p, reason)
 	}
+}
 
-	activeRecording := false
-	if (p != nil && p.IsRecorder()) || p == nil && r.protoRoom.ActiveRecording {
-		for _, op := range r.participants {
-			if op.IsRecorder() {
-				activeRecording = true
-				break
-			}
+// RemoveParticipantSession removes the session of a participant, other sessions of its identity stay in the room
+func (r *Room) RemoveParticipantSession(p types.LocalParticipant, reason types.ParticipantCloseReason) {
+	identity := p.Identity()
+	r.lock.Lock()
+	ok := r.removeParticipantLocked(p)
+	withdrawn := false
+	// the identity keeps its publish request and membership while any of its sessions remains
+	if len(r.identities[identity]) == 0 {
+		withdrawn = r.dequeuePublishRequestLocked(identity)
+		if ok && leavesRoom(reason) {
+			delete(r.members, identity)
 		}
 	}
-
-	if r.protoRoom.ActiveRecording != activeRecording {
-		r.protoRoom.ActiveRecording = activeRecording
-		r.sendRoomUpdateLocked()
-	}
 	r.lock.Unlock()
 
 	if !ok {
 		return
 	}
+	if withdrawn {
+		r.sendStageMessage(StageEventWithdrawn, identity, nil)
+	}
 
 	// send broadcast only if it's not already closed
 	sendUpdates := p.State() != livekit.ParticipantInfo_DISCONNECTED
@@ -386,9 +582,12 @@ func (r *Room) RemoveParticipant(identity livekit.ParticipantIdentity, reason ty
 	p.OnTrackPublished(nil)
 	p.OnStateChange(nil)
 	p.OnParticipantUpdate(nil)
+	p.OnAttributesUpdate(nil)
 	p.OnDataPacket(nil)
 	p.OnSubscribedTo(nil)
 
+	r.forgetJoinState(p)
+
 	// close participant as well
 	r.Logger.Infow("closing participant for removal", "pID", p.ID(), "participant", p.Identity())
 	_ = p.Close(true, reason)
@@ -407,6 +606,176 @@ func (r *Room) RemoveParticipant(identity livekit.ParticipantIdentity, reason ty
 	}
 }
 
+// leavesRoom returns whether a participant removed for reason is no longer a member of the room, as opposed to
+// one that lost its connection or was replaced by a session with its identity
+func leavesRoom(reason types.ParticipantCloseReason) bool {
+	switch reason {
+	case types.ParticipantCloseReasonClientRequestLeave,
+		types.ParticipantCloseReasonServiceRequestRemoveParticipant,
+		types.ParticipantCloseReasonDataLimitExceeded,
+		types.ParticipantCloseReasonSignalLimitExceeded:
+		return true
+	}
+	return false
+}
+
+// removeParticipantLocked removes the participant from the room's state, returning whether it was in the room.
+// r.lock must be held
+func (r *Room) removeParticipantLocked(p types.LocalParticipant) bool {
+	ok := r.participants[p.ID()] == p
+	if ok {
+		delete(r.participants, p.ID())
+		delete(r.participantOpts, p.ID())
+		sids := r.identities[p.Identity()]
+		for i, sid := range sids {
+			if sid == p.ID() {
+				sids = append(sids[:i:i], sids[i+1:]...)
+				break
+			}
+		}
+		if len(sids) == 0 {
+			delete(r.identities, p.Identity())
+		} else {
+			r.identities[p.Identity()] = sids
+		}
+		if !p.Hidden() {
+			r.protoRoom.NumParticipants--
+		}
+	}
+
+	activeRecording := false
+	if p.IsRecorder() || !ok && r.protoRoom.ActiveRecording {
+		for _, op := range r.participants {
+			if op.IsRecorder() {
+				activeRecording = true
+				break
+			}
+		}
+	}
+
+	if r.protoRoom.ActiveRecording != activeRecording {
+		r.protoRoom.ActiveRecording = activeRecording
+		r.sendRoomUpdateLocked()
+	}
+	return ok
+}
+
+// MoveParticipant re-homes an active participant into dest, keeping its peer connections.
+// participants of this room see it leave, while those of dest see it join with its published tracks.
+// its subscriptions to tracks of this room are replaced with subscriptions to tracks of dest
+func (r *Room) MoveParticipant(identity livekit.ParticipantIdentity, dest *Room) error {
+	r.lock.RLock()
+	participant := r.participantLocked(identity)
+	var opts *ParticipantOptions
+	if participant != nil {
+		opts = r.participantOpts[participant.ID()]
+	}
+	r.lock.RUnlock()
+
+	if participant == nil {
+		return ErrParticipantNotFound
+	}
+	if participant.State() != livekit.ParticipantInfo_ACTIVE {
+		return ErrParticipantNotActive
+	}
+
+	// take the participant's place in dest first, so it's never left without a room
+	dest.lock.Lock()
+	if _, err := dest.checkJoinLocked(participant, opts); err != nil {
+		dest.lock.Unlock()
+		return err
+	}
+	dest.setParticipantCallbacks(participant)
+	dest.addParticipantLocked(participant, opts)
+	dest.lock.Unlock()
+
+	r.lock.Lock()
+	ok := r.removeParticipantLocked(participant)
+	withdrawn := false
+	if len(r.identities[identity]) == 0 {
+		withdrawn = r.dequeuePublishRequestLocked(identity)
+		if ok {
+			delete(r.members, identity)
+		}
+	}
+	empty := len(r.participants) == 0
+	r.lock.Unlock()
+	if !ok {
+		// participant left this room in the meantime
+		dest.lock.Lock()
+		dest.removeParticipantLocked(participant)
+		dest.lock.Unlock()
+		return ErrParticipantNotFound
+	}
+	if empty {
+		r.leftAt.Store(time.Now().Unix())
+	}
+	if withdrawn {
+		r.sendStageMessage(StageEventWithdrawn, identity, nil)
+	}
+
+	r.Logger.Infow("moving participant",
+		"pID", participant.ID(),
+		"participant", participant.Identity(),
+		"destination", dest.Name(),
+		"destinationID", dest.ID())
+
+	// stop exchanging media with participants of this room
+	for _, track := range participant.GetPublishedTracks() {
+		track.RemoveAllSubscribers(false)
+	}
+	for _, st := range participant.GetSubscribedTracks() {
+		if pub := r.GetParticipantBySid(st.PublisherID()); pub != nil {
+			pub.RemoveSubscriber(participant, st.ID(), false)
+		}
+	}
+
+	// to participants of this room, the moved participant has left, and the other way around
+	left := make([]*livekit.ParticipantInfo, 0)
+	for _, op := range r.GetParticipants() {
+		if !op.Hidden() {
+			pi := op.ToProto()
+			pi.State = livekit.ParticipantInfo_DISCONNECTED
+			left = append(left, pi)
+		}
+	}
+	if !participant.Hidden() {
+		pi := participant.ToProto()
+		pi.State = livekit.ParticipantInfo_DISCONNECTED
+		r.sendParticipantUpdates(r.pushAndDequeueUpdates(pi, true))
+	}
+
+	dest.moveInParticipant(participant, left)
+	return nil
+}
+
+// moveInParticipant announces a participant moved from another room, and links its media with participants of
+// the room. left are the participants of its previous room, which it no longer sees
+func (r *Room) moveInParticipant(participant types.LocalParticipant, left []*livekit.ParticipantInfo) {
+	updates := left
+	for _, op := range r.GetParticipants() {
+		if op.ID() != participant.ID() && !op.Hidden() {
+			updates = append(updates, op.ToProto())
+		}
+	}
+	if err := participant.SendRoomUpdate(r.ToProto()); err != nil {
+		r.Logger.Warnw("could not send room update to moved participant", err, "participant", participant.Identity())
+	}
+	if err := participant.SendParticipantUpdate(updates); err != nil {
+		r.Logger.Warnw("could not send participant update to moved participant", err, "participant", participant.Identity())
+	}
+
+	r.broadcastParticipantState(participant, broadcastOptions{skipSource: true, immediate: true})
+	if r.onParticipantChanged != nil {
+		r.onParticipantChanged(participant)
+	}
+
+	for _, track := range participant.GetPublishedTracks() {
+		r.onTrackPublished(participant, track)
+	}
+	r.subscribeToExistingTracks(participant)
+}
+
 func (r *Room) UpdateSubscriptions(
 	participant types.LocalParticipant,
 	trackIDs []livekit.TrackID,
@@ -507,8 +876,14 @@ func (r *Room) CloseIfEmpty() {
 		return
 	}
 
-	for _, p := range r.participants {
-		if !p.IsRecorder() {
+	// participants of breakout rooms return to their parent, which stays open until the breakout rooms close
+	if len(r.internal.Breakouts) > 0 {
+		r.lock.Unlock()
+		return
+	}
+
+	for sid, p := range r.participants {
+		if opts := r.participantOpts[sid]; !p.IsRecorder() && (opts == nil || !opts.Bridge) {
 			r.lock.Unlock()
 			return
 		}
@@ -544,6 +919,7 @@ func (r *Room) Close() {
 	close(r.closed)
 	r.lock.Unlock()
 	r.Logger.Infow("closing room")
+	r.stopStateTimers()
 	for _, p := range r.GetParticipants() {
 		_ = p.Close(true, types.ParticipantCloseReasonRoomClose)
 	}
@@ -570,18 +946,55 @@ func (r *Room) SendDataPacket(up *livekit.UserPacket, kind livekit.DataPacket_Ki
 	r.onDataPacket(nil, dp)
 }
 
-func (r *Room) SetMetadata(metadata string) {
+// SendServerDataPacket sends a data packet originating from the server itself, such as a warning, to participants
+// of the room. Unlike SendDataPacket, it's never retained in the room's data history
+func (r *Room) SendServerDataPacket(up *livekit.UserPacket, kind livekit.DataPacket_Kind) {
+	_ = r.forwardDataPacket(nil, &livekit.DataPacket{
+		Kind: kind,
+		Value: &livekit.DataPacket_User{
+			User: up,
+		},
+	}, nil)
+}
+
+// sendJSONMessage sends a participant a data message from the server, see SendJSONMessage
+func (r *Room) sendJSONMessage(participant types.LocalParticipant, msg interface{}) {
+	if err := SendJSONMessage(participant, msg); err != nil {
+		r.Logger.Infow("could not send data message", "error", err, "participant", participant.Identity())
+	}
+}
+
+// SetMetadata replaces the room's metadata and increments its version, returning the new version. When ifVersion is
+// set, the update is rejected unless metadata is still at that version. Updates are serialized along with the
+// callback set by OnMetadataUpdate, so that they're persisted in order
+func (r *Room) SetMetadata(metadata string, ifVersion *uint32) (uint32, error) {
+	r.metadataLock.Lock()
+	defer r.metadataLock.Unlock()
+
 	r.lock.Lock()
+	if ifVersion != nil && *ifVersion != r.metadataVersion {
+		version := r.metadataVersion
+		r.lock.Unlock()
+		return version, ErrMetadataChanged
+	}
 	r.protoRoom.Metadata = metadata
-	r.lock.Unlock()
-
-	r.lock.RLock()
+	r.metadataVersion++
+	version := r.metadataVersion
 	r.sendRoomUpdateLocked()
-	r.lock.RUnlock()
+	onMetadataUpdate := r.onMetadataUpdate
+	r.lock.Unlock()
 
-	if r.onMetadataUpdate != nil {
-		r.onMetadataUpdate(metadata)
+	if onMetadataUpdate != nil {
+		onMetadataUpdate(metadata, version)
 	}
+	return version, nil
+}
+
+// MetadataVersion returns the version of the room's metadata, incremented on every update
+func (r *Room) MetadataVersion() uint32 {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.metadataVersion
 }
 
 func (r *Room) sendRoomUpdateLocked() {
@@ -598,7 +1011,7 @@ func (r *Room) sendRoomUpdateLocked() {
 	}
 }
 
-func (r *Room) OnMetadataUpdate(f func(metadata string)) {
+func (r *Room) OnMetadataUpdate(f func(metadata string, version uint32)) {
 	r.onMetadataUpdate = f
 }
 
@@ -655,7 +1068,7 @@ func (r *Room) autoSubscribe(participant types.LocalParticipant) bool {
 		return false
 	}
 
-	opts := r.participantOpts[participant.Identity()]
+	opts := r.participantOpts[participant.ID()]
 	// default to true if no options are set
 	if opts != nil && !opts.AutoSubscribe {
 		return false
@@ -665,6 +1078,11 @@ func (r *Room) autoSubscribe(participant types.LocalParticipant) bool {
 
 // a ParticipantImpl in the room added a new remoteTrack, subscribe other participants to it
 func (r *Room) onTrackPublished(participant types.LocalParticipant, track types.MediaTrack) {
+	if !r.moderateOnEntry(participant, track) {
+		// unpublished right away
+		return
+	}
+
 	// publish participant update, since track state is changed
 	r.broadcastParticipantState(participant, broadcastOptions{skipSource: true})
 
@@ -718,9 +1136,53 @@ func (r *Room) onParticipantUpdate(p types.LocalParticipant) {
 	}
 }
 
+func (r *Room) onAttributesUpdate(p types.LocalParticipant, changed map[string]string, version uint32) {
+	// participants are sent only the keys that changed, and stored with all of them
+	pi := p.ToProto()
+	SetParticipantAttributesDelta(pi, changed, version)
+	r.broadcastParticipantInfo(p, pi, broadcastOptions{immediate: true})
+	if r.onParticipantChanged != nil {
+		r.onParticipantChanged(p)
+	}
+}
+
 func (r *Room) onDataPacket(source types.LocalParticipant, dp *livekit.DataPacket) {
-	dest := dp.GetUser().GetDestinationSids()
+	if dp.Kind != livekit.DataPacket_RELIABLE {
+		r.forwardDataPacket(source, dp, nil)
+		return
+	}
 
+	if !r.historyEnabled.Load() {
+		r.forwardDataPacket(source, dp, nil)
+		return
+	}
+
+	// forward holding the lock, so that the history is in the order participants receive messages
+	r.historyLock.Lock()
+	var msg *types.DataMessage
+	if r.forwardDataPacket(source, dp, r.replaying) {
+		msg = r.retainDataPacketLocked(source, dp)
+	}
+	onDataRetained := r.onDataRetained
+	r.historyLock.Unlock()
+
+	if msg != nil && onDataRetained != nil {
+		onDataRetained(msg)
+	}
+}
+
+// forwardDataPacket sends a data packet to the participants it's meant for, returning false when its source isn't
+// allowed to publish it. Participants in replaying get the packet appended instead, historyLock must be held when
+// it's set
+func (r *Room) forwardDataPacket(source types.LocalParticipant, dp *livekit.DataPacket, replaying map[livekit.ParticipantID][]*livekit.DataPacket) bool {
+	topic, dest := dataTopic(dp.GetUser())
+	if topic != "" && source != nil && !source.CanPublishDataTopic(topic) {
+		source.GetLogger().Infow("dropping data packet", "error", ErrTopicNotAllowed, "topic", topic)
+		prometheus.AddDataTopicDropped(r.dataTopicClass(topic))
+		return false
+	}
+
+	forwarded := 0
 	for _, op := range r.GetParticipants() {
 		if op.State() != livekit.ParticipantInfo_ACTIVE {
 			continue
@@ -728,6 +1190,9 @@ func (r *Room) onDataPacket(source types.LocalParticipant, dp *livekit.DataPacke
 		if source != nil && op.ID() == source.ID() {
 			continue
 		}
+		if topic != "" && !op.IsSubscribedToDataTopic(topic) {
+			continue
+		}
 		if len(dest) > 0 {
 			found := false
 			for _, dID := range dest {
@@ -740,11 +1205,22 @@ func (r *Room) onDataPacket(source types.LocalParticipant, dp *livekit.DataPacke
 				continue
 			}
 		}
+		if pending, ok := replaying[op.ID()]; ok {
+			replaying[op.ID()] = append(pending, dp)
+			forwarded++
+			continue
+		}
 		err := op.SendDataPacket(dp)
 		if err != nil {
 			r.Logger.Infow("send data packet error", "error", err, "participant", op.Identity())
+			continue
 		}
+		forwarded++
 	}
+	if topic != "" {
+		r.countDataTopicPacket(topic, len(dp.GetUser().GetPayload()), forwarded)
+	}
+	return true
 }
 
 func (r *Room) subscribeToExistingTracks(p types.LocalParticipant) int {
@@ -780,8 +1256,10 @@ func (r *Room) subscribeToExistingTracks(p types.LocalParticipant) int {
 
 // broadcast an update about participant p
 func (r *Room) broadcastParticipantState(p types.LocalParticipant, opts broadcastOptions) {
-	pi := p.ToProto()
+	r.broadcastParticipantInfo(p, p.ToProto(), opts)
+}
 
+func (r *Room) broadcastParticipantInfo(p types.LocalParticipant, pi *livekit.ParticipantInfo, opts broadcastOptions) {
 	if p.Hidden() {
 		if !opts.skipSource {
 			// send update only to hidden participant
@@ -850,7 +1328,11 @@ func (r *Room) pushAndDequeueUpdates(pi *livekit.ParticipantInfo, isImmediate bo
 	defer r.batchedUpdatesMu.Unlock()
 
 	var updates []*livekit.ParticipantInfo
+	// sessions of an identity joined several times are batched apart, by the suffix of their SID
 	identity := livekit.ParticipantIdentity(pi.Identity)
+	if i := strings.Index(pi.Sid, "#"); i >= 0 {
+		identity += livekit.ParticipantIdentity(pi.Sid[i:])
+	}
 	existing := r.batchedUpdates[identity]
 	shouldSend := isImmediate || pi.IsPublisher
 
@@ -1,6 +1,9 @@
 package rtc
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -8,8 +11,10 @@ import (
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils"
 	"github.com/livekit/protocol/webhook"
 
 	"github.com/livekit/livekit-server/pkg/config"
@@ -71,10 +76,10 @@ func TestRoomJoin(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: numParticipants})
 		pNew := newMockParticipant("new", types.DefaultProtocol, false, false)
 
-		_ = rm.Join(pNew, nil, iceServersForRoom, "test")
+		_ = rm.Join(context.Background(), pNew, nil, iceServersForRoom, "test")
 
 		// expect new participant to get a JoinReply
-		info, participants, iceServers, _ := pNew.SendJoinResponseArgsForCall(0)
+		info, participants, _, iceServers, _ := pNew.SendJoinResponseArgsForCall(0)
 		require.Equal(t, livekit.RoomID(info.Sid), rm.ID())
 		require.Len(t, participants, numParticipants)
 		require.Len(t, rm.GetParticipants(), numParticipants+1)
@@ -86,7 +91,7 @@ func TestRoomJoin(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: numExisting})
 		p := newMockParticipant("new", types.DefaultProtocol, false, false)
 
-		err := rm.Join(p, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, "")
+		err := rm.Join(context.Background(), p, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, "")
 		require.NoError(t, err)
 
 		stateChangeCB := p.OnStateChangeArgsForCall(0)
@@ -142,9 +147,624 @@ func TestRoomJoin(t *testing.T) {
 		rm.protoRoom.MaxParticipants = 1
 		p := newMockParticipant("second", types.ProtocolVersion(0), false, false)
 
-		err := rm.Join(p, nil, iceServersForRoom, "")
+		err := rm.Join(context.Background(), p, nil, iceServersForRoom, "")
 		require.Equal(t, ErrMaxParticipantsExceeded, err)
 	})
+
+	t.Run("cannot exceed max publishers", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		rm.internal.MaxPublishers = 1
+
+		p := newMockParticipant("publisher", types.ProtocolVersion(0), false, true)
+		err := rm.Join(context.Background(), p, nil, iceServersForRoom, "")
+		require.Equal(t, ErrMaxPublishersExceeded, err)
+
+		// subscribers can still join
+		p = newMockParticipant("subscriber", types.ProtocolVersion(0), false, false)
+		p.CanPublishReturns(false)
+		err = rm.Join(context.Background(), p, nil, iceServersForRoom, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("locked room admits members only", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 3})
+		p0 := rm.GetParticipant("p0")
+		rm.SetInternal(&types.RoomInternal{Locked: true})
+		require.True(t, RoomLocked(rm.ToProto()))
+		require.Equal(t, 1, p0.(*typesfakes.FakeLocalParticipant).SendRoomUpdateCallCount())
+
+		err := rm.Join(context.Background(), newMockParticipant("new", types.DefaultProtocol, false, false), nil, iceServersForRoom, "")
+		require.Equal(t, ErrRoomLocked, err)
+		// hidden participants join regardless
+		err = rm.Join(context.Background(), newMockParticipant("hidden", types.DefaultProtocol, true, false), nil, iceServersForRoom, "")
+		require.NoError(t, err)
+
+		// participants that lost their connection rejoin, those that left don't
+		rm.RemoveParticipant("p1", types.ParticipantCloseReasonStateDisconnected)
+		rm.RemoveParticipant("p2", types.ParticipantCloseReasonClientRequestLeave)
+		err = rm.Join(context.Background(), newMockParticipant("p1", types.DefaultProtocol, false, false), nil, iceServersForRoom, "")
+		require.NoError(t, err)
+		err = rm.Join(context.Background(), newMockParticipant("p2", types.DefaultProtocol, false, false), nil, iceServersForRoom, "")
+		require.Equal(t, ErrRoomLocked, err)
+
+		// nor can participants be moved in
+		other := newRoomWithParticipants(t, testRoomOpts{num: 0})
+		require.NoError(t, other.Join(context.Background(), newMockParticipant("moved", types.DefaultProtocol, false, false), nil, iceServersForRoom, ""))
+		other.GetParticipant("moved").(*typesfakes.FakeLocalParticipant).StateReturns(livekit.ParticipantInfo_ACTIVE)
+		require.Equal(t, ErrRoomLocked, other.MoveParticipant("moved", rm))
+
+		rm.SetInternal(&types.RoomInternal{})
+		require.False(t, RoomLocked(rm.ToProto()))
+		err = rm.Join(context.Background(), newMockParticipant("p2", types.DefaultProtocol, false, false), nil, iceServersForRoom, "")
+		require.NoError(t, err)
+	})
+
+	t.Run("duplicate sessions join alongside with the same identity", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		p0 := rm.GetParticipant("p0")
+
+		err := rm.Join(context.Background(), newMockParticipant("p0", types.DefaultProtocol, false, false), nil, iceServersForRoom, "")
+		require.Equal(t, ErrAlreadyJoined, err)
+
+		sid := rm.DuplicateParticipantID(p0)
+		require.Equal(t, p0.ID()+"#2", sid)
+		dup := newMockParticipant("p0", types.DefaultProtocol, false, false)
+		dup.IDReturns(sid)
+		require.NoError(t, rm.Join(context.Background(), dup, &ParticipantOptions{Duplicate: true}, iceServersForRoom, ""))
+		require.Len(t, rm.GetParticipants(), 2)
+		require.Equal(t, p0.ID()+"#3", rm.DuplicateParticipantID(dup))
+
+		// sessions leave on their own, the identity's remaining one is still found by it
+		rm.RemoveParticipantSession(p0, types.ParticipantCloseReasonClientRequestLeave)
+		require.Equal(t, dup, rm.GetParticipant("p0"))
+		require.Equal(t, dup, rm.GetParticipantBySid(sid))
+		rm.RemoveParticipant("p0", types.ParticipantCloseReasonClientRequestLeave)
+		require.Empty(t, rm.GetParticipants())
+	})
+
+	t.Run("operations on an identity apply to each of its sessions", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1, numHidden: 1})
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+		dup := newMockParticipant("p1", types.DefaultProtocol, true, false)
+		dup.IDReturns(rm.DuplicateParticipantID(p1))
+		require.NoError(t, rm.Join(context.Background(), dup, &ParticipantOptions{Duplicate: true}, iceServersForRoom, ""))
+		require.Equal(t, []types.LocalParticipant{p1, dup}, rm.GetParticipantSessions("p1"))
+
+		require.NoError(t, rm.RequestPublish("p1"))
+		require.NoError(t, rm.ApprovePublish("p1"))
+		require.Equal(t, 1, p1.SetPermissionCallCount())
+		require.Equal(t, 1, dup.SetPermissionCallCount())
+		require.True(t, dup.SetPermissionArgsForCall(0).CanPublish)
+		// both sessions are told they were approved
+		require.Equal(t, 1, p1.SendDataPacketCallCount())
+		require.Equal(t, 1, dup.SendDataPacketCallCount())
+
+		p1.CanPublishReturns(true)
+		dup.CanPublishReturns(true)
+		require.NoError(t, rm.DemotePublisher("p1"))
+		require.Equal(t, 2, p1.SetPermissionCallCount())
+		require.Equal(t, 2, dup.SetPermissionCallCount())
+		require.False(t, dup.SetPermissionArgsForCall(1).CanPublish)
+
+		rm.RemoveParticipant("p1", types.ParticipantCloseReasonServiceRequestRemoveParticipant)
+		require.Empty(t, rm.GetParticipantSessions("p1"))
+		require.Nil(t, rm.GetParticipantBySid(dup.ID()))
+		require.Len(t, rm.GetParticipants(), 1)
+		require.Equal(t, 1, p1.CloseCallCount())
+		require.Equal(t, 1, dup.CloseCallCount())
+	})
+}
+
+func TestMoveParticipant(t *testing.T) {
+	t.Run("participant is moved with its tracks", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		dest := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		p := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+		track := &typesfakes.FakeMediaTrack{}
+		p.GetPublishedTracksReturns([]types.MediaTrack{track})
+		updateCount := p.SendParticipantUpdateCallCount()
+
+		require.NoError(t, rm.MoveParticipant("p1", dest))
+		require.Nil(t, rm.GetParticipant("p1"))
+		require.Equal(t, p, dest.GetParticipant("p1"))
+		require.Equal(t, uint32(1), rm.ToProto().NumParticipants)
+		require.Equal(t, uint32(2), dest.ToProto().NumParticipants)
+
+		// no longer published to the previous room
+		require.Equal(t, 1, track.RemoveAllSubscribersCallCount())
+		// subscribed to and by participants of the destination
+		require.Equal(t, 1, p.AddSubscriberCallCount())
+		destP := dest.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		require.Equal(t, 1, destP.AddSubscriberCallCount())
+
+		// sees the destination in place of the previous room
+		require.Equal(t, 1, p.SendRoomUpdateCallCount())
+		updates := p.SendParticipantUpdateArgsForCall(updateCount)
+		require.Len(t, updates, 2)
+		require.Equal(t, rm.GetParticipant("p0").ID(), livekit.ParticipantID(updates[0].Sid))
+		require.Equal(t, livekit.ParticipantInfo_DISCONNECTED, updates[0].State)
+		require.Equal(t, destP.ID(), livekit.ParticipantID(updates[1].Sid))
+	})
+
+	t.Run("cannot move to a room with the same identity", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		dest := newRoomWithParticipants(t, testRoomOpts{num: 1})
+
+		require.Equal(t, ErrAlreadyJoined, rm.MoveParticipant("p0", dest))
+		require.NotNil(t, rm.GetParticipant("p0"))
+	})
+
+	t.Run("only active participants can be moved", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		dest := newRoomWithParticipants(t, testRoomOpts{num: 0})
+		rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant).StateReturns(livekit.ParticipantInfo_JOINED)
+
+		require.Equal(t, ErrParticipantNotActive, rm.MoveParticipant("p0", dest))
+		require.Equal(t, ErrParticipantNotFound, rm.MoveParticipant("unknown", dest))
+	})
+}
+
+func TestStage(t *testing.T) {
+	t.Run("requests are queued in order until moderated", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1, numHidden: 2})
+		moderator := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		moderator.ClaimGrantsReturns(&auth.ClaimGrants{Video: &auth.VideoGrant{RoomAdmin: true}})
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+		p1.ToProtoReturns(&livekit.ParticipantInfo{Permission: &livekit.ParticipantPermission{CanSubscribe: true}})
+
+		require.NoError(t, rm.RequestPublish("p1"))
+		require.NoError(t, rm.RequestPublish("p2"))
+		require.NoError(t, rm.RequestPublish("p1"))
+		require.Equal(t, []livekit.ParticipantIdentity{"p1", "p2"}, rm.StageQueue())
+		require.Equal(t, 2, moderator.SendDataPacketCallCount())
+		require.Equal(t, ErrAlreadyPublisher, rm.RequestPublish("p0"))
+
+		require.NoError(t, rm.ApprovePublish("p1"))
+		require.Equal(t, 1, p1.SetPermissionCallCount())
+		require.True(t, proto.Equal(&livekit.ParticipantPermission{CanSubscribe: true, CanPublish: true}, p1.SetPermissionArgsForCall(0)))
+		require.Equal(t, 1, p1.SendDataPacketCallCount())
+		require.Equal(t, ErrNoPublishRequest, rm.DenyPublish("p1"))
+
+		rm.RemoveParticipant("p2", types.ParticipantCloseReasonClientRequestLeave)
+		require.Empty(t, rm.StageQueue())
+	})
+
+	t.Run("requests and moderation are sent over signaling", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1, numHidden: 1})
+		moderator := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		moderator.ClaimGrantsReturns(&auth.ClaimGrants{Video: &auth.VideoGrant{RoomAdmin: true}})
+		moderator.AllowSignalRequestReturns(true)
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+		p1.AllowSignalRequestReturns(true)
+		p1.ToProtoReturns(&livekit.ParticipantInfo{Permission: &livekit.ParticipantPermission{CanSubscribe: true}})
+		signal := func(p types.LocalParticipant, action types.StageAction, identity livekit.ParticipantIdentity) {
+			req := &livekit.SignalRequest{}
+			SetSignalStage(req, action, identity)
+			require.NoError(t, HandleParticipantSignal(rm, p, req, logger.GetDefaultLogger()))
+		}
+
+		// requests apply to the participant sending them, whatever identity they name
+		signal(p1, types.StageActionRequest, "p0")
+		require.Equal(t, []livekit.ParticipantIdentity{"p1"}, rm.StageQueue())
+		// only room admins moderate
+		signal(p1, types.StageActionApprove, "p1")
+		require.Zero(t, p1.SetPermissionCallCount())
+
+		signal(moderator, types.StageActionApprove, "p1")
+		require.Equal(t, 1, p1.SetPermissionCallCount())
+		require.True(t, p1.SetPermissionArgsForCall(0).CanPublish)
+		require.Empty(t, rm.StageQueue())
+	})
+
+	t.Run("approvals respect max publishers", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1, numHidden: 1})
+		rm.SetInternal(&types.RoomInternal{MaxPublishers: 1})
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+
+		require.NoError(t, rm.RequestPublish("p1"))
+		require.Equal(t, ErrMaxPublishersExceeded, rm.ApprovePublish("p1"))
+		require.Equal(t, 0, p1.SetPermissionCallCount())
+		// the request stays queued until a publisher leaves the stage
+		require.Equal(t, []livekit.ParticipantIdentity{"p1"}, rm.StageQueue())
+
+		require.NoError(t, rm.DemotePublisher("p0"))
+		rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant).CanPublishReturns(false)
+		require.NoError(t, rm.ApprovePublish("p1"))
+		require.Equal(t, 1, p1.SetPermissionCallCount())
+		require.Empty(t, rm.StageQueue())
+	})
+}
+
+func TestRoomState(t *testing.T) {
+	set := func(value string) *RoomStateUpdate {
+		return &RoomStateUpdate{Set: map[string]*RoomStateValue{"key": {Value: value}}}
+	}
+
+	t.Run("keys are only writable by their owner and admins unless shared", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+
+		state, err := rm.UpdateState("p0", set("v0"), 0)
+		require.NoError(t, err)
+		require.Equal(t, &types.RoomStateEntry{Value: "v0", Owner: "p0"}, state["key"])
+		require.Equal(t, 1, p0.SendDataPacketCallCount())
+
+		_, err = rm.UpdateState("p1", set("v1"), 0)
+		require.Equal(t, ErrStateKeyNotWritable, err)
+		_, err = rm.UpdateState("p1", &RoomStateUpdate{Delete: []string{"key"}}, 0)
+		require.Equal(t, ErrStateKeyNotWritable, err)
+
+		// admins keep the key's owner
+		state, err = rm.UpdateState("", &RoomStateUpdate{Set: map[string]*RoomStateValue{"key": {Value: "admin", Shared: true}}}, 0)
+		require.NoError(t, err)
+		require.Equal(t, &types.RoomStateEntry{Value: "admin", Owner: "p0", Shared: true}, state["key"])
+
+		// other participants can't unshare it
+		state, err = rm.UpdateState("p1", set("v1"), 0)
+		require.NoError(t, err)
+		require.Equal(t, &types.RoomStateEntry{Value: "v1", Owner: "p0", Shared: true}, state["key"])
+
+		state, err = rm.UpdateState("p1", &RoomStateUpdate{Delete: []string{"key"}}, 0)
+		require.NoError(t, err)
+		require.Empty(t, state)
+		require.Equal(t, 4, p0.SendDataPacketCallCount())
+	})
+
+	t.Run("size limits apply to the whole state", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+
+		_, err := rm.UpdateState("p0", set("0123456789"), 16)
+		require.NoError(t, err)
+		_, err = rm.UpdateState("p0", &RoomStateUpdate{Set: map[string]*RoomStateValue{"other": {Value: "value"}}}, 16)
+		require.Equal(t, ErrStateTooLarge, err)
+		// replaced and deleted keys don't count
+		_, err = rm.UpdateState("p0", &RoomStateUpdate{
+			Set:    map[string]*RoomStateValue{"other": {Value: "value"}},
+			Delete: []string{"key"},
+		}, 16)
+		require.NoError(t, err)
+		require.Len(t, rm.State(), 1)
+
+		_, err = rm.UpdateState("p0", &RoomStateUpdate{Set: map[string]*RoomStateValue{"": {Value: "value"}}}, 0)
+		require.Equal(t, ErrEmptyStateKey, err)
+	})
+
+	t.Run("keys expire after their ttl", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+
+		_, err := rm.UpdateState("p0", &RoomStateUpdate{Set: map[string]*RoomStateValue{"key": {Value: "value", TTL: 10 * time.Millisecond}}}, 0)
+		require.NoError(t, err)
+		require.NotZero(t, rm.State()["key"].ExpiresAt)
+		require.Eventually(t, func() bool {
+			return len(rm.State()) == 0
+		}, time.Second, 5*time.Millisecond)
+		require.Eventually(t, func() bool {
+			return p0.SendDataPacketCallCount() == 2
+		}, time.Second, 5*time.Millisecond)
+
+		// setting a key again resets its ttl
+		_, err = rm.UpdateState("p0", &RoomStateUpdate{Set: map[string]*RoomStateValue{"key": {Value: "value", TTL: 10 * time.Millisecond}}}, 0)
+		require.NoError(t, err)
+		_, err = rm.UpdateState("p0", set("kept"), 0)
+		require.NoError(t, err)
+		time.Sleep(30 * time.Millisecond)
+		require.Equal(t, "kept", rm.State()["key"].Value)
+	})
+
+	t.Run("keys set in one update expire on their own", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+
+		_, err := rm.UpdateState("p0", &RoomStateUpdate{Set: map[string]*RoomStateValue{
+			"short": {Value: "value", TTL: 10 * time.Millisecond},
+			"long":  {Value: "value", TTL: 50 * time.Millisecond},
+			"kept":  {Value: "value"},
+		}}, 0)
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			_, ok := rm.State()["short"]
+			return !ok
+		}, time.Second, 5*time.Millisecond)
+		require.Contains(t, rm.State(), "long")
+		require.Eventually(t, func() bool {
+			return len(rm.State()) == 1
+		}, time.Second, 5*time.Millisecond)
+		require.Contains(t, rm.State(), "kept")
+	})
+
+	t.Run("refused joins forget the state sent to them", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		rm.protoRoom.MaxParticipants = 1
+
+		pRefused := newMockParticipant("refused", types.DefaultProtocol, false, false)
+		err := rm.Join(context.Background(), pRefused, nil, iceServersForRoom, "")
+		require.Equal(t, ErrMaxParticipantsExceeded, err)
+		require.NotContains(t, rm.stateSent, pRefused.ID())
+
+		rm.protoRoom.MaxParticipants = 0
+		pFailed := newMockParticipant("failed", types.DefaultProtocol, false, false)
+		pFailed.SendJoinResponseReturns(errors.New("could not send"))
+		require.Error(t, rm.Join(context.Background(), pFailed, nil, iceServersForRoom, ""))
+		require.NotContains(t, rm.stateSent, pFailed.ID())
+	})
+
+	t.Run("joining participants receive the state in their join response", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		_, err := rm.UpdateState("p0", set("v0"), 0)
+		require.NoError(t, err)
+
+		// no snapshot once active when the state didn't change since joining
+		pNew := newMockParticipant("new", types.DefaultProtocol, false, false)
+		require.NoError(t, rm.Join(context.Background(), pNew, nil, iceServersForRoom, ""))
+		_, _, state, _, _ := pNew.SendJoinResponseArgsForCall(0)
+		require.Equal(t, &types.RoomStateEntry{Value: "v0", Owner: "p0"}, state["key"])
+		pNew.StateReturns(livekit.ParticipantInfo_ACTIVE)
+		pNew.OnStateChangeArgsForCall(0)(pNew, livekit.ParticipantInfo_JOINED)
+		require.Zero(t, pNew.SendDataPacketCallCount())
+
+		// changes made before becoming active are sent as a snapshot
+		pLate := newMockParticipant("late", types.DefaultProtocol, false, false)
+		require.NoError(t, rm.Join(context.Background(), pLate, nil, iceServersForRoom, ""))
+		_, err = rm.UpdateState("p0", set("v1"), 0)
+		require.NoError(t, err)
+		require.Zero(t, pLate.SendDataPacketCallCount())
+		pLate.StateReturns(livekit.ParticipantInfo_ACTIVE)
+		pLate.OnStateChangeArgsForCall(0)(pLate, livekit.ParticipantInfo_JOINED)
+		require.Equal(t, 1, pLate.SendDataPacketCallCount())
+		msg := &RoomStateMessage{}
+		require.NoError(t, json.Unmarshal(pLate.SendDataPacketArgsForCall(0).GetUser().Payload, msg))
+		require.True(t, msg.Snapshot)
+		require.Equal(t, "v1", msg.Entries["key"].Value)
+	})
+}
+
+func TestDataTopics(t *testing.T) {
+	topicPacket := func(p types.LocalParticipant, topic string, dest ...string) *livekit.DataPacket {
+		up := &livekit.UserPacket{
+			ParticipantSid:  string(p.ID()),
+			Payload:         []byte("message"),
+			DestinationSids: dest,
+		}
+		SetUserPacketTopic(up, topic)
+		return &livekit.DataPacket{
+			Kind:  livekit.DataPacket_RELIABLE,
+			Value: &livekit.DataPacket_User{User: up},
+		}
+	}
+
+	t.Run("data is only forwarded to subscribers of its topic", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 4})
+		defer rm.Close()
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+		p2 := rm.GetParticipant("p2").(*typesfakes.FakeLocalParticipant)
+		p3 := rm.GetParticipant("p3").(*typesfakes.FakeLocalParticipant)
+		p0.CanPublishDataTopicReturns(true)
+		p1.IsSubscribedToDataTopicReturns(true)
+		p2.IsSubscribedToDataTopicReturns(true)
+
+		p0.OnDataPacketArgsForCall(0)(p0, topicPacket(p0, "chat"))
+		require.Equal(t, "chat", p1.IsSubscribedToDataTopicArgsForCall(0))
+		require.Equal(t, 1, p1.SendDataPacketCallCount())
+		require.Equal(t, 1, p2.SendDataPacketCallCount())
+		require.Zero(t, p3.SendDataPacketCallCount())
+
+		// destination sids restrict subscribers further
+		p0.OnDataPacketArgsForCall(0)(p0, topicPacket(p0, "chat", string(p2.ID())))
+		require.Equal(t, 1, p1.SendDataPacketCallCount())
+		require.Equal(t, 2, p2.SendDataPacketCallCount())
+	})
+
+	t.Run("data is dropped when its publisher isn't allowed to publish to the topic", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		defer rm.Close()
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+		p1.IsSubscribedToDataTopicReturns(true)
+		rm.SetDataHistory(&types.DataHistory{MaxMessages: 10}, nil)
+
+		p0.OnDataPacketArgsForCall(0)(p0, topicPacket(p0, "chat"))
+		require.Equal(t, "chat", p0.CanPublishDataTopicArgsForCall(0))
+		require.Zero(t, p1.SendDataPacketCallCount())
+		require.Empty(t, rm.DataHistory())
+
+		// the server may publish to any topic
+		rm.SendDataPacket(topicPacket(p0, "chat").GetUser(), livekit.DataPacket_RELIABLE)
+		require.Equal(t, 1, p1.SendDataPacketCallCount())
+		require.Len(t, rm.DataHistory(), 1)
+		require.Equal(t, "chat", rm.DataHistory()[0].Topic)
+		require.Empty(t, rm.DataHistory()[0].DestinationSids)
+	})
+
+	t.Run("history of a topic is only replayed to its subscribers", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		defer rm.Close()
+		rm.SetDataHistory(&types.DataHistory{MaxMessages: 10}, []*types.DataMessage{
+			{SenderSid: "PA_sender", Topic: "chat", Payload: []byte("chat")},
+		})
+
+		for _, subscribed := range []bool{false, true} {
+			p := newMockParticipant(livekit.ParticipantIdentity(fmt.Sprintf("new-%v", subscribed)), types.DefaultProtocol, false, false)
+			p.IsSubscribedToDataTopicReturns(subscribed)
+			require.NoError(t, rm.Join(context.Background(), p, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, ""))
+			p.StateReturns(livekit.ParticipantInfo_ACTIVE)
+			p.OnStateChangeArgsForCall(0)(p, livekit.ParticipantInfo_JOINED)
+
+			if !subscribed {
+				require.Zero(t, p.SendDataPacketCallCount())
+				continue
+			}
+			require.Equal(t, 1, p.SendDataPacketCallCount())
+			up := p.SendDataPacketArgsForCall(0).GetUser()
+			require.Equal(t, "chat", UserPacketTopic(up))
+			require.Empty(t, up.DestinationSids)
+		}
+	})
+}
+
+func TestDataHistory(t *testing.T) {
+	sendData := func(p *typesfakes.FakeLocalParticipant, payload string, dest ...livekit.ParticipantID) {
+		up := &livekit.UserPacket{
+			ParticipantSid: string(p.ID()),
+			Payload:        []byte(payload),
+		}
+		for _, pID := range dest {
+			up.DestinationSids = append(up.DestinationSids, string(pID))
+		}
+		p.OnDataPacketArgsForCall(0)(p, &livekit.DataPacket{
+			Kind:  livekit.DataPacket_RELIABLE,
+			Value: &livekit.DataPacket_User{User: up},
+		})
+	}
+
+	t.Run("retains reliable messages within limits", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		defer rm.Close()
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+
+		var retained []*types.DataMessage
+		rm.SetDataHistory(&types.DataHistory{MaxMessages: 3, MaxBytes: 8}, nil)
+		rm.OnDataRetained(func(msg *types.DataMessage) {
+			retained = append(retained, msg)
+		})
+
+		sendData(p0, "m1")
+		sendData(p0, "m2", p1.ID())
+		p0.OnDataPacketArgsForCall(0)(p0, &livekit.DataPacket{
+			Kind:  livekit.DataPacket_LOSSY,
+			Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Payload: []byte("lossy")}},
+		})
+		rm.SendServerDataPacket(&livekit.UserPacket{Payload: []byte("warning")}, livekit.DataPacket_RELIABLE)
+		rm.SendDataPacket(&livekit.UserPacket{Payload: []byte("m3")}, livekit.DataPacket_RELIABLE)
+
+		// direct, lossy and server messages aren't retained
+		history := rm.DataHistory()
+		require.Len(t, history, 2)
+		require.Equal(t, "p0", history[0].Sender)
+		require.Equal(t, string(p0.ID()), history[0].SenderSid)
+		require.Equal(t, []byte("m1"), history[0].Payload)
+		require.Empty(t, history[1].Sender)
+		require.Equal(t, []byte("m3"), history[1].Payload)
+		require.Len(t, retained, 2)
+
+		// the oldest messages are dropped beyond the limits
+		sendData(p0, "m4")
+		sendData(p0, "m5")
+		require.Len(t, rm.DataHistory(), 3)
+		sendData(p1, "large")
+		history = rm.DataHistory()
+		require.Len(t, history, 2)
+		require.Equal(t, []byte("m5"), history[0].Payload)
+		require.Equal(t, []byte("large"), history[1].Payload)
+	})
+
+	t.Run("replays messages sent to everyone to participants joining later", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		defer rm.Close()
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+
+		rm.SetDataHistory(&types.DataHistory{MaxMessages: 10, IncludeDirect: true}, []*types.DataMessage{
+			{Sender: "gone", SenderSid: "PA_gone", Payload: []byte("persisted")},
+		})
+		sendData(p0, "m1")
+		sendData(p0, "m2", p1.ID())
+		require.Len(t, rm.DataHistory(), 3)
+
+		p := newMockParticipant("new", types.DefaultProtocol, false, false)
+		require.NoError(t, rm.Join(context.Background(), p, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, ""))
+		p.StateReturns(livekit.ParticipantInfo_ACTIVE)
+		p.OnStateChangeArgsForCall(0)(p, livekit.ParticipantInfo_JOINED)
+
+		require.Equal(t, 2, p.SendDataPacketCallCount())
+		up := p.SendDataPacketArgsForCall(0).GetUser()
+		require.Equal(t, "PA_gone", up.ParticipantSid)
+		require.Equal(t, []byte("persisted"), up.Payload)
+		up = p.SendDataPacketArgsForCall(1).GetUser()
+		require.Equal(t, string(p0.ID()), up.ParticipantSid)
+		require.Equal(t, []byte("m1"), up.Payload)
+	})
+
+	t.Run("messages sent while replaying are received after the history", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		defer rm.Close()
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+
+		rm.SetDataHistory(&types.DataHistory{MaxMessages: 10}, nil)
+		sendData(p0, "m1")
+
+		p := newMockParticipant("new", types.DefaultProtocol, false, false)
+		require.NoError(t, rm.Join(context.Background(), p, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, ""))
+		p.StateReturns(livekit.ParticipantInfo_ACTIVE)
+		sent := 0
+		p.SendDataPacketCalls(func(*livekit.DataPacket) error {
+			if sent++; sent == 1 {
+				sendData(p0, "live")
+			}
+			return nil
+		})
+		p.OnStateChangeArgsForCall(0)(p, livekit.ParticipantInfo_JOINED)
+
+		require.Equal(t, 2, p.SendDataPacketCallCount())
+		require.Equal(t, []byte("m1"), p.SendDataPacketArgsForCall(0).GetUser().Payload)
+		require.Equal(t, []byte("live"), p.SendDataPacketArgsForCall(1).GetUser().Payload)
+		require.Len(t, rm.DataHistory(), 2)
+	})
+}
+
+func TestModerateTracks(t *testing.T) {
+	publish := func(p *typesfakes.FakeLocalParticipant, source livekit.TrackSource) *typesfakes.FakeMediaTrack {
+		track := &typesfakes.FakeMediaTrack{}
+		track.IDReturns(livekit.TrackID(utils.NewGuid(utils.TrackPrefix)))
+		track.SourceReturns(source)
+		p.GetPublishedTracksReturns(append(p.GetPublishedTracks(), track))
+		return track
+	}
+
+	t.Run("tracks of sources are moderated except for given participants", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+		p1 := rm.GetParticipant("p1").(*typesfakes.FakeLocalParticipant)
+		mic := publish(p0, livekit.TrackSource_MICROPHONE)
+		publish(p0, livekit.TrackSource_CAMERA)
+		publish(p1, livekit.TrackSource_MICROPHONE)
+
+		moderated := rm.ModerateTracks(&TrackModeration{
+			Sources: []livekit.TrackSource{livekit.TrackSource_MICROPHONE},
+			Except:  []livekit.ParticipantIdentity{"p1"},
+		})
+		require.Equal(t, 1, moderated)
+		require.Equal(t, 1, p0.SetTrackMutedCallCount())
+		trackID, muted, fromAdmin := p0.SetTrackMutedArgsForCall(0)
+		require.Equal(t, mic.ID(), trackID)
+		require.True(t, muted)
+		require.True(t, fromAdmin)
+		require.Equal(t, 0, p1.SetTrackMutedCallCount())
+
+		// without a window, later tracks are left alone
+		rm.onTrackPublished(p0, publish(p0, livekit.TrackSource_MICROPHONE))
+		require.Equal(t, 1, p0.SetTrackMutedCallCount())
+	})
+
+	t.Run("tracks published during the window are moderated on entry", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		p0 := rm.GetParticipant("p0").(*typesfakes.FakeLocalParticipant)
+
+		require.Equal(t, 0, rm.ModerateTracks(&TrackModeration{
+			Sources:   []livekit.TrackSource{livekit.TrackSource_SCREEN_SHARE},
+			Unpublish: true,
+			Until:     time.Now().Add(time.Minute),
+		}))
+		publish(p0, livekit.TrackSource_CAMERA)
+		rm.onTrackPublished(p0, p0.GetPublishedTracks()[0])
+		require.Equal(t, 0, p0.UnpublishTrackCallCount())
+
+		screen := publish(p0, livekit.TrackSource_SCREEN_SHARE)
+		rm.onTrackPublished(p0, screen)
+		require.Equal(t, 1, p0.UnpublishTrackCallCount())
+		require.Equal(t, screen.ID(), p0.UnpublishTrackArgsForCall(0))
+	})
 }
 
 // various state changes to participant and that others are receiving update
@@ -205,6 +825,35 @@ func TestParticipantUpdate(t *testing.T) {
 	}
 }
 
+func TestAttributesUpdate(t *testing.T) {
+	rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
+	sender := rm.GetParticipants()[0].(*typesfakes.FakeLocalParticipant)
+	other := rm.GetParticipants()[1].(*typesfakes.FakeLocalParticipant)
+	sender.ToProtoStub = func() *livekit.ParticipantInfo {
+		info := &livekit.ParticipantInfo{Sid: string(sender.ID()), Identity: string(sender.Identity())}
+		SetParticipantAttributes(info, map[string]string{"role": "speaker", "mic": "on"}, 3)
+		return info
+	}
+	stored := 0
+	rm.OnParticipantChanged(func(p types.LocalParticipant) {
+		stored++
+	})
+
+	require.Equal(t, 1, sender.OnAttributesUpdateCallCount())
+	onAttributesUpdate := sender.OnAttributesUpdateArgsForCall(0)
+	onAttributesUpdate(sender, map[string]string{"hand": "", "mic": "on"}, 3)
+
+	// others receive only the keys that changed
+	require.Equal(t, 1, other.SendParticipantUpdateCallCount())
+	updates := other.SendParticipantUpdateArgsForCall(0)
+	require.Len(t, updates, 1)
+	attributes, version := ParticipantAttributes(updates[0])
+	require.Equal(t, map[string]string{"hand": "", "mic": "on"}, attributes)
+	require.EqualValues(t, 3, version)
+	require.True(t, ParticipantAttributesDelta(updates[0]))
+	require.Equal(t, 1, stored)
+}
+
 func TestPushAndDequeueUpdates(t *testing.T) {
 	identity := "test_user"
 	publisher1v1 := &livekit.ParticipantInfo{
@@ -340,7 +989,7 @@ func TestRoomClosure(t *testing.T) {
 		require.Len(t, rm.GetParticipants(), 0)
 		require.True(t, isClosed)
 
-		require.Equal(t, ErrRoomClosed, rm.Join(p, nil, iceServersForRoom, ""))
+		require.Equal(t, ErrRoomClosed, rm.Join(context.Background(), p, nil, iceServersForRoom, ""))
 	})
 
 	t.Run("room does not close before empty timeout", func(t *testing.T) {
@@ -366,6 +1015,44 @@ func TestRoomClosure(t *testing.T) {
 		rm.CloseIfEmpty()
 		require.True(t, isClosed)
 	})
+
+	t.Run("parent room stays open for its breakout rooms", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		isClosed := false
+		rm.OnClose(func() {
+			isClosed = true
+		})
+		rm.protoRoom.EmptyTimeout = 0
+		rm.SetInternal(&types.RoomInternal{Breakouts: []livekit.RoomName{"breakout"}})
+		// everyone moved into the breakout room
+		rm.RemoveParticipant(rm.GetParticipants()[0].Identity(), types.ParticipantCloseReasonClientRequestLeave)
+
+		rm.CloseIfEmpty()
+		require.False(t, isClosed)
+
+		// closes once the breakout room is closed
+		rm.SetInternal(&types.RoomInternal{})
+		rm.CloseIfEmpty()
+		require.True(t, isClosed)
+	})
+
+	t.Run("bridge participants don't keep the room open", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		isClosed := false
+		rm.OnClose(func() {
+			isClosed = true
+		})
+		rm.protoRoom.EmptyTimeout = 0
+		identity := rm.GetParticipants()[0].Identity()
+		require.NoError(t, rm.JoinBridge(newMockParticipant("bridge", types.DefaultProtocol, false, true)))
+
+		rm.CloseIfEmpty()
+		require.False(t, isClosed)
+
+		rm.RemoveParticipant(identity, types.ParticipantCloseReasonClientRequestLeave)
+		rm.CloseIfEmpty()
+		require.True(t, isClosed)
+	})
 }
 
 func TestNewTrack(t *testing.T) {
@@ -620,10 +1307,10 @@ func TestHiddenParticipants(t *testing.T) {
 		defer rm.Close()
 
 		pNew := newMockParticipant("new", types.DefaultProtocol, false, false)
-		rm.Join(pNew, nil, iceServersForRoom, "testregion")
+		rm.Join(context.Background(), pNew, nil, iceServersForRoom, "testregion")
 
 		// expect new participant to get a JoinReply
-		info, participants, iceServers, region := pNew.SendJoinResponseArgsForCall(0)
+		info, participants, _, iceServers, region := pNew.SendJoinResponseArgsForCall(0)
 		require.Equal(t, livekit.RoomID(info.Sid), rm.ID())
 		require.Len(t, participants, 2)
 		require.Len(t, rm.GetParticipants(), 4)
@@ -635,7 +1322,7 @@ func TestHiddenParticipants(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: 2, numHidden: 1})
 		p := newMockParticipant("new", types.DefaultProtocol, false, true)
 
-		err := rm.Join(p, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, "")
+		err := rm.Join(context.Background(), p, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, "")
 		require.NoError(t, err)
 
 		stateChangeCB := p.OnStateChangeArgsForCall(0)
@@ -663,13 +1350,40 @@ func TestRoomUpdate(t *testing.T) {
 		rm := newRoomWithParticipants(t, testRoomOpts{num: 2})
 		defer rm.Close()
 
-		rm.SetMetadata("test metadata...")
+		_, err := rm.SetMetadata("test metadata...", nil)
+		require.NoError(t, err)
 
 		for _, op := range rm.GetParticipants() {
 			fp := op.(*typesfakes.FakeLocalParticipant)
 			require.Equal(t, 1, fp.SendRoomUpdateCallCount())
 		}
 	})
+
+	t.Run("metadata updates are versioned", func(t *testing.T) {
+		rm := newRoomWithParticipants(t, testRoomOpts{num: 1})
+		defer rm.Close()
+		rm.metadataVersion = 3
+		var persisted []uint32
+		rm.OnMetadataUpdate(func(_ string, version uint32) {
+			persisted = append(persisted, version)
+		})
+
+		stale := uint32(2)
+		version, err := rm.SetMetadata("stale", &stale)
+		require.Equal(t, ErrMetadataChanged, err)
+		require.EqualValues(t, 3, version)
+		require.Empty(t, rm.ToProto().Metadata)
+
+		current := uint32(3)
+		version, err = rm.SetMetadata("current", &current)
+		require.NoError(t, err)
+		require.EqualValues(t, 4, version)
+		version, err = rm.SetMetadata("unconditional", nil)
+		require.NoError(t, err)
+		require.EqualValues(t, 5, version)
+		require.Equal(t, "unconditional", rm.ToProto().Metadata)
+		require.Equal(t, []uint32{4, 5}, persisted)
+	})
 }
 
 type testRoomOpts struct {
@@ -682,17 +1396,18 @@ type testRoomOpts struct {
 func newRoomWithParticipants(t *testing.T, opts testRoomOpts) *Room {
 	rm := NewRoom(
 		&livekit.Room{Name: "room"},
+		nil,
 		WebRTCConfig{},
 		&config.AudioConfig{
 			UpdateInterval:  audioUpdateInterval,
 			SmoothIntervals: opts.audioSmoothIntervals,
 		},
-		telemetry.NewTelemetryService(webhook.NewNotifier("", "", nil), &telemetryfakes.FakeAnalyticsService{}),
+		telemetry.NewTelemetryService(webhook.NewNotifier("", "", nil), &telemetryfakes.FakeAnalyticsService{}, nil, nil),
 	)
 	for i := 0; i < opts.num+opts.numHidden; i++ {
 		identity := livekit.ParticipantIdentity(fmt.Sprintf("p%d", i))
 		participant := newMockParticipant(identity, opts.protocol, i >= opts.num, true)
-		err := rm.Join(participant, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, "")
+		err := rm.Join(context.Background(), participant, &ParticipantOptions{AutoSubscribe: true}, iceServersForRoom, "")
 		require.NoError(t, err)
 		participant.StateReturns(livekit.ParticipantInfo_ACTIVE)
 		participant.IsReadyReturns(true)
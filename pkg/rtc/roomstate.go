@@ -0,0 +1,232 @@
+package rtc
+
+import (
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// type of the data messages carrying a room's shared state
+const roomStateMessageType = "room_state"
+
+// RoomStateValue sets a key of a room's shared state
+type RoomStateValue struct {
+	Value string
+	// lets any participant change the key, only the key's owner and room admins may set it
+	Shared bool
+	// time after which the key is removed, 0 to keep it
+	TTL time.Duration
+}
+
+// RoomStateUpdate changes keys of a room's shared state. Keys are deleted before others are set
+type RoomStateUpdate struct {
+	Set    map[string]*RoomStateValue
+	Delete []string
+}
+
+// RoomStateMessage is the payload of the data message participants receive when the room's state changes.
+// It carries changed keys, with deleted keys set to null. A participant becoming active after the state changed
+// since its join response receives a snapshot of the whole state instead
+type RoomStateMessage struct {
+	Type     string                           `json:"type"`
+	Snapshot bool                             `json:"snapshot,omitempty"`
+	Entries  map[string]*types.RoomStateEntry `json:"entries"`
+}
+
+// UpdateState applies an update to the room's shared state on behalf of a participant, or of a room admin when
+// identity is empty. maxSize limits the total size of keys and values, 0 for no limit. Changes are broadcast to
+// participants of the room, the whole state is returned
+func (r *Room) UpdateState(identity livekit.ParticipantIdentity, update *RoomStateUpdate, maxSize uint32) (map[string]*types.RoomStateEntry, error) {
+	r.stateLock.Lock()
+	defer r.stateLock.Unlock()
+
+	changed, err := r.updateStateLocked(identity, update, maxSize)
+	if err != nil {
+		return nil, err
+	}
+	if len(changed) > 0 {
+		r.Logger.Debugw("updated room state", "participant", identity, "keys", len(changed))
+		// broadcast holding the lock, so participants receive changes in order
+		r.broadcastState(changed)
+	}
+	return r.copyStateLocked(), nil
+}
+
+// State returns a copy of the room's shared state
+func (r *Room) State() map[string]*types.RoomStateEntry {
+	r.stateLock.Lock()
+	defer r.stateLock.Unlock()
+
+	return r.copyStateLocked()
+}
+
+func (r *Room) updateStateLocked(identity livekit.ParticipantIdentity, update *RoomStateUpdate, maxSize uint32) (map[string]*types.RoomStateEntry, error) {
+	// validate the whole update before applying any of it
+	size := 0
+	for key, entry := range r.state {
+		size += entry.Size(key)
+	}
+	updated := make(map[string]*types.RoomStateEntry)
+	for _, key := range update.Delete {
+		if key == "" {
+			return nil, ErrEmptyStateKey
+		}
+		if entry := r.state[key]; entry != nil {
+			if !entry.WritableBy(identity) {
+				return nil, ErrStateKeyNotWritable
+			}
+			size -= entry.Size(key)
+			updated[key] = nil
+		}
+	}
+	now := time.Now()
+	for key, value := range update.Set {
+		if key == "" {
+			return nil, ErrEmptyStateKey
+		}
+		entry := &types.RoomStateEntry{
+			Value:  value.Value,
+			Owner:  string(identity),
+			Shared: value.Shared,
+		}
+		current := r.state[key]
+		if _, deleted := updated[key]; deleted {
+			current = nil
+		}
+		if current != nil {
+			if !current.WritableBy(identity) {
+				return nil, ErrStateKeyNotWritable
+			}
+			entry.Owner = current.Owner
+			if identity != "" && current.Owner != string(identity) {
+				// sharing is up to the key's owner
+				entry.Shared = current.Shared
+			}
+			size -= current.Size(key)
+		}
+		if value.TTL > 0 {
+			entry.ExpiresAt = now.Add(value.TTL).Unix()
+		}
+		size += entry.Size(key)
+		updated[key] = entry
+	}
+	if maxSize > 0 && size > int(maxSize) {
+		return nil, ErrStateTooLarge
+	}
+
+	if len(updated) > 0 {
+		r.stateVersion++
+	}
+	if r.state == nil {
+		r.state = make(map[string]*types.RoomStateEntry)
+	}
+	if r.stateTimers == nil {
+		r.stateTimers = make(map[string]*time.Timer)
+	}
+	for key, entry := range updated {
+		key, entry := key, entry
+		if timer := r.stateTimers[key]; timer != nil {
+			timer.Stop()
+			delete(r.stateTimers, key)
+		}
+		if entry == nil {
+			delete(r.state, key)
+			continue
+		}
+		r.state[key] = entry
+		if ttl := update.Set[key].TTL; ttl > 0 {
+			r.stateTimers[key] = time.AfterFunc(ttl, func() {
+				r.expireState(key, entry)
+			})
+		}
+	}
+	return updated, nil
+}
+
+// expireState removes a key when its TTL elapses, unless it was set again meanwhile
+func (r *Room) expireState(key string, entry *types.RoomStateEntry) {
+	r.stateLock.Lock()
+	defer r.stateLock.Unlock()
+
+	if r.state[key] != entry {
+		return
+	}
+	delete(r.state, key)
+	delete(r.stateTimers, key)
+	r.stateVersion++
+	r.broadcastState(map[string]*types.RoomStateEntry{key: nil})
+}
+
+func (r *Room) stopStateTimers() {
+	r.stateLock.Lock()
+	defer r.stateLock.Unlock()
+
+	for _, timer := range r.stateTimers {
+		timer.Stop()
+	}
+	r.stateTimers = nil
+}
+
+func (r *Room) copyStateLocked() map[string]*types.RoomStateEntry {
+	state := make(map[string]*types.RoomStateEntry, len(r.state))
+	for key, entry := range r.state {
+		copied := *entry
+		state[key] = &copied
+	}
+	return state
+}
+
+func (r *Room) broadcastState(changed map[string]*types.RoomStateEntry) {
+	msg := &RoomStateMessage{
+		Type:    roomStateMessageType,
+		Entries: changed,
+	}
+	for _, op := range r.GetParticipants() {
+		if op.State() != livekit.ParticipantInfo_ACTIVE {
+			continue
+		}
+		r.sendJSONMessage(op, msg)
+	}
+}
+
+// joinState returns the room's state sent in a participant's join response, recording its version
+func (r *Room) joinState(participant types.LocalParticipant) map[string]*types.RoomStateEntry {
+	r.stateLock.Lock()
+	defer r.stateLock.Unlock()
+
+	if r.stateSent == nil {
+		r.stateSent = make(map[livekit.ParticipantID]uint64)
+	}
+	r.stateSent[participant.ID()] = r.stateVersion
+	return r.copyStateLocked()
+}
+
+func (r *Room) forgetJoinState(participant types.LocalParticipant) {
+	r.stateLock.Lock()
+	defer r.stateLock.Unlock()
+
+	delete(r.stateSent, participant.ID())
+}
+
+// sendStateSnapshot sends the room's state to a participant that became active, unless it didn't change since the
+// participant's join response
+func (r *Room) sendStateSnapshot(participant types.LocalParticipant) {
+	r.stateLock.Lock()
+	defer r.stateLock.Unlock()
+
+	version, sent := r.stateSent[participant.ID()]
+	delete(r.stateSent, participant.ID())
+	if sent && version == r.stateVersion {
+		return
+	}
+	if !sent && len(r.state) == 0 {
+		return
+	}
+	r.sendJSONMessage(participant, &RoomStateMessage{
+		Type:     roomStateMessageType,
+		Snapshot: true,
+		Entries:  r.state,
+	})
+}
@@ -8,6 +8,11 @@ import (
 )
 
 func HandleParticipantSignal(room types.Room, participant types.LocalParticipant, req *livekit.SignalRequest, pLogger logger.Logger) error {
+	if !participant.AllowSignalRequest(req) {
+		// rejected requests are counted by the participant
+		return nil
+	}
+
 	switch msg := req.Message.(type) {
 	case *livekit.SignalRequest_Offer:
 		err := participant.HandleOffer(FromProtoSessionDescription(msg.Offer))
@@ -76,7 +81,7 @@ func HandleParticipantSignal(room types.Room, participant types.LocalParticipant
 		}
 	case *livekit.SignalRequest_Leave:
 		pLogger.Infow("client leaving room")
-		room.RemoveParticipant(participant.Identity(), types.ParticipantCloseReasonClientRequestLeave)
+		room.RemoveParticipantSession(participant, types.ParticipantCloseReasonClientRequestLeave)
 	case *livekit.SignalRequest_SubscriptionPermission:
 		err := room.UpdateSubscriptionPermission(participant, msg.SubscriptionPermission)
 		if err != nil {
@@ -95,6 +100,20 @@ func HandleParticipantSignal(room types.Room, participant types.LocalParticipant
 			pLogger.Warnw("could not simulate scenario", err,
 				"simulate", msg.Simulate)
 		}
+	default:
+		if subscribe, unsubscribe, ok := SignalDataTopics(req); ok {
+			if err := participant.UpdateDataTopics(subscribe, unsubscribe); err != nil {
+				pLogger.Warnw("could not update data topics", err,
+					"subscribe", subscribe, "unsubscribe", unsubscribe)
+				return nil
+			}
+			pLogger.Debugw("updated data topics", "topics", participant.DataTopics())
+		} else if action, identity, ok := SignalStage(req); ok {
+			if err := room.UpdateStage(participant, action, identity); err != nil {
+				pLogger.Warnw("could not update stage", err,
+					"action", action, "identity", identity)
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,96 @@
+package rtc
+
+import (
+	"github.com/livekit/protocol/livekit"
+)
+
+// reasons signal requests are rejected
+const (
+	SignalLimitReasonRate            = "rate"
+	SignalLimitReasonPendingTracks   = "pending_tracks"
+	SignalLimitReasonPublishedTracks = "published_tracks"
+)
+
+// SignalLimits limits the signal requests a participant sends
+type SignalLimits struct {
+	// limiters of requests by type of request, types without one aren't limited
+	Requests map[string]*DataRateLimiter
+	// max tracks pending publication, and published including pending ones. 0 for no limit
+	MaxPendingTracks   uint32
+	MaxPublishedTracks uint32
+	// limits requests rejected for exceeding the limits above, the participant is disconnected beyond it.
+	// nil to never disconnect
+	Violations *DataRateLimiter
+}
+
+// NewSignalLimits returns limits of a participant's signal requests, allowing rates of requests per second by type
+func NewSignalLimits(requestRates map[string]uint32, maxPendingTracks uint32, maxPublishedTracks uint32, maxViolationRate uint32) SignalLimits {
+	limits := SignalLimits{
+		MaxPendingTracks:   maxPendingTracks,
+		MaxPublishedTracks: maxPublishedTracks,
+		Violations:         NewDataRateLimiter(maxViolationRate, 0),
+	}
+	for requestType, rate := range requestRates {
+		if limiter := NewDataRateLimiter(rate, 0); limiter != nil {
+			if limits.Requests == nil {
+				limits.Requests = make(map[string]*DataRateLimiter)
+			}
+			limits.Requests[requestType] = limiter
+		}
+	}
+	return limits
+}
+
+// signalRequestType returns the type of a signal request as it's named in limits and metrics
+func signalRequestType(req *livekit.SignalRequest) string {
+	switch req.Message.(type) {
+	case *livekit.SignalRequest_Offer:
+		return "offer"
+	case *livekit.SignalRequest_Answer:
+		return "answer"
+	case *livekit.SignalRequest_Trickle:
+		return "trickle"
+	case *livekit.SignalRequest_AddTrack:
+		return "add_track"
+	case *livekit.SignalRequest_Mute:
+		return "mute"
+	case *livekit.SignalRequest_Subscription:
+		return "subscription"
+	case *livekit.SignalRequest_TrackSetting:
+		return "track_setting"
+	case *livekit.SignalRequest_Leave:
+		return "leave"
+	case *livekit.SignalRequest_UpdateLayers:
+		return "update_layers"
+	case *livekit.SignalRequest_SubscriptionPermission:
+		return "subscription_permission"
+	case *livekit.SignalRequest_SyncState:
+		return "sync_state"
+	case *livekit.SignalRequest_Simulate:
+		return "simulate"
+	default:
+		if _, _, ok := SignalDataTopics(req); ok {
+			return "data_topics"
+		}
+		if _, _, ok := SignalStage(req); ok {
+			return "stage"
+		}
+		return "unknown"
+	}
+}
+
+// allowRequest returns whether a request of the given type is within the rate limits, counting it when it is
+func (l *SignalLimits) allowRequest(requestType string) bool {
+	return l.Requests[requestType].Allow(0)
+}
+
+// trackLimitExceeded returns the reason publishing another track exceeds the limits, empty when it doesn't
+func (l *SignalLimits) trackLimitExceeded(pending int, published int) string {
+	if l.MaxPendingTracks > 0 && pending >= int(l.MaxPendingTracks) {
+		return SignalLimitReasonPendingTracks
+	}
+	if l.MaxPublishedTracks > 0 && pending+published >= int(l.MaxPublishedTracks) {
+		return SignalLimitReasonPublishedTracks
+	}
+	return ""
+}
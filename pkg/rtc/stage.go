@@ -0,0 +1,244 @@
+package rtc
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// type of the data messages about a room's stage
+const stageMessageType = "stage"
+
+type StageEvent string
+
+const (
+	StageEventRequested StageEvent = "requested"
+	StageEventWithdrawn StageEvent = "withdrawn"
+	StageEventApproved  StageEvent = "approved"
+	StageEventDenied    StageEvent = "denied"
+	StageEventDemoted   StageEvent = "demoted"
+)
+
+// StageMessage is the payload of the data message moderators of a room receive when its stage changes.
+// The participant it concerns receives it too, without Queue
+type StageMessage struct {
+	Type     string     `json:"type"`
+	Event    StageEvent `json:"event"`
+	Identity string     `json:"identity"`
+	// identities of participants waiting to be approved, in order of their requests
+	Queue []string `json:"queue,omitempty"`
+}
+
+// RequestPublish queues a request of a participant to be allowed to publish, moderators of the room are notified
+func (r *Room) RequestPublish(identity livekit.ParticipantIdentity) error {
+	r.lock.Lock()
+	participant := r.participantLocked(identity)
+	if participant == nil {
+		r.lock.Unlock()
+		return ErrParticipantNotFound
+	}
+	if participant.CanPublish() {
+		r.lock.Unlock()
+		return ErrAlreadyPublisher
+	}
+	for _, queued := range r.stageQueue {
+		if queued == identity {
+			r.lock.Unlock()
+			return nil
+		}
+	}
+	r.stageQueue = append(r.stageQueue, identity)
+	r.lock.Unlock()
+
+	r.Logger.Infow("participant requested to publish", "participant", identity)
+	r.sendStageMessage(StageEventRequested, identity, nil)
+	return nil
+}
+
+// WithdrawPublishRequest removes a participant's request from the queue
+func (r *Room) WithdrawPublishRequest(identity livekit.ParticipantIdentity) error {
+	if !r.dequeuePublishRequest(identity) {
+		return ErrNoPublishRequest
+	}
+
+	r.sendStageMessage(StageEventWithdrawn, identity, nil)
+	return nil
+}
+
+// ApprovePublish grants publish permission to every session of a participant that requested it. The request stays
+// queued while the room has as many publishers as it allows
+func (r *Room) ApprovePublish(identity livekit.ParticipantIdentity) error {
+	if !r.hasPublishRequest(identity) {
+		return ErrNoPublishRequest
+	}
+	sessions := r.GetParticipantSessions(identity)
+	if len(sessions) == 0 {
+		return ErrParticipantNotFound
+	}
+	for _, participant := range sessions {
+		if err := r.setCanPublish(participant, true); err != nil {
+			return err
+		}
+	}
+	r.dequeuePublishRequest(identity)
+
+	r.Logger.Infow("approved participant to publish", "participant", identity)
+	r.sendStageMessage(StageEventApproved, identity, sessions)
+	return nil
+}
+
+// DenyPublish refuses the request of a participant to publish
+func (r *Room) DenyPublish(identity livekit.ParticipantIdentity) error {
+	if !r.dequeuePublishRequest(identity) {
+		return ErrNoPublishRequest
+	}
+
+	r.Logger.Infow("denied participant to publish", "participant", identity)
+	r.sendStageMessage(StageEventDenied, identity, r.GetParticipantSessions(identity))
+	return nil
+}
+
+// DemotePublisher revokes publish permission of every session of a participant, unpublishing their tracks
+func (r *Room) DemotePublisher(identity livekit.ParticipantIdentity) error {
+	sessions := r.GetParticipantSessions(identity)
+	if len(sessions) == 0 {
+		return ErrParticipantNotFound
+	}
+	var publishers []types.LocalParticipant
+	for _, participant := range sessions {
+		if participant.CanPublish() {
+			publishers = append(publishers, participant)
+		}
+	}
+	if len(publishers) == 0 {
+		return ErrNotPublisher
+	}
+
+	r.Logger.Infow("demoted participant", "participant", identity)
+	for _, participant := range publishers {
+		if err := r.setCanPublish(participant, false); err != nil {
+			return err
+		}
+	}
+	r.sendStageMessage(StageEventDemoted, identity, sessions)
+	return nil
+}
+
+// ApplyStageAction applies action to the participant identity of the stage
+func (r *Room) ApplyStageAction(action types.StageAction, identity livekit.ParticipantIdentity) error {
+	switch action {
+	case types.StageActionRequest:
+		return r.RequestPublish(identity)
+	case types.StageActionWithdraw:
+		return r.WithdrawPublishRequest(identity)
+	case types.StageActionApprove:
+		return r.ApprovePublish(identity)
+	case types.StageActionDeny:
+		return r.DenyPublish(identity)
+	case types.StageActionDemote:
+		return r.DemotePublisher(identity)
+	}
+	return ErrInvalidStageAction
+}
+
+// UpdateStage applies a stage action a participant sent over signaling. Participants request to publish and
+// withdraw their requests themselves, only room admins may moderate others
+func (r *Room) UpdateStage(participant types.LocalParticipant, action types.StageAction, identity livekit.ParticipantIdentity) error {
+	switch action {
+	case types.StageActionRequest, types.StageActionWithdraw:
+		return r.ApplyStageAction(action, participant.Identity())
+	}
+	if grants := participant.ClaimGrants(); grants == nil || grants.Video == nil || !grants.Video.RoomAdmin {
+		return ErrPermissionDenied
+	}
+	return r.ApplyStageAction(action, identity)
+}
+
+// StageQueue returns identities of participants waiting to be approved, in order of their requests
+func (r *Room) StageQueue() []livekit.ParticipantIdentity {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]livekit.ParticipantIdentity(nil), r.stageQueue...)
+}
+
+func (r *Room) hasPublishRequest(identity livekit.ParticipantIdentity) bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, queued := range r.stageQueue {
+		if queued == identity {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) dequeuePublishRequest(identity livekit.ParticipantIdentity) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.dequeuePublishRequestLocked(identity)
+}
+
+// dequeuePublishRequestLocked removes the participant's request, r.lock must be held
+func (r *Room) dequeuePublishRequestLocked(identity livekit.ParticipantIdentity) bool {
+	for i, queued := range r.stageQueue {
+		if queued == identity {
+			r.stageQueue = append(r.stageQueue[:i], r.stageQueue[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Room) setCanPublish(participant types.LocalParticipant, canPublish bool) error {
+	// serializes grants, so that concurrent approvals can't exceed the room's max publishers
+	r.stageLock.Lock()
+	defer r.stageLock.Unlock()
+
+	if canPublish && !participant.CanPublish() {
+		r.lock.RLock()
+		full := r.internal.MaxPublishers > 0 && r.numPublishersLocked() >= int(r.internal.MaxPublishers)
+		r.lock.RUnlock()
+		if full {
+			return ErrMaxPublishersExceeded
+		}
+	}
+
+	permission := &livekit.ParticipantPermission{}
+	if current := participant.ToProto().Permission; current != nil {
+		permission = proto.Clone(current).(*livekit.ParticipantPermission)
+	}
+	permission.CanPublish = canPublish
+	return r.SetParticipantPermission(participant, permission)
+}
+
+// sendStageMessage notifies moderators of the room, and the sessions of the participant the event concerns given
+func (r *Room) sendStageMessage(event StageEvent, identity livekit.ParticipantIdentity, sessions []types.LocalParticipant) {
+	msg := &StageMessage{
+		Type:     stageMessageType,
+		Event:    event,
+		Identity: string(identity),
+	}
+	for _, participant := range sessions {
+		r.sendJSONMessage(participant, msg)
+	}
+
+	queue := r.StageQueue()
+	msg.Queue = make([]string, 0, len(queue))
+	for _, queued := range queue {
+		msg.Queue = append(msg.Queue, string(queued))
+	}
+	for _, op := range r.GetParticipants() {
+		if op.State() != livekit.ParticipantInfo_ACTIVE || op.Identity() == identity {
+			continue
+		}
+		if grants := op.ClaimGrants(); grants == nil || grants.Video == nil || !grants.Video.RoomAdmin {
+			continue
+		}
+		r.sendJSONMessage(op, msg)
+	}
+}
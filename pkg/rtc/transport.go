@@ -1,6 +1,7 @@
 package rtc
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -15,6 +16,8 @@ import (
 	"github.com/pion/interceptor/pkg/twcc"
 	"github.com/pion/sdp/v3"
 	"github.com/pion/webrtc/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/atomic"
 
 	"github.com/livekit/protocol/livekit"
@@ -26,6 +29,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/sfu"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 )
 
 const (
@@ -76,6 +80,13 @@ type PCTransport struct {
 	negotiateCounter           atomic.Int32
 	signalStateCheckTimer      *time.Timer
 	onNegotiationFailed        func()
+	onICEConnectionStateChange func(state webrtc.ICEConnectionState)
+
+	// spans of connection establishment, guarded separately as pion may
+	// fire state changes synchronously from calls made under lock
+	traceLock sync.Mutex
+	iceSpan   trace.Span
+	dtlsSpan  trace.Span
 
 	// stream allocator for subscriber PC
 	streamAllocator *sfu.StreamAllocator
@@ -99,6 +110,7 @@ type TransportParams struct {
 	EnabledCodecs           []*livekit.Codec
 	Logger                  logger.Logger
 	SimTracks               map[uint32]SimulcastTrackInfo
+	TraceParent             trace.SpanContext
 }
 
 func newPeerConnection(params TransportParams, onBandwidthEstimator func(estimator cc.BandwidthEstimator)) (*webrtc.PeerConnection, *webrtc.MediaEngine, error) {
@@ -304,6 +316,11 @@ func (t *PCTransport) createPeerConnection() error {
 		}
 	})
 
+	t.pc.OnICEConnectionStateChange(t.handleICEConnectionStateChange)
+	if sctp := t.pc.SCTP(); sctp != nil {
+		sctp.Transport().OnStateChange(t.handleDTLSStateChange)
+	}
+
 	t.me = me
 
 	if bwe != nil && t.streamAllocator != nil {
@@ -313,6 +330,64 @@ func (t *PCTransport) createPeerConnection() error {
 	return nil
 }
 
+func (t *PCTransport) handleICEConnectionStateChange(state webrtc.ICEConnectionState) {
+	t.traceLock.Lock()
+	switch state {
+	case webrtc.ICEConnectionStateChecking:
+		if t.iceSpan == nil {
+			_, t.iceSpan = t.startSpan("PCTransport.ICE")
+		}
+	case webrtc.ICEConnectionStateConnected, webrtc.ICEConnectionStateCompleted:
+		if t.iceSpan != nil {
+			t.iceSpan.End()
+			t.iceSpan = nil
+		}
+	case webrtc.ICEConnectionStateFailed, webrtc.ICEConnectionStateClosed:
+		if t.iceSpan != nil {
+			tracing.EndSpan(t.iceSpan, fmt.Errorf("ICE %s", state))
+			t.iceSpan = nil
+		}
+	}
+	t.traceLock.Unlock()
+
+	t.lock.RLock()
+	onICEConnectionStateChange := t.onICEConnectionStateChange
+	t.lock.RUnlock()
+	if onICEConnectionStateChange != nil {
+		onICEConnectionStateChange(state)
+	}
+}
+
+func (t *PCTransport) handleDTLSStateChange(state webrtc.DTLSTransportState) {
+	t.traceLock.Lock()
+	defer t.traceLock.Unlock()
+
+	switch state {
+	case webrtc.DTLSTransportStateConnecting:
+		if t.dtlsSpan == nil {
+			_, t.dtlsSpan = t.startSpan("PCTransport.DTLS")
+		}
+	case webrtc.DTLSTransportStateConnected:
+		if t.dtlsSpan != nil {
+			t.dtlsSpan.End()
+			t.dtlsSpan = nil
+		}
+	case webrtc.DTLSTransportStateFailed, webrtc.DTLSTransportStateClosed:
+		if t.dtlsSpan != nil {
+			tracing.EndSpan(t.dtlsSpan, fmt.Errorf("DTLS %s", state))
+			t.dtlsSpan = nil
+		}
+	}
+}
+
+func (t *PCTransport) startSpan(spanName string) (context.Context, trace.Span) {
+	return tracing.StartWithParent(t.params.TraceParent, spanName, trace.WithAttributes(
+		attribute.String("participant", string(t.params.ParticipantIdentity)),
+		attribute.String("pID", string(t.params.ParticipantID)),
+		attribute.String("target", t.params.Target.String()),
+	))
+}
+
 func (t *PCTransport) AddICECandidate(candidate webrtc.ICECandidateInit) error {
 	if t.pc.RemoteDescription() == nil {
 		t.lock.Lock()
@@ -443,6 +518,12 @@ func (t *PCTransport) OnRemoteDescripitonSettled(f func() error) {
 	t.lock.Unlock()
 }
 
+func (t *PCTransport) OnICEConnectionStateChange(f func(state webrtc.ICEConnectionState)) {
+	t.lock.Lock()
+	t.onICEConnectionStateChange = f
+	t.lock.Unlock()
+}
+
 func (t *PCTransport) OnNegotiationFailed(f func()) {
 	t.onNegotiationFailed = f
 }
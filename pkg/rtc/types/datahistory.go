@@ -0,0 +1,28 @@
+package types
+
+// DataHistory sets how many reliable data messages of a room are retained, to be replayed to participants joining
+// later
+type DataHistory struct {
+	// number of messages retained, history is disabled when 0
+	MaxMessages uint32 `json:"maxMessages,omitempty"`
+	// total size of retained payloads, the oldest messages are dropped beyond it. 0 for no limit
+	MaxBytes uint32 `json:"maxBytes,omitempty"`
+	// also retain messages sent to specific participants, for moderation. they're never replayed
+	IncludeDirect bool `json:"includeDirect,omitempty"`
+	// retain messages in the object store as well, keeping them across restarts of the room's node
+	Persist bool `json:"persist,omitempty"`
+}
+
+// DataMessage is a data message retained in the history of a room
+type DataMessage struct {
+	// identity and sid of the sender, empty when sent through the RoomService
+	Sender    string `json:"sender,omitempty"`
+	SenderSid string `json:"senderSid,omitempty"`
+	// topic the message was published to, it's only replayed to participants subscribed to it
+	Topic string `json:"topic,omitempty"`
+	// sids of the participants the message was sent to, empty when it was sent to everyone
+	DestinationSids []string `json:"destinationSids,omitempty"`
+	Payload         []byte   `json:"payload"`
+	// unix time in milliseconds the message was sent at
+	Timestamp int64 `json:"timestamp"`
+}
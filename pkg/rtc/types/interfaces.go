@@ -81,6 +81,11 @@ const (
 	ParticipantCloseReasonSimulateNodeFailure
 	ParticipantCloseReasonSimulateServerLeave
 	ParticipantCloseReasonNegotiateFailed
+	ParticipantCloseReasonRoomDurationExceeded
+	ParticipantCloseReasonSessionDurationExceeded
+	ParticipantCloseReasonBridgedTrackClosed
+	ParticipantCloseReasonDataLimitExceeded
+	ParticipantCloseReasonSignalLimitExceeded
 )
 
 func (p ParticipantCloseReason) String() string {
@@ -121,11 +126,25 @@ func (p ParticipantCloseReason) String() string {
 		return "SIMULATE_SERVER_LEAVE"
 	case ParticipantCloseReasonNegotiateFailed:
 		return "NEGOTIATE_FAILED"
+	case ParticipantCloseReasonRoomDurationExceeded:
+		return "ROOM_DURATION_EXCEEDED"
+	case ParticipantCloseReasonSessionDurationExceeded:
+		return "SESSION_DURATION_EXCEEDED"
+	case ParticipantCloseReasonBridgedTrackClosed:
+		return "BRIDGED_TRACK_CLOSED"
+	case ParticipantCloseReasonDataLimitExceeded:
+		return "DATA_LIMIT_EXCEEDED"
+	case ParticipantCloseReasonSignalLimitExceeded:
+		return "SIGNAL_LIMIT_EXCEEDED"
 	default:
 		return fmt.Sprintf("%d", int(p))
 	}
 }
 
+// DisconnectReasonSignalLimitExceeded is sent to participants disconnected for exceeding their signal limits.
+// The protocol doesn't define it, it's numbered well past the protocol's own reasons so that they don't collide
+const DisconnectReasonSignalLimitExceeded livekit.DisconnectReason = 100
+
 func (p ParticipantCloseReason) ToDisconnectReason() livekit.DisconnectReason {
 	switch p {
 	case ParticipantCloseReasonClientRequestLeave:
@@ -151,6 +170,12 @@ func (p ParticipantCloseReason) ToDisconnectReason() livekit.DisconnectReason {
 		return livekit.DisconnectReason_SERVER_SHUTDOWN
 	case ParticipantCloseReasonNegotiateFailed:
 		return livekit.DisconnectReason_STATE_MISMATCH
+	case ParticipantCloseReasonRoomDurationExceeded:
+		return livekit.DisconnectReason_ROOM_DELETED
+	case ParticipantCloseReasonSessionDurationExceeded, ParticipantCloseReasonDataLimitExceeded:
+		return livekit.DisconnectReason_PARTICIPANT_REMOVED
+	case ParticipantCloseReasonSignalLimitExceeded:
+		return DisconnectReasonSignalLimitExceeded
 	default:
 		// the other types will map to unknown reason
 		return livekit.DisconnectReason_UNKNOWN_REASON
@@ -167,6 +192,10 @@ type Participant interface {
 	ToProto() *livekit.ParticipantInfo
 
 	SetMetadata(metadata string)
+	// Attributes returns a copy of the participant's attributes and their version
+	Attributes() (map[string]string, uint32)
+	// UpdateAttributes sets attributes key by key, removing keys set to an empty value, returning the keys that changed
+	UpdateAttributes(update map[string]string, ifVersion *uint32, maxSize uint32) (map[string]string, uint32, error)
 
 	GetPublishedTrack(sid livekit.TrackID) MediaTrack
 	GetPublishedTracks() []MediaTrack
@@ -221,16 +250,27 @@ type LocalParticipant interface {
 	// permissions
 	ClaimGrants() *auth.ClaimGrants
 	SetPermission(permission *livekit.ParticipantPermission) bool
+	SetRoomName(roomName livekit.RoomName)
 	CanPublish() bool
 	CanSubscribe() bool
 	CanPublishData() bool
+	CanPublishDataTopic(topic string) bool
+
+	// data topics
+	DataTopics() []string
+	UpdateDataTopics(subscribe []string, unsubscribe []string) error
+	IsSubscribedToDataTopic(topic string) bool
 
 	AddICECandidate(candidate webrtc.ICECandidateInit, target livekit.SignalTarget) error
 
 	HandleOffer(sdp webrtc.SessionDescription) error
 
+	// AllowSignalRequest returns whether a signal request is within the participant's limits, it's dropped otherwise
+	AllowSignalRequest(req *livekit.SignalRequest) bool
+
 	AddTrack(req *livekit.AddTrackRequest)
 	SetTrackMuted(trackID livekit.TrackID, muted bool, fromAdmin bool)
+	UnpublishTrack(trackID livekit.TrackID)
 
 	SubscriberMediaEngine() *webrtc.MediaEngine
 	SubscriberPC() *webrtc.PeerConnection
@@ -253,7 +293,13 @@ type LocalParticipant interface {
 	GetConnectionQuality() *livekit.ConnectionQualityInfo
 
 	// server sent messages
-	SendJoinResponse(info *livekit.Room, otherParticipants []*livekit.ParticipantInfo, iceServers []*livekit.ICEServer, region string) error
+	SendJoinResponse(
+		info *livekit.Room,
+		otherParticipants []*livekit.ParticipantInfo,
+		roomState map[string]*RoomStateEntry,
+		iceServers []*livekit.ICEServer,
+		region string,
+	) error
 	SendParticipantUpdate(participants []*livekit.ParticipantInfo) error
 	SendSpeakerUpdate(speakers []*livekit.SpeakerInfo) error
 	SendDataPacket(packet *livekit.DataPacket) error
@@ -270,7 +316,11 @@ type LocalParticipant interface {
 	OnTrackUpdated(callback func(LocalParticipant, MediaTrack))
 	// OnParticipantUpdate - metadata or permission is updated
 	OnParticipantUpdate(callback func(LocalParticipant))
+	// OnAttributesUpdate - attributes changed, with only the changed keys and their version
+	OnAttributesUpdate(callback func(LocalParticipant, map[string]string, uint32))
 	OnDataPacket(callback func(LocalParticipant, *livekit.DataPacket))
+	// OnDataThrottled is called with the reason data packets of the participant started being dropped
+	OnDataThrottled(callback func(LocalParticipant, string))
 	OnSubscribedTo(callback func(LocalParticipant, livekit.ParticipantID))
 	OnClose(callback func(LocalParticipant, map[livekit.TrackID]livekit.ParticipantID))
 	OnClaimsChanged(callback func(LocalParticipant))
@@ -304,12 +354,14 @@ type Room interface {
 	Name() livekit.RoomName
 	ID() livekit.RoomID
 	RemoveParticipant(identity livekit.ParticipantIdentity, reason ParticipantCloseReason)
+	RemoveParticipantSession(participant LocalParticipant, reason ParticipantCloseReason)
 	UpdateSubscriptions(participant LocalParticipant, trackIDs []livekit.TrackID, participantTracks []*livekit.ParticipantTracks, subscribe bool) error
 	UpdateSubscriptionPermission(participant LocalParticipant, permissions *livekit.SubscriptionPermission) error
 	SyncState(participant LocalParticipant, state *livekit.SyncState) error
 	SimulateScenario(participant LocalParticipant, scenario *livekit.SimulateScenario) error
 	SetParticipantPermission(participant LocalParticipant, permission *livekit.ParticipantPermission) error
 	UpdateVideoLayers(participant Participant, updateVideoLayers *livekit.UpdateVideoLayers) error
+	UpdateStage(participant LocalParticipant, action StageAction, identity livekit.ParticipantIdentity) error
 }
 
 // MediaTrack represents a media track
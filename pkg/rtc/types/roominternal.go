@@ -0,0 +1,86 @@
+package types
+
+import "github.com/livekit/protocol/livekit"
+
+// DuplicateIdentityPolicy decides what happens when a participant joins a room with the identity of a participant
+// already in it
+type DuplicateIdentityPolicy string
+
+const (
+	// the participant in the room is disconnected, the default
+	DuplicateIdentityReplace DuplicateIdentityPolicy = "replace"
+	// the joining participant is refused
+	DuplicateIdentityReject DuplicateIdentityPolicy = "reject"
+	// the joining participant joins alongside with the same identity, its SID suffixed with "#<n>" to tell the
+	// sessions apart
+	DuplicateIdentityAllow DuplicateIdentityPolicy = "allow"
+)
+
+// IsValid returns whether the policy is known, an empty policy being valid
+func (p DuplicateIdentityPolicy) IsValid() bool {
+	switch p {
+	case "", DuplicateIdentityReplace, DuplicateIdentityReject, DuplicateIdentityAllow:
+		return true
+	}
+	return false
+}
+
+// RoomInternal holds server side settings of a room that aren't part of the Room sent to clients.
+// it's stored alongside the room, so every node applies the same settings
+type RoomInternal struct {
+	// name of the template the room was created from
+	Template string `json:"template,omitempty"`
+	// overrides the server wide limit when set
+	MaxMetadataSize uint32 `json:"maxMetadataSize,omitempty"`
+	// overrides the server wide limit on participant attributes when set
+	MaxAttributesSize uint32 `json:"maxAttributesSize,omitempty"`
+	// overrides the server wide limit on the room's shared state when set
+	MaxStateSize uint32 `json:"maxStateSize,omitempty"`
+	// number of seconds the room may stay open after creation, overrides the server wide limit when set
+	MaxDuration uint32 `json:"maxDuration,omitempty"`
+	// max number of participants allowed to publish, 0 for unlimited
+	MaxPublishers uint32 `json:"maxPublishers,omitempty"`
+	// what happens when a participant joins with the identity of one in the room, overrides the server wide policy
+	// when set
+	DuplicateIdentity DuplicateIdentityPolicy `json:"duplicateIdentity,omitempty"`
+
+	// retention of data messages, overrides the server wide setting when set
+	DataHistory *DataHistory `json:"dataHistory,omitempty"`
+
+	// incremented on every update of the room's metadata
+	MetadataVersion uint32 `json:"metadataVersion,omitempty"`
+
+	// new identities can't join a locked room
+	Locked bool `json:"locked,omitempty"`
+
+	// parent of a breakout room, and breakout rooms of a parent
+	Parent    livekit.RoomName   `json:"parent,omitempty"`
+	Breakouts []livekit.RoomName `json:"breakouts,omitempty"`
+	// unix time a breakout room closes at, moving its participants back to the parent. 0 when it's closed explicitly
+	CloseAt int64 `json:"closeAt,omitempty"`
+
+	RecordingDisabled bool `json:"recordingDisabled,omitempty"`
+	// egress types allowed for the room, all are allowed when empty
+	AllowedEgressTypes []string `json:"allowedEgressTypes,omitempty"`
+
+	// sources of tracks participants start with muted
+	StartMuted []livekit.TrackSource `json:"startMuted,omitempty"`
+	// sources of tracks participants may publish, all are allowed when empty
+	PublishSources []livekit.TrackSource `json:"publishSources,omitempty"`
+	// sources allowed by role of the participant, overriding PublishSources
+	RolePublishSources map[string][]livekit.TrackSource `json:"rolePublishSources,omitempty"`
+}
+
+// AllowedSources returns the sources of tracks a participant with the given role may publish, nil when all are allowed
+func (ri *RoomInternal) AllowedSources(role string) []livekit.TrackSource {
+	if sources, ok := ri.RolePublishSources[role]; ok && role != "" {
+		if sources == nil {
+			return []livekit.TrackSource{}
+		}
+		return sources
+	}
+	if len(ri.PublishSources) == 0 {
+		return nil
+	}
+	return ri.PublishSources
+}
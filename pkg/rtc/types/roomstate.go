@@ -0,0 +1,24 @@
+package types
+
+import "github.com/livekit/protocol/livekit"
+
+// RoomStateEntry is the value of a key of a room's shared state
+type RoomStateEntry struct {
+	Value string `json:"value"`
+	// identity of the participant that created the key, empty when created by a room admin.
+	// only the owner and room admins may change the key, unless it's shared
+	Owner  string `json:"owner,omitempty"`
+	Shared bool   `json:"shared,omitempty"`
+	// unix time the key expires at, 0 when it doesn't
+	ExpiresAt int64 `json:"expiresAt,omitempty"`
+}
+
+// WritableBy returns whether a participant may change the key, room admins having no identity
+func (e *RoomStateEntry) WritableBy(identity livekit.ParticipantIdentity) bool {
+	return identity == "" || e.Shared || e.Owner == string(identity)
+}
+
+// Size returns the size of the key and its value, counted against the size limit of the state
+func (e *RoomStateEntry) Size(key string) int {
+	return len(key) + len(e.Value)
+}
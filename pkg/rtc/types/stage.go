@@ -0,0 +1,13 @@
+package types
+
+// StageAction is applied to a participant of a room's stage. Participants request to publish and withdraw their
+// requests themselves, room admins approve, deny or demote them
+type StageAction string
+
+const (
+	StageActionRequest  StageAction = "request"
+	StageActionWithdraw StageAction = "withdraw"
+	StageActionApprove  StageAction = "approve"
+	StageActionDeny     StageAction = "deny"
+	StageActionDemote   StageAction = "demote"
+)
@@ -56,6 +56,29 @@ type FakeLocalParticipant struct {
 	addTrackArgsForCall []struct {
 		arg1 *livekit.AddTrackRequest
 	}
+	AllowSignalRequestStub        func(*livekit.SignalRequest) bool
+	allowSignalRequestMutex       sync.RWMutex
+	allowSignalRequestArgsForCall []struct {
+		arg1 *livekit.SignalRequest
+	}
+	allowSignalRequestReturns struct {
+		result1 bool
+	}
+	allowSignalRequestReturnsOnCall map[int]struct {
+		result1 bool
+	}
+	AttributesStub        func() (map[string]string, uint32)
+	attributesMutex       sync.RWMutex
+	attributesArgsForCall []struct {
+	}
+	attributesReturns struct {
+		result1 map[string]string
+		result2 uint32
+	}
+	attributesReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 uint32
+	}
 	CacheDownTrackStub        func(livekit.TrackID, *webrtc.RTPTransceiver, sfu.ForwarderState)
 	cacheDownTrackMutex       sync.RWMutex
 	cacheDownTrackArgsForCall []struct {
@@ -83,6 +106,17 @@ type FakeLocalParticipant struct {
 	canPublishDataReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	CanPublishDataTopicStub        func(string) bool
+	canPublishDataTopicMutex       sync.RWMutex
+	canPublishDataTopicArgsForCall []struct {
+		arg1 string
+	}
+	canPublishDataTopicReturns struct {
+		result1 bool
+	}
+	canPublishDataTopicReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	CanSubscribeStub        func() bool
 	canSubscribeMutex       sync.RWMutex
 	canSubscribeArgsForCall []struct {
@@ -130,6 +164,16 @@ type FakeLocalParticipant struct {
 	connectedAtReturnsOnCall map[int]struct {
 		result1 time.Time
 	}
+	DataTopicsStub        func() []string
+	dataTopicsMutex       sync.RWMutex
+	dataTopicsArgsForCall []struct {
+	}
+	dataTopicsReturns struct {
+		result1 []string
+	}
+	dataTopicsReturnsOnCall map[int]struct {
+		result1 []string
+	}
 	DebugInfoStub        func() map[string]interface{}
 	debugInfoMutex       sync.RWMutex
 	debugInfoArgsForCall []struct {
@@ -374,6 +418,17 @@ type FakeLocalParticipant struct {
 	isSubscribedToReturnsOnCall map[int]struct {
 		result1 bool
 	}
+	IsSubscribedToDataTopicStub        func(string) bool
+	isSubscribedToDataTopicMutex       sync.RWMutex
+	isSubscribedToDataTopicArgsForCall []struct {
+		arg1 string
+	}
+	isSubscribedToDataTopicReturns struct {
+		result1 bool
+	}
+	isSubscribedToDataTopicReturnsOnCall map[int]struct {
+		result1 bool
+	}
 	MigrateStateStub        func() types.MigrateState
 	migrateStateMutex       sync.RWMutex
 	migrateStateArgsForCall []struct {
@@ -389,6 +444,11 @@ type FakeLocalParticipant struct {
 	negotiateArgsForCall []struct {
 		arg1 bool
 	}
+	OnAttributesUpdateStub        func(func(types.LocalParticipant, map[string]string, uint32))
+	onAttributesUpdateMutex       sync.RWMutex
+	onAttributesUpdateArgsForCall []struct {
+		arg1 func(types.LocalParticipant, map[string]string, uint32)
+	}
 	OnClaimsChangedStub        func(func(types.LocalParticipant))
 	onClaimsChangedMutex       sync.RWMutex
 	onClaimsChangedArgsForCall []struct {
@@ -404,6 +464,11 @@ type FakeLocalParticipant struct {
 	onDataPacketArgsForCall []struct {
 		arg1 func(types.LocalParticipant, *livekit.DataPacket)
 	}
+	OnDataThrottledStub        func(func(types.LocalParticipant, string))
+	onDataThrottledMutex       sync.RWMutex
+	onDataThrottledArgsForCall []struct {
+		arg1 func(types.LocalParticipant, string)
+	}
 	OnICEConfigChangedStub        func(func(participant types.LocalParticipant, iceConfig types.IceConfig))
 	onICEConfigChangedMutex       sync.RWMutex
 	onICEConfigChangedArgsForCall []struct {
@@ -483,13 +548,14 @@ type FakeLocalParticipant struct {
 	sendDataPacketReturnsOnCall map[int]struct {
 		result1 error
 	}
-	SendJoinResponseStub        func(*livekit.Room, []*livekit.ParticipantInfo, []*livekit.ICEServer, string) error
+	SendJoinResponseStub        func(*livekit.Room, []*livekit.ParticipantInfo, map[string]*types.RoomStateEntry, []*livekit.ICEServer, string) error
 	sendJoinResponseMutex       sync.RWMutex
 	sendJoinResponseArgsForCall []struct {
 		arg1 *livekit.Room
 		arg2 []*livekit.ParticipantInfo
-		arg3 []*livekit.ICEServer
-		arg4 string
+		arg3 map[string]*types.RoomStateEntry
+		arg4 []*livekit.ICEServer
+		arg5 string
 	}
 	sendJoinResponseReturns struct {
 		result1 error
@@ -579,6 +645,11 @@ type FakeLocalParticipant struct {
 	setResponseSinkArgsForCall []struct {
 		arg1 routing.MessageSink
 	}
+	SetRoomNameStub        func(livekit.RoomName)
+	setRoomNameMutex       sync.RWMutex
+	setRoomNameArgsForCall []struct {
+		arg1 livekit.RoomName
+	}
 	SetTrackMutedStub        func(livekit.TrackID, bool, bool)
 	setTrackMutedMutex       sync.RWMutex
 	setTrackMutedArgsForCall []struct {
@@ -664,6 +735,40 @@ type FakeLocalParticipant struct {
 	uncacheDownTrackArgsForCall []struct {
 		arg1 *webrtc.RTPTransceiver
 	}
+	UnpublishTrackStub        func(livekit.TrackID)
+	unpublishTrackMutex       sync.RWMutex
+	unpublishTrackArgsForCall []struct {
+		arg1 livekit.TrackID
+	}
+	UpdateAttributesStub        func(map[string]string, *uint32, uint32) (map[string]string, uint32, error)
+	updateAttributesMutex       sync.RWMutex
+	updateAttributesArgsForCall []struct {
+		arg1 map[string]string
+		arg2 *uint32
+		arg3 uint32
+	}
+	updateAttributesReturns struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}
+	updateAttributesReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}
+	UpdateDataTopicsStub        func([]string, []string) error
+	updateDataTopicsMutex       sync.RWMutex
+	updateDataTopicsArgsForCall []struct {
+		arg1 []string
+		arg2 []string
+	}
+	updateDataTopicsReturns struct {
+		result1 error
+	}
+	updateDataTopicsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateMediaLossStub        func(livekit.NodeID, livekit.TrackID, uint32) error
 	updateMediaLossMutex       sync.RWMutex
 	updateMediaLossArgsForCall []struct {
@@ -959,6 +1064,123 @@ func (fake *FakeLocalParticipant) AddTrackArgsForCall(i int) *livekit.AddTrackRe
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) AllowSignalRequest(arg1 *livekit.SignalRequest) bool {
+	fake.allowSignalRequestMutex.Lock()
+	ret, specificReturn := fake.allowSignalRequestReturnsOnCall[len(fake.allowSignalRequestArgsForCall)]
+	fake.allowSignalRequestArgsForCall = append(fake.allowSignalRequestArgsForCall, struct {
+		arg1 *livekit.SignalRequest
+	}{arg1})
+	stub := fake.AllowSignalRequestStub
+	fakeReturns := fake.allowSignalRequestReturns
+	fake.recordInvocation("AllowSignalRequest", []interface{}{arg1})
+	fake.allowSignalRequestMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) AllowSignalRequestCallCount() int {
+	fake.allowSignalRequestMutex.RLock()
+	defer fake.allowSignalRequestMutex.RUnlock()
+	return len(fake.allowSignalRequestArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) AllowSignalRequestCalls(stub func(*livekit.SignalRequest) bool) {
+	fake.allowSignalRequestMutex.Lock()
+	defer fake.allowSignalRequestMutex.Unlock()
+	fake.AllowSignalRequestStub = stub
+}
+
+func (fake *FakeLocalParticipant) AllowSignalRequestArgsForCall(i int) *livekit.SignalRequest {
+	fake.allowSignalRequestMutex.RLock()
+	defer fake.allowSignalRequestMutex.RUnlock()
+	argsForCall := fake.allowSignalRequestArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) AllowSignalRequestReturns(result1 bool) {
+	fake.allowSignalRequestMutex.Lock()
+	defer fake.allowSignalRequestMutex.Unlock()
+	fake.AllowSignalRequestStub = nil
+	fake.allowSignalRequestReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) AllowSignalRequestReturnsOnCall(i int, result1 bool) {
+	fake.allowSignalRequestMutex.Lock()
+	defer fake.allowSignalRequestMutex.Unlock()
+	fake.AllowSignalRequestStub = nil
+	if fake.allowSignalRequestReturnsOnCall == nil {
+		fake.allowSignalRequestReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.allowSignalRequestReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) Attributes() (map[string]string, uint32) {
+	fake.attributesMutex.Lock()
+	ret, specificReturn := fake.attributesReturnsOnCall[len(fake.attributesArgsForCall)]
+	fake.attributesArgsForCall = append(fake.attributesArgsForCall, struct {
+	}{})
+	stub := fake.AttributesStub
+	fakeReturns := fake.attributesReturns
+	fake.recordInvocation("Attributes", []interface{}{})
+	fake.attributesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeLocalParticipant) AttributesCallCount() int {
+	fake.attributesMutex.RLock()
+	defer fake.attributesMutex.RUnlock()
+	return len(fake.attributesArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) AttributesCalls(stub func() (map[string]string, uint32)) {
+	fake.attributesMutex.Lock()
+	defer fake.attributesMutex.Unlock()
+	fake.AttributesStub = stub
+}
+
+func (fake *FakeLocalParticipant) AttributesReturns(result1 map[string]string, result2 uint32) {
+	fake.attributesMutex.Lock()
+	defer fake.attributesMutex.Unlock()
+	fake.AttributesStub = nil
+	fake.attributesReturns = struct {
+		result1 map[string]string
+		result2 uint32
+	}{result1, result2}
+}
+
+func (fake *FakeLocalParticipant) AttributesReturnsOnCall(i int, result1 map[string]string, result2 uint32) {
+	fake.attributesMutex.Lock()
+	defer fake.attributesMutex.Unlock()
+	fake.AttributesStub = nil
+	if fake.attributesReturnsOnCall == nil {
+		fake.attributesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 uint32
+		})
+	}
+	fake.attributesReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 uint32
+	}{result1, result2}
+}
+
 func (fake *FakeLocalParticipant) CacheDownTrack(arg1 livekit.TrackID, arg2 *webrtc.RTPTransceiver, arg3 sfu.ForwarderState) {
 	fake.cacheDownTrackMutex.Lock()
 	fake.cacheDownTrackArgsForCall = append(fake.cacheDownTrackArgsForCall, struct {
@@ -1099,6 +1321,67 @@ func (fake *FakeLocalParticipant) CanPublishDataReturnsOnCall(i int, result1 boo
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) CanPublishDataTopic(arg1 string) bool {
+	fake.canPublishDataTopicMutex.Lock()
+	ret, specificReturn := fake.canPublishDataTopicReturnsOnCall[len(fake.canPublishDataTopicArgsForCall)]
+	fake.canPublishDataTopicArgsForCall = append(fake.canPublishDataTopicArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.CanPublishDataTopicStub
+	fakeReturns := fake.canPublishDataTopicReturns
+	fake.recordInvocation("CanPublishDataTopic", []interface{}{arg1})
+	fake.canPublishDataTopicMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) CanPublishDataTopicCallCount() int {
+	fake.canPublishDataTopicMutex.RLock()
+	defer fake.canPublishDataTopicMutex.RUnlock()
+	return len(fake.canPublishDataTopicArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) CanPublishDataTopicCalls(stub func(string) bool) {
+	fake.canPublishDataTopicMutex.Lock()
+	defer fake.canPublishDataTopicMutex.Unlock()
+	fake.CanPublishDataTopicStub = stub
+}
+
+func (fake *FakeLocalParticipant) CanPublishDataTopicArgsForCall(i int) string {
+	fake.canPublishDataTopicMutex.RLock()
+	defer fake.canPublishDataTopicMutex.RUnlock()
+	argsForCall := fake.canPublishDataTopicArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) CanPublishDataTopicReturns(result1 bool) {
+	fake.canPublishDataTopicMutex.Lock()
+	defer fake.canPublishDataTopicMutex.Unlock()
+	fake.CanPublishDataTopicStub = nil
+	fake.canPublishDataTopicReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) CanPublishDataTopicReturnsOnCall(i int, result1 bool) {
+	fake.canPublishDataTopicMutex.Lock()
+	defer fake.canPublishDataTopicMutex.Unlock()
+	fake.CanPublishDataTopicStub = nil
+	if fake.canPublishDataTopicReturnsOnCall == nil {
+		fake.canPublishDataTopicReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.canPublishDataTopicReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) CanSubscribe() bool {
 	fake.canSubscribeMutex.Lock()
 	ret, specificReturn := fake.canSubscribeReturnsOnCall[len(fake.canSubscribeArgsForCall)]
@@ -1352,6 +1635,59 @@ func (fake *FakeLocalParticipant) ConnectedAtReturnsOnCall(i int, result1 time.T
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) DataTopics() []string {
+	fake.dataTopicsMutex.Lock()
+	ret, specificReturn := fake.dataTopicsReturnsOnCall[len(fake.dataTopicsArgsForCall)]
+	fake.dataTopicsArgsForCall = append(fake.dataTopicsArgsForCall, struct {
+	}{})
+	stub := fake.DataTopicsStub
+	fakeReturns := fake.dataTopicsReturns
+	fake.recordInvocation("DataTopics", []interface{}{})
+	fake.dataTopicsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) DataTopicsCallCount() int {
+	fake.dataTopicsMutex.RLock()
+	defer fake.dataTopicsMutex.RUnlock()
+	return len(fake.dataTopicsArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) DataTopicsCalls(stub func() []string) {
+	fake.dataTopicsMutex.Lock()
+	defer fake.dataTopicsMutex.Unlock()
+	fake.DataTopicsStub = stub
+}
+
+func (fake *FakeLocalParticipant) DataTopicsReturns(result1 []string) {
+	fake.dataTopicsMutex.Lock()
+	defer fake.dataTopicsMutex.Unlock()
+	fake.DataTopicsStub = nil
+	fake.dataTopicsReturns = struct {
+		result1 []string
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) DataTopicsReturnsOnCall(i int, result1 []string) {
+	fake.dataTopicsMutex.Lock()
+	defer fake.dataTopicsMutex.Unlock()
+	fake.DataTopicsStub = nil
+	if fake.dataTopicsReturnsOnCall == nil {
+		fake.dataTopicsReturnsOnCall = make(map[int]struct {
+			result1 []string
+		})
+	}
+	fake.dataTopicsReturnsOnCall[i] = struct {
+		result1 []string
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) DebugInfo() map[string]interface{} {
 	fake.debugInfoMutex.Lock()
 	ret, specificReturn := fake.debugInfoReturnsOnCall[len(fake.debugInfoArgsForCall)]
@@ -2647,6 +2983,67 @@ func (fake *FakeLocalParticipant) IsSubscribedToReturnsOnCall(i int, result1 boo
 	}{result1}
 }
 
+func (fake *FakeLocalParticipant) IsSubscribedToDataTopic(arg1 string) bool {
+	fake.isSubscribedToDataTopicMutex.Lock()
+	ret, specificReturn := fake.isSubscribedToDataTopicReturnsOnCall[len(fake.isSubscribedToDataTopicArgsForCall)]
+	fake.isSubscribedToDataTopicArgsForCall = append(fake.isSubscribedToDataTopicArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.IsSubscribedToDataTopicStub
+	fakeReturns := fake.isSubscribedToDataTopicReturns
+	fake.recordInvocation("IsSubscribedToDataTopic", []interface{}{arg1})
+	fake.isSubscribedToDataTopicMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) IsSubscribedToDataTopicCallCount() int {
+	fake.isSubscribedToDataTopicMutex.RLock()
+	defer fake.isSubscribedToDataTopicMutex.RUnlock()
+	return len(fake.isSubscribedToDataTopicArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) IsSubscribedToDataTopicCalls(stub func(string) bool) {
+	fake.isSubscribedToDataTopicMutex.Lock()
+	defer fake.isSubscribedToDataTopicMutex.Unlock()
+	fake.IsSubscribedToDataTopicStub = stub
+}
+
+func (fake *FakeLocalParticipant) IsSubscribedToDataTopicArgsForCall(i int) string {
+	fake.isSubscribedToDataTopicMutex.RLock()
+	defer fake.isSubscribedToDataTopicMutex.RUnlock()
+	argsForCall := fake.isSubscribedToDataTopicArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) IsSubscribedToDataTopicReturns(result1 bool) {
+	fake.isSubscribedToDataTopicMutex.Lock()
+	defer fake.isSubscribedToDataTopicMutex.Unlock()
+	fake.IsSubscribedToDataTopicStub = nil
+	fake.isSubscribedToDataTopicReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) IsSubscribedToDataTopicReturnsOnCall(i int, result1 bool) {
+	fake.isSubscribedToDataTopicMutex.Lock()
+	defer fake.isSubscribedToDataTopicMutex.Unlock()
+	fake.IsSubscribedToDataTopicStub = nil
+	if fake.isSubscribedToDataTopicReturnsOnCall == nil {
+		fake.isSubscribedToDataTopicReturnsOnCall = make(map[int]struct {
+			result1 bool
+		})
+	}
+	fake.isSubscribedToDataTopicReturnsOnCall[i] = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) MigrateState() types.MigrateState {
 	fake.migrateStateMutex.Lock()
 	ret, specificReturn := fake.migrateStateReturnsOnCall[len(fake.migrateStateArgsForCall)]
@@ -2732,6 +3129,38 @@ func (fake *FakeLocalParticipant) NegotiateArgsForCall(i int) bool {
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) OnAttributesUpdate(arg1 func(types.LocalParticipant, map[string]string, uint32)) {
+	fake.onAttributesUpdateMutex.Lock()
+	fake.onAttributesUpdateArgsForCall = append(fake.onAttributesUpdateArgsForCall, struct {
+		arg1 func(types.LocalParticipant, map[string]string, uint32)
+	}{arg1})
+	stub := fake.OnAttributesUpdateStub
+	fake.recordInvocation("OnAttributesUpdate", []interface{}{arg1})
+	fake.onAttributesUpdateMutex.Unlock()
+	if stub != nil {
+		fake.OnAttributesUpdateStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) OnAttributesUpdateCallCount() int {
+	fake.onAttributesUpdateMutex.RLock()
+	defer fake.onAttributesUpdateMutex.RUnlock()
+	return len(fake.onAttributesUpdateArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) OnAttributesUpdateCalls(stub func(func(types.LocalParticipant, map[string]string, uint32))) {
+	fake.onAttributesUpdateMutex.Lock()
+	defer fake.onAttributesUpdateMutex.Unlock()
+	fake.OnAttributesUpdateStub = stub
+}
+
+func (fake *FakeLocalParticipant) OnAttributesUpdateArgsForCall(i int) func(types.LocalParticipant, map[string]string, uint32) {
+	fake.onAttributesUpdateMutex.RLock()
+	defer fake.onAttributesUpdateMutex.RUnlock()
+	argsForCall := fake.onAttributesUpdateArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) OnClaimsChanged(arg1 func(types.LocalParticipant)) {
 	fake.onClaimsChangedMutex.Lock()
 	fake.onClaimsChangedArgsForCall = append(fake.onClaimsChangedArgsForCall, struct {
@@ -2828,6 +3257,38 @@ func (fake *FakeLocalParticipant) OnDataPacketArgsForCall(i int) func(types.Loca
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) OnDataThrottled(arg1 func(types.LocalParticipant, string)) {
+	fake.onDataThrottledMutex.Lock()
+	fake.onDataThrottledArgsForCall = append(fake.onDataThrottledArgsForCall, struct {
+		arg1 func(types.LocalParticipant, string)
+	}{arg1})
+	stub := fake.OnDataThrottledStub
+	fake.recordInvocation("OnDataThrottled", []interface{}{arg1})
+	fake.onDataThrottledMutex.Unlock()
+	if stub != nil {
+		fake.OnDataThrottledStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) OnDataThrottledCallCount() int {
+	fake.onDataThrottledMutex.RLock()
+	defer fake.onDataThrottledMutex.RUnlock()
+	return len(fake.onDataThrottledArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) OnDataThrottledCalls(stub func(func(types.LocalParticipant, string))) {
+	fake.onDataThrottledMutex.Lock()
+	defer fake.onDataThrottledMutex.Unlock()
+	fake.OnDataThrottledStub = stub
+}
+
+func (fake *FakeLocalParticipant) OnDataThrottledArgsForCall(i int) func(types.LocalParticipant, string) {
+	fake.onDataThrottledMutex.RLock()
+	defer fake.onDataThrottledMutex.RUnlock()
+	argsForCall := fake.onDataThrottledArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) OnICEConfigChanged(arg1 func(participant types.LocalParticipant, iceConfig types.IceConfig)) {
 	fake.onICEConfigChangedMutex.Lock()
 	fake.onICEConfigChangedArgsForCall = append(fake.onICEConfigChangedArgsForCall, struct {
@@ -3293,31 +3754,32 @@ func (fake *FakeLocalParticipant) SendDataPacketReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
-func (fake *FakeLocalParticipant) SendJoinResponse(arg1 *livekit.Room, arg2 []*livekit.ParticipantInfo, arg3 []*livekit.ICEServer, arg4 string) error {
+func (fake *FakeLocalParticipant) SendJoinResponse(arg1 *livekit.Room, arg2 []*livekit.ParticipantInfo, arg3 map[string]*types.RoomStateEntry, arg4 []*livekit.ICEServer, arg5 string) error {
 	var arg2Copy []*livekit.ParticipantInfo
 	if arg2 != nil {
 		arg2Copy = make([]*livekit.ParticipantInfo, len(arg2))
 		copy(arg2Copy, arg2)
 	}
-	var arg3Copy []*livekit.ICEServer
-	if arg3 != nil {
-		arg3Copy = make([]*livekit.ICEServer, len(arg3))
-		copy(arg3Copy, arg3)
+	var arg4Copy []*livekit.ICEServer
+	if arg4 != nil {
+		arg4Copy = make([]*livekit.ICEServer, len(arg4))
+		copy(arg4Copy, arg4)
 	}
 	fake.sendJoinResponseMutex.Lock()
 	ret, specificReturn := fake.sendJoinResponseReturnsOnCall[len(fake.sendJoinResponseArgsForCall)]
 	fake.sendJoinResponseArgsForCall = append(fake.sendJoinResponseArgsForCall, struct {
 		arg1 *livekit.Room
 		arg2 []*livekit.ParticipantInfo
-		arg3 []*livekit.ICEServer
-		arg4 string
-	}{arg1, arg2Copy, arg3Copy, arg4})
+		arg3 map[string]*types.RoomStateEntry
+		arg4 []*livekit.ICEServer
+		arg5 string
+	}{arg1, arg2Copy, arg3, arg4Copy, arg5})
 	stub := fake.SendJoinResponseStub
 	fakeReturns := fake.sendJoinResponseReturns
-	fake.recordInvocation("SendJoinResponse", []interface{}{arg1, arg2Copy, arg3Copy, arg4})
+	fake.recordInvocation("SendJoinResponse", []interface{}{arg1, arg2Copy, arg3, arg4Copy, arg5})
 	fake.sendJoinResponseMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1
@@ -3331,17 +3793,17 @@ func (fake *FakeLocalParticipant) SendJoinResponseCallCount() int {
 	return len(fake.sendJoinResponseArgsForCall)
 }
 
-func (fake *FakeLocalParticipant) SendJoinResponseCalls(stub func(*livekit.Room, []*livekit.ParticipantInfo, []*livekit.ICEServer, string) error) {
+func (fake *FakeLocalParticipant) SendJoinResponseCalls(stub func(*livekit.Room, []*livekit.ParticipantInfo, map[string]*types.RoomStateEntry, []*livekit.ICEServer, string) error) {
 	fake.sendJoinResponseMutex.Lock()
 	defer fake.sendJoinResponseMutex.Unlock()
 	fake.SendJoinResponseStub = stub
 }
 
-func (fake *FakeLocalParticipant) SendJoinResponseArgsForCall(i int) (*livekit.Room, []*livekit.ParticipantInfo, []*livekit.ICEServer, string) {
+func (fake *FakeLocalParticipant) SendJoinResponseArgsForCall(i int) (*livekit.Room, []*livekit.ParticipantInfo, map[string]*types.RoomStateEntry, []*livekit.ICEServer, string) {
 	fake.sendJoinResponseMutex.RLock()
 	defer fake.sendJoinResponseMutex.RUnlock()
 	argsForCall := fake.sendJoinResponseArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeLocalParticipant) SendJoinResponseReturns(result1 error) {
@@ -3854,6 +4316,38 @@ func (fake *FakeLocalParticipant) SetResponseSinkArgsForCall(i int) routing.Mess
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) SetRoomName(arg1 livekit.RoomName) {
+	fake.setRoomNameMutex.Lock()
+	fake.setRoomNameArgsForCall = append(fake.setRoomNameArgsForCall, struct {
+		arg1 livekit.RoomName
+	}{arg1})
+	stub := fake.SetRoomNameStub
+	fake.recordInvocation("SetRoomName", []interface{}{arg1})
+	fake.setRoomNameMutex.Unlock()
+	if stub != nil {
+		fake.SetRoomNameStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) SetRoomNameCallCount() int {
+	fake.setRoomNameMutex.RLock()
+	defer fake.setRoomNameMutex.RUnlock()
+	return len(fake.setRoomNameArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) SetRoomNameCalls(stub func(livekit.RoomName)) {
+	fake.setRoomNameMutex.Lock()
+	defer fake.setRoomNameMutex.Unlock()
+	fake.SetRoomNameStub = stub
+}
+
+func (fake *FakeLocalParticipant) SetRoomNameArgsForCall(i int) livekit.RoomName {
+	fake.setRoomNameMutex.RLock()
+	defer fake.setRoomNameMutex.RUnlock()
+	argsForCall := fake.setRoomNameArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeLocalParticipant) SetTrackMuted(arg1 livekit.TrackID, arg2 bool, arg3 bool) {
 	fake.setTrackMutedMutex.Lock()
 	fake.setTrackMutedArgsForCall = append(fake.setTrackMutedArgsForCall, struct {
@@ -4299,6 +4793,179 @@ func (fake *FakeLocalParticipant) UncacheDownTrackArgsForCall(i int) *webrtc.RTP
 	return argsForCall.arg1
 }
 
+func (fake *FakeLocalParticipant) UnpublishTrack(arg1 livekit.TrackID) {
+	fake.unpublishTrackMutex.Lock()
+	fake.unpublishTrackArgsForCall = append(fake.unpublishTrackArgsForCall, struct {
+		arg1 livekit.TrackID
+	}{arg1})
+	stub := fake.UnpublishTrackStub
+	fake.recordInvocation("UnpublishTrack", []interface{}{arg1})
+	fake.unpublishTrackMutex.Unlock()
+	if stub != nil {
+		fake.UnpublishTrackStub(arg1)
+	}
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackCallCount() int {
+	fake.unpublishTrackMutex.RLock()
+	defer fake.unpublishTrackMutex.RUnlock()
+	return len(fake.unpublishTrackArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackCalls(stub func(livekit.TrackID)) {
+	fake.unpublishTrackMutex.Lock()
+	defer fake.unpublishTrackMutex.Unlock()
+	fake.UnpublishTrackStub = stub
+}
+
+func (fake *FakeLocalParticipant) UnpublishTrackArgsForCall(i int) livekit.TrackID {
+	fake.unpublishTrackMutex.RLock()
+	defer fake.unpublishTrackMutex.RUnlock()
+	argsForCall := fake.unpublishTrackArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeLocalParticipant) UpdateAttributes(arg1 map[string]string, arg2 *uint32, arg3 uint32) (map[string]string, uint32, error) {
+	fake.updateAttributesMutex.Lock()
+	ret, specificReturn := fake.updateAttributesReturnsOnCall[len(fake.updateAttributesArgsForCall)]
+	fake.updateAttributesArgsForCall = append(fake.updateAttributesArgsForCall, struct {
+		arg1 map[string]string
+		arg2 *uint32
+		arg3 uint32
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateAttributesStub
+	fakeReturns := fake.updateAttributesReturns
+	fake.recordInvocation("UpdateAttributes", []interface{}{arg1, arg2, arg3})
+	fake.updateAttributesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeLocalParticipant) UpdateAttributesCallCount() int {
+	fake.updateAttributesMutex.RLock()
+	defer fake.updateAttributesMutex.RUnlock()
+	return len(fake.updateAttributesArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) UpdateAttributesCalls(stub func(map[string]string, *uint32, uint32) (map[string]string, uint32, error)) {
+	fake.updateAttributesMutex.Lock()
+	defer fake.updateAttributesMutex.Unlock()
+	fake.UpdateAttributesStub = stub
+}
+
+func (fake *FakeLocalParticipant) UpdateAttributesArgsForCall(i int) (map[string]string, *uint32, uint32) {
+	fake.updateAttributesMutex.RLock()
+	defer fake.updateAttributesMutex.RUnlock()
+	argsForCall := fake.updateAttributesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeLocalParticipant) UpdateAttributesReturns(result1 map[string]string, result2 uint32, result3 error) {
+	fake.updateAttributesMutex.Lock()
+	defer fake.updateAttributesMutex.Unlock()
+	fake.UpdateAttributesStub = nil
+	fake.updateAttributesReturns = struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeLocalParticipant) UpdateAttributesReturnsOnCall(i int, result1 map[string]string, result2 uint32, result3 error) {
+	fake.updateAttributesMutex.Lock()
+	defer fake.updateAttributesMutex.Unlock()
+	fake.UpdateAttributesStub = nil
+	if fake.updateAttributesReturnsOnCall == nil {
+		fake.updateAttributesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 uint32
+			result3 error
+		})
+	}
+	fake.updateAttributesReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeLocalParticipant) UpdateDataTopics(arg1 []string, arg2 []string) error {
+	var arg1Copy []string
+	if arg1 != nil {
+		arg1Copy = make([]string, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	var arg2Copy []string
+	if arg2 != nil {
+		arg2Copy = make([]string, len(arg2))
+		copy(arg2Copy, arg2)
+	}
+	fake.updateDataTopicsMutex.Lock()
+	ret, specificReturn := fake.updateDataTopicsReturnsOnCall[len(fake.updateDataTopicsArgsForCall)]
+	fake.updateDataTopicsArgsForCall = append(fake.updateDataTopicsArgsForCall, struct {
+		arg1 []string
+		arg2 []string
+	}{arg1Copy, arg2Copy})
+	stub := fake.UpdateDataTopicsStub
+	fakeReturns := fake.updateDataTopicsReturns
+	fake.recordInvocation("UpdateDataTopics", []interface{}{arg1Copy, arg2Copy})
+	fake.updateDataTopicsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeLocalParticipant) UpdateDataTopicsCallCount() int {
+	fake.updateDataTopicsMutex.RLock()
+	defer fake.updateDataTopicsMutex.RUnlock()
+	return len(fake.updateDataTopicsArgsForCall)
+}
+
+func (fake *FakeLocalParticipant) UpdateDataTopicsCalls(stub func([]string, []string) error) {
+	fake.updateDataTopicsMutex.Lock()
+	defer fake.updateDataTopicsMutex.Unlock()
+	fake.UpdateDataTopicsStub = stub
+}
+
+func (fake *FakeLocalParticipant) UpdateDataTopicsArgsForCall(i int) ([]string, []string) {
+	fake.updateDataTopicsMutex.RLock()
+	defer fake.updateDataTopicsMutex.RUnlock()
+	argsForCall := fake.updateDataTopicsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeLocalParticipant) UpdateDataTopicsReturns(result1 error) {
+	fake.updateDataTopicsMutex.Lock()
+	defer fake.updateDataTopicsMutex.Unlock()
+	fake.UpdateDataTopicsStub = nil
+	fake.updateDataTopicsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeLocalParticipant) UpdateDataTopicsReturnsOnCall(i int, result1 error) {
+	fake.updateDataTopicsMutex.Lock()
+	defer fake.updateDataTopicsMutex.Unlock()
+	fake.UpdateDataTopicsStub = nil
+	if fake.updateDataTopicsReturnsOnCall == nil {
+		fake.updateDataTopicsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateDataTopicsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeLocalParticipant) UpdateMediaLoss(arg1 livekit.NodeID, arg2 livekit.TrackID, arg3 uint32) error {
 	fake.updateMediaLossMutex.Lock()
 	ret, specificReturn := fake.updateMediaLossReturnsOnCall[len(fake.updateMediaLossArgsForCall)]
@@ -4662,12 +5329,18 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.addSubscriberMutex.RUnlock()
 	fake.addTrackMutex.RLock()
 	defer fake.addTrackMutex.RUnlock()
+	fake.allowSignalRequestMutex.RLock()
+	defer fake.allowSignalRequestMutex.RUnlock()
+	fake.attributesMutex.RLock()
+	defer fake.attributesMutex.RUnlock()
 	fake.cacheDownTrackMutex.RLock()
 	defer fake.cacheDownTrackMutex.RUnlock()
 	fake.canPublishMutex.RLock()
 	defer fake.canPublishMutex.RUnlock()
 	fake.canPublishDataMutex.RLock()
 	defer fake.canPublishDataMutex.RUnlock()
+	fake.canPublishDataTopicMutex.RLock()
+	defer fake.canPublishDataTopicMutex.RUnlock()
 	fake.canSubscribeMutex.RLock()
 	defer fake.canSubscribeMutex.RUnlock()
 	fake.claimGrantsMutex.RLock()
@@ -4678,6 +5351,8 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.closeMutex.RUnlock()
 	fake.connectedAtMutex.RLock()
 	defer fake.connectedAtMutex.RUnlock()
+	fake.dataTopicsMutex.RLock()
+	defer fake.dataTopicsMutex.RUnlock()
 	fake.debugInfoMutex.RLock()
 	defer fake.debugInfoMutex.RUnlock()
 	fake.enqueueSubscribeTrackMutex.RLock()
@@ -4726,16 +5401,22 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.isRecorderMutex.RUnlock()
 	fake.isSubscribedToMutex.RLock()
 	defer fake.isSubscribedToMutex.RUnlock()
+	fake.isSubscribedToDataTopicMutex.RLock()
+	defer fake.isSubscribedToDataTopicMutex.RUnlock()
 	fake.migrateStateMutex.RLock()
 	defer fake.migrateStateMutex.RUnlock()
 	fake.negotiateMutex.RLock()
 	defer fake.negotiateMutex.RUnlock()
+	fake.onAttributesUpdateMutex.RLock()
+	defer fake.onAttributesUpdateMutex.RUnlock()
 	fake.onClaimsChangedMutex.RLock()
 	defer fake.onClaimsChangedMutex.RUnlock()
 	fake.onCloseMutex.RLock()
 	defer fake.onCloseMutex.RUnlock()
 	fake.onDataPacketMutex.RLock()
 	defer fake.onDataPacketMutex.RUnlock()
+	fake.onDataThrottledMutex.RLock()
+	defer fake.onDataThrottledMutex.RUnlock()
 	fake.onICEConfigChangedMutex.RLock()
 	defer fake.onICEConfigChangedMutex.RUnlock()
 	fake.onParticipantUpdateMutex.RLock()
@@ -4782,6 +5463,8 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.setPermissionMutex.RUnlock()
 	fake.setResponseSinkMutex.RLock()
 	defer fake.setResponseSinkMutex.RUnlock()
+	fake.setRoomNameMutex.RLock()
+	defer fake.setRoomNameMutex.RUnlock()
 	fake.setTrackMutedMutex.RLock()
 	defer fake.setTrackMutedMutex.RUnlock()
 	fake.startMutex.RLock()
@@ -4802,6 +5485,12 @@ func (fake *FakeLocalParticipant) Invocations() map[string][][]interface{} {
 	defer fake.toProtoMutex.RUnlock()
 	fake.uncacheDownTrackMutex.RLock()
 	defer fake.uncacheDownTrackMutex.RUnlock()
+	fake.unpublishTrackMutex.RLock()
+	defer fake.unpublishTrackMutex.RUnlock()
+	fake.updateAttributesMutex.RLock()
+	defer fake.updateAttributesMutex.RUnlock()
+	fake.updateDataTopicsMutex.RLock()
+	defer fake.updateDataTopicsMutex.RUnlock()
 	fake.updateMediaLossMutex.RLock()
 	defer fake.updateMediaLossMutex.RUnlock()
 	fake.updateRTTMutex.RLock()
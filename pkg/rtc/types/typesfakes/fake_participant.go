@@ -23,6 +23,18 @@ type FakeParticipant struct {
 		result1 int
 		result2 error
 	}
+	AttributesStub        func() (map[string]string, uint32)
+	attributesMutex       sync.RWMutex
+	attributesArgsForCall []struct {
+	}
+	attributesReturns struct {
+		result1 map[string]string
+		result2 uint32
+	}
+	attributesReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 uint32
+	}
 	CloseStub        func(bool, types.ParticipantCloseReason) error
 	closeMutex       sync.RWMutex
 	closeArgsForCall []struct {
@@ -144,6 +156,23 @@ type FakeParticipant struct {
 	toProtoReturnsOnCall map[int]struct {
 		result1 *livekit.ParticipantInfo
 	}
+	UpdateAttributesStub        func(map[string]string, *uint32, uint32) (map[string]string, uint32, error)
+	updateAttributesMutex       sync.RWMutex
+	updateAttributesArgsForCall []struct {
+		arg1 map[string]string
+		arg2 *uint32
+		arg3 uint32
+	}
+	updateAttributesReturns struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}
+	updateAttributesReturnsOnCall map[int]struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}
 	UpdateSubscriptionPermissionStub        func(*livekit.SubscriptionPermission, *livekit.TimedVersion, func(participantIdentity livekit.ParticipantIdentity) types.LocalParticipant, func(participantID livekit.ParticipantID) types.LocalParticipant) error
 	updateSubscriptionPermissionMutex       sync.RWMutex
 	updateSubscriptionPermissionArgsForCall []struct {
@@ -238,6 +267,62 @@ func (fake *FakeParticipant) AddSubscriberReturnsOnCall(i int, result1 int, resu
 	}{result1, result2}
 }
 
+func (fake *FakeParticipant) Attributes() (map[string]string, uint32) {
+	fake.attributesMutex.Lock()
+	ret, specificReturn := fake.attributesReturnsOnCall[len(fake.attributesArgsForCall)]
+	fake.attributesArgsForCall = append(fake.attributesArgsForCall, struct {
+	}{})
+	stub := fake.AttributesStub
+	fakeReturns := fake.attributesReturns
+	fake.recordInvocation("Attributes", []interface{}{})
+	fake.attributesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeParticipant) AttributesCallCount() int {
+	fake.attributesMutex.RLock()
+	defer fake.attributesMutex.RUnlock()
+	return len(fake.attributesArgsForCall)
+}
+
+func (fake *FakeParticipant) AttributesCalls(stub func() (map[string]string, uint32)) {
+	fake.attributesMutex.Lock()
+	defer fake.attributesMutex.Unlock()
+	fake.AttributesStub = stub
+}
+
+func (fake *FakeParticipant) AttributesReturns(result1 map[string]string, result2 uint32) {
+	fake.attributesMutex.Lock()
+	defer fake.attributesMutex.Unlock()
+	fake.AttributesStub = nil
+	fake.attributesReturns = struct {
+		result1 map[string]string
+		result2 uint32
+	}{result1, result2}
+}
+
+func (fake *FakeParticipant) AttributesReturnsOnCall(i int, result1 map[string]string, result2 uint32) {
+	fake.attributesMutex.Lock()
+	defer fake.attributesMutex.Unlock()
+	fake.AttributesStub = nil
+	if fake.attributesReturnsOnCall == nil {
+		fake.attributesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 uint32
+		})
+	}
+	fake.attributesReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 uint32
+	}{result1, result2}
+}
+
 func (fake *FakeParticipant) Close(arg1 bool, arg2 types.ParticipantCloseReason) error {
 	fake.closeMutex.Lock()
 	ret, specificReturn := fake.closeReturnsOnCall[len(fake.closeArgsForCall)]
@@ -878,6 +963,75 @@ func (fake *FakeParticipant) ToProtoReturnsOnCall(i int, result1 *livekit.Partic
 	}{result1}
 }
 
+func (fake *FakeParticipant) UpdateAttributes(arg1 map[string]string, arg2 *uint32, arg3 uint32) (map[string]string, uint32, error) {
+	fake.updateAttributesMutex.Lock()
+	ret, specificReturn := fake.updateAttributesReturnsOnCall[len(fake.updateAttributesArgsForCall)]
+	fake.updateAttributesArgsForCall = append(fake.updateAttributesArgsForCall, struct {
+		arg1 map[string]string
+		arg2 *uint32
+		arg3 uint32
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateAttributesStub
+	fakeReturns := fake.updateAttributesReturns
+	fake.recordInvocation("UpdateAttributes", []interface{}{arg1, arg2, arg3})
+	fake.updateAttributesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2, ret.result3
+	}
+	return fakeReturns.result1, fakeReturns.result2, fakeReturns.result3
+}
+
+func (fake *FakeParticipant) UpdateAttributesCallCount() int {
+	fake.updateAttributesMutex.RLock()
+	defer fake.updateAttributesMutex.RUnlock()
+	return len(fake.updateAttributesArgsForCall)
+}
+
+func (fake *FakeParticipant) UpdateAttributesCalls(stub func(map[string]string, *uint32, uint32) (map[string]string, uint32, error)) {
+	fake.updateAttributesMutex.Lock()
+	defer fake.updateAttributesMutex.Unlock()
+	fake.UpdateAttributesStub = stub
+}
+
+func (fake *FakeParticipant) UpdateAttributesArgsForCall(i int) (map[string]string, *uint32, uint32) {
+	fake.updateAttributesMutex.RLock()
+	defer fake.updateAttributesMutex.RUnlock()
+	argsForCall := fake.updateAttributesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeParticipant) UpdateAttributesReturns(result1 map[string]string, result2 uint32, result3 error) {
+	fake.updateAttributesMutex.Lock()
+	defer fake.updateAttributesMutex.Unlock()
+	fake.UpdateAttributesStub = nil
+	fake.updateAttributesReturns = struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}{result1, result2, result3}
+}
+
+func (fake *FakeParticipant) UpdateAttributesReturnsOnCall(i int, result1 map[string]string, result2 uint32, result3 error) {
+	fake.updateAttributesMutex.Lock()
+	defer fake.updateAttributesMutex.Unlock()
+	fake.UpdateAttributesStub = nil
+	if fake.updateAttributesReturnsOnCall == nil {
+		fake.updateAttributesReturnsOnCall = make(map[int]struct {
+			result1 map[string]string
+			result2 uint32
+			result3 error
+		})
+	}
+	fake.updateAttributesReturnsOnCall[i] = struct {
+		result1 map[string]string
+		result2 uint32
+		result3 error
+	}{result1, result2, result3}
+}
+
 func (fake *FakeParticipant) UpdateSubscriptionPermission(arg1 *livekit.SubscriptionPermission, arg2 *livekit.TimedVersion, arg3 func(participantIdentity livekit.ParticipantIdentity) types.LocalParticipant, arg4 func(participantID livekit.ParticipantID) types.LocalParticipant) error {
 	fake.updateSubscriptionPermissionMutex.Lock()
 	ret, specificReturn := fake.updateSubscriptionPermissionReturnsOnCall[len(fake.updateSubscriptionPermissionArgsForCall)]
@@ -1008,6 +1162,8 @@ func (fake *FakeParticipant) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.addSubscriberMutex.RLock()
 	defer fake.addSubscriberMutex.RUnlock()
+	fake.attributesMutex.RLock()
+	defer fake.attributesMutex.RUnlock()
 	fake.closeMutex.RLock()
 	defer fake.closeMutex.RUnlock()
 	fake.debugInfoMutex.RLock()
@@ -1034,6 +1190,8 @@ func (fake *FakeParticipant) Invocations() map[string][][]interface{} {
 	defer fake.subscriptionPermissionMutex.RUnlock()
 	fake.toProtoMutex.RLock()
 	defer fake.toProtoMutex.RUnlock()
+	fake.updateAttributesMutex.RLock()
+	defer fake.updateAttributesMutex.RUnlock()
 	fake.updateSubscriptionPermissionMutex.RLock()
 	defer fake.updateSubscriptionPermissionMutex.RUnlock()
 	fake.updateVideoLayersMutex.RLock()
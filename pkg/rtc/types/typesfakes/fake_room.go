@@ -35,6 +35,12 @@ type FakeRoom struct {
 		arg1 livekit.ParticipantIdentity
 		arg2 types.ParticipantCloseReason
 	}
+	RemoveParticipantSessionStub        func(types.LocalParticipant, types.ParticipantCloseReason)
+	removeParticipantSessionMutex       sync.RWMutex
+	removeParticipantSessionArgsForCall []struct {
+		arg1 types.LocalParticipant
+		arg2 types.ParticipantCloseReason
+	}
 	SetParticipantPermissionStub        func(types.LocalParticipant, *livekit.ParticipantPermission) error
 	setParticipantPermissionMutex       sync.RWMutex
 	setParticipantPermissionArgsForCall []struct {
@@ -71,6 +77,19 @@ type FakeRoom struct {
 	syncStateReturnsOnCall map[int]struct {
 		result1 error
 	}
+	UpdateStageStub        func(types.LocalParticipant, types.StageAction, livekit.ParticipantIdentity) error
+	updateStageMutex       sync.RWMutex
+	updateStageArgsForCall []struct {
+		arg1 types.LocalParticipant
+		arg2 types.StageAction
+		arg3 livekit.ParticipantIdentity
+	}
+	updateStageReturns struct {
+		result1 error
+	}
+	updateStageReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UpdateSubscriptionPermissionStub        func(types.LocalParticipant, *livekit.SubscriptionPermission) error
 	updateSubscriptionPermissionMutex       sync.RWMutex
 	updateSubscriptionPermissionArgsForCall []struct {
@@ -252,6 +271,39 @@ func (fake *FakeRoom) RemoveParticipantArgsForCall(i int) (livekit.ParticipantId
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeRoom) RemoveParticipantSession(arg1 types.LocalParticipant, arg2 types.ParticipantCloseReason) {
+	fake.removeParticipantSessionMutex.Lock()
+	fake.removeParticipantSessionArgsForCall = append(fake.removeParticipantSessionArgsForCall, struct {
+		arg1 types.LocalParticipant
+		arg2 types.ParticipantCloseReason
+	}{arg1, arg2})
+	stub := fake.RemoveParticipantSessionStub
+	fake.recordInvocation("RemoveParticipantSession", []interface{}{arg1, arg2})
+	fake.removeParticipantSessionMutex.Unlock()
+	if stub != nil {
+		fake.RemoveParticipantSessionStub(arg1, arg2)
+	}
+}
+
+func (fake *FakeRoom) RemoveParticipantSessionCallCount() int {
+	fake.removeParticipantSessionMutex.RLock()
+	defer fake.removeParticipantSessionMutex.RUnlock()
+	return len(fake.removeParticipantSessionArgsForCall)
+}
+
+func (fake *FakeRoom) RemoveParticipantSessionCalls(stub func(types.LocalParticipant, types.ParticipantCloseReason)) {
+	fake.removeParticipantSessionMutex.Lock()
+	defer fake.removeParticipantSessionMutex.Unlock()
+	fake.RemoveParticipantSessionStub = stub
+}
+
+func (fake *FakeRoom) RemoveParticipantSessionArgsForCall(i int) (types.LocalParticipant, types.ParticipantCloseReason) {
+	fake.removeParticipantSessionMutex.RLock()
+	defer fake.removeParticipantSessionMutex.RUnlock()
+	argsForCall := fake.removeParticipantSessionArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
 func (fake *FakeRoom) SetParticipantPermission(arg1 types.LocalParticipant, arg2 *livekit.ParticipantPermission) error {
 	fake.setParticipantPermissionMutex.Lock()
 	ret, specificReturn := fake.setParticipantPermissionReturnsOnCall[len(fake.setParticipantPermissionArgsForCall)]
@@ -438,6 +490,69 @@ func (fake *FakeRoom) SyncStateReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeRoom) UpdateStage(arg1 types.LocalParticipant, arg2 types.StageAction, arg3 livekit.ParticipantIdentity) error {
+	fake.updateStageMutex.Lock()
+	ret, specificReturn := fake.updateStageReturnsOnCall[len(fake.updateStageArgsForCall)]
+	fake.updateStageArgsForCall = append(fake.updateStageArgsForCall, struct {
+		arg1 types.LocalParticipant
+		arg2 types.StageAction
+		arg3 livekit.ParticipantIdentity
+	}{arg1, arg2, arg3})
+	stub := fake.UpdateStageStub
+	fakeReturns := fake.updateStageReturns
+	fake.recordInvocation("UpdateStage", []interface{}{arg1, arg2, arg3})
+	fake.updateStageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeRoom) UpdateStageCallCount() int {
+	fake.updateStageMutex.RLock()
+	defer fake.updateStageMutex.RUnlock()
+	return len(fake.updateStageArgsForCall)
+}
+
+func (fake *FakeRoom) UpdateStageCalls(stub func(types.LocalParticipant, types.StageAction, livekit.ParticipantIdentity) error) {
+	fake.updateStageMutex.Lock()
+	defer fake.updateStageMutex.Unlock()
+	fake.UpdateStageStub = stub
+}
+
+func (fake *FakeRoom) UpdateStageArgsForCall(i int) (types.LocalParticipant, types.StageAction, livekit.ParticipantIdentity) {
+	fake.updateStageMutex.RLock()
+	defer fake.updateStageMutex.RUnlock()
+	argsForCall := fake.updateStageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeRoom) UpdateStageReturns(result1 error) {
+	fake.updateStageMutex.Lock()
+	defer fake.updateStageMutex.Unlock()
+	fake.UpdateStageStub = nil
+	fake.updateStageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeRoom) UpdateStageReturnsOnCall(i int, result1 error) {
+	fake.updateStageMutex.Lock()
+	defer fake.updateStageMutex.Unlock()
+	fake.UpdateStageStub = nil
+	if fake.updateStageReturnsOnCall == nil {
+		fake.updateStageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.updateStageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeRoom) UpdateSubscriptionPermission(arg1 types.LocalParticipant, arg2 *livekit.SubscriptionPermission) error {
 	fake.updateSubscriptionPermissionMutex.Lock()
 	ret, specificReturn := fake.updateSubscriptionPermissionReturnsOnCall[len(fake.updateSubscriptionPermissionArgsForCall)]
@@ -645,12 +760,16 @@ func (fake *FakeRoom) Invocations() map[string][][]interface{} {
 	defer fake.nameMutex.RUnlock()
 	fake.removeParticipantMutex.RLock()
 	defer fake.removeParticipantMutex.RUnlock()
+	fake.removeParticipantSessionMutex.RLock()
+	defer fake.removeParticipantSessionMutex.RUnlock()
 	fake.setParticipantPermissionMutex.RLock()
 	defer fake.setParticipantPermissionMutex.RUnlock()
 	fake.simulateScenarioMutex.RLock()
 	defer fake.simulateScenarioMutex.RUnlock()
 	fake.syncStateMutex.RLock()
 	defer fake.syncStateMutex.RUnlock()
+	fake.updateStageMutex.RLock()
+	defer fake.updateStageMutex.RUnlock()
 	fake.updateSubscriptionPermissionMutex.RLock()
 	defer fake.updateSubscriptionPermissionMutex.RUnlock()
 	fake.updateSubscriptionsMutex.RLock()
@@ -95,6 +95,31 @@ func FromProtoTrickle(trickle *livekit.TrickleRequest) (webrtc.ICECandidateInit,
 	return ci, nil
 }
 
+// NewJSONUserPacket returns a user packet carrying msg encoded as JSON, for messages the server itself sends to
+// participants, such as warnings. It's sent to destinations only when given
+func NewJSONUserPacket(msg interface{}, destinations ...livekit.ParticipantID) (*livekit.UserPacket, error) {
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &livekit.UserPacket{
+		Payload:         payload,
+		DestinationSids: livekit.ParticipantIDsAsStrings(destinations),
+	}, nil
+}
+
+// SendJSONMessage sends a participant a reliable data message carrying msg encoded as JSON
+func SendJSONMessage(participant types.LocalParticipant, msg interface{}) error {
+	up, err := NewJSONUserPacket(msg)
+	if err != nil {
+		return err
+	}
+	return participant.SendDataPacket(&livekit.DataPacket{
+		Kind:  livekit.DataPacket_RELIABLE,
+		Value: &livekit.DataPacket_User{User: up},
+	})
+}
+
 func ToProtoTrackKind(kind webrtc.RTPCodecType) livekit.TrackType {
 	switch kind {
 	case webrtc.RTPCodecTypeVideo:
@@ -105,6 +130,15 @@ func ToProtoTrackKind(kind webrtc.RTPCodecType) livekit.TrackType {
 	panic("unsupported track direction")
 }
 
+func containsTrackSource(sources []livekit.TrackSource, source livekit.TrackSource) bool {
+	for _, s := range sources {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
+
 func IsEOF(err error) bool {
 	return err == io.ErrClosedPipe || err == io.EOF
 }
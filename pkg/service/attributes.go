@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+const roomOpUpdateAttributes = "update_attributes"
+
+// updateAttributesRequest updates attributes of a participant of a room hosted on the node. MaxSize is the configured
+// limit of their size, which the room's own limit overrides
+type updateAttributesRequest struct {
+	Identity   livekit.ParticipantIdentity `json:"identity"`
+	Attributes map[string]string           `json:"attributes"`
+	Version    *uint32                     `json:"version,omitempty"`
+	MaxSize    uint32                      `json:"maxSize"`
+}
+
+type attributesResult struct {
+	Attributes map[string]string `json:"attributes"`
+	Version    uint32            `json:"version"`
+}
+
+// UpdateParticipantAttributes updates attributes of a participant on behalf of a room admin
+func (s *RoomService) UpdateParticipantAttributes(ctx context.Context, req *UpdateAttributesRequest) (*AttributesResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if req.Identity == "" {
+		return nil, twirp.InvalidArgumentError("identity", ErrIdentityEmpty.Error())
+	}
+
+	return s.updateAttributes(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity), req.Attributes, req.Version)
+}
+
+// SetAttributes updates attributes of the calling participant, in the room of its token
+func (s *RoomService) SetAttributes(ctx context.Context, req *SetAttributesRequest) (*AttributesResponse, error) {
+	roomName, err := EnsureJoinPermission(ctx)
+	if err != nil {
+		return nil, twirpAuthError(err)
+	}
+	identity := livekit.ParticipantIdentity(GetGrants(ctx).Identity)
+	if identity == "" {
+		return nil, twirp.InvalidArgumentError("identity", ErrIdentityEmpty.Error())
+	}
+
+	return s.updateAttributes(ctx, roomName, identity, req.Attributes, req.Version)
+}
+
+// updateAttributes applies an update to a participant on the node hosting its room, the name as known to the
+// request's tenant
+func (s *RoomService) updateAttributes(
+	ctx context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	update map[string]string,
+	ifVersion *uint32,
+) (*AttributesResponse, error) {
+	res := &attributesResult{}
+	err := executeRoomOperation(ctx, s.router, TenantRoomName(ctx, roomName), roomOpUpdateAttributes, &updateAttributesRequest{
+		Identity:   identity,
+		Attributes: update,
+		Version:    ifVersion,
+		MaxSize:    s.roomConf().MaxAttributesSize,
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AttributesResponse{
+		Room:       string(roomName),
+		Identity:   string(identity),
+		Attributes: res.Attributes,
+		Version:    res.Version,
+	}, nil
+}
+
+func (r *RoomManager) updateAttributes(_ context.Context, room *rtc.Room, req *updateAttributesRequest) (*attributesResult, error) {
+	maxSize := req.MaxSize
+	if internal := room.Internal(); internal.MaxAttributesSize > 0 {
+		maxSize = internal.MaxAttributesSize
+	}
+	attributes, version, err := room.UpdateParticipantAttributes(req.Identity, req.Attributes, req.Version, maxSize)
+	switch err {
+	case nil:
+	case rtc.ErrParticipantNotFound:
+		return nil, twirp.NotFoundError(err.Error())
+	case rtc.ErrAttributesChanged:
+		return nil, twirp.NewError(twirp.Aborted, err.Error())
+	case rtc.ErrAttributesTooLarge:
+		return nil, twirp.InvalidArgumentError(err.Error(), strconv.Itoa(int(maxSize)))
+	case rtc.ErrEmptyAttributeKey:
+		return nil, twirp.InvalidArgumentError("attributes", err.Error())
+	default:
+		return nil, err
+	}
+
+	return &attributesResult{Attributes: attributes, Version: version}, nil
+}
@@ -5,8 +5,11 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/twitchtv/twirp"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
@@ -20,6 +23,29 @@ const (
 
 type grantsKey struct{}
 
+type apiKeyKey struct{}
+
+type serverClaimsKey struct{}
+
+// ServerClaims are claims understood by this server, carried in the access token alongside the standard grants
+type ServerClaims struct {
+	// tenant to act on behalf of, honored only for tokens signed by global keys
+	Tenant string `json:"tenant,omitempty"`
+	// template applied when the room is auto created on join
+	RoomTemplate string `json:"roomTemplate,omitempty"`
+	// number of seconds the participant may stay connected to the room
+	MaxSessionDuration uint32 `json:"maxSessionDuration,omitempty"`
+	// role of the participant, selecting the track sources it may publish when the room's template restricts them
+	Role string `json:"role,omitempty"`
+	// data topics the participant is subscribed to on join
+	DataTopics []string `json:"dataTopics,omitempty"`
+	// data topics the participant may publish to, any when unset. topics may end with a wildcard, i.e. "chat/*"
+	PublishTopics []string `json:"publishTopics,omitempty"`
+	// what happens when the room has a participant with the same identity: replace, reject or allow. overrides the
+	// policy of the room when set
+	DuplicateIdentity string `json:"duplicateIdentity,omitempty"`
+}
+
 var (
 	ErrPermissionDenied = errors.New("permissions denied")
 )
@@ -74,9 +100,18 @@ func (m *APIKeyAuthMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request,
 			return
 		}
 
+		// token has been verified at this point, so it's safe to read additional claims
+		serverClaims := &ServerClaims{}
+		if tok, err := jwt.ParseSigned(authToken); err == nil {
+			_ = tok.UnsafeClaimsWithoutVerification(serverClaims)
+		}
+
 		// set grants in context
 		ctx := r.Context()
-		r = r.WithContext(context.WithValue(ctx, grantsKey{}, grants))
+		ctx = context.WithValue(ctx, grantsKey{}, grants)
+		ctx = context.WithValue(ctx, apiKeyKey{}, v.APIKey())
+		ctx = context.WithValue(ctx, serverClaimsKey{}, serverClaims)
+		r = r.WithContext(ctx)
 	}
 
 	next.ServeHTTP(w, r)
@@ -95,6 +130,47 @@ func WithGrants(ctx context.Context, grants *auth.ClaimGrants) context.Context {
 	return context.WithValue(ctx, grantsKey{}, grants)
 }
 
+// GetAPIKey returns the API key that signed the request's token
+func GetAPIKey(ctx context.Context) string {
+	apiKey, _ := ctx.Value(apiKeyKey{}).(string)
+	return apiKey
+}
+
+func GetServerClaims(ctx context.Context) *ServerClaims {
+	claims, ok := ctx.Value(serverClaimsKey{}).(*ServerClaims)
+	if !ok {
+		return &ServerClaims{}
+	}
+	return claims
+}
+
+func WithServerClaims(ctx context.Context, claims *ServerClaims) context.Context {
+	return context.WithValue(ctx, serverClaimsKey{}, claims)
+}
+
+// signAccessToken returns a token for grants signed with apiKey, carrying server claims alongside the grants,
+// which auth.AccessToken cannot do
+func signAccessToken(apiKey, secret string, grants *auth.ClaimGrants, claims *ServerClaims, validFor time.Duration) (string, error) {
+	if apiKey == "" || secret == "" {
+		return "", auth.ErrKeysMissing
+	}
+
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(secret)},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	cl := jwt.Claims{
+		Issuer:    apiKey,
+		NotBefore: jwt.NewNumericDate(now),
+		Expiry:    jwt.NewNumericDate(now.Add(validFor)),
+		Subject:   grants.Identity,
+	}
+	return jwt.Signed(sig).Claims(cl).Claims(grants).Claims(claims).CompactSerialize()
+}
+
 func SetAuthorizationToken(r *http.Request, token string) {
 	r.Header.Set(authorizationHeader, bearerPrefix+token)
 }
@@ -127,6 +203,20 @@ func EnsureAdminPermission(ctx context.Context, room livekit.RoomName) error {
 	return nil
 }
 
+// EnsureServerAdminPermission requires an admin grant that isn't restricted to a room or a tenant
+func EnsureServerAdminPermission(ctx context.Context) error {
+	claims := GetGrants(ctx)
+	if claims == nil || claims.Video == nil {
+		return ErrPermissionDenied
+	}
+
+	if !claims.Video.RoomAdmin || claims.Video.Room != "" || GetTenant(ctx) != "" {
+		return ErrPermissionDenied
+	}
+
+	return nil
+}
+
 func EnsureCreatePermission(ctx context.Context) error {
 	claims := GetGrants(ctx)
 	if claims == nil {
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// ListRoomInfo lists rooms along with their breakout links
+func (s *RoomService) ListRoomInfo(ctx context.Context, req *ListRoomsRequest) (*ListRoomsResponse, error) {
+	rooms, err := s.ListRooms(ctx, &livekit.ListRoomsRequest{Names: req.Names})
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ListRoomsResponse{
+		Rooms: make([]*RoomInfo, 0, len(rooms.Rooms)),
+	}
+	for _, rm := range rooms.Rooms {
+		internal, err := s.roomStore.LoadRoomInternal(ctx, TenantRoomName(ctx, livekit.RoomName(rm.Name)))
+		if err != nil {
+			return nil, err
+		}
+		res.Rooms = append(res.Rooms, roomInfo(ctx, rm, internal))
+	}
+	return res, nil
+}
+
+// CreateBreakoutRoom creates a room linked to a parent room, and moves participants of the parent into it
+func (s *RoomService) CreateBreakoutRoom(ctx context.Context, req *CreateBreakoutRoomRequest) (*RoomInfo, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if err := EnsureCreatePermission(ctx); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if req.Name == "" || req.Name == req.Room {
+		return nil, twirp.InvalidArgumentError("name", "must be another room")
+	}
+	parentName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+	name := TenantRoomName(ctx, livekit.RoomName(req.Name))
+
+	if _, err := s.roomStore.LoadRoom(ctx, parentName); err == ErrRoomNotFound {
+		return nil, twirp.NotFoundError(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	parentInternal, err := s.roomStore.LoadRoomInternal(ctx, parentName)
+	if err != nil {
+		return nil, err
+	}
+	if parentInternal.Parent != "" {
+		return nil, twirp.InvalidArgumentError("room", "breakout rooms cannot have breakout rooms")
+	}
+	if _, err = s.roomStore.LoadRoom(ctx, name); err == nil {
+		return nil, twirp.NewError(twirp.AlreadyExists, "room already exists")
+	} else if err != ErrRoomNotFound {
+		return nil, err
+	}
+
+	rm, err := s.roomAllocator.CreateRoom(ctx, &livekit.CreateRoomRequest{
+		Name:     string(name),
+		Metadata: req.Metadata,
+	})
+	if err != nil {
+		return nil, twirpCreateRoomError(err)
+	}
+
+	var internal *types.RoomInternal
+	err = updateRoomInternal(ctx, s.roomStore, s.router, name, func(ri *types.RoomInternal) {
+		ri.Parent = parentName
+		if req.Duration > 0 {
+			ri.CloseAt = rm.CreationTime + int64(req.Duration)
+		}
+		internal = ri
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = updateRoomInternal(ctx, s.roomStore, s.router, parentName, func(ri *types.RoomInternal) {
+		ri.Breakouts = append(ri.Breakouts, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, identity := range req.Participants {
+		pi, err := s.roomStore.LoadParticipant(ctx, parentName, livekit.ParticipantIdentity(identity))
+		if err == ErrParticipantNotFound {
+			return nil, twirp.NotFoundError(err.Error())
+		} else if err != nil {
+			return nil, err
+		}
+		if _, err = s.moveParticipant(ctx, parentName, livekit.ParticipantIdentity(pi.Identity), name); err != nil {
+			return nil, err
+		}
+	}
+
+	return roomInfo(ctx, localRoom(ctx, rm), internal), nil
+}
+
+// BroadcastBreakouts sends data to participants of all breakout rooms of a room
+func (s *RoomService) BroadcastBreakouts(ctx context.Context, req *BroadcastBreakoutsRequest) (*BreakoutsResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	internal, err := s.loadParentInternal(ctx, TenantRoomName(ctx, livekit.RoomName(req.Room)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range internal.Breakouts {
+		err = s.router.WriteRoomRTC(ctx, name, &livekit.RTCNodeMessage{
+			Message: &livekit.RTCNodeMessage_SendData{
+				SendData: &livekit.SendDataRequest{
+					Room: string(name),
+					Data: req.Data,
+					Kind: livekit.DataPacket_RELIABLE,
+				},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return breakoutsResponse(ctx, req.Room, internal.Breakouts), nil
+}
+
+// CloseBreakouts moves participants of all breakout rooms of a room back into it, and closes the breakout rooms
+func (s *RoomService) CloseBreakouts(ctx context.Context, req *CloseBreakoutsRequest) (*BreakoutsResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	parentName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+	internal, err := s.loadParentInternal(ctx, parentName)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, name := range internal.Breakouts {
+		participants, err := s.roomStore.ListParticipants(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, pi := range participants {
+			if _, err = s.moveParticipant(ctx, name, livekit.ParticipantIdentity(pi.Identity), parentName); err != nil {
+				logger.Warnw("could not move participant back to parent room", err,
+					"room", name, "participant", pi.Identity, "parent", parentName)
+			}
+		}
+
+		err = s.router.WriteRoomRTC(ctx, name, &livekit.RTCNodeMessage{
+			Message: &livekit.RTCNodeMessage_DeleteRoom{
+				DeleteRoom: &livekit.DeleteRoomRequest{Room: string(name)},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// rooms without a session are deleted without being unlinked
+	err = updateRoomInternal(ctx, s.roomStore, s.router, parentName, func(ri *types.RoomInternal) {
+		ri.Breakouts = removeRoomName(ri.Breakouts, internal.Breakouts...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return breakoutsResponse(ctx, req.Room, internal.Breakouts), nil
+}
+
+func (s *RoomService) loadParentInternal(ctx context.Context, roomName livekit.RoomName) (*types.RoomInternal, error) {
+	if _, err := s.roomStore.LoadRoom(ctx, roomName); err == ErrRoomNotFound {
+		return nil, twirp.NotFoundError(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return s.roomStore.LoadRoomInternal(ctx, roomName)
+}
+
+// breakoutDeadline returns the limit closing a breakout room back into its parent, or nil when it has no deadline
+func (r *RoomManager) breakoutDeadline(room *rtc.Room, internal *types.RoomInternal, roomConfig config.RoomConfig) *durationLimit {
+	if internal.Parent == "" || internal.CloseAt == 0 {
+		return nil
+	}
+
+	warning := time.Duration(roomConfig.DurationWarning) * time.Second
+	return newDurationLimit(time.Unix(internal.CloseAt, 0), warning, func(remaining time.Duration) {
+		if up, err := rtc.NewJSONUserPacket(newDurationWarning(durationWarningBreakout, remaining)); err == nil {
+			room.SendServerDataPacket(up, livekit.DataPacket_RELIABLE)
+		}
+	}, func() {
+		r.closeBreakoutRoom(room, internal.Parent)
+	})
+}
+
+// closeBreakoutRoom moves participants of a breakout room back into its parent, and closes it
+func (r *RoomManager) closeBreakoutRoom(room *rtc.Room, parent livekit.RoomName) {
+	if room.IsClosed() {
+		return
+	}
+
+	room.Logger.Infow("closing breakout room", "parent", parent)
+	for _, p := range room.GetParticipants() {
+		if _, err := r.MoveParticipant(context.Background(), room, p.Identity(), parent); err != nil {
+			p.GetLogger().Warnw("could not move participant back to parent room", err, "parent", parent)
+			_ = p.Close(true, types.ParticipantCloseReasonServiceRequestDeleteRoom)
+		}
+	}
+	room.Close()
+}
+
+// cleanupBreakouts unlinks a deleted breakout room from its parent, or deletes breakout rooms of a deleted parent
+func (r *RoomManager) cleanupBreakouts(ctx context.Context, roomName livekit.RoomName, internal *types.RoomInternal) {
+	if internal.Parent != "" {
+		if _, err := r.roomStore.LoadRoom(ctx, internal.Parent); err == nil {
+			err = updateRoomInternal(ctx, r.roomStore, r.router, internal.Parent, func(ri *types.RoomInternal) {
+				ri.Breakouts = removeRoomName(ri.Breakouts, roomName)
+			})
+			if err != nil {
+				logger.Errorw("could not unlink breakout room", err, "room", roomName, "parent", internal.Parent)
+			}
+		}
+	}
+
+	for _, name := range internal.Breakouts {
+		// closed by the node running it, or deleted right away when it has no session
+		err := r.router.WriteRoomRTC(ctx, name, &livekit.RTCNodeMessage{
+			Message: &livekit.RTCNodeMessage_DeleteRoom{
+				DeleteRoom: &livekit.DeleteRoomRequest{Room: string(name)},
+			},
+		})
+		if err != nil {
+			if err = r.DeleteRoom(ctx, name); err != nil {
+				logger.Errorw("could not delete breakout room", err, "room", name, "parent", roomName)
+			}
+		}
+	}
+}
+
+// updateRoomInternal applies update to a copy of the room's internal settings and stores it, holding the room's lock.
+// the node hosting the room applies them before the lock is released, so that it applies updates in order
+func updateRoomInternal(
+	ctx context.Context,
+	store ObjectStore,
+	router routing.MessageRouter,
+	roomName livekit.RoomName,
+	update func(internal *types.RoomInternal),
+) error {
+	token, err := store.LockRoom(ctx, roomName, 5*time.Second)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = store.UnlockRoom(ctx, roomName, token)
+	}()
+
+	internal, err := store.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		return err
+	}
+	updated := *internal
+	updated.Breakouts = append([]livekit.RoomName(nil), internal.Breakouts...)
+	update(&updated)
+	if err = store.StoreRoomInternal(ctx, roomName, &updated); err != nil {
+		return err
+	}
+	return syncRoomInternal(ctx, router, roomName)
+}
+
+func removeRoomName(names []livekit.RoomName, removed ...livekit.RoomName) []livekit.RoomName {
+	filtered := make([]livekit.RoomName, 0, len(names))
+	for _, name := range names {
+		keep := true
+		for _, r := range removed {
+			if name == r {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			filtered = append(filtered, name)
+		}
+	}
+	return filtered
+}
+
+// roomInfo returns the room with its breakout links, as known to the request's tenant
+func roomInfo(ctx context.Context, rm *livekit.Room, internal *types.RoomInternal) *RoomInfo {
+	return &RoomInfo{
+		Room:      rm,
+		Parent:    string(LocalRoomName(ctx, internal.Parent)),
+		Breakouts: localRoomNames(ctx, internal.Breakouts),
+		CloseAt:   internal.CloseAt,
+
+		MetadataVersion: internal.MetadataVersion,
+		Locked:          internal.Locked,
+	}
+}
+
+func breakoutsResponse(ctx context.Context, room string, breakouts []livekit.RoomName) *BreakoutsResponse {
+	return &BreakoutsResponse{
+		Room:      room,
+		Breakouts: localRoomNames(ctx, breakouts),
+	}
+}
+
+func localRoomNames(ctx context.Context, names []livekit.RoomName) []string {
+	local := make([]string, 0, len(names))
+	for _, name := range names {
+		local = append(local, string(LocalRoomName(ctx, name)))
+	}
+	return local
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/utils"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const roomOpBridgeTrack = "bridge_track"
+
+// bridgeTrackRequest bridges a track of a room hosted on the node into Destination
+type bridgeTrackRequest struct {
+	Identity       livekit.ParticipantIdentity `json:"identity"`
+	TrackID        livekit.TrackID             `json:"trackId"`
+	Destination    livekit.RoomName            `json:"destination"`
+	BridgeIdentity livekit.ParticipantIdentity `json:"bridgeIdentity"`
+}
+
+type bridgeTrackResult struct {
+	TrackID livekit.TrackID `json:"trackId"`
+}
+
+// BridgeTrack republishes a track of a room hosted on this node into destination, as a track of a participant
+// with bridgeIdentity, which publishes nothing else. ErrRoomNotLocal is returned when destination is hosted on
+// another node. The bridge participant leaves once the track is closed, and removing it stops the bridge
+func (r *RoomManager) BridgeTrack(
+	ctx context.Context,
+	room *rtc.Room,
+	identity livekit.ParticipantIdentity,
+	trackID livekit.TrackID,
+	destination livekit.RoomName,
+	bridgeIdentity livekit.ParticipantIdentity,
+) (*rtc.BridgedTrack, error) {
+	roomName := room.Name()
+	publisher := room.GetParticipant(identity)
+	if publisher == nil {
+		return nil, ErrParticipantNotFound
+	}
+	source, ok := publisher.GetPublishedTrack(trackID).(*rtc.MediaTrack)
+	if !ok {
+		return nil, ErrTrackNotFound
+	}
+
+	node, err := r.router.GetNodeForRoom(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	if node.Id != r.currentNode.Id {
+		return nil, ErrRoomNotLocal
+	}
+
+	dest, err := r.getOrCreateRoom(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	defer dest.Release()
+
+	rtcConf := *r.rtcConfig
+	rtcConf.SetBufferFactory(dest.GetBufferFactory())
+	sid := livekit.ParticipantID(utils.NewGuid(utils.ParticipantPrefix))
+	pLogger := rtc.LoggerWithParticipant(dest.Logger, bridgeIdentity, sid, false)
+	protoRoom := dest.ToProto()
+	participant, err := rtc.NewParticipant(rtc.ParticipantParams{
+		Identity:                bridgeIdentity,
+		Name:                    livekit.ParticipantName(bridgeIdentity),
+		SID:                     sid,
+		Config:                  &rtcConf,
+		AudioConfig:             r.config.Audio,
+		VideoConfig:             r.config.Video,
+		ProtocolVersion:         types.DefaultProtocol,
+		Telemetry:               r.telemetry,
+		PLIThrottleConfig:       r.config.RTC.PLIThrottle,
+		CongestionControlConfig: r.config.RTC.CongestionControl,
+		EnabledCodecs:           protoRoom.EnabledCodecs,
+		Grants:                  bridgeGrants(publisher, roomName, destination, bridgeIdentity),
+		Logger:                  pLogger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = dest.JoinBridge(participant); err != nil {
+		pLogger.Errorw("could not join room", err)
+		_ = participant.Close(false, types.ParticipantCloseReasonJoinFailed)
+		return nil, err
+	}
+	track := participant.AddBridgedTrack(source)
+	track.AddOnClose(func() {
+		if len(participant.GetPublishedTracks()) == 0 {
+			dest.RemoveParticipant(bridgeIdentity, types.ParticipantCloseReasonBridgedTrackClosed)
+		}
+	})
+	pLogger.Infow("bridged track",
+		"room", roomName,
+		"participant", identity,
+		"sourceTrackID", trackID,
+		"trackID", track.ID())
+
+	if err = r.roomStore.StoreParticipant(ctx, destination, participant.ToProto()); err != nil {
+		pLogger.Errorw("could not store participant", err)
+	}
+	if err = r.roomStore.StoreRoom(ctx, dest.ToProto()); err != nil {
+		logger.Errorw("could not store room", err)
+	}
+	participant.OnClose(func(p types.LocalParticipant, _ map[livekit.TrackID]livekit.ParticipantID) {
+		if err := r.roomStore.DeleteParticipant(ctx, destination, p.Identity()); err != nil {
+			pLogger.Errorw("could not delete participant", err)
+		}
+		if err := r.roomStore.StoreRoom(ctx, dest.ToProto()); err != nil {
+			logger.Errorw("could not store room", err)
+		}
+	})
+
+	return track, nil
+}
+
+func (r *RoomManager) bridgeTrack(ctx context.Context, room *rtc.Room, req *bridgeTrackRequest) (*bridgeTrackResult, error) {
+	track, err := r.BridgeTrack(ctx, room, req.Identity, req.TrackID, req.Destination, req.BridgeIdentity)
+	switch err {
+	case nil:
+		return &bridgeTrackResult{TrackID: track.ID()}, nil
+	case ErrParticipantNotFound, ErrTrackNotFound:
+		return nil, twirp.NotFoundError(err.Error())
+	case ErrRoomNotLocal, rtc.ErrRoomClosed, rtc.ErrRoomLocked, rtc.ErrAlreadyJoined,
+		rtc.ErrMaxParticipantsExceeded, rtc.ErrMaxPublishersExceeded:
+		return nil, twirp.NewError(twirp.FailedPrecondition, err.Error())
+	default:
+		return nil, err
+	}
+}
+
+// BridgeTrack republishes a track of a participant into another room, on the node hosting both rooms
+func (s *RoomService) BridgeTrack(ctx context.Context, req *BridgeTrackRequest) (*BridgeTrackResponse, error) {
+	// publishing into another room requires more than admin permission on a single room
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if err := EnsureCreatePermission(ctx); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if req.Destination == "" || req.Destination == req.Room {
+		return nil, twirp.InvalidArgumentError("destination", "must be another room")
+	}
+	if req.BridgeIdentity == "" {
+		return nil, twirp.InvalidArgumentError("bridgeIdentity", ErrIdentityEmpty.Error())
+	}
+	destination := TenantRoomName(ctx, livekit.RoomName(req.Destination))
+	if _, err := s.roomStore.LoadRoom(ctx, destination); err == ErrRoomNotFound {
+		return nil, twirp.NotFoundError(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	res := &bridgeTrackResult{}
+	err := executeRoomOperation(ctx, s.router, TenantRoomName(ctx, livekit.RoomName(req.Room)), roomOpBridgeTrack, &bridgeTrackRequest{
+		Identity:       livekit.ParticipantIdentity(req.Identity),
+		TrackID:        livekit.TrackID(req.TrackSid),
+		Destination:    destination,
+		BridgeIdentity: livekit.ParticipantIdentity(req.BridgeIdentity),
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BridgeTrackResponse{
+		Room:     req.Destination,
+		Identity: req.BridgeIdentity,
+		TrackSid: string(res.TrackID),
+	}, nil
+}
+
+// bridgeGrants returns the grants of a participant publishing bridged tracks, which does not subscribe or send data
+func bridgeGrants(publisher types.LocalParticipant, roomName livekit.RoomName, destination livekit.RoomName, identity livekit.ParticipantIdentity) *auth.ClaimGrants {
+	video := &auth.VideoGrant{
+		RoomJoin: true,
+		Room:     string(participantRoomName(publisher, roomName, destination)),
+	}
+	video.SetCanPublish(true)
+	video.SetCanSubscribe(false)
+	video.SetCanPublishData(false)
+	return &auth.ClaimGrants{
+		Identity: string(identity),
+		Name:     string(identity),
+		Video:    video,
+	}
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+	"github.com/livekit/protocol/webhook"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	serverlogger "github.com/livekit/livekit-server/pkg/logger"
+	"github.com/livekit/livekit-server/pkg/routing/selector"
+)
+
+var ErrConfigReloadUnavailable = errors.New("config reload is not available")
+
+// top level config sections that are applied without a restart
+var reloadableConfigSections = map[string]bool{
+	"keys":          true,
+	"key_file":      true,
+	"webhook":       true,
+	"room":          true,
+	"limit":         true,
+	"node_selector": true,
+	"logging":       true,
+}
+
+// ConfigUpdater is implemented by services that apply reloadable configuration while running
+type ConfigUpdater interface {
+	UpdateConfig(conf *config.Config)
+}
+
+// ConfigLoader re-reads configuration from the sources the server was started with
+type ConfigLoader func() (*config.Config, error)
+
+type ConfigReloadResult struct {
+	// changed fields that have been applied
+	Applied []string `json:"applied"`
+	// changed fields that only take effect after a restart
+	RestartRequired []string `json:"restart_required"`
+}
+
+// ConfigReloader re-reads configuration on demand, validates it and applies the reloadable subset
+// to running services. Changes to any other section are reported as requiring a restart
+type ConfigReloader struct {
+	lock        sync.Mutex
+	loader      ConfigLoader
+	started     *config.Config
+	current     *config.Config
+	keyProvider *ReloadableKeyProvider
+	notifier    *ReloadableNotifier
+	updaters    []ConfigUpdater
+}
+
+func NewConfigReloader(
+	conf *config.Config,
+	keyProvider *ReloadableKeyProvider,
+	notifier *ReloadableNotifier,
+	roomAllocator RoomAllocator,
+	roomService *RoomService,
+	rtcService *RTCService,
+	roomManager *RoomManager,
+) *ConfigReloader {
+	started := *conf
+	r := &ConfigReloader{
+		started:     &started,
+		current:     &started,
+		keyProvider: keyProvider,
+		notifier:    notifier,
+		updaters:    []ConfigUpdater{roomService, rtcService, roomManager},
+	}
+	if updater, ok := roomAllocator.(ConfigUpdater); ok {
+		r.updaters = append(r.updaters, updater)
+	}
+	return r
+}
+
+func (r *ConfigReloader) SetLoader(loader ConfigLoader) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.loader = loader
+}
+
+// Reload loads configuration and applies it. Nothing is applied when the new configuration is invalid
+func (r *ConfigReloader) Reload() (*ConfigReloadResult, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.loader == nil {
+		return nil, ErrConfigReloadUnavailable
+	}
+	conf, err := r.loader()
+	if err != nil {
+		return nil, err
+	}
+	if err = validateReloadableConfig(conf); err != nil {
+		return nil, err
+	}
+
+	res := &ConfigReloadResult{
+		Applied:         []string{},
+		RestartRequired: []string{},
+	}
+	for _, field := range diffConfig(r.current, conf) {
+		if isReloadableField(field) {
+			res.Applied = append(res.Applied, field)
+		}
+	}
+	// compared to the configuration the server was started with, as those changes are still pending
+	for _, field := range diffConfig(r.started, conf) {
+		if !isReloadableField(field) {
+			res.RestartRequired = append(res.RestartRequired, field)
+		}
+	}
+
+	r.keyProvider.Update(conf.Keys)
+	if err = r.notifier.Update(conf.WebHook, r.keyProvider); err != nil {
+		// checked during validation
+		logger.Errorw("could not update webhook notifier", err)
+	}
+	for _, updater := range r.updaters {
+		updater.UpdateConfig(conf)
+	}
+	if !reflect.DeepEqual(r.current.Logging, conf.Logging) {
+		serverlogger.InitFromConfig(conf.Logging)
+	}
+
+	// non-reloadable sections remain as started
+	current := *r.started
+	current.Keys = conf.Keys
+	current.KeyFile = conf.KeyFile
+	current.WebHook = conf.WebHook
+	current.Room = conf.Room
+	current.Limit = conf.Limit
+	current.NodeSelector = conf.NodeSelector
+	current.Logging = conf.Logging
+	r.current = &current
+
+	logger.Infow("configuration reloaded", "applied", res.Applied, "restartRequired", res.RestartRequired)
+	return res, nil
+}
+
+func (r *ConfigReloader) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		handleError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err := EnsureServerAdminPermission(req.Context()); err != nil {
+		handleError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	res, err := r.Reload()
+	if err == ErrConfigReloadUnavailable {
+		handleError(w, http.StatusNotFound, err.Error())
+		return
+	} else if err != nil {
+		handleError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func validateReloadableConfig(conf *config.Config) error {
+	if err := loadKeys(conf); err != nil {
+		return err
+	}
+	if wc := conf.WebHook; len(wc.URLs) > 0 && conf.Keys[wc.APIKey] == "" {
+		return ErrWebHookMissingAPIKey
+	}
+	_, err := selector.CreateNodeSelector(conf)
+	return err
+}
+
+func isReloadableField(field string) bool {
+	return reloadableConfigSections[strings.SplitN(field, ".", 2)[0]]
+}
+
+// diffConfig returns yaml paths of changed fields, nested structs are compared field by field
+func diffConfig(a, b *config.Config) []string {
+	var fields []string
+	diffStruct("", reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem(), &fields)
+	sort.Strings(fields)
+	return fields
+}
+
+func diffStruct(prefix string, a, b reflect.Value, fields *[]string) {
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tag := strings.Split(f.Tag.Get("yaml"), ",")
+		name := tag[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		if len(tag) > 1 && tag[1] == "inline" {
+			name = prefix
+		} else if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		av, bv := a.Field(i), b.Field(i)
+		if f.Type.Kind() == reflect.Struct {
+			diffStruct(name, av, bv, fields)
+		} else if !reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			*fields = append(*fields, name)
+		}
+	}
+}
+
+// ReloadableKeyProvider is a KeyProvider whose keys can be replaced while running
+type ReloadableKeyProvider struct {
+	lock sync.RWMutex
+	keys map[string]string
+}
+
+func NewReloadableKeyProvider(keys map[string]string) *ReloadableKeyProvider {
+	return &ReloadableKeyProvider{
+		keys: keys,
+	}
+}
+
+func (p *ReloadableKeyProvider) GetSecret(key string) string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return p.keys[key]
+}
+
+func (p *ReloadableKeyProvider) NumKeys() int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	return len(p.keys)
+}
+
+func (p *ReloadableKeyProvider) Update(keys map[string]string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.keys = keys
+}
+
+// ReloadableNotifier is a webhook Notifier whose URLs and signing key can be replaced while running.
+// Notifications are dropped while no URLs are configured
+type ReloadableNotifier struct {
+	lock     sync.RWMutex
+	notifier webhook.Notifier
+	// events name rooms as known to their tenant
+	tenancy bool
+}
+
+func NewReloadableNotifier(conf config.WebHookConfig, tenancy bool, provider *ReloadableKeyProvider) (*ReloadableNotifier, error) {
+	n := &ReloadableNotifier{tenancy: tenancy}
+	if err := n.Update(conf, provider); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *ReloadableNotifier) Notify(ctx context.Context, payload interface{}) error {
+	n.lock.RLock()
+	notifier := n.notifier
+	n.lock.RUnlock()
+
+	if notifier == nil {
+		return nil
+	}
+	if event, ok := payload.(*livekit.WebhookEvent); ok && n.tenancy {
+		payload = localWebhookEvent(event)
+	}
+	return notifier.Notify(ctx, payload)
+}
+
+func (n *ReloadableNotifier) Update(conf config.WebHookConfig, provider *ReloadableKeyProvider) error {
+	var notifier webhook.Notifier
+	if len(conf.URLs) > 0 {
+		secret := provider.GetSecret(conf.APIKey)
+		if secret == "" {
+			return ErrWebHookMissingAPIKey
+		}
+		notifier = webhook.NewNotifier(conf.APIKey, secret, conf.URLs)
+	}
+
+	n.lock.Lock()
+	n.notifier = notifier
+	n.lock.Unlock()
+	return nil
+}
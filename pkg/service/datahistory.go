@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/utils"
+)
+
+// number of retained messages waiting to be persisted, beyond which they're dropped
+const dataHistoryQueueSize = 100
+
+// GetDataHistory returns data messages retained by a room on behalf of a room admin, i.e. for moderation.
+// Rooms hosted on other nodes only have a history when it's persisted
+func (s *RoomService) GetDataHistory(ctx context.Context, req *DataHistoryRequest) (*DataHistoryResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	roomName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+
+	res := &DataHistoryResponse{
+		Room: req.Room,
+	}
+	if room := s.roomManager.GetRoom(ctx, roomName); room != nil {
+		res.Messages = room.DataHistory()
+		return res, nil
+	}
+
+	messages, err := s.roomStore.LoadDataHistory(ctx, roomName)
+	if err != nil {
+		return nil, err
+	}
+	res.Messages = messages
+	return res, nil
+}
+
+// dataHistoryPolicy converts data history settings, nil when history is disabled
+func dataHistoryPolicy(conf config.DataHistoryConfig) *types.DataHistory {
+	if conf.MaxMessages == 0 {
+		return nil
+	}
+	return &types.DataHistory{
+		MaxMessages:   conf.MaxMessages,
+		MaxBytes:      conf.MaxBytes,
+		IncludeDirect: conf.IncludeDirect,
+		Persist:       conf.Persist,
+	}
+}
+
+// loadDataHistory returns the data history policy of a room, along with messages it persisted previously
+func (r *RoomManager) loadDataHistory(ctx context.Context, roomName livekit.RoomName, internal *types.RoomInternal, roomConfig config.RoomConfig) (*types.DataHistory, []*types.DataMessage) {
+	policy := internal.DataHistory
+	if policy == nil {
+		policy = dataHistoryPolicy(roomConfig.DataHistory)
+	}
+	if policy == nil || !policy.Persist {
+		return policy, nil
+	}
+
+	messages, err := r.roomStore.LoadDataHistory(ctx, roomName)
+	if err != nil {
+		logger.Warnw("could not load data history", err, "room", roomName)
+	}
+	return policy, messages
+}
+
+// retainDataHistory enables the data history of a room. When it's persisted, messages are appended to the store
+// in the order they're retained, off the goroutines of their senders. The returned queue is stopped when the room
+// closes, nil when there's nothing to persist
+func (r *RoomManager) retainDataHistory(ctx context.Context, room *rtc.Room, policy *types.DataHistory, messages []*types.DataMessage) *utils.OpsQueue {
+	room.SetDataHistory(policy, messages)
+	if policy == nil || !policy.Persist {
+		return nil
+	}
+
+	queue := utils.NewOpsQueue(room.Logger, "data-history", dataHistoryQueueSize)
+	queue.Start()
+	room.OnDataRetained(func(msg *types.DataMessage) {
+		queue.Enqueue(func() {
+			// the room's history is deleted along with it
+			if room.IsClosed() {
+				return
+			}
+			if err := r.roomStore.AppendDataMessage(ctx, room.Name(), msg, policy.MaxMessages, policy.MaxBytes); err != nil {
+				room.Logger.Errorw("could not persist data message", err)
+			}
+		})
+	})
+	return queue
+}
+
+// dataHistoryOverflow returns the number of the oldest messages of a history to drop to keep it within limits
+func dataHistoryOverflow(history []*types.DataMessage, maxMessages, maxBytes uint32) int {
+	drop := 0
+	if maxMessages > 0 && len(history) > int(maxMessages) {
+		drop = len(history) - int(maxMessages)
+	}
+	if maxBytes == 0 {
+		return drop
+	}
+	size := 0
+	for _, msg := range history[drop:] {
+		size += len(msg.Payload)
+	}
+	for ; drop < len(history) && size > int(maxBytes); drop++ {
+		size -= len(history[drop].Payload)
+	}
+	return drop
+}
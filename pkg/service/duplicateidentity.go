@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// duplicateIdentityPolicy returns what happens when a participant joins with the identity of one in the room. The
+// policy claimed by its token takes precedence over the room's, which takes precedence over the server wide one
+func duplicateIdentityPolicy(claimed string, internal *types.RoomInternal, conf config.RoomConfig) types.DuplicateIdentityPolicy {
+	if claimed != "" {
+		return types.DuplicateIdentityPolicy(claimed)
+	}
+	if internal != nil && internal.DuplicateIdentity != "" {
+		return internal.DuplicateIdentity
+	}
+	if conf.DuplicateIdentity != "" {
+		return types.DuplicateIdentityPolicy(conf.DuplicateIdentity)
+	}
+	return types.DuplicateIdentityReplace
+}
+
+// checkDuplicateIdentity refuses a participant joining with the identity of one in the room when duplicates are
+// rejected, before its signal connection is established. The room's node checks again as it joins
+func (s *RTCService) checkDuplicateIdentity(ctx context.Context, roomName livekit.RoomName, pi routing.ParticipantInit) error {
+	if _, err := s.store.LoadParticipant(ctx, roomName, pi.Identity); err == ErrParticipantNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	internal, err := s.store.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		return err
+	}
+	if duplicateIdentityPolicy(pi.DuplicateIdentity, internal, s.getRoomConfig()) == types.DuplicateIdentityReject {
+		return rtc.ErrAlreadyJoined
+	}
+	return nil
+}
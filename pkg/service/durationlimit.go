@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	// types of warnings sent to clients as data messages before a limit is reached
+	durationWarningRoom    = "room_duration_warning"
+	durationWarningSession = "session_duration_warning"
+	// sent to participants of a breakout room before they're moved back to the parent
+	durationWarningBreakout = "breakout_closing_warning"
+)
+
+// DurationWarning is the payload of the data message clients receive ahead of their room or session ending
+type DurationWarning struct {
+	Type             string `json:"type"`
+	RemainingSeconds uint32 `json:"remainingSeconds"`
+}
+
+// durationLimit ends a room or a participant session at a deadline, warning ahead of time
+type durationLimit struct {
+	warnTimer *time.Timer
+	endTimer  *time.Timer
+}
+
+func newDurationLimit(deadline time.Time, warning time.Duration, onWarning func(remaining time.Duration), onEnd func()) *durationLimit {
+	d := &durationLimit{}
+	remaining := time.Until(deadline)
+	if warning > 0 && remaining > 0 {
+		warnIn := remaining - warning
+		if warnIn < 0 {
+			warnIn = 0
+			warning = remaining
+		}
+		d.warnTimer = time.AfterFunc(warnIn, func() {
+			onWarning(warning)
+		})
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	d.endTimer = time.AfterFunc(remaining, onEnd)
+	return d
+}
+
+func (d *durationLimit) Stop() {
+	if d == nil {
+		return
+	}
+	if d.warnTimer != nil {
+		d.warnTimer.Stop()
+	}
+	d.endTimer.Stop()
+}
+
+func newDurationWarning(warningType string, remaining time.Duration) *DurationWarning {
+	return &DurationWarning{
+		Type:             warningType,
+		RemainingSeconds: uint32(remaining.Round(time.Second) / time.Second),
+	}
+}
+
+// roomDurationLimit returns the limit enforcing the room's max duration, or nil when it's unlimited
+func (r *RoomManager) roomDurationLimit(room *rtc.Room, ri *livekit.Room, internal *types.RoomInternal, roomConfig config.RoomConfig) *durationLimit {
+	maxDuration := internal.MaxDuration
+	if maxDuration == 0 {
+		maxDuration = roomConfig.MaxDuration
+	}
+	if maxDuration == 0 {
+		return nil
+	}
+
+	deadline := time.Unix(ri.CreationTime, 0).Add(time.Duration(maxDuration) * time.Second)
+	warning := time.Duration(roomConfig.DurationWarning) * time.Second
+	return newDurationLimit(deadline, warning, func(remaining time.Duration) {
+		if up, err := rtc.NewJSONUserPacket(newDurationWarning(durationWarningRoom, remaining)); err == nil {
+			room.SendServerDataPacket(up, livekit.DataPacket_RELIABLE)
+		}
+	}, func() {
+		if room.IsClosed() {
+			return
+		}
+		room.Logger.Infow("room reached its max duration, closing", "maxDuration", maxDuration)
+		r.telemetry.DurationLimitReached(context.Background(), room.ToProto(), nil)
+		for _, p := range room.GetParticipants() {
+			_ = p.Close(true, types.ParticipantCloseReasonRoomDurationExceeded)
+		}
+		room.Close()
+	})
+}
+
+// sessionDurationLimit returns the limit enforcing the participant's max session duration, or nil when it's unlimited.
+// The duration counts from the first session of its identity in the room, rejoining doesn't extend it
+func (r *RoomManager) sessionDurationLimit(ctx context.Context, session *rtcSession, participant types.LocalParticipant, maxDuration time.Duration) *durationLimit {
+	if maxDuration <= 0 {
+		return nil
+	}
+
+	startedAt, err := r.roomStore.LoadOrStoreSessionStart(ctx, session.Room().Name(), participant.Identity(), time.Now())
+	if err != nil {
+		participant.GetLogger().Warnw("could not load session start", err)
+		startedAt = time.Now()
+	}
+
+	warning := time.Duration(r.getRoomConfig().DurationWarning) * time.Second
+	return newDurationLimit(startedAt.Add(maxDuration), warning, func(remaining time.Duration) {
+		_ = rtc.SendJSONMessage(participant, newDurationWarning(durationWarningSession, remaining))
+	}, func() {
+		if participant.State() == livekit.ParticipantInfo_DISCONNECTED {
+			return
+		}
+		participant.GetLogger().Infow("participant reached its max session duration, closing", "maxDuration", maxDuration)
+		r.telemetry.DurationLimitReached(context.Background(), session.Room().ToProto(), participant.ToProto())
+		_ = participant.Close(true, types.ParticipantCloseReasonSessionDurationExceeded)
+	})
+}
@@ -6,12 +6,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/twitchtv/twirp"
 	"google.golang.org/protobuf/proto"
 
 	"github.com/livekit/protocol/egress"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/telemetry"
 )
 
@@ -59,6 +61,7 @@ func (s *EgressService) Stop() {
 }
 
 func (s *EgressService) StartRoomCompositeEgress(ctx context.Context, req *livekit.RoomCompositeEgressRequest) (*livekit.EgressInfo, error) {
+	req.RoomName = string(TenantRoomName(ctx, livekit.RoomName(req.RoomName)))
 	return s.StartEgress(ctx, livekit.RoomName(req.RoomName), &livekit.StartEgressRequest{
 		Request: &livekit.StartEgressRequest_RoomComposite{
 			RoomComposite: req,
@@ -67,6 +70,7 @@ func (s *EgressService) StartRoomCompositeEgress(ctx context.Context, req *livek
 }
 
 func (s *EgressService) StartTrackCompositeEgress(ctx context.Context, req *livekit.TrackCompositeEgressRequest) (*livekit.EgressInfo, error) {
+	req.RoomName = string(TenantRoomName(ctx, livekit.RoomName(req.RoomName)))
 	return s.StartEgress(ctx, livekit.RoomName(req.RoomName), &livekit.StartEgressRequest{
 		Request: &livekit.StartEgressRequest_TrackComposite{
 			TrackComposite: req,
@@ -75,6 +79,7 @@ func (s *EgressService) StartTrackCompositeEgress(ctx context.Context, req *live
 }
 
 func (s *EgressService) StartTrackEgress(ctx context.Context, req *livekit.TrackEgressRequest) (*livekit.EgressInfo, error) {
+	req.RoomName = string(TenantRoomName(ctx, livekit.RoomName(req.RoomName)))
 	return s.StartEgress(ctx, livekit.RoomName(req.RoomName), &livekit.StartEgressRequest{
 		Request: &livekit.StartEgressRequest_Track{
 			Track: req,
@@ -96,6 +101,14 @@ func (s *EgressService) StartEgress(ctx context.Context, roomName livekit.RoomNa
 	}
 	req.RoomId = room.Sid
 
+	internal, err := s.store.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		return nil, err
+	}
+	if !isEgressAllowed(internal, req) {
+		return nil, twirp.NewError(twirp.PermissionDenied, ErrRecordingNotAllowed.Error())
+	}
+
 	info, err := s.rpcClient.SendRequest(ctx, req)
 	if err != nil {
 		return nil, err
@@ -110,7 +123,39 @@ func (s *EgressService) StartEgress(ctx context.Context, roomName livekit.RoomNa
 		}
 	}()
 
-	return info, nil
+	return localEgressInfo(ctx, info), nil
+}
+
+const (
+	EgressTypeRoomComposite  = "room_composite"
+	EgressTypeTrackComposite = "track_composite"
+	EgressTypeTrack          = "track"
+)
+
+// isEgressAllowed checks the request against the recording rules of the room's template
+func isEgressAllowed(internal *types.RoomInternal, req *livekit.StartEgressRequest) bool {
+	if internal.RecordingDisabled {
+		return false
+	}
+	if len(internal.AllowedEgressTypes) == 0 {
+		return true
+	}
+
+	var egressType string
+	switch req.Request.(type) {
+	case *livekit.StartEgressRequest_RoomComposite:
+		egressType = EgressTypeRoomComposite
+	case *livekit.StartEgressRequest_TrackComposite:
+		egressType = EgressTypeTrackComposite
+	case *livekit.StartEgressRequest_Track:
+		egressType = EgressTypeTrack
+	}
+	for _, allowed := range internal.AllowedEgressTypes {
+		if allowed == egressType {
+			return true
+		}
+	}
+	return false
 }
 
 type LayoutMetadata struct {
@@ -131,12 +176,17 @@ func (s *EgressService) UpdateLayout(ctx context.Context, req *livekit.UpdateLay
 	}
 
 	ensureRoomName(info)
+	if !IsTenantRoom(ctx, livekit.RoomName(info.RoomName)) {
+		return nil, ErrEgressNotFound
+	}
 
 	metadata, err := json.Marshal(&LayoutMetadata{Layout: req.Layout})
 	if err != nil {
 		return nil, err
 	}
 
+	info = localEgressInfo(ctx, info)
+
 	grants := GetGrants(ctx)
 	grants.Video.Room = info.RoomName
 	grants.Video.RoomAdmin = true
@@ -161,6 +211,10 @@ func (s *EgressService) UpdateStream(ctx context.Context, req *livekit.UpdateStr
 		return nil, ErrEgressNotConnected
 	}
 
+	if err := s.ensureTenantEgress(ctx, req.EgressId); err != nil {
+		return nil, err
+	}
+
 	info, err := s.rpcClient.SendRequest(ctx, &livekit.EgressRequest{
 		EgressId: req.EgressId,
 		Request: &livekit.EgressRequest_UpdateStream{
@@ -179,7 +233,7 @@ func (s *EgressService) UpdateStream(ctx context.Context, req *livekit.UpdateStr
 		}
 	}()
 
-	return info, nil
+	return localEgressInfo(ctx, info), nil
 }
 
 func (s *EgressService) ListEgress(ctx context.Context, req *livekit.ListEgressRequest) (*livekit.ListEgressResponse, error) {
@@ -190,12 +244,20 @@ func (s *EgressService) ListEgress(ctx context.Context, req *livekit.ListEgressR
 		return nil, ErrEgressNotConnected
 	}
 
-	infos, err := s.es.ListEgress(ctx, livekit.RoomName(req.RoomName))
+	infos, err := s.es.ListEgress(ctx, TenantRoomName(ctx, livekit.RoomName(req.RoomName)))
 	if err != nil {
 		return nil, err
 	}
 
-	return &livekit.ListEgressResponse{Items: infos}, nil
+	items := make([]*livekit.EgressInfo, 0, len(infos))
+	for _, info := range infos {
+		ensureRoomName(info)
+		if IsTenantRoom(ctx, livekit.RoomName(info.RoomName)) {
+			items = append(items, localEgressInfo(ctx, info))
+		}
+	}
+
+	return &livekit.ListEgressResponse{Items: items}, nil
 }
 
 func (s *EgressService) StopEgress(ctx context.Context, req *livekit.StopEgressRequest) (*livekit.EgressInfo, error) {
@@ -206,6 +268,10 @@ func (s *EgressService) StopEgress(ctx context.Context, req *livekit.StopEgressR
 		return nil, ErrEgressNotConnected
 	}
 
+	if err := s.ensureTenantEgress(ctx, req.EgressId); err != nil {
+		return nil, err
+	}
+
 	info, err := s.rpcClient.SendRequest(ctx, &livekit.EgressRequest{
 		EgressId: req.EgressId,
 		Request: &livekit.EgressRequest_Stop{
@@ -224,7 +290,24 @@ func (s *EgressService) StopEgress(ctx context.Context, req *livekit.StopEgressR
 		}
 	}()
 
-	return info, nil
+	return localEgressInfo(ctx, info), nil
+}
+
+// ensureTenantEgress prevents tenants from operating on egresses of other tenants
+func (s *EgressService) ensureTenantEgress(ctx context.Context, egressID string) error {
+	if GetTenant(ctx) == "" {
+		return nil
+	}
+
+	info, err := s.es.LoadEgress(ctx, egressID)
+	if err != nil {
+		return err
+	}
+	ensureRoomName(info)
+	if !IsTenantRoom(ctx, livekit.RoomName(info.RoomName)) {
+		return ErrEgressNotFound
+	}
+	return nil
 }
 
 func (s *EgressService) startWorker() error {
@@ -8,12 +8,19 @@ var (
 	ErrIdentityEmpty         = errors.New("identity cannot be empty")
 	ErrIngressNotConnected   = errors.New("ingress not connected (redis required)")
 	ErrIngressNotFound       = errors.New("ingress does not exist")
+	ErrInvalidIdentityPolicy = errors.New("invalid duplicate identity policy")
+	ErrInvalidRoomCodecs     = errors.New("room codecs must be audio or video mime types")
+	ErrInvalidTenant         = errors.New("invalid tenant")
 	ErrMetadataExceedsLimits = errors.New("metadata size exceeds limits")
 	ErrOperationFailed       = errors.New("operation cannot be completed")
 	ErrParticipantNotFound   = errors.New("participant does not exist")
+	ErrRecordingNotAllowed   = errors.New("recording is not allowed in this room")
 	ErrRoomNotFound          = errors.New("requested room does not exist")
+	ErrRoomNotLocal          = errors.New("room is not hosted on this node")
+	ErrRoomTemplateNotFound  = errors.New("requested room template does not exist")
 	ErrRoomLockFailed        = errors.New("could not lock room")
 	ErrRoomUnlockFailed      = errors.New("could not unlock room, lock token does not match")
+	ErrTenantLimitExceeded   = errors.New("tenant limit exceeded")
 	ErrTrackNotFound         = errors.New("track is not found")
 	ErrWebHookMissingAPIKey  = errors.New("api_key is required to use webhooks")
 )
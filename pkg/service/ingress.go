@@ -72,7 +72,7 @@ func (s *IngressService) CreateIngress(ctx context.Context, req *livekit.CreateI
 		InputType:           req.InputType,
 		Audio:               req.Audio,
 		Video:               req.Video,
-		RoomName:            req.RoomName,
+		RoomName:            string(TenantRoomName(ctx, livekit.RoomName(req.RoomName))),
 		ParticipantIdentity: req.ParticipantIdentity,
 		ParticipantName:     req.ParticipantName,
 		Reusable:            req.InputType == livekit.IngressInput_RTMP_INPUT,
@@ -86,7 +86,7 @@ func (s *IngressService) CreateIngress(ctx context.Context, req *livekit.CreateI
 		return nil, err
 	}
 
-	return info, nil
+	return localIngressInfo(ctx, info), nil
 }
 
 func (s *IngressService) UpdateIngress(ctx context.Context, req *livekit.UpdateIngressRequest) (*livekit.IngressInfo, error) {
@@ -107,6 +107,10 @@ func (s *IngressService) UpdateIngress(ctx context.Context, req *livekit.UpdateI
 		logger.Errorw("could not load ingress info", err)
 		return nil, err
 	}
+	if !IsTenantRoom(ctx, livekit.RoomName(info.RoomName)) {
+		return nil, ErrIngressNotFound
+	}
+	req.RoomName = string(TenantRoomName(ctx, livekit.RoomName(req.RoomName)))
 
 	switch info.State.Status {
 	case livekit.IngressState_ENDPOINT_ERROR:
@@ -151,7 +155,7 @@ func (s *IngressService) UpdateIngress(ctx context.Context, req *livekit.UpdateI
 		return nil, err
 	}
 
-	return info, nil
+	return localIngressInfo(ctx, info), nil
 }
 
 func (s *IngressService) ListIngress(ctx context.Context, req *livekit.ListIngressRequest) (*livekit.ListIngressResponse, error) {
@@ -167,13 +171,20 @@ func (s *IngressService) ListIngress(ctx context.Context, req *livekit.ListIngre
 		return nil, ErrIngressNotConnected
 	}
 
-	infos, err := s.store.ListIngress(ctx, livekit.RoomName(req.RoomName))
+	infos, err := s.store.ListIngress(ctx, TenantRoomName(ctx, livekit.RoomName(req.RoomName)))
 	if err != nil {
 		logger.Errorw("could not list ingress info", err)
 		return nil, err
 	}
 
-	return &livekit.ListIngressResponse{Items: infos}, nil
+	items := make([]*livekit.IngressInfo, 0, len(infos))
+	for _, info := range infos {
+		if IsTenantRoom(ctx, livekit.RoomName(info.RoomName)) {
+			items = append(items, localIngressInfo(ctx, info))
+		}
+	}
+
+	return &livekit.ListIngressResponse{Items: items}, nil
 }
 
 func (s *IngressService) DeleteIngress(ctx context.Context, req *livekit.DeleteIngressRequest) (*livekit.IngressInfo, error) {
@@ -189,6 +200,9 @@ func (s *IngressService) DeleteIngress(ctx context.Context, req *livekit.DeleteI
 	if err != nil {
 		return nil, err
 	}
+	if !IsTenantRoom(ctx, livekit.RoomName(info.RoomName)) {
+		return nil, ErrIngressNotFound
+	}
 
 	switch info.State.Status {
 	case livekit.IngressState_ENDPOINT_BUFFERING,
@@ -210,7 +224,7 @@ func (s *IngressService) DeleteIngress(ctx context.Context, req *livekit.DeleteI
 	}
 
 	info.State.Status = livekit.IngressState_ENDPOINT_INACTIVE
-	return info, nil
+	return localIngressInfo(ctx, info), nil
 }
 
 func (s *IngressService) updateWorker() {
@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 )
 
 //go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 -generate
@@ -21,8 +23,15 @@ type ObjectStore interface {
 
 	StoreRoom(ctx context.Context, room *livekit.Room) error
 	DeleteRoom(ctx context.Context, name livekit.RoomName) error
+	StoreRoomInternal(ctx context.Context, name livekit.RoomName, internal *types.RoomInternal) error
+	// AppendDataMessage adds a message to the data history of a room, keeping at most maxMessages of the latest,
+	// with payloads of at most maxBytes in total when it's not 0
+	AppendDataMessage(ctx context.Context, name livekit.RoomName, msg *types.DataMessage, maxMessages, maxBytes uint32) error
 
 	StoreParticipant(ctx context.Context, roomName livekit.RoomName, participant *livekit.ParticipantInfo) error
+	// LoadOrStoreSessionStart records when a participant first started a session in the room, returning the time
+	// recorded by an earlier session when there's one
+	LoadOrStoreSessionStart(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, startedAt time.Time) (time.Time, error)
 	DeleteParticipant(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) error
 }
 
@@ -32,9 +41,15 @@ type ServiceStore interface {
 	// ListRooms returns currently active rooms. if names is not nil, it'll filter and return
 	// only rooms that match
 	ListRooms(ctx context.Context, names []livekit.RoomName) ([]*livekit.Room, error)
+	// LoadRoomInternal returns server side settings of a room, empty when none have been stored
+	LoadRoomInternal(ctx context.Context, name livekit.RoomName) (*types.RoomInternal, error)
+	// LoadDataHistory returns data messages retained for a room, oldest first
+	LoadDataHistory(ctx context.Context, name livekit.RoomName) ([]*types.DataMessage, error)
 
 	LoadParticipant(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error)
 	ListParticipants(ctx context.Context, roomName livekit.RoomName) ([]*livekit.ParticipantInfo, error)
+	// CountTenantParticipants returns the number of participants in rooms of a tenant
+	CountTenantParticipants(ctx context.Context, tenant string) (int, error)
 }
 
 //counterfeiter:generate . EgressStore
@@ -8,14 +8,25 @@ import (
 	"github.com/thoas/go-funk"
 
 	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/telemetry"
 )
 
 // encapsulates CRUD operations for room settings
 type LocalStore struct {
 	// map of roomName => room
 	rooms map[livekit.RoomName]*livekit.Room
+	// map of roomName => server side settings
+	roomInternal map[livekit.RoomName]*types.RoomInternal
+	// map of roomName => data messages, oldest first
+	dataHistory map[livekit.RoomName][]*types.DataMessage
 	// map of roomName => { identity: participant }
 	participants map[livekit.RoomName]map[livekit.ParticipantIdentity]*livekit.ParticipantInfo
+	// map of roomName => { identity: start of its first session }
+	sessionStart map[livekit.RoomName]map[livekit.ParticipantIdentity]time.Time
+	// map of usage kind => { room name or API key: usage }
+	usage map[telemetry.UsageKind]map[string]*telemetry.UsageStats
 
 	lock       sync.RWMutex
 	globalLock sync.Mutex
@@ -24,7 +35,11 @@ type LocalStore struct {
 func NewLocalStore() *LocalStore {
 	return &LocalStore{
 		rooms:        make(map[livekit.RoomName]*livekit.Room),
+		roomInternal: make(map[livekit.RoomName]*types.RoomInternal),
+		dataHistory:  make(map[livekit.RoomName][]*types.DataMessage),
 		participants: make(map[livekit.RoomName]map[livekit.ParticipantIdentity]*livekit.ParticipantInfo),
+		sessionStart: make(map[livekit.RoomName]map[livekit.ParticipantIdentity]time.Time),
+		usage:        make(map[telemetry.UsageKind]map[string]*telemetry.UsageStats),
 		lock:         sync.RWMutex{},
 	}
 }
@@ -75,9 +90,65 @@ func (s *LocalStore) DeleteRoom(ctx context.Context, name livekit.RoomName) erro
 
 	delete(s.participants, livekit.RoomName(room.Name))
 	delete(s.rooms, livekit.RoomName(room.Name))
+	delete(s.roomInternal, livekit.RoomName(room.Name))
+	delete(s.dataHistory, livekit.RoomName(room.Name))
+	delete(s.sessionStart, livekit.RoomName(room.Name))
 	return nil
 }
 
+func (s *LocalStore) StoreRoomInternal(_ context.Context, name livekit.RoomName, internal *types.RoomInternal) error {
+	s.lock.Lock()
+	s.roomInternal[name] = internal
+	s.lock.Unlock()
+	return nil
+}
+
+func (s *LocalStore) LoadRoomInternal(_ context.Context, name livekit.RoomName) (*types.RoomInternal, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	internal := s.roomInternal[name]
+	if internal == nil {
+		return &types.RoomInternal{}, nil
+	}
+	return internal, nil
+}
+
+func (s *LocalStore) AppendDataMessage(_ context.Context, name livekit.RoomName, msg *types.DataMessage, maxMessages, maxBytes uint32) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	history := append(s.dataHistory[name], msg)
+	if drop := dataHistoryOverflow(history, maxMessages, maxBytes); drop > 0 {
+		history = append([]*types.DataMessage{}, history[drop:]...)
+	}
+	s.dataHistory[name] = history
+	return nil
+}
+
+func (s *LocalStore) LoadOrStoreSessionStart(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, startedAt time.Time) (time.Time, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	starts := s.sessionStart[roomName]
+	if starts == nil {
+		starts = make(map[livekit.ParticipantIdentity]time.Time)
+		s.sessionStart[roomName] = starts
+	}
+	if stored, ok := starts[identity]; ok {
+		return stored, nil
+	}
+	starts[identity] = startedAt
+	return startedAt, nil
+}
+
+func (s *LocalStore) LoadDataHistory(_ context.Context, name livekit.RoomName) ([]*types.DataMessage, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return append([]*types.DataMessage{}, s.dataHistory[name]...), nil
+}
+
 func (s *LocalStore) LockRoom(_ context.Context, _ livekit.RoomName, _ time.Duration) (string, error) {
 	// local rooms lock & unlock globally
 	s.globalLock.Lock()
@@ -134,6 +205,19 @@ func (s *LocalStore) ListParticipants(_ context.Context, roomName livekit.RoomNa
 	return items, nil
 }
 
+func (s *LocalStore) CountTenantParticipants(_ context.Context, tenant string) (int, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	count := 0
+	for roomName, roomParticipants := range s.participants {
+		if roomTenant(roomName) == tenant {
+			count += len(roomParticipants)
+		}
+	}
+	return count, nil
+}
+
 func (s *LocalStore) DeleteParticipant(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
@@ -144,3 +228,35 @@ func (s *LocalStore) DeleteParticipant(_ context.Context, roomName livekit.RoomN
 	}
 	return nil
 }
+
+func (s *LocalStore) IncrementUsage(_ context.Context, records []*telemetry.UsageRecord) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, r := range records {
+		kindUsage := s.usage[r.Kind]
+		if kindUsage == nil {
+			kindUsage = make(map[string]*telemetry.UsageStats)
+			s.usage[r.Kind] = kindUsage
+		}
+		usage := kindUsage[r.Key]
+		if usage == nil {
+			usage = &telemetry.UsageStats{}
+			kindUsage[r.Key] = usage
+		}
+		usage.Add(&r.UsageStats)
+	}
+	return nil
+}
+
+func (s *LocalStore) ListUsage(_ context.Context, kind telemetry.UsageKind) (map[string]*telemetry.UsageStats, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	usage := make(map[string]*telemetry.UsageStats, len(s.usage[kind]))
+	for key, u := range s.usage[kind] {
+		copied := *u
+		usage[key] = &copied
+	}
+	return usage, nil
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	roomOpModerateTracks     = "moderate_tracks"
+	roomOpRemoveParticipants = "remove_participants"
+)
+
+// ModerateTracks mutes or unpublishes all tracks of the given sources in a room, and those published during the
+// mute on entry window
+func (s *RoomService) ModerateTracks(ctx context.Context, req *ModerateTracksRequest) (*ModerationResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if len(req.Sources) == 0 {
+		return nil, twirp.RequiredArgumentError("sources")
+	}
+	if _, err := trackSources(req.Sources); err != nil {
+		return nil, err
+	}
+
+	res := &ModerationResponse{}
+	if err := executeRoomOperation(ctx, s.router, TenantRoomName(ctx, livekit.RoomName(req.Room)), roomOpModerateTracks, req, res); err != nil {
+		return nil, err
+	}
+	res.Room = req.Room
+	return res, nil
+}
+
+// RemoveParticipants removes participants of a room whose identity and metadata match the given patterns
+func (s *RoomService) RemoveParticipants(ctx context.Context, req *RemoveParticipantsRequest) (*ModerationResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if req.Identity == "" && req.Metadata == "" {
+		return nil, twirp.InvalidArgumentError("identity", "identity or metadata pattern is required")
+	}
+	if _, err := participantMatcher(req); err != nil {
+		return nil, err
+	}
+
+	res := &ModerationResponse{}
+	if err := executeRoomOperation(ctx, s.router, TenantRoomName(ctx, livekit.RoomName(req.Room)), roomOpRemoveParticipants, req, res); err != nil {
+		return nil, err
+	}
+	res.Room = req.Room
+	return res, nil
+}
+
+// moderateTracks applies a track moderation to a room hosted on this node
+func (r *RoomManager) moderateTracks(_ context.Context, room *rtc.Room, req *ModerateTracksRequest) (*ModerationResponse, error) {
+	sources, err := trackSources(req.Sources)
+	if err != nil {
+		return nil, err
+	}
+	except := make([]livekit.ParticipantIdentity, 0, len(req.Except))
+	for _, identity := range req.Except {
+		except = append(except, livekit.ParticipantIdentity(identity))
+	}
+	moderated := room.ModerateTracks(&rtc.TrackModeration{
+		Sources:   sources,
+		Except:    except,
+		Unpublish: req.Unpublish,
+		Until:     time.Now().Add(time.Duration(req.MuteOnEntry) * time.Second),
+	})
+	room.Logger.Infow("moderated tracks", "tracks", moderated)
+	return &ModerationResponse{Tracks: moderated}, nil
+}
+
+// removeParticipants removes matching participants from a room hosted on this node
+func (r *RoomManager) removeParticipants(_ context.Context, room *rtc.Room, req *RemoveParticipantsRequest) (*ModerationResponse, error) {
+	match, err := participantMatcher(req)
+	if err != nil {
+		return nil, err
+	}
+	removed := room.RemoveParticipants(match, types.ParticipantCloseReasonServiceRequestRemoveParticipant)
+	room.Logger.Infow("removed participants", "participants", removed)
+	res := &ModerationResponse{}
+	for _, identity := range removed {
+		res.Participants = append(res.Participants, string(identity))
+	}
+	return res, nil
+}
+
+// trackSources parses names of track sources, such as microphone or screen_share
+func trackSources(names []string) ([]livekit.TrackSource, error) {
+	sources := make([]livekit.TrackSource, 0, len(names))
+	for _, name := range names {
+		source, ok := livekit.TrackSource_value[strings.ToUpper(name)]
+		if !ok || source == int32(livekit.TrackSource_UNKNOWN) {
+			return nil, twirp.InvalidArgumentError("sources", "unknown track source "+name)
+		}
+		sources = append(sources, livekit.TrackSource(source))
+	}
+	return sources, nil
+}
+
+// participantMatcher returns whether a participant matches all patterns of the request, and isn't excepted
+func participantMatcher(req *RemoveParticipantsRequest) (func(participant types.LocalParticipant) bool, error) {
+	var identity, metadata *regexp.Regexp
+	var err error
+	if req.Identity != "" {
+		if identity, err = regexp.Compile(req.Identity); err != nil {
+			return nil, twirp.InvalidArgumentError("identity", err.Error())
+		}
+	}
+	if req.Metadata != "" {
+		if metadata, err = regexp.Compile(req.Metadata); err != nil {
+			return nil, twirp.InvalidArgumentError("metadata", err.Error())
+		}
+	}
+
+	return func(participant types.LocalParticipant) bool {
+		for _, except := range req.Except {
+			if participant.Identity() == livekit.ParticipantIdentity(except) {
+				return false
+			}
+		}
+		if identity != nil && !identity.MatchString(string(participant.Identity())) {
+			return false
+		}
+		if metadata != nil && !metadata.MatchString(participant.ToProto().Metadata) {
+			return false
+		}
+		return true
+	}, nil
+}
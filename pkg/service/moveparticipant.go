@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	roomOpMoveParticipant = "move_participant"
+
+	// type of the data message guiding a participant to the room it's moved to
+	roomMoveRedirect = "room_move"
+)
+
+// RoomMoveRedirect is the payload of the data message a participant receives when it's moved to a room hosted on
+// another node. The participant is disconnected right after, and should join Room with Token
+type RoomMoveRedirect struct {
+	Type  string `json:"type"`
+	Room  string `json:"room"`
+	Token string `json:"token"`
+}
+
+// moveParticipantRequest moves a participant of a room hosted on the node to Destination
+type moveParticipantRequest struct {
+	Identity    livekit.ParticipantIdentity `json:"identity"`
+	Destination livekit.RoomName            `json:"destination"`
+}
+
+type moveParticipantResult struct {
+	Reconnect bool `json:"reconnect"`
+}
+
+// MoveParticipant moves a participant of a room hosted on this node to destination. When destination is hosted on this
+// node too, the participant keeps its session and peer connections. Otherwise it's redirected to rejoin there, and true
+// is returned
+func (r *RoomManager) MoveParticipant(ctx context.Context, room *rtc.Room, identity livekit.ParticipantIdentity, destination livekit.RoomName) (bool, error) {
+	roomName := room.Name()
+	participant := room.GetParticipant(identity)
+	if participant == nil {
+		return false, ErrParticipantNotFound
+	}
+	r.lock.RLock()
+	session := r.sessions[participant.ID()]
+	r.lock.RUnlock()
+	if session == nil {
+		return false, ErrParticipantNotFound
+	}
+	grantRoom := participantRoomName(participant, roomName, destination)
+
+	node, err := r.router.GetNodeForRoom(ctx, destination)
+	if err != nil {
+		return false, err
+	}
+	if node.Id != r.currentNode.Id {
+		return true, r.redirectParticipant(room, participant, session, grantRoom)
+	}
+
+	dest, err := r.getOrCreateRoom(ctx, destination)
+	if err != nil {
+		return false, err
+	}
+	defer dest.Release()
+
+	if err = room.MoveParticipant(identity, dest); err != nil {
+		return false, err
+	}
+	session.setRoom(dest)
+	// refreshes the client's token, so it reconnects to the destination
+	participant.SetRoomName(grantRoom)
+
+	pLogger := participant.GetLogger()
+	if err = r.roomStore.DeleteParticipant(ctx, roomName, identity); err != nil {
+		pLogger.Errorw("could not delete participant", err)
+	}
+	if err = r.roomStore.StoreParticipant(ctx, destination, participant.ToProto()); err != nil {
+		pLogger.Errorw("could not store participant", err)
+	}
+	protoRoom, destRoom := room.ToProto(), dest.ToProto()
+	if !participant.Hidden() {
+		for _, rm := range []*livekit.Room{protoRoom, destRoom} {
+			if err = r.roomStore.StoreRoom(ctx, rm); err != nil {
+				logger.Errorw("could not store room", err)
+			}
+		}
+	}
+
+	pi := participant.ToProto()
+	clientMeta := &livekit.AnalyticsClientMeta{Region: r.currentNode.Region, Node: r.currentNode.Id}
+	r.telemetry.ParticipantLeft(ctx, protoRoom, pi)
+	r.telemetry.ParticipantJoined(ctx, destRoom, pi, session.client, clientMeta, session.apiKey)
+	r.telemetry.ParticipantActive(ctx, destRoom, pi, clientMeta)
+	return false, nil
+}
+
+// redirectParticipant sends the participant a token for destination, and disconnects it to rejoin there
+func (r *RoomManager) redirectParticipant(room *rtc.Room, participant types.LocalParticipant, session *rtcSession, destination livekit.RoomName) error {
+	payload, err := r.roomMoveRedirect(session, participant, destination)
+	if err != nil {
+		return err
+	}
+	err = participant.SendDataPacket(&livekit.DataPacket{
+		Kind:  livekit.DataPacket_RELIABLE,
+		Value: &livekit.DataPacket_User{User: &livekit.UserPacket{Payload: payload}},
+	})
+	if err != nil {
+		return err
+	}
+
+	participant.GetLogger().Infow("redirecting participant", "destination", destination)
+	room.RemoveParticipantSession(participant, types.ParticipantCloseReasonServiceRequestRemoveParticipant)
+	return nil
+}
+
+// roomMoveRedirect returns the payload guiding the participant to rejoin in destination, with a token carrying the
+// grants and claims of its session, signed with the key it joined with
+func (r *RoomManager) roomMoveRedirect(session *rtcSession, participant types.LocalParticipant, destination livekit.RoomName) ([]byte, error) {
+	r.lock.RLock()
+	secret, ok := r.keys[session.apiKey]
+	r.lock.RUnlock()
+	if !ok {
+		return nil, ErrPermissionDenied
+	}
+
+	grants := participant.ClaimGrants()
+	grants.Identity = string(participant.Identity())
+	grants.Video.Room = string(destination)
+	token, err := signAccessToken(session.apiKey, secret, grants, session.claims, tokenDefaultTTL)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&RoomMoveRedirect{
+		Type:  roomMoveRedirect,
+		Room:  string(destination),
+		Token: token,
+	})
+}
+
+func (r *RoomManager) moveParticipant(ctx context.Context, room *rtc.Room, req *moveParticipantRequest) (*moveParticipantResult, error) {
+	reconnect, err := r.MoveParticipant(ctx, room, req.Identity, req.Destination)
+	switch err {
+	case nil:
+		return &moveParticipantResult{Reconnect: reconnect}, nil
+	case ErrParticipantNotFound, rtc.ErrParticipantNotFound:
+		return nil, twirp.NotFoundError(err.Error())
+	case rtc.ErrParticipantNotActive, rtc.ErrRoomClosed, rtc.ErrRoomLocked, rtc.ErrAlreadyJoined,
+		rtc.ErrMaxParticipantsExceeded, rtc.ErrMaxPublishersExceeded:
+		return nil, twirp.NewError(twirp.FailedPrecondition, err.Error())
+	default:
+		return nil, err
+	}
+}
+
+// moveParticipant moves the participant to destination on the node hosting its room, returning whether the
+// participant was asked to reconnect
+func (s *RoomService) moveParticipant(ctx context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, destination livekit.RoomName) (bool, error) {
+	res := &moveParticipantResult{}
+	err := executeRoomOperation(ctx, s.router, roomName, roomOpMoveParticipant, &moveParticipantRequest{
+		Identity:    identity,
+		Destination: destination,
+	}, res)
+	return res.Reconnect, err
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"strings"
@@ -12,6 +13,8 @@ import (
 	"github.com/pkg/errors"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/version"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -24,6 +27,9 @@ const (
 	// RoomsKey is hash of room_name => Room proto
 	RoomsKey = "rooms"
 
+	// RoomInternalKey is hash of room_name => RoomInternal json
+	RoomInternalKey = "room_internal"
+
 	// EgressKey is a hash of egressID => egress info
 	EgressKey                  = "egress"
 	EndedEgressKey             = "ended_egress"
@@ -38,9 +44,23 @@ const (
 	// RoomParticipantsPrefix is hash of participant_name => ParticipantInfo
 	RoomParticipantsPrefix = "room_participants:"
 
+	// RoomDataHistoryPrefix is a list of DataMessage json, oldest first
+	RoomDataHistoryPrefix = "room_data_history:"
+
+	// TenantParticipantsPrefix is a hash of quoted room_name + participant_name => 1, for participants in rooms of a tenant
+	TenantParticipantsPrefix = "tenant_participants:"
+
+	// RoomSessionStartsPrefix is a hash of participant_name => unix time in milliseconds of its first session
+	RoomSessionStartsPrefix = "room_session_starts:"
+
 	// RoomLockPrefix is a simple key containing a provided lock uid
 	RoomLockPrefix = "room_lock:"
 
+	// UsagePrefix is a hash of usage field => accumulated value, e.g. usage:room:<room_name>
+	UsagePrefix = "usage:"
+	// UsageKeysPrefix is a set of room names or API keys with usage, e.g. usage_keys:room
+	UsageKeysPrefix = "usage_keys:"
+
 	maxRetries = 5
 )
 
@@ -173,14 +193,112 @@ func (s *RedisStore) DeleteRoom(ctx context.Context, name livekit.RoomName) erro
 		return nil
 	}
 
+	var identities []string
+	tenant := roomTenant(name)
+	if tenant != "" {
+		identities, err = s.rc.HKeys(s.ctx, RoomParticipantsPrefix+string(name)).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
 	pp := s.rc.Pipeline()
+	for _, identity := range identities {
+		pp.HDel(s.ctx, TenantParticipantsPrefix+tenant, tenantParticipantField(name, livekit.ParticipantIdentity(identity)))
+	}
 	pp.HDel(s.ctx, RoomsKey, string(name))
+	pp.HDel(s.ctx, RoomInternalKey, string(name))
 	pp.Del(s.ctx, RoomParticipantsPrefix+string(name))
+	pp.Del(s.ctx, RoomDataHistoryPrefix+string(name))
+	pp.Del(s.ctx, RoomSessionStartsPrefix+string(name))
 
 	_, err = pp.Exec(s.ctx)
 	return err
 }
 
+func (s *RedisStore) StoreRoomInternal(_ context.Context, name livekit.RoomName, internal *types.RoomInternal) error {
+	data, err := json.Marshal(internal)
+	if err != nil {
+		return err
+	}
+	return s.rc.HSet(s.ctx, RoomInternalKey, string(name), data).Err()
+}
+
+func (s *RedisStore) LoadRoomInternal(_ context.Context, name livekit.RoomName) (*types.RoomInternal, error) {
+	internal := &types.RoomInternal{}
+	data, err := s.rc.HGet(s.ctx, RoomInternalKey, string(name)).Result()
+	if err == redis.Nil {
+		return internal, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal([]byte(data), internal); err != nil {
+		return nil, err
+	}
+	return internal, nil
+}
+
+func (s *RedisStore) AppendDataMessage(_ context.Context, name livekit.RoomName, msg *types.DataMessage, maxMessages, maxBytes uint32) error {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	key := RoomDataHistoryPrefix + string(name)
+	pp := s.rc.TxPipeline()
+	pp.RPush(s.ctx, key, data)
+	if maxMessages > 0 {
+		pp.LTrim(s.ctx, key, -int64(maxMessages), -1)
+	}
+	if _, err = pp.Exec(s.ctx); err != nil || maxBytes == 0 {
+		return err
+	}
+
+	// only the room's node appends to its history, which can't change until the list is trimmed
+	history, err := s.LoadDataHistory(s.ctx, name)
+	if err != nil {
+		return err
+	}
+	if drop := dataHistoryOverflow(history, maxMessages, maxBytes); drop > 0 {
+		return s.rc.LTrim(s.ctx, key, int64(drop), -1).Err()
+	}
+	return nil
+}
+
+func (s *RedisStore) LoadOrStoreSessionStart(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity, startedAt time.Time) (time.Time, error) {
+	key := RoomSessionStartsPrefix + string(roomName)
+	pp := s.rc.TxPipeline()
+	pp.HSetNX(s.ctx, key, string(identity), startedAt.UnixMilli())
+	stored := pp.HGet(s.ctx, key, string(identity))
+	if _, err := pp.Exec(s.ctx); err != nil {
+		return time.Time{}, err
+	}
+
+	millis, err := stored.Int64()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(millis), nil
+}
+
+func (s *RedisStore) LoadDataHistory(_ context.Context, name livekit.RoomName) ([]*types.DataMessage, error) {
+	items, err := s.rc.LRange(s.ctx, RoomDataHistoryPrefix+string(name), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*types.DataMessage, 0, len(items))
+	for _, item := range items {
+		msg := &types.DataMessage{}
+		if err = json.Unmarshal([]byte(item), msg); err != nil {
+			return nil, err
+		}
+		history = append(history, msg)
+	}
+	return history, nil
+}
+
 func (s *RedisStore) LockRoom(_ context.Context, name livekit.RoomName, duration time.Duration) (string, error) {
 	token := utils.NewGuid("LOCK")
 	key := RoomLockPrefix + string(name)
@@ -231,7 +349,16 @@ func (s *RedisStore) StoreParticipant(_ context.Context, roomName livekit.RoomNa
 		return err
 	}
 
-	return s.rc.HSet(s.ctx, key, participant.Identity, data).Err()
+	tenant := roomTenant(roomName)
+	if tenant == "" {
+		return s.rc.HSet(s.ctx, key, participant.Identity, data).Err()
+	}
+
+	tx := s.rc.TxPipeline()
+	tx.HSet(s.ctx, key, participant.Identity, data)
+	tx.HSet(s.ctx, TenantParticipantsPrefix+tenant, tenantParticipantField(roomName, livekit.ParticipantIdentity(participant.Identity)), 1)
+	_, err = tx.Exec(s.ctx)
+	return err
 }
 
 func (s *RedisStore) LoadParticipant(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error) {
@@ -273,7 +400,30 @@ func (s *RedisStore) ListParticipants(_ context.Context, roomName livekit.RoomNa
 func (s *RedisStore) DeleteParticipant(_ context.Context, roomName livekit.RoomName, identity livekit.ParticipantIdentity) error {
 	key := RoomParticipantsPrefix + string(roomName)
 
-	return s.rc.HDel(s.ctx, key, string(identity)).Err()
+	tenant := roomTenant(roomName)
+	if tenant == "" {
+		return s.rc.HDel(s.ctx, key, string(identity)).Err()
+	}
+
+	tx := s.rc.TxPipeline()
+	tx.HDel(s.ctx, key, string(identity))
+	tx.HDel(s.ctx, TenantParticipantsPrefix+tenant, tenantParticipantField(roomName, identity))
+	_, err := tx.Exec(s.ctx)
+	return err
+}
+
+func (s *RedisStore) CountTenantParticipants(_ context.Context, tenant string) (int, error) {
+	count, err := s.rc.HLen(s.ctx, TenantParticipantsPrefix+tenant).Result()
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+	return int(count), nil
+}
+
+// tenantParticipantField identifies a participant among those of its tenant, the room name is quoted
+// as both names may contain any character
+func tenantParticipantField(roomName livekit.RoomName, identity livekit.ParticipantIdentity) string {
+	return strconv.Quote(string(roomName)) + string(identity)
 }
 
 func (s *RedisStore) StoreEgress(_ context.Context, info *livekit.EgressInfo) error {
@@ -611,6 +761,63 @@ func (s *RedisStore) DeleteIngress(_ context.Context, info *livekit.IngressInfo)
 	return nil
 }
 
+func (s *RedisStore) IncrementUsage(_ context.Context, records []*telemetry.UsageRecord) error {
+	_, err := s.rc.TxPipelined(s.ctx, func(tx redis.Pipeliner) error {
+		for _, r := range records {
+			key := UsagePrefix + string(r.Kind) + ":" + r.Key
+			tx.SAdd(s.ctx, UsageKeysPrefix+string(r.Kind), r.Key)
+			tx.HIncrByFloat(s.ctx, key, "participant_minutes", r.ParticipantMinutes)
+			tx.HIncrByFloat(s.ctx, key, "published_audio_minutes", r.PublishedAudioMinutes)
+			tx.HIncrByFloat(s.ctx, key, "published_video_minutes", r.PublishedVideoMinutes)
+			tx.HIncrByFloat(s.ctx, key, "subscribed_audio_minutes", r.SubscribedAudioMinutes)
+			tx.HIncrByFloat(s.ctx, key, "subscribed_video_minutes", r.SubscribedVideoMinutes)
+			tx.HIncrBy(s.ctx, key, "bytes_in", int64(r.BytesIn))
+			tx.HIncrBy(s.ctx, key, "bytes_out", int64(r.BytesOut))
+		}
+		return nil
+	})
+	return err
+}
+
+func (s *RedisStore) ListUsage(_ context.Context, kind telemetry.UsageKind) (map[string]*telemetry.UsageStats, error) {
+	keys, err := s.rc.SMembers(s.ctx, UsageKeysPrefix+string(kind)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	pp := s.rc.Pipeline()
+	cmds := make([]*redis.StringStringMapCmd, 0, len(keys))
+	for _, key := range keys {
+		cmds = append(cmds, pp.HGetAll(s.ctx, UsagePrefix+string(kind)+":"+key))
+	}
+	if _, err = pp.Exec(s.ctx); err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	usage := make(map[string]*telemetry.UsageStats, len(keys))
+	for i, key := range keys {
+		fields := cmds[i].Val()
+		parseFloat := func(field string) float64 {
+			v, _ := strconv.ParseFloat(fields[field], 64)
+			return v
+		}
+		parseUint := func(field string) uint64 {
+			v, _ := strconv.ParseUint(fields[field], 10, 64)
+			return v
+		}
+		usage[key] = &telemetry.UsageStats{
+			ParticipantMinutes:     parseFloat("participant_minutes"),
+			PublishedAudioMinutes:  parseFloat("published_audio_minutes"),
+			PublishedVideoMinutes:  parseFloat("published_video_minutes"),
+			SubscribedAudioMinutes: parseFloat("subscribed_audio_minutes"),
+			SubscribedVideoMinutes: parseFloat("subscribed_video_minutes"),
+			BytesIn:                parseUint("bytes_in"),
+			BytesOut:               parseUint("bytes_out"),
+		}
+	}
+	return usage, nil
+}
+
 // Migration to LiveKit >= v1.1.3
 func (s *RedisStore) MigrateEgressInfo() (int, error) {
 	locked, err := s.rc.SetNX(s.ctx, "egress-migration", utils.NewGuid("LOCK"), time.Minute).Result()
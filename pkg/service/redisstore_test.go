@@ -63,6 +63,43 @@ func TestParticipantPersistence(t *testing.T) {
 	require.Equal(t, err, service.ErrParticipantNotFound)
 }
 
+func TestTenantParticipantCount(t *testing.T) {
+	ctx := context.Background()
+	rs := service.NewRedisStore(redisClient())
+
+	roomName := livekit.RoomName("tenant1/room1")
+	otherRoomName := livekit.RoomName("tenant2/room1")
+	_ = rs.DeleteRoom(ctx, roomName)
+	_ = rs.DeleteRoom(ctx, otherRoomName)
+	require.NoError(t, rs.StoreRoom(ctx, &livekit.Room{Name: string(roomName)}))
+
+	require.NoError(t, rs.StoreParticipant(ctx, roomName, &livekit.ParticipantInfo{Sid: "PA_1", Identity: "p1"}))
+	require.NoError(t, rs.StoreParticipant(ctx, roomName, &livekit.ParticipantInfo{Sid: "PA_2", Identity: "p2"}))
+	require.NoError(t, rs.StoreParticipant(ctx, otherRoomName, &livekit.ParticipantInfo{Sid: "PA_3", Identity: "p1"}))
+	// updates of a participant are counted once
+	require.NoError(t, rs.StoreParticipant(ctx, roomName, &livekit.ParticipantInfo{Sid: "PA_1", Identity: "p1", Metadata: "updated"}))
+
+	count, err := rs.CountTenantParticipants(ctx, "tenant1")
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	require.NoError(t, rs.DeleteParticipant(ctx, roomName, "p2"))
+	count, err = rs.CountTenantParticipants(ctx, "tenant1")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	// participants of deleted rooms are no longer counted
+	require.NoError(t, rs.DeleteRoom(ctx, roomName))
+	count, err = rs.CountTenantParticipants(ctx, "tenant1")
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	count, err = rs.CountTenantParticipants(ctx, "tenant2")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.NoError(t, rs.DeleteParticipant(ctx, otherRoomName, "p1"))
+}
+
 func TestRoomLock(t *testing.T) {
 	ctx := context.Background()
 	rs := service.NewRedisStore(redisClient())
@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
@@ -11,13 +17,22 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/selector"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 )
 
+// key of the room's JSON metadata selecting a template
+const roomTemplateMetadataKey = "template"
+
 type StandardRoomAllocator struct {
 	config    *config.Config
 	router    routing.Router
-	selector  selector.NodeSelector
 	roomStore ObjectStore
+
+	lock       sync.RWMutex
+	selector   selector.NodeSelector
+	roomConfig config.RoomConfig
+	limit      config.LimitConfig
 }
 
 func NewRoomAllocator(conf *config.Config, router routing.Router, rs ObjectStore) (RoomAllocator, error) {
@@ -27,16 +42,38 @@ func NewRoomAllocator(conf *config.Config, router routing.Router, rs ObjectStore
 	}
 
 	return &StandardRoomAllocator{
-		config:    conf,
-		router:    router,
-		selector:  ns,
-		roomStore: rs,
+		config:     conf,
+		router:     router,
+		roomStore:  rs,
+		selector:   ns,
+		roomConfig: conf.Room,
+		limit:      conf.Limit,
 	}, nil
 }
 
+func (r *StandardRoomAllocator) UpdateConfig(conf *config.Config) {
+	ns, err := selector.CreateNodeSelector(conf)
+	if err != nil {
+		logger.Errorw("could not create node selector, keeping current", err)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if ns != nil {
+		r.selector = ns
+	}
+	r.roomConfig = conf.Room
+	r.limit = conf.Limit
+}
+
 // CreateRoom creates a new room from a request and allocates it to a node to handle
 // it'll also monitor its state, and cleans it up when appropriate
-func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (*livekit.Room, error) {
+func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.CreateRoomRequest) (rm *livekit.Room, err error) {
+	ctx, span := tracing.Start(ctx, "RoomAllocator.CreateRoom", trace.WithAttributes(attribute.String("room", req.Name)))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	token, err := r.roomStore.LockRoom(ctx, livekit.RoomName(req.Name), 5*time.Second)
 	if err != nil {
 		return nil, err
@@ -45,16 +82,53 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 		_ = r.roomStore.UnlockRoom(ctx, livekit.RoomName(req.Name), token)
 	}()
 
+	r.lock.RLock()
+	ns, roomConfig, limit := r.selector, r.roomConfig, r.limit
+	r.lock.RUnlock()
+
 	// find existing room and update it
-	rm, err := r.roomStore.LoadRoom(ctx, livekit.RoomName(req.Name))
+	rm, err = r.roomStore.LoadRoom(ctx, livekit.RoomName(req.Name))
 	if err == ErrRoomNotFound {
+		if err = checkTenantRoomLimit(ctx, &r.config.Tenancy, r.roomStore); err != nil {
+			return nil, err
+		}
+		opts := parseRoomMetadataOptions(req.Metadata)
+		codecs, err := roomCodecs(opts)
+		if err != nil {
+			return nil, err
+		}
+		templateName := roomTemplateName(ctx, opts)
+		var template config.RoomTemplate
+		if templateName != "" {
+			var ok bool
+			if template, ok = roomConfig.Templates[templateName]; !ok {
+				return nil, ErrRoomTemplateNotFound
+			}
+		}
+
 		rm = &livekit.Room{
 			Sid:          utils.NewGuid(utils.RoomPrefix),
 			Name:         req.Name,
 			CreationTime: time.Now().Unix(),
 			TurnPassword: utils.RandomSecret(),
 		}
-		applyDefaultRoomConfig(rm, &r.config.Room)
+		applyDefaultRoomConfig(rm, &roomConfig)
+		internal := &types.RoomInternal{}
+		if templateName != "" {
+			applyRoomTemplate(rm, &template)
+			internal = roomInternalFromTemplate(templateName, &template)
+		}
+		if opts.MaxDuration > 0 {
+			internal.MaxDuration = opts.MaxDuration
+		}
+		if templateName != "" || internal.MaxDuration > 0 {
+			if err = r.roomStore.StoreRoomInternal(ctx, livekit.RoomName(rm.Name), internal); err != nil {
+				return nil, err
+			}
+		}
+		if len(codecs) > 0 {
+			rm.EnabledCodecs = codecs
+		}
 	} else if err != nil {
 		return nil, err
 	}
@@ -81,7 +155,7 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 	// if already assigned and still available, keep it on that node
 	if err == nil && selector.IsAvailable(existing) {
 		// if node hosting the room is full, deny entry
-		if selector.LimitsReached(r.config.Limit, existing.Stats) {
+		if selector.LimitsReached(limit, existing.Stats) {
 			return nil, routing.ErrNodeLimitReached
 		}
 
@@ -96,7 +170,7 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 			return nil, err
 		}
 
-		node, err := r.selector.SelectNode(nodes)
+		node, err := ns.SelectNode(nodes)
 		if err != nil {
 			return nil, err
 		}
@@ -104,6 +178,7 @@ func (r *StandardRoomAllocator) CreateRoom(ctx context.Context, req *livekit.Cre
 		nodeID = livekit.NodeID(node.Id)
 	}
 
+	span.SetAttributes(attribute.String("nodeID", string(nodeID)))
 	logger.Debugw("selected node for room", "room", rm.Name, "roomID", rm.Sid, "nodeID", nodeID)
 	err = r.router.SetNodeForRoom(ctx, livekit.RoomName(rm.Name), nodeID)
 	if err != nil {
@@ -123,3 +198,87 @@ func applyDefaultRoomConfig(room *livekit.Room, conf *config.RoomConfig) {
 		})
 	}
 }
+
+// roomMetadataOptions are room settings requested through keys of the room's JSON metadata, applied when it's created
+type roomMetadataOptions struct {
+	// name of the template to create the room from
+	Template string `json:"template"`
+	// codecs replacing server and template codecs, i.e. ["video/h264", "audio/opus"]
+	Codecs []string `json:"codecs"`
+	// number of seconds the room may stay open
+	MaxDuration uint32 `json:"maxDuration"`
+}
+
+func parseRoomMetadataOptions(metadata string) *roomMetadataOptions {
+	opts := &roomMetadataOptions{}
+	if metadata != "" {
+		// metadata isn't required to be JSON, keys that can't be decoded are ignored
+		_ = json.Unmarshal([]byte(metadata), opts)
+	}
+	return opts
+}
+
+// roomTemplateName returns the template requested in the room's metadata, or the token's claim when rooms are auto created
+func roomTemplateName(ctx context.Context, opts *roomMetadataOptions) string {
+	if opts.Template != "" {
+		return opts.Template
+	}
+	return GetServerClaims(ctx).RoomTemplate
+}
+
+func roomCodecs(opts *roomMetadataOptions) ([]*livekit.Codec, error) {
+	var codecs []*livekit.Codec
+	for _, mime := range opts.Codecs {
+		lower := strings.ToLower(mime)
+		if !strings.HasPrefix(lower, "audio/") && !strings.HasPrefix(lower, "video/") {
+			return nil, ErrInvalidRoomCodecs
+		}
+		codecs = append(codecs, &livekit.Codec{Mime: mime})
+	}
+	return codecs, nil
+}
+
+func applyRoomTemplate(room *livekit.Room, template *config.RoomTemplate) {
+	if template.EmptyTimeout > 0 {
+		room.EmptyTimeout = template.EmptyTimeout
+	}
+	if template.MaxParticipants > 0 {
+		room.MaxParticipants = template.MaxParticipants
+	}
+	if len(template.EnabledCodecs) > 0 {
+		room.EnabledCodecs = nil
+		for _, codec := range template.EnabledCodecs {
+			room.EnabledCodecs = append(room.EnabledCodecs, &livekit.Codec{
+				Mime:     codec.Mime,
+				FmtpLine: codec.FmtpLine,
+			})
+		}
+	}
+}
+
+func roomInternalFromTemplate(name string, template *config.RoomTemplate) *types.RoomInternal {
+	internal := &types.RoomInternal{
+		Template:           name,
+		MaxMetadataSize:    template.MaxMetadataSize,
+		MaxAttributesSize:  template.MaxAttributesSize,
+		MaxStateSize:       template.MaxStateSize,
+		MaxDuration:        template.MaxDuration,
+		MaxPublishers:      template.MaxPublishers,
+		RecordingDisabled:  template.Recording.Disabled,
+		AllowedEgressTypes: template.Recording.AllowedTypes,
+	}
+	// sources are validated when the config is loaded
+	internal.StartMuted, _ = trackSources(template.StartMuted)
+	if len(template.PublishSources) > 0 {
+		internal.PublishSources, _ = trackSources(template.PublishSources)
+	}
+	if len(template.RolePublishSources) > 0 {
+		internal.RolePublishSources = make(map[string][]livekit.TrackSource, len(template.RolePublishSources))
+		for role, names := range template.RolePublishSources {
+			internal.RolePublishSources[role], _ = trackSources(names)
+		}
+	}
+	internal.DataHistory = dataHistoryPolicy(template.DataHistory)
+	internal.DuplicateIdentity = types.DuplicateIdentityPolicy(template.DuplicateIdentity)
+	return internal
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/routingfakes"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/livekit-server/pkg/service/servicefakes"
 )
@@ -64,6 +65,107 @@ func TestCreateRoom(t *testing.T) {
 	})
 }
 
+func TestCreateRoomFromTemplate(t *testing.T) {
+	newAllocator := func(t *testing.T) (service.RoomAllocator, *servicefakes.FakeObjectStore) {
+		conf, err := config.NewConfig("", nil)
+		require.NoError(t, err)
+		conf.Room.Templates = map[string]config.RoomTemplate{
+			"webinar": {
+				MaxParticipants: 500,
+				MaxPublishers:   2,
+				EnabledCodecs:   []config.CodecSpec{{Mime: "audio/opus"}},
+				Recording: config.RoomTemplateRecording{
+					AllowedTypes: []string{service.EgressTypeRoomComposite},
+				},
+				StartMuted: []string{"microphone"},
+				RolePublishSources: map[string][]string{
+					"presenter": {"screen_share", "screen_share_audio"},
+					"audience":  {},
+				},
+				DataHistory:       config.DataHistoryConfig{MaxMessages: 50},
+				DuplicateIdentity: "reject",
+			},
+		}
+
+		node, err := routing.NewLocalNode(conf)
+		require.NoError(t, err)
+
+		store := &servicefakes.FakeObjectStore{}
+		store.LoadRoomReturns(nil, service.ErrRoomNotFound)
+		router := &routingfakes.FakeRouter{}
+		router.GetNodeForRoomReturns(node, nil)
+
+		ra, err := service.NewRoomAllocator(conf, router, store)
+		require.NoError(t, err)
+		return ra, store
+	}
+
+	t.Run("template selected by metadata", func(t *testing.T) {
+		ra, store := newAllocator(t)
+
+		room, err := ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{
+			Name:     "myroom",
+			Metadata: `{"template": "webinar"}`,
+		})
+		require.NoError(t, err)
+		require.EqualValues(t, 500, room.MaxParticipants)
+		require.Len(t, room.EnabledCodecs, 1)
+
+		require.Equal(t, 1, store.StoreRoomInternalCallCount())
+		_, name, internal := store.StoreRoomInternalArgsForCall(0)
+		require.Equal(t, livekit.RoomName("myroom"), name)
+		require.Equal(t, "webinar", internal.Template)
+		require.EqualValues(t, 2, internal.MaxPublishers)
+		require.Equal(t, []string{service.EgressTypeRoomComposite}, internal.AllowedEgressTypes)
+		require.Equal(t, []livekit.TrackSource{livekit.TrackSource_MICROPHONE}, internal.StartMuted)
+		require.Nil(t, internal.AllowedSources(""))
+		require.Equal(t, []livekit.TrackSource{livekit.TrackSource_SCREEN_SHARE, livekit.TrackSource_SCREEN_SHARE_AUDIO}, internal.AllowedSources("presenter"))
+		require.Empty(t, internal.AllowedSources("audience"))
+		require.NotNil(t, internal.AllowedSources("audience"))
+		require.Equal(t, &types.DataHistory{MaxMessages: 50}, internal.DataHistory)
+		require.Equal(t, types.DuplicateIdentityReject, internal.DuplicateIdentity)
+	})
+
+	t.Run("template selected by token claim", func(t *testing.T) {
+		ra, store := newAllocator(t)
+
+		ctx := service.WithServerClaims(context.Background(), &service.ServerClaims{RoomTemplate: "webinar"})
+		room, err := ra.CreateRoom(ctx, &livekit.CreateRoomRequest{Name: "myroom"})
+		require.NoError(t, err)
+		require.EqualValues(t, 500, room.MaxParticipants)
+		require.Equal(t, 1, store.StoreRoomInternalCallCount())
+	})
+
+	t.Run("codecs in metadata replace template codecs", func(t *testing.T) {
+		ra, _ := newAllocator(t)
+
+		room, err := ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{
+			Name:     "myroom",
+			Metadata: `{"template": "webinar", "codecs": ["video/h264", "audio/opus"]}`,
+		})
+		require.NoError(t, err)
+		require.Len(t, room.EnabledCodecs, 2)
+		require.Equal(t, "video/h264", room.EnabledCodecs[0].Mime)
+
+		_, err = ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{
+			Name:     "otherroom",
+			Metadata: `{"codecs": ["h264"]}`,
+		})
+		require.ErrorIs(t, err, service.ErrInvalidRoomCodecs)
+	})
+
+	t.Run("unknown template is rejected", func(t *testing.T) {
+		ra, store := newAllocator(t)
+
+		_, err := ra.CreateRoom(context.Background(), &livekit.CreateRoomRequest{
+			Name:     "myroom",
+			Metadata: `{"template": "unknown"}`,
+		})
+		require.ErrorIs(t, err, service.ErrRoomTemplateNotFound)
+		require.Zero(t, store.StoreRoomCallCount())
+	})
+}
+
 func newTestRoomAllocator(t *testing.T, conf *config.Config, node *livekit.Node) (service.RoomAllocator, *config.Config) {
 	store := &servicefakes.FakeObjectStore{}
 	store.LoadRoomReturns(nil, service.ErrRoomNotFound)
@@ -0,0 +1,412 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const roomAPIPrefix = "/room/"
+
+// LockRoomRequest locks or unlocks a room
+type LockRoomRequest struct {
+	Room   string `json:"room"`
+	Locked bool   `json:"locked"`
+}
+
+type LockRoomResponse struct {
+	Room   string `json:"room"`
+	Locked bool   `json:"locked"`
+}
+
+// MoveParticipantRequest moves a participant from Room to Destination
+type MoveParticipantRequest struct {
+	Room        string `json:"room"`
+	Identity    string `json:"identity"`
+	Destination string `json:"destination"`
+}
+
+type MoveParticipantResponse struct {
+	Room     string `json:"room"`
+	Identity string `json:"identity"`
+	// set when the participant was asked to reconnect, as the rooms are hosted on different nodes
+	Reconnect bool `json:"reconnect"`
+}
+
+// RoomInfo is a room along with its breakout links
+type RoomInfo struct {
+	*livekit.Room
+	Parent    string   `json:"parent,omitempty"`
+	Breakouts []string `json:"breakouts,omitempty"`
+	// unix time a breakout room closes back into its parent
+	CloseAt int64 `json:"closeAt,omitempty"`
+	// incremented on every update of the room's metadata
+	MetadataVersion uint32 `json:"metadataVersion"`
+	// new identities can't join a locked room
+	Locked bool `json:"locked"`
+}
+
+// ListRoomsRequest lists rooms like RoomService.ListRooms does, along with their breakout links
+type ListRoomsRequest struct {
+	Names []string `json:"names"`
+}
+
+type ListRoomsResponse struct {
+	Rooms []*RoomInfo `json:"rooms"`
+}
+
+// CreateBreakoutRoomRequest creates a breakout room of Room, moving Participants into it
+type CreateBreakoutRoomRequest struct {
+	Room     string `json:"room"`
+	Name     string `json:"name"`
+	Metadata string `json:"metadata"`
+	// seconds until the breakout room closes back into Room, 0 to close it explicitly
+	Duration     uint32   `json:"duration"`
+	Participants []string `json:"participants"`
+}
+
+// BroadcastBreakoutsRequest sends data to participants of all breakout rooms of Room
+type BroadcastBreakoutsRequest struct {
+	Room string `json:"room"`
+	Data []byte `json:"data"`
+}
+
+// CloseBreakoutsRequest moves participants of all breakout rooms of Room back into it, and closes them
+type CloseBreakoutsRequest struct {
+	Room string `json:"room"`
+}
+
+// BreakoutsResponse lists the breakout rooms an operation applied to
+type BreakoutsResponse struct {
+	Room      string   `json:"room"`
+	Breakouts []string `json:"breakouts"`
+}
+
+// BridgeTrackRequest republishes track TrackSid of participant Identity in Room into Destination,
+// as the track of a participant with BridgeIdentity
+type BridgeTrackRequest struct {
+	Room           string `json:"room"`
+	Identity       string `json:"identity"`
+	TrackSid       string `json:"trackSid"`
+	Destination    string `json:"destination"`
+	BridgeIdentity string `json:"bridgeIdentity"`
+}
+
+type BridgeTrackResponse struct {
+	Room     string `json:"room"`
+	Identity string `json:"identity"`
+	// sid of the bridged track in Room
+	TrackSid string `json:"trackSid"`
+}
+
+// StageRequest queues a request of the participant identified by the token to publish, or withdraws it
+type StageRequest struct {
+	Withdraw bool `json:"withdraw"`
+}
+
+type StageAction = types.StageAction
+
+const (
+	StageActionList    StageAction = "list"
+	StageActionApprove             = types.StageActionApprove
+	StageActionDeny                = types.StageActionDeny
+	StageActionDemote              = types.StageActionDemote
+)
+
+// ModerateStageRequest applies Action to participant Identity of Room's stage
+type ModerateStageRequest struct {
+	Room     string      `json:"room"`
+	Action   StageAction `json:"action"`
+	Identity string      `json:"identity"`
+}
+
+// StageResponse lists participants waiting to be approved, in order of their requests
+type StageResponse struct {
+	Room  string   `json:"room"`
+	Queue []string `json:"queue"`
+}
+
+// ModerateTracksRequest mutes, or unpublishes, all tracks of Sources in Room except those of participants in Except
+type ModerateTracksRequest struct {
+	Room string `json:"room"`
+	// track sources, one of camera, microphone, screen_share or screen_share_audio
+	Sources   []string `json:"sources"`
+	Except    []string `json:"except"`
+	Unpublish bool     `json:"unpublish"`
+	// seconds during which tracks published afterwards are moderated too
+	MuteOnEntry uint32 `json:"muteOnEntry"`
+}
+
+// RemoveParticipantsRequest removes participants of Room whose identity and metadata match the given regular
+// expressions, except participants in Except
+type RemoveParticipantsRequest struct {
+	Room     string   `json:"room"`
+	Identity string   `json:"identity"`
+	Metadata string   `json:"metadata"`
+	Except   []string `json:"except"`
+}
+
+// ModerationResponse holds what a moderation applied to, once the node hosting the room has applied it
+type ModerationResponse struct {
+	Room string `json:"room"`
+	// number of tracks moderated
+	Tracks int `json:"tracks,omitempty"`
+	// identities of participants removed
+	Participants []string `json:"participants,omitempty"`
+}
+
+// UpdateAttributesRequest sets attributes of participant Identity in Room key by key, keys set to an empty value are
+// removed. When Version is set, the update is applied only if the attributes are still at that version
+type UpdateAttributesRequest struct {
+	Room       string            `json:"room"`
+	Identity   string            `json:"identity"`
+	Attributes map[string]string `json:"attributes"`
+	Version    *uint32           `json:"version,omitempty"`
+}
+
+// SetAttributesRequest updates attributes of the participant identified by the token, like UpdateAttributesRequest
+type SetAttributesRequest struct {
+	Attributes map[string]string `json:"attributes"`
+	Version    *uint32           `json:"version,omitempty"`
+}
+
+// AttributesResponse holds all attributes of the participant after the update
+type AttributesResponse struct {
+	Room       string            `json:"room"`
+	Identity   string            `json:"identity"`
+	Attributes map[string]string `json:"attributes"`
+	Version    uint32            `json:"version"`
+}
+
+// UpdateMetadataRequest replaces metadata of Room. When Version is set, the update is applied only if the room's
+// metadata is still at that version
+type UpdateMetadataRequest struct {
+	Room     string  `json:"room"`
+	Metadata string  `json:"metadata"`
+	Version  *uint32 `json:"version,omitempty"`
+}
+
+// RoomStateValue sets a key of a room's shared state
+type RoomStateValue struct {
+	Value string `json:"value"`
+	// lets any participant change the key, otherwise only its owner and room admins may
+	Shared bool `json:"shared"`
+	// seconds after which the key is removed, 0 to keep it
+	TTL uint32 `json:"ttl"`
+}
+
+// UpdateRoomStateRequest sets and deletes keys of Room's shared state as a room admin. Keys are deleted first
+type UpdateRoomStateRequest struct {
+	Room   string                     `json:"room"`
+	Set    map[string]*RoomStateValue `json:"set"`
+	Delete []string                   `json:"delete"`
+}
+
+// SetRoomStateRequest updates the shared state of the room of the token, like UpdateRoomStateRequest.
+// keys the participant creates are owned by it
+type SetRoomStateRequest struct {
+	Set    map[string]*RoomStateValue `json:"set"`
+	Delete []string                   `json:"delete"`
+}
+
+type GetRoomStateRequest struct {
+	Room string `json:"room"`
+}
+
+// RoomStateResponse holds all keys of the room's shared state
+type RoomStateResponse struct {
+	Room    string                           `json:"room"`
+	Entries map[string]*types.RoomStateEntry `json:"entries"`
+}
+
+type DataHistoryRequest struct {
+	Room string `json:"room"`
+}
+
+// DataHistoryResponse holds data messages retained by a room, oldest first. Payloads are base64 encoded
+type DataHistoryResponse struct {
+	Room     string               `json:"room"`
+	Messages []*types.DataMessage `json:"messages"`
+}
+
+// roomAPIOperation decodes a request into the type its RoomService method takes, and calls it
+type roomAPIOperation func(ctx context.Context, decode func(req interface{}) error) (interface{}, error)
+
+// RoomAPI serves room operations that RoomService in the protocol doesn't define.
+// operations are POSTed as JSON to /room/<operation>, and are authorized like RoomService calls
+type RoomAPI struct {
+	operations map[string]roomAPIOperation
+}
+
+func NewRoomAPI(roomService *RoomService) *RoomAPI {
+	return &RoomAPI{
+		operations: map[string]roomAPIOperation{
+			"lock": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &LockRoomRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.LockRoom(ctx, req)
+			},
+			"move": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &MoveParticipantRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.MoveParticipant(ctx, req)
+			},
+			"list": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &ListRoomsRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.ListRoomInfo(ctx, req)
+			},
+			"create_breakout": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &CreateBreakoutRoomRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.CreateBreakoutRoom(ctx, req)
+			},
+			"broadcast_breakouts": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &BroadcastBreakoutsRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.BroadcastBreakouts(ctx, req)
+			},
+			"close_breakouts": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &CloseBreakoutsRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.CloseBreakouts(ctx, req)
+			},
+			"bridge_track": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &BridgeTrackRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.BridgeTrack(ctx, req)
+			},
+			"stage_request": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &StageRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.RequestPublish(ctx, req)
+			},
+			"stage": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &ModerateStageRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.ModerateStage(ctx, req)
+			},
+			"moderate_tracks": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &ModerateTracksRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.ModerateTracks(ctx, req)
+			},
+			"remove_participants": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &RemoveParticipantsRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.RemoveParticipants(ctx, req)
+			},
+			"update_metadata": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &UpdateMetadataRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.UpdateMetadata(ctx, req)
+			},
+			"get_state": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &GetRoomStateRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.GetRoomState(ctx, req)
+			},
+			"update_state": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &UpdateRoomStateRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.UpdateRoomState(ctx, req)
+			},
+			"set_state": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &SetRoomStateRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.SetRoomState(ctx, req)
+			},
+			"update_attributes": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &UpdateAttributesRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.UpdateParticipantAttributes(ctx, req)
+			},
+			"set_attributes": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &SetAttributesRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.SetAttributes(ctx, req)
+			},
+			"data_history": func(ctx context.Context, decode func(req interface{}) error) (interface{}, error) {
+				req := &DataHistoryRequest{}
+				if err := decode(req); err != nil {
+					return nil, err
+				}
+				return roomService.GetDataHistory(ctx, req)
+			},
+		},
+	}
+}
+
+func (a *RoomAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		handleError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	operation, ok := a.operations[strings.TrimPrefix(r.URL.Path, roomAPIPrefix)]
+	if !ok {
+		handleError(w, http.StatusNotFound, "unknown room operation")
+		return
+	}
+
+	decode := func(req interface{}) error {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			return twirp.NewError(twirp.Malformed, "could not decode request: "+err.Error())
+		}
+		return nil
+	}
+	res, err := operation(r.Context(), decode)
+	if err != nil {
+		var twErr twirp.Error
+		if errors.As(err, &twErr) {
+			handleError(w, twirp.ServerHTTPStatusFromErrorCode(twErr.Code()), twErr.Msg())
+		} else {
+			handleError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
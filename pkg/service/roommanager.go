@@ -7,8 +7,9 @@ import (
 	"time"
 
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
-	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
 	"github.com/livekit/protocol/utils"
@@ -19,6 +20,7 @@ import (
 	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 )
 
 const (
@@ -33,6 +35,30 @@ type iceConfigCacheEntry struct {
 	modifiedAt time.Time
 }
 
+// rtcSession tracks the room a participant's session runs in, which changes when the participant is moved
+type rtcSession struct {
+	lock sync.RWMutex
+	room *rtc.Room
+
+	// reported again when the participant joins another room
+	client *livekit.ClientInfo
+	// key that signed the participant's token, signing its refreshed tokens with the claims it joined with
+	apiKey string
+	claims *ServerClaims
+}
+
+func (s *rtcSession) Room() *rtc.Room {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.room
+}
+
+func (s *rtcSession) setRoom(room *rtc.Room) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.room = room
+}
+
 // RoomManager manages rooms and its interaction with participants.
 // It's responsible for creating, deleting rooms, as well as running sessions for participants
 type RoomManager struct {
@@ -46,9 +72,17 @@ type RoomManager struct {
 	telemetry         telemetry.TelemetryService
 	clientConfManager clientconfiguration.ClientConfigurationManager
 
-	rooms map[livekit.RoomName]*rtc.Room
+	rooms    map[livekit.RoomName]*rtc.Room
+	sessions map[livekit.ParticipantID]*rtcSession
+
+	// reloadable configuration
+	roomConfig config.RoomConfig
+	keys       map[string]string
 
 	iceConfigCache map[livekit.ParticipantIdentity]*iceConfigCacheEntry
+
+	// operations routed to rooms hosted on this node
+	roomOperations map[string]roomOperationHandler
 }
 
 func NewLocalRoomManager(
@@ -74,17 +108,127 @@ func NewLocalRoomManager(
 		telemetry:         telemetry,
 		clientConfManager: clientConfManager,
 
-		rooms: make(map[livekit.RoomName]*rtc.Room),
+		rooms:    make(map[livekit.RoomName]*rtc.Room),
+		sessions: make(map[livekit.ParticipantID]*rtcSession),
+
+		roomConfig: conf.Room,
+		keys:       conf.Keys,
 
 		iceConfigCache: make(map[livekit.ParticipantIdentity]*iceConfigCacheEntry),
 	}
+	r.roomOperations = map[string]roomOperationHandler{
+		roomOpSyncInternal: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &syncInternalRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.syncRoomInternal(ctx, room, req)
+		},
+		roomOpMoveParticipant: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &moveParticipantRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.moveParticipant(ctx, room, req)
+		},
+		roomOpModerateTracks: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &ModerateTracksRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.moderateTracks(ctx, room, req)
+		},
+		roomOpRemoveParticipants: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &RemoveParticipantsRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.removeParticipants(ctx, room, req)
+		},
+		roomOpBridgeTrack: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &bridgeTrackRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.bridgeTrack(ctx, room, req)
+		},
+		roomOpStage: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &stageOperationRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.updateStage(ctx, room, req)
+		},
+		roomOpUpdateAttributes: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &updateAttributesRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.updateAttributes(ctx, room, req)
+		},
+		roomOpUpdateMetadata: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &updateMetadataRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.updateMetadata(ctx, room, req)
+		},
+		roomOpGetState: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &getStateRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.getState(ctx, room, req)
+		},
+		roomOpUpdateState: func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error) {
+			req := &updateStateRequest{}
+			if err := decode(req); err != nil {
+				return nil, err
+			}
+			return r.updateState(ctx, room, req)
+		},
+	}
 
 	// hook up to router
 	router.OnNewParticipantRTC(r.StartSession)
 	router.OnRTCMessage(r.handleRTCMessage)
+	router.OnRoomOperation(r.handleRoomOperation)
 	return r, nil
 }
 
+func (r *RoomManager) UpdateConfig(conf *config.Config) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.roomConfig = conf.Room
+	r.keys = conf.Keys
+}
+
+func (r *RoomManager) getRoomConfig() config.RoomConfig {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.roomConfig
+}
+
+// dataLimits returns the limits on data packets of a participant joining the room
+func (r *RoomManager) dataLimits(room *rtc.Room) rtc.DataLimits {
+	conf := r.getRoomConfig().DataLimits
+	limits := rtc.DataLimits{
+		MaxPacketSize: conf.MaxPacketSize,
+		Participant:   rtc.NewDataRateLimiter(conf.ParticipantPacketRate, conf.ParticipantByteRate),
+		Room:          room.DataLimiter(),
+		Action:        rtc.DataLimitAction(conf.Action),
+	}
+	if limits.Action == "" {
+		limits.Action = rtc.DataLimitDrop
+	}
+	return limits
+}
+
+func (r *RoomManager) signalLimits() rtc.SignalLimits {
+	conf := r.getRoomConfig().SignalLimits
+	return rtc.NewSignalLimits(conf.RequestRates, conf.MaxPendingTracks, conf.MaxPublishedTracks, conf.MaxViolationRate)
+}
+
 func (r *RoomManager) GetRoom(_ context.Context, roomName livekit.RoomName) *rtc.Room {
 	r.lock.RLock()
 	defer r.lock.RUnlock()
@@ -94,11 +238,17 @@ func (r *RoomManager) GetRoom(_ context.Context, roomName livekit.RoomName) *rtc
 // DeleteRoom completely deletes all room information, including active sessions, room store, and routing info
 func (r *RoomManager) DeleteRoom(ctx context.Context, roomName livekit.RoomName) error {
 	logger.Infow("deleting room state", "room", roomName)
+	internal, err := r.roomStore.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		logger.Warnw("could not load room internal", err, "room", roomName)
+		internal = &types.RoomInternal{}
+	}
+
 	r.lock.Lock()
 	delete(r.rooms, roomName)
 	r.lock.Unlock()
 
-	var err, err2 error
+	var err2 error
 	wg := sync.WaitGroup{}
 	wg.Add(2)
 	// clear routing information
@@ -117,6 +267,7 @@ func (r *RoomManager) DeleteRoom(ctx context.Context, roomName livekit.RoomName)
 		err = err2
 	}
 
+	r.cleanupBreakouts(ctx, roomName, internal)
 	return err
 }
 
@@ -191,21 +342,37 @@ func (r *RoomManager) Stop() {
 	}
 }
 
-// StartSession starts WebRTC session when a new participant is connected, takes place on RTC node
+// StartSession starts WebRTC session when a new participant is connected, takes place on RTC node. Returns the SID of
+// the session started, or resumed when reconnecting
 func (r *RoomManager) StartSession(
 	ctx context.Context,
 	roomName livekit.RoomName,
 	pi routing.ParticipantInit,
 	requestSource routing.MessageSource,
 	responseSink routing.MessageSink,
-) error {
+) (pID livekit.ParticipantID, err error) {
+	ctx, span := tracing.Start(ctx, "RoomManager.StartSession", trace.WithAttributes(
+		attribute.String("room", string(roomName)),
+		attribute.String("participant", string(pi.Identity)),
+	))
+	defer func() {
+		tracing.EndSpan(span, err)
+	}()
+
 	room, err := r.getOrCreateRoom(ctx, roomName)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer room.Release()
 
 	participant := room.GetParticipant(pi.Identity)
+	if pi.Reconnect && pi.ParticipantID != "" {
+		// sessions of an identity joined several times resume by their SID
+		if p := room.GetParticipantBySid(pi.ParticipantID); p != nil && p.Identity() == pi.Identity {
+			participant = p
+		}
+	}
+	var duplicateID livekit.ParticipantID
 	if participant != nil {
 		// When reconnecting, it means WS has interrupted by underlying peer connection is still ok
 		// in this mode, we'll keep the participant SID, and just swap the sink for the underlying connection
@@ -215,11 +382,23 @@ func (r *RoomManager) StartSession(
 				"nodeID", r.currentNode.Id,
 				"participant", pi.Identity,
 			)
-			return room.ResumeParticipant(participant, responseSink)
-		} else {
+			return participant.ID(), room.ResumeParticipant(participant, responseSink)
+		}
+
+		switch duplicateIdentityPolicy(pi.DuplicateIdentity, room.Internal(), r.getRoomConfig()) {
+		case types.DuplicateIdentityReject:
+			participant.GetLogger().Infow("refusing duplicate participant")
+			return "", rtc.ErrAlreadyJoined
+		case types.DuplicateIdentityAllow:
+			// the new session keeps the identity, and is told apart by its SID
+			duplicateID = room.DuplicateParticipantID(participant)
+			participant.GetLogger().Infow("joining duplicate participant", "duplicatePID", duplicateID)
+		default:
 			participant.GetLogger().Infow("removing duplicate participant")
-			// we need to clean up the existing participant, so a new one can join
-			room.RemoveParticipant(participant.Identity(), types.ParticipantCloseReasonDuplicateIdentity)
+			// we need to clean up the existing sessions, so a new one can join
+			room.RemoveParticipants(func(p types.LocalParticipant) bool {
+				return p.Identity() == pi.Identity
+			}, types.ParticipantCloseReasonDuplicateIdentity)
 		}
 	} else if pi.Reconnect {
 		// send leave request if participant is trying to reconnect without keep subscribe state
@@ -232,7 +411,7 @@ func (r *RoomManager) StartSession(
 				},
 			},
 		})
-		return errors.New("could not restart participant")
+		return "", errors.New("could not restart participant")
 	}
 
 	logger.Infow("starting RTC session",
@@ -250,8 +429,12 @@ func (r *RoomManager) StartSession(
 	rtcConf := *r.rtcConfig
 	rtcConf.SetBufferFactory(room.GetBufferFactory())
 	sid := livekit.ParticipantID(utils.NewGuid(utils.ParticipantPrefix))
+	if duplicateID != "" {
+		sid = duplicateID
+	}
 	pLogger := rtc.LoggerWithParticipant(room.Logger, pi.Identity, sid, false)
 	protoRoom := room.ToProto()
+	internal := room.Internal()
 	participant, err = rtc.NewParticipant(rtc.ParticipantParams{
 		Identity:                pi.Identity,
 		Name:                    pi.Name,
@@ -265,25 +448,34 @@ func (r *RoomManager) StartSession(
 		PLIThrottleConfig:       r.config.RTC.PLIThrottle,
 		CongestionControlConfig: r.config.RTC.CongestionControl,
 		EnabledCodecs:           protoRoom.EnabledCodecs,
+		StartMutedSources:       internal.StartMuted,
+		AllowedSources:          internal.AllowedSources(pi.Role),
+		DataTopics:              pi.DataTopics,
+		PublishTopics:           pi.PublishTopics,
+		DataLimits:              r.dataLimits(room),
+		SignalLimits:            r.signalLimits(),
 		Grants:                  pi.Grants,
 		Logger:                  pLogger,
 		ClientConf:              clientConf,
 		Region:                  pi.Region,
 		AdaptiveStream:          pi.AdaptiveStream,
+		TraceParent:             span.SpanContext(),
+		RoomNamePrefix:          tenantRoomPrefix(pi.Tenant),
 	})
 	if err != nil {
-		return err
+		return "", err
 	}
 	r.setIceConfig(participant)
 
 	// join room
 	opts := rtc.ParticipantOptions{
 		AutoSubscribe: pi.AutoSubscribe,
+		Duplicate:     duplicateID != "",
 	}
-	if err = room.Join(participant, &opts, r.iceServersForRoom(protoRoom), r.currentNode.Region); err != nil {
+	if err = room.Join(ctx, participant, &opts, r.iceServersForRoom(protoRoom), r.currentNode.Region); err != nil {
 		pLogger.Errorw("could not join room", err)
 		_ = participant.Close(true, types.ParticipantCloseReasonJoinFailed)
-		return err
+		return "", err
 	}
 	if err = r.roomStore.StoreParticipant(ctx, roomName, participant.ToProto()); err != nil {
 		pLogger.Errorw("could not store participant", err)
@@ -302,9 +494,26 @@ func (r *RoomManager) StartSession(
 	updateParticipantCount(protoRoom)
 
 	clientMeta := &livekit.AnalyticsClientMeta{Region: r.currentNode.Region, Node: r.currentNode.Id}
-	r.telemetry.ParticipantJoined(ctx, protoRoom, participant.ToProto(), pi.Client, clientMeta)
+	r.telemetry.ParticipantJoined(ctx, protoRoom, participant.ToProto(), pi.Client, clientMeta, pi.APIKey)
+	session := &rtcSession{room: room, client: pi.Client, apiKey: pi.APIKey, claims: sessionServerClaims(&pi)}
+	r.lock.Lock()
+	r.sessions[participant.ID()] = session
+	r.lock.Unlock()
+	sessionLimit := r.sessionDurationLimit(ctx, session, participant, pi.MaxSessionDuration)
 	participant.OnClose(func(p types.LocalParticipant, disallowedSubscriptions map[livekit.TrackID]livekit.ParticipantID) {
-		if err := r.roomStore.DeleteParticipant(ctx, roomName, p.Identity()); err != nil {
+		sessionLimit.Stop()
+		r.lock.Lock()
+		delete(r.sessions, p.ID())
+		r.lock.Unlock()
+
+		// the participant may have been moved to another room since joining. the stored participant is one of the
+		// identity's sessions, another one remaining takes its place
+		room := session.Room()
+		if other := room.GetParticipant(p.Identity()); other != nil && other.ID() != p.ID() {
+			if err := r.roomStore.StoreParticipant(ctx, room.Name(), other.ToProto()); err != nil {
+				pLogger.Errorw("could not store participant", err)
+			}
+		} else if err := r.roomStore.DeleteParticipant(ctx, room.Name(), p.Identity()); err != nil {
 			pLogger.Errorw("could not delete participant", err)
 		}
 
@@ -317,7 +526,7 @@ func (r *RoomManager) StartSession(
 	})
 	participant.OnClaimsChanged(func(participant types.LocalParticipant) {
 		pLogger.Debugw("refreshing client token after claims change")
-		if err := r.refreshToken(participant); err != nil {
+		if err := r.refreshToken(session, participant); err != nil {
 			logger.Errorw("could not refresh token", err)
 		}
 	})
@@ -330,8 +539,8 @@ func (r *RoomManager) StartSession(
 		r.lock.Unlock()
 	})
 
-	go r.rtcSessionWorker(room, participant, requestSource)
-	return nil
+	go r.rtcSessionWorker(session, participant, requestSource)
+	return participant.ID(), nil
 }
 
 // create the actual room object, to be used on RTC node
@@ -349,6 +558,12 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, roomName livekit.Room
 	if err != nil {
 		return nil, err
 	}
+	internal, err := r.roomStore.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		return nil, err
+	}
+	roomConfig := r.getRoomConfig()
+	historyPolicy, history := r.loadDataHistory(ctx, roomName, internal, roomConfig)
 
 	r.lock.Lock()
 
@@ -365,9 +580,19 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, roomName livekit.Room
 	}
 
 	// construct ice servers
-	newRoom := rtc.NewRoom(ri, *r.rtcConfig, &r.config.Audio, r.telemetry)
+	newRoom := rtc.NewRoom(ri, internal, *r.rtcConfig, &r.config.Audio, r.telemetry)
+	durationLimit := r.roomDurationLimit(newRoom, ri, internal, roomConfig)
+	breakoutDeadline := r.breakoutDeadline(newRoom, internal, roomConfig)
+	historyQueue := r.retainDataHistory(ctx, newRoom, historyPolicy, history)
+	newRoom.SetDataTopicClasses(roomConfig.DataTopicClasses)
+	newRoom.SetDataLimiter(rtc.NewDataRateLimiter(roomConfig.DataLimits.RoomPacketRate, roomConfig.DataLimits.RoomByteRate))
 
 	newRoom.OnClose(func() {
+		durationLimit.Stop()
+		breakoutDeadline.Stop()
+		if historyQueue != nil {
+			historyQueue.Stop()
+		}
 		r.telemetry.RoomEnded(ctx, newRoom.ToProto())
 		if err := r.DeleteRoom(ctx, roomName); err != nil {
 			newRoom.Logger.Errorw("could not delete room", err)
@@ -376,8 +601,11 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, roomName livekit.Room
 		newRoom.Logger.Infow("room closed")
 	})
 
-	newRoom.OnMetadataUpdate(func(metadata string) {
-		if err := r.roomStore.StoreRoom(ctx, newRoom.ToProto()); err != nil {
+	newRoom.OnMetadataUpdate(func(metadata string, version uint32) {
+		_, err := storeRoomMetadata(ctx, r.roomStore, roomName, metadata, func(uint32) (uint32, error) {
+			return version, nil
+		})
+		if err != nil {
 			newRoom.Logger.Errorw("could not handle metadata update", err)
 		}
 	})
@@ -402,8 +630,9 @@ func (r *RoomManager) getOrCreateRoom(ctx context.Context, roomName livekit.Room
 }
 
 // manages an RTC session for a participant, runs on the RTC node
-func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalParticipant, requestSource routing.MessageSource) {
+func (r *RoomManager) rtcSessionWorker(session *rtcSession, participant types.LocalParticipant, requestSource routing.MessageSource) {
 	defer func() {
+		room := session.Room()
 		// give time for the participant to be closed with a proper reason.
 		// if participant is closed from here, we would be obscuring the real reason the participant is closed.
 		time.Sleep(2 * time.Second)
@@ -418,6 +647,7 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 	}()
 	defer rtc.Recover()
 
+	room := session.Room()
 	pLogger := rtc.LoggerWithParticipant(
 		rtc.LoggerWithRoom(logger.GetDefaultLogger(), room.Name(), room.ID()),
 		participant.Identity(),
@@ -426,7 +656,7 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 	)
 
 	// send first refresh for cases when client token is close to expiring
-	_ = r.refreshToken(participant)
+	_ = r.refreshToken(session, participant)
 	tokenTicker := time.NewTicker(tokenRefreshInterval)
 	defer tokenTicker.Stop()
 	stateCheckTicker := time.NewTicker(time.Millisecond * 50)
@@ -439,8 +669,8 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 				return
 			}
 		case <-tokenTicker.C:
-			// refresh token with the first API Key/secret pair
-			if err := r.refreshToken(participant); err != nil {
+			// refresh token with the API key the participant joined with
+			if err := r.refreshToken(session, participant); err != nil {
 				pLogger.Errorw("could not refresh token", err)
 			}
 		case obj := <-requestSource.ReadChan():
@@ -451,7 +681,7 @@ func (r *RoomManager) rtcSessionWorker(room *rtc.Room, participant types.LocalPa
 			}
 
 			req := obj.(*livekit.SignalRequest)
-			if err := rtc.HandleParticipantSignal(room, participant, req, pLogger); err != nil {
+			if err := rtc.HandleParticipantSignal(session.Room(), participant, req, pLogger); err != nil {
 				// more specific errors are already logged
 				// treat errors returned as fatal
 				return
@@ -470,7 +700,7 @@ func (r *RoomManager) handleRTCMessage(ctx context.Context, roomName livekit.Roo
 		if _, ok := msg.Message.(*livekit.RTCNodeMessage_DeleteRoom); ok {
 			// special case of a non-RTC room e.g. room created but no participants joined
 			logger.Debugw("Deleting non-rtc room, loading from roomstore")
-			err := r.roomStore.DeleteRoom(ctx, roomName)
+			err := r.DeleteRoom(ctx, roomName)
 			if err != nil {
 				logger.Debugw("Error deleting non-rtc room", "err", err)
 			}
@@ -481,10 +711,11 @@ func (r *RoomManager) handleRTCMessage(ctx context.Context, roomName livekit.Roo
 		}
 	}
 
-	participant := room.GetParticipant(identity)
+	// messages targeting an identity apply to each of its sessions
+	sessions := room.GetParticipantSessions(identity)
 	var sid livekit.ParticipantID
-	if participant != nil {
-		sid = participant.ID()
+	if len(sessions) > 0 {
+		sid = sessions[0].ID()
 	}
 	pLogger := rtc.LoggerWithParticipant(
 		rtc.LoggerWithRoom(logger.GetDefaultLogger(), roomName, room.ID()),
@@ -495,35 +726,40 @@ func (r *RoomManager) handleRTCMessage(ctx context.Context, roomName livekit.Roo
 
 	switch rm := msg.Message.(type) {
 	case *livekit.RTCNodeMessage_RemoveParticipant:
-		if participant == nil {
+		if len(sessions) == 0 {
 			return
 		}
 		pLogger.Infow("removing participant")
 		room.RemoveParticipant(identity, types.ParticipantCloseReasonServiceRequestRemoveParticipant)
 	case *livekit.RTCNodeMessage_MuteTrack:
-		if participant == nil {
+		if len(sessions) == 0 {
 			return
 		}
 		pLogger.Debugw("setting track muted",
 			"trackID", rm.MuteTrack.TrackSid, "muted", rm.MuteTrack.Muted)
-		if !rm.MuteTrack.Muted && !r.config.Room.EnableRemoteUnmute {
+		if !rm.MuteTrack.Muted && !r.getRoomConfig().EnableRemoteUnmute {
 			pLogger.Errorw("cannot unmute track, remote unmute is disabled", nil)
 			return
 		}
-		participant.SetTrackMuted(livekit.TrackID(rm.MuteTrack.TrackSid), rm.MuteTrack.Muted, true)
+		// the track is published by one of the sessions, others don't have it
+		for _, participant := range sessions {
+			participant.SetTrackMuted(livekit.TrackID(rm.MuteTrack.TrackSid), rm.MuteTrack.Muted, true)
+		}
 	case *livekit.RTCNodeMessage_UpdateParticipant:
-		if participant == nil {
+		if len(sessions) == 0 {
 			return
 		}
 		pLogger.Debugw("updating participant", "metadata", rm.UpdateParticipant.Metadata,
 			"permission", rm.UpdateParticipant.Permission)
-		if rm.UpdateParticipant.Metadata != "" {
-			participant.SetMetadata(rm.UpdateParticipant.Metadata)
-		}
-		if rm.UpdateParticipant.Permission != nil {
-			err := room.SetParticipantPermission(participant, rm.UpdateParticipant.Permission)
-			if err != nil {
-				pLogger.Errorw("could not update permissions", err)
+		for _, participant := range sessions {
+			if rm.UpdateParticipant.Metadata != "" {
+				participant.SetMetadata(rm.UpdateParticipant.Metadata)
+			}
+			if rm.UpdateParticipant.Permission != nil {
+				err := room.SetParticipantPermission(participant, rm.UpdateParticipant.Permission)
+				if err != nil {
+					pLogger.Errorw("could not update permissions", err, "pID", participant.ID())
+				}
 			}
 		}
 	case *livekit.RTCNodeMessage_DeleteRoom:
@@ -533,19 +769,22 @@ func (r *RoomManager) handleRTCMessage(ctx context.Context, roomName livekit.Roo
 		}
 		room.Close()
 	case *livekit.RTCNodeMessage_UpdateSubscriptions:
-		if participant == nil {
+		if len(sessions) == 0 {
 			return
 		}
 		pLogger.Debugw("updating participant subscriptions")
-		if err := room.UpdateSubscriptions(
-			participant,
-			livekit.StringsAsTrackIDs(rm.UpdateSubscriptions.TrackSids),
-			rm.UpdateSubscriptions.ParticipantTracks,
-			rm.UpdateSubscriptions.Subscribe,
-		); err != nil {
-			pLogger.Warnw("could not update subscription", err,
-				"tracks", rm.UpdateSubscriptions.TrackSids,
-				"subscribe", rm.UpdateSubscriptions.Subscribe)
+		for _, participant := range sessions {
+			if err := room.UpdateSubscriptions(
+				participant,
+				livekit.StringsAsTrackIDs(rm.UpdateSubscriptions.TrackSids),
+				rm.UpdateSubscriptions.ParticipantTracks,
+				rm.UpdateSubscriptions.Subscribe,
+			); err != nil {
+				pLogger.Warnw("could not update subscription", err,
+					"pID", participant.ID(),
+					"tracks", rm.UpdateSubscriptions.TrackSids,
+					"subscribe", rm.UpdateSubscriptions.Subscribe)
+			}
 		}
 	case *livekit.RTCNodeMessage_SendData:
 		pLogger.Debugw("SendData", "size", len(rm.SendData.Data))
@@ -556,7 +795,9 @@ func (r *RoomManager) handleRTCMessage(ctx context.Context, roomName livekit.Roo
 		room.SendDataPacket(up, rm.SendData.Kind)
 	case *livekit.RTCNodeMessage_UpdateRoomMetadata:
 		pLogger.Debugw("updating room")
-		room.SetMetadata(rm.UpdateRoomMetadata.Metadata)
+		if _, err := room.SetMetadata(rm.UpdateRoomMetadata.Metadata, nil); err != nil {
+			pLogger.Warnw("could not update room metadata", err)
+		}
 	}
 }
 
@@ -616,25 +857,35 @@ func (r *RoomManager) iceServersForRoom(ri *livekit.Room) []*livekit.ICEServer {
 	return iceServers
 }
 
-func (r *RoomManager) refreshToken(participant types.LocalParticipant) error {
-	for key, secret := range r.config.Keys {
-		grants := participant.ClaimGrants()
-		token := auth.NewAccessToken(key, secret)
-		token.SetName(grants.Name).
-			SetIdentity(string(participant.Identity())).
-			SetValidFor(tokenDefaultTTL).
-			SetMetadata(grants.Metadata).
-			AddGrant(grants.Video)
-		jwt, err := token.ToJWT()
-		if err == nil {
-			err = participant.SendRefreshToken(jwt)
-		}
-		if err != nil {
-			return err
-		}
-		break
+func (r *RoomManager) refreshToken(session *rtcSession, participant types.LocalParticipant) error {
+	r.lock.RLock()
+	secret, ok := r.keys[session.apiKey]
+	r.lock.RUnlock()
+	if !ok {
+		// the key has been removed since the participant joined
+		return ErrPermissionDenied
+	}
+
+	grants := participant.ClaimGrants()
+	grants.Identity = string(participant.Identity())
+	token, err := signAccessToken(session.apiKey, secret, grants, session.claims, tokenDefaultTTL)
+	if err != nil {
+		return err
+	}
+	return participant.SendRefreshToken(token)
+}
+
+// sessionServerClaims returns the server claims of the token the participant joined with
+func sessionServerClaims(pi *routing.ParticipantInit) *ServerClaims {
+	return &ServerClaims{
+		Tenant:             pi.Tenant,
+		RoomTemplate:       pi.RoomTemplate,
+		MaxSessionDuration: uint32(pi.MaxSessionDuration / time.Second),
+		Role:               pi.Role,
+		DataTopics:         pi.DataTopics,
+		PublishTopics:      pi.PublishTopics,
+		DuplicateIdentity:  pi.DuplicateIdentity,
 	}
-	return nil
 }
 
 func (r *RoomManager) setIceConfig(participant types.LocalParticipant) {
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+const roomOpUpdateMetadata = "update_metadata"
+
+// updateMetadataRequest updates metadata of a room hosted on the node, when it's at Version if set
+type updateMetadataRequest struct {
+	Metadata string  `json:"metadata"`
+	Version  *uint32 `json:"version,omitempty"`
+}
+
+type updateMetadataResult struct {
+	Version uint32 `json:"version"`
+}
+
+// UpdateMetadata updates metadata of a room like UpdateRoomMetadata. When a version is given, the update is applied
+// only if the room's metadata is still at that version, failing with twirp.Aborted otherwise
+func (s *RoomService) UpdateMetadata(ctx context.Context, req *UpdateMetadataRequest) (*RoomInfo, error) {
+	roomName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+	if err := s.checkMetadataSize(ctx, roomName, req.Metadata); err != nil {
+		return nil, err
+	}
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	res := &updateMetadataResult{}
+	err := executeRoomOperation(ctx, s.router, roomName, roomOpUpdateMetadata, &updateMetadataRequest{
+		Metadata: req.Metadata,
+		Version:  req.Version,
+	}, res)
+	if isNotFoundError(err) {
+		// no one has joined the room, its node loads metadata from the store once it's created
+		res.Version, err = storeRoomMetadata(ctx, s.roomStore, roomName, req.Metadata, func(current uint32) (uint32, error) {
+			if req.Version != nil && *req.Version != current {
+				return 0, rtc.ErrMetadataChanged
+			}
+			return current + 1, nil
+		})
+		switch err {
+		case rtc.ErrMetadataChanged:
+			return nil, twirp.NewError(twirp.Aborted, err.Error())
+		case ErrRoomNotFound:
+			return nil, twirp.NotFoundError(err.Error())
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.roomStore.LoadRoom(ctx, roomName)
+	if err != nil {
+		return nil, err
+	}
+	internal, err := s.roomStore.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		return nil, err
+	}
+	info := roomInfo(ctx, localRoom(ctx, room), internal)
+	// the version of this update, metadata may have been updated again since
+	info.MetadataVersion = res.Version
+	return info, nil
+}
+
+func (r *RoomManager) updateMetadata(_ context.Context, room *rtc.Room, req *updateMetadataRequest) (*updateMetadataResult, error) {
+	version, err := room.SetMetadata(req.Metadata, req.Version)
+	switch err {
+	case nil:
+		return &updateMetadataResult{Version: version}, nil
+	case rtc.ErrMetadataChanged:
+		return nil, twirp.NewError(twirp.Aborted, err.Error())
+	default:
+		return nil, err
+	}
+}
+
+// storeRoomMetadata replaces metadata of a stored room under its lock, so that updates from any node are serialized.
+// next returns the version of the update given the stored version, or an error to abandon it
+func storeRoomMetadata(
+	ctx context.Context,
+	store ObjectStore,
+	roomName livekit.RoomName,
+	metadata string,
+	next func(current uint32) (uint32, error),
+) (uint32, error) {
+	token, err := store.LockRoom(ctx, roomName, 5*time.Second)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = store.UnlockRoom(ctx, roomName, token)
+	}()
+
+	room, err := store.LoadRoom(ctx, roomName)
+	if err != nil {
+		return 0, err
+	}
+	internal, err := store.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		return 0, err
+	}
+	version, err := next(internal.MetadataVersion)
+	if err != nil {
+		return 0, err
+	}
+
+	room.Metadata = metadata
+	if err = store.StoreRoom(ctx, room); err != nil {
+		return 0, err
+	}
+	updated := *internal
+	updated.MetadataVersion = version
+	if err = store.StoreRoomInternal(ctx, roomName, &updated); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
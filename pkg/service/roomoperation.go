@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+)
+
+const roomOpSyncInternal = "sync_internal"
+
+// syncInternalRequest has the node hosting a room reload its internal settings from the store
+type syncInternalRequest struct{}
+
+// roomOperationHandler executes an operation on a room hosted on this node, decoding its request into the type its
+// RoomManager method takes. Requests are authorized and validated by the node serving the API, and name rooms in full
+type roomOperationHandler func(ctx context.Context, room *rtc.Room, decode func(req interface{}) error) (interface{}, error)
+
+// roomOperationResult carries the result of a room operation back to the node serving the API, along with the code
+// of the twirp error it failed with
+type roomOperationResult struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Code   twirp.ErrorCode `json:"code,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// handleRoomOperation executes an operation routed to this node, encoding its result and error
+func (r *RoomManager) handleRoomOperation(ctx context.Context, roomName livekit.RoomName, op string, req []byte) ([]byte, error) {
+	res, err := r.runRoomOperation(ctx, roomName, op, req)
+
+	result := &roomOperationResult{}
+	if err != nil {
+		var twErr twirp.Error
+		if errors.As(err, &twErr) {
+			result.Code = twErr.Code()
+			result.Error = twErr.Msg()
+		} else {
+			result.Code = twirp.Internal
+			result.Error = err.Error()
+		}
+	} else if result.Result, err = json.Marshal(res); err != nil {
+		return nil, err
+	}
+	return json.Marshal(result)
+}
+
+func (r *RoomManager) runRoomOperation(ctx context.Context, roomName livekit.RoomName, op string, req []byte) (interface{}, error) {
+	handler, ok := r.roomOperations[op]
+	if !ok {
+		return nil, twirp.NewError(twirp.BadRoute, "unknown room operation "+op)
+	}
+	room := r.GetRoom(ctx, roomName)
+	if room == nil {
+		return nil, twirp.NotFoundError(ErrRoomNotFound.Error())
+	}
+
+	return handler(ctx, room, func(v interface{}) error {
+		if err := json.Unmarshal(req, v); err != nil {
+			return twirp.NewError(twirp.Malformed, "could not decode room operation: "+err.Error())
+		}
+		return nil
+	})
+}
+
+// executeRoomOperation executes op on the node hosting the room, decoding its result into res
+func executeRoomOperation(ctx context.Context, router routing.MessageRouter, roomName livekit.RoomName, op string, req interface{}, res interface{}) error {
+	encoded, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	encoded, err = router.ExecuteRoomOperation(ctx, roomName, op, encoded)
+	if err == routing.ErrNotFound {
+		// no node hosts the room until a participant joins it
+		return twirp.NotFoundError(ErrRoomNotFound.Error())
+	} else if err != nil {
+		return err
+	}
+
+	result := &roomOperationResult{}
+	if err = json.Unmarshal(encoded, result); err != nil {
+		return err
+	}
+	if result.Code != "" {
+		return twirp.NewError(result.Code, result.Error)
+	}
+	return json.Unmarshal(result.Result, res)
+}
+
+// syncRoomInternal has the node hosting the room apply its internal settings after they're updated in the store.
+// rooms that aren't active load them once created
+func syncRoomInternal(ctx context.Context, router routing.MessageRouter, roomName livekit.RoomName) error {
+	err := executeRoomOperation(ctx, router, roomName, roomOpSyncInternal, &syncInternalRequest{}, &syncInternalRequest{})
+	if isNotFoundError(err) {
+		return nil
+	}
+	return err
+}
+
+// isNotFoundError returns whether a room operation failed because no node hosts the room, or what it operates on
+// doesn't exist
+func isNotFoundError(err error) bool {
+	var twErr twirp.Error
+	return errors.As(err, &twErr) && twErr.Code() == twirp.NotFound
+}
+
+func (r *RoomManager) syncRoomInternal(ctx context.Context, room *rtc.Room, _ *syncInternalRequest) (*syncInternalRequest, error) {
+	internal, err := r.roomStore.LoadRoomInternal(ctx, room.Name())
+	if err != nil {
+		return nil, err
+	}
+	room.SetInternal(internal)
+	return &syncInternalRequest{}, nil
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -12,6 +13,8 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/protocol/livekit"
 )
 
@@ -24,15 +27,19 @@ const (
 type RoomService struct {
 	router        routing.MessageRouter
 	roomAllocator RoomAllocator
-	roomStore     ServiceStore
-	conf          config.RoomConfig
+	roomStore     ObjectStore
+	roomManager   *RoomManager
+
+	lock sync.RWMutex
+	conf config.RoomConfig
 }
 
-func NewRoomService(ra RoomAllocator, rs ServiceStore, router routing.MessageRouter, conf config.RoomConfig) (svc *RoomService, err error) {
+func NewRoomService(ra RoomAllocator, rs ObjectStore, router routing.MessageRouter, roomManager *RoomManager, conf config.RoomConfig) (svc *RoomService, err error) {
 	svc = &RoomService{
 		router:        router,
 		roomAllocator: ra,
 		roomStore:     rs,
+		roomManager:   roomManager,
 		conf:          conf,
 	}
 	return
@@ -43,14 +50,31 @@ func (s *RoomService) CreateRoom(ctx context.Context, req *livekit.CreateRoomReq
 		return nil, twirpAuthError(err)
 	}
 
+	if GetTenant(ctx) != "" {
+		req = proto.Clone(req).(*livekit.CreateRoomRequest)
+		req.Name = string(TenantRoomName(ctx, livekit.RoomName(req.Name)))
+	}
+
 	rm, err = s.roomAllocator.CreateRoom(ctx, req)
 	if err != nil {
-		err = errors.Wrap(err, "could not create room")
+		return nil, twirpCreateRoomError(err)
 	}
 
+	rm = localRoom(ctx, rm)
 	return
 }
 
+func twirpCreateRoomError(err error) error {
+	switch err {
+	case ErrTenantLimitExceeded:
+		return twirp.NewError(twirp.ResourceExhausted, err.Error())
+	case ErrRoomTemplateNotFound, ErrInvalidRoomCodecs:
+		return twirp.InvalidArgumentError("metadata", err.Error())
+	default:
+		return errors.Wrap(err, "could not create room")
+	}
+}
+
 func (s *RoomService) ListRooms(ctx context.Context, req *livekit.ListRoomsRequest) (res *livekit.ListRoomsResponse, err error) {
 	err = EnsureListPermission(ctx)
 	if err != nil {
@@ -59,7 +83,10 @@ func (s *RoomService) ListRooms(ctx context.Context, req *livekit.ListRoomsReque
 
 	var names []livekit.RoomName
 	if len(req.Names) > 0 {
-		names = livekit.StringsAsRoomNames(req.Names)
+		names = make([]livekit.RoomName, 0, len(req.Names))
+		for _, name := range req.Names {
+			names = append(names, TenantRoomName(ctx, livekit.RoomName(name)))
+		}
 	}
 	rooms, err := s.roomStore.ListRooms(ctx, names)
 	if err != nil {
@@ -68,16 +95,36 @@ func (s *RoomService) ListRooms(ctx context.Context, req *livekit.ListRoomsReque
 	}
 
 	res = &livekit.ListRoomsResponse{
-		Rooms: rooms,
+		Rooms: make([]*livekit.Room, 0, len(rooms)),
+	}
+	for _, rm := range rooms {
+		if !IsTenantRoom(ctx, livekit.RoomName(rm.Name)) {
+			continue
+		}
+		internal, err := s.roomStore.LoadRoomInternal(ctx, livekit.RoomName(rm.Name))
+		if err != nil {
+			return nil, err
+		}
+		res.Rooms = append(res.Rooms, localRoom(ctx, roomWithInternal(ctx, rm, internal)))
 	}
 	return
 }
 
+// roomWithInternal returns a copy of the room carrying the internal settings clients are told about, such as its lock
+// and breakout links, room names as known to the request's tenant
+func roomWithInternal(ctx context.Context, rm *livekit.Room, internal *types.RoomInternal) *livekit.Room {
+	rm = proto.Clone(rm).(*livekit.Room)
+	rtc.SetRoomLocked(rm, internal.Locked)
+	rtc.SetRoomBreakoutLinks(rm, string(LocalRoomName(ctx, internal.Parent)), localRoomNames(ctx, internal.Breakouts))
+	return rm
+}
+
 func (s *RoomService) DeleteRoom(ctx context.Context, req *livekit.DeleteRoomRequest) (*livekit.DeleteRoomResponse, error) {
 	if err := EnsureCreatePermission(ctx); err != nil {
 		return nil, twirpAuthError(err)
 	}
-	err := s.router.WriteRoomRTC(ctx, livekit.RoomName(req.Room), &livekit.RTCNodeMessage{
+	roomName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+	err := s.router.WriteRoomRTC(ctx, roomName, &livekit.RTCNodeMessage{
 		Message: &livekit.RTCNodeMessage_DeleteRoom{
 			DeleteRoom: req,
 		},
@@ -88,7 +135,7 @@ func (s *RoomService) DeleteRoom(ctx context.Context, req *livekit.DeleteRoomReq
 
 	// we should not return until when the room is confirmed deleted
 	err = confirmExecution(func() error {
-		_, err := s.roomStore.LoadRoom(ctx, livekit.RoomName(req.Room))
+		_, err := s.roomStore.LoadRoom(ctx, roomName)
 		if err == nil {
 			return ErrOperationFailed
 		} else if err != ErrRoomNotFound {
@@ -109,7 +156,7 @@ func (s *RoomService) ListParticipants(ctx context.Context, req *livekit.ListPar
 		return nil, twirpAuthError(err)
 	}
 
-	participants, err := s.roomStore.ListParticipants(ctx, livekit.RoomName(req.Room))
+	participants, err := s.roomStore.ListParticipants(ctx, TenantRoomName(ctx, livekit.RoomName(req.Room)))
 	if err != nil {
 		return
 	}
@@ -125,7 +172,7 @@ func (s *RoomService) GetParticipant(ctx context.Context, req *livekit.RoomParti
 		return nil, twirpAuthError(err)
 	}
 
-	participant, err := s.roomStore.LoadParticipant(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity))
+	participant, err := s.roomStore.LoadParticipant(ctx, TenantRoomName(ctx, livekit.RoomName(req.Room)), livekit.ParticipantIdentity(req.Identity))
 	if err != nil {
 		return
 	}
@@ -145,7 +192,7 @@ func (s *RoomService) RemoveParticipant(ctx context.Context, req *livekit.RoomPa
 	}
 
 	err = confirmExecution(func() error {
-		_, err := s.roomStore.LoadParticipant(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity))
+		_, err := s.roomStore.LoadParticipant(ctx, TenantRoomName(ctx, livekit.RoomName(req.Room)), livekit.ParticipantIdentity(req.Identity))
 		if err == ErrParticipantNotFound {
 			return nil
 		} else if err != nil {
@@ -178,7 +225,7 @@ func (s *RoomService) MutePublishedTrack(ctx context.Context, req *livekit.MuteR
 
 	var track *livekit.TrackInfo
 	err = confirmExecution(func() error {
-		p, err := s.roomStore.LoadParticipant(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity))
+		p, err := s.roomStore.LoadParticipant(ctx, TenantRoomName(ctx, livekit.RoomName(req.Room)), livekit.ParticipantIdentity(req.Identity))
 		if err != nil {
 			return err
 		}
@@ -207,8 +254,8 @@ func (s *RoomService) MutePublishedTrack(ctx context.Context, req *livekit.MuteR
 }
 
 func (s *RoomService) UpdateParticipant(ctx context.Context, req *livekit.UpdateParticipantRequest) (*livekit.ParticipantInfo, error) {
-	if s.conf.MaxMetadataSize > 0 && len(req.Metadata) > int(s.conf.MaxMetadataSize) {
-		return nil, twirp.InvalidArgumentError(ErrMetadataExceedsLimits.Error(), strconv.Itoa(int(s.conf.MaxMetadataSize)))
+	if err := s.checkMetadataSize(ctx, TenantRoomName(ctx, livekit.RoomName(req.Room)), req.Metadata); err != nil {
+		return nil, err
 	}
 
 	err := s.writeParticipantMessage(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity), &livekit.RTCNodeMessage{
@@ -222,7 +269,7 @@ func (s *RoomService) UpdateParticipant(ctx context.Context, req *livekit.Update
 
 	var participant *livekit.ParticipantInfo
 	err = confirmExecution(func() error {
-		participant, err = s.roomStore.LoadParticipant(ctx, livekit.RoomName(req.Room), livekit.ParticipantIdentity(req.Identity))
+		participant, err = s.roomStore.LoadParticipant(ctx, TenantRoomName(ctx, livekit.RoomName(req.Room)), livekit.ParticipantIdentity(req.Identity))
 		if err != nil {
 			return err
 		}
@@ -260,7 +307,7 @@ func (s *RoomService) SendData(ctx context.Context, req *livekit.SendDataRequest
 		return nil, twirpAuthError(err)
 	}
 
-	err := s.router.WriteRoomRTC(ctx, roomName, &livekit.RTCNodeMessage{
+	err := s.router.WriteRoomRTC(ctx, TenantRoomName(ctx, roomName), &livekit.RTCNodeMessage{
 		Message: &livekit.RTCNodeMessage_SendData{
 			SendData: req,
 		},
@@ -273,15 +320,15 @@ func (s *RoomService) SendData(ctx context.Context, req *livekit.SendDataRequest
 }
 
 func (s *RoomService) UpdateRoomMetadata(ctx context.Context, req *livekit.UpdateRoomMetadataRequest) (*livekit.Room, error) {
-	if s.conf.MaxMetadataSize > 0 && len(req.Metadata) > int(s.conf.MaxMetadataSize) {
-		return nil, twirp.InvalidArgumentError(ErrMetadataExceedsLimits.Error(), strconv.Itoa(int(s.conf.MaxMetadataSize)))
+	roomName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+	if err := s.checkMetadataSize(ctx, roomName, req.Metadata); err != nil {
+		return nil, err
 	}
 
 	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
 		return nil, twirpAuthError(err)
 	}
-
-	room, err := s.roomStore.LoadRoom(ctx, livekit.RoomName(req.Room))
+	room, err := s.roomStore.LoadRoom(ctx, roomName)
 	if err != nil {
 		return nil, err
 	}
@@ -289,14 +336,14 @@ func (s *RoomService) UpdateRoomMetadata(ctx context.Context, req *livekit.Updat
 	// no one has joined the room, would not have been created on an RTC node.
 	// in this case, we'd want to run create again
 	_, err = s.roomAllocator.CreateRoom(ctx, &livekit.CreateRoomRequest{
-		Name:     req.Room,
+		Name:     string(roomName),
 		Metadata: req.Metadata,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	err = s.router.WriteRoomRTC(ctx, livekit.RoomName(req.Room), &livekit.RTCNodeMessage{
+	err = s.router.WriteRoomRTC(ctx, roomName, &livekit.RTCNodeMessage{
 		Message: &livekit.RTCNodeMessage_UpdateRoomMetadata{
 			UpdateRoomMetadata: req,
 		},
@@ -306,7 +353,7 @@ func (s *RoomService) UpdateRoomMetadata(ctx context.Context, req *livekit.Updat
 	}
 
 	err = confirmExecution(func() error {
-		room, err = s.roomStore.LoadRoom(ctx, livekit.RoomName(req.Room))
+		room, err = s.roomStore.LoadRoom(ctx, roomName)
 		if err != nil {
 			return err
 		}
@@ -319,7 +366,64 @@ func (s *RoomService) UpdateRoomMetadata(ctx context.Context, req *livekit.Updat
 		return nil, err
 	}
 
-	return room, nil
+	return localRoom(ctx, room), nil
+}
+
+// LockRoom locks or unlocks a room. new identities can't join a locked room, while participants in it may still reconnect
+func (s *RoomService) LockRoom(ctx context.Context, req *LockRoomRequest) (*LockRoomResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	roomName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+
+	if _, err := s.roomStore.LoadRoom(ctx, roomName); err == ErrRoomNotFound {
+		return nil, twirp.NotFoundError(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	err := updateRoomInternal(ctx, s.roomStore, s.router, roomName, func(internal *types.RoomInternal) {
+		internal.Locked = req.Locked
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &LockRoomResponse{
+		Room:   req.Room,
+		Locked: req.Locked,
+	}, nil
+}
+
+// MoveParticipant moves a participant to another existing room. When both rooms are hosted on the same node, the participant
+// keeps its connection and tracks. Otherwise it's sent a token for the destination and disconnected, to rejoin there
+func (s *RoomService) MoveParticipant(ctx context.Context, req *MoveParticipantRequest) (*MoveParticipantResponse, error) {
+	// moving into another room requires more than admin permission on a single room
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if err := EnsureCreatePermission(ctx); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	if req.Destination == "" || req.Destination == req.Room {
+		return nil, twirp.InvalidArgumentError("destination", "must be another room")
+	}
+	roomName := TenantRoomName(ctx, livekit.RoomName(req.Room))
+	destination := TenantRoomName(ctx, livekit.RoomName(req.Destination))
+	if _, err := s.roomStore.LoadRoom(ctx, destination); err == ErrRoomNotFound {
+		return nil, twirp.NotFoundError(err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+
+	reconnect, err := s.moveParticipant(ctx, roomName, livekit.ParticipantIdentity(req.Identity), destination)
+	if err != nil {
+		return nil, err
+	}
+	return &MoveParticipantResponse{
+		Room:      req.Destination,
+		Identity:  req.Identity,
+		Reconnect: reconnect,
+	}, nil
 }
 
 func (s *RoomService) writeParticipantMessage(ctx context.Context, room livekit.RoomName, identity livekit.ParticipantIdentity, msg *livekit.RTCNodeMessage) error {
@@ -327,7 +431,7 @@ func (s *RoomService) writeParticipantMessage(ctx context.Context, room livekit.
 		return twirpAuthError(err)
 	}
 
-	return s.router.WriteParticipantRTC(ctx, room, identity, msg)
+	return s.router.WriteParticipantRTC(ctx, TenantRoomName(ctx, room), identity, msg)
 }
 
 func confirmExecution(f func() error) error {
@@ -346,3 +450,35 @@ func confirmExecution(f func() error) error {
 		}
 	}
 }
+
+func (s *RoomService) UpdateConfig(conf *config.Config) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.conf = conf.Room
+}
+
+// checkMetadataSize enforces the limit of the room's template, or the server wide limit
+func (s *RoomService) checkMetadataSize(ctx context.Context, roomName livekit.RoomName, metadata string) error {
+	if metadata == "" {
+		return nil
+	}
+	maxSize := s.roomConf().MaxMetadataSize
+	internal, err := s.roomStore.LoadRoomInternal(ctx, roomName)
+	if err != nil {
+		return err
+	}
+	if internal.MaxMetadataSize > 0 {
+		maxSize = internal.MaxMetadataSize
+	}
+
+	if maxSize > 0 && len(metadata) > int(maxSize) {
+		return twirp.InvalidArgumentError(ErrMetadataExceedsLimits.Error(), strconv.Itoa(int(maxSize)))
+	}
+	return nil
+}
+
+func (s *RoomService) roomConf() config.RoomConfig {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.conf
+}
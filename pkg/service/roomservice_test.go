@@ -11,7 +11,9 @@ import (
 	"github.com/livekit/protocol/livekit"
 
 	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/routingfakes"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/livekit-server/pkg/service/servicefakes"
 )
@@ -70,6 +72,23 @@ func TestMetaDataLimits(t *testing.T) {
 		require.Equal(t, twirp.InvalidArgument, terr.Code())
 	})
 
+	t.Run("template limit overrides server limit", func(t *testing.T) {
+		svc := newTestRoomService(config.RoomConfig{MaxMetadataSize: 5})
+		svc.store.LoadRoomInternalReturns(&types.RoomInternal{Template: "webinar", MaxMetadataSize: 2}, nil)
+		grant := &auth.ClaimGrants{
+			Video: &auth.VideoGrant{},
+		}
+		ctx := service.WithGrants(context.Background(), grant)
+		_, err := svc.UpdateParticipant(ctx, &livekit.UpdateParticipantRequest{
+			Room:     "testroom",
+			Identity: "123",
+			Metadata: "abc",
+		})
+		terr, ok := err.(twirp.Error)
+		require.True(t, ok)
+		require.Equal(t, twirp.InvalidArgument, terr.Code())
+	})
+
 	notExceedsLimitsSvc := map[string]*TestRoomService{
 		"metadata noe exceeds limits": newTestRoomService(config.RoomConfig{MaxMetadataSize: 5}),
 		"metadata no limits":          newTestRoomService(config.RoomConfig{}), // no limits
@@ -103,16 +122,95 @@ func TestMetaDataLimits(t *testing.T) {
 	}
 }
 
+func TestUpdateMetadataVersion(t *testing.T) {
+	newService := func() *TestRoomService {
+		svc := newTestRoomService(config.RoomConfig{})
+		svc.store.LoadRoomReturns(&livekit.Room{Name: "testroom", Metadata: "old"}, nil)
+		svc.store.LoadRoomInternalReturns(&types.RoomInternal{MetadataVersion: 3}, nil)
+		return svc
+	}
+	ctx := service.WithGrants(context.Background(), &auth.ClaimGrants{
+		Video: &auth.VideoGrant{RoomAdmin: true, Room: "testroom"},
+	})
+
+	t.Run("the hosting node checks the version", func(t *testing.T) {
+		svc := newService()
+		svc.router.ExecuteRoomOperationReturns([]byte(`{"result":{"version":4}}`), nil)
+		current := uint32(3)
+		info, err := svc.UpdateMetadata(ctx, &service.UpdateMetadataRequest{Room: "testroom", Metadata: "new", Version: &current})
+		require.NoError(t, err)
+		require.EqualValues(t, 4, info.MetadataVersion)
+		_, roomName, op, _ := svc.router.ExecuteRoomOperationArgsForCall(0)
+		require.Equal(t, livekit.RoomName("testroom"), roomName)
+		require.Equal(t, "update_metadata", op)
+		// stored by the hosting node
+		require.Equal(t, 0, svc.store.StoreRoomCallCount())
+
+		svc.router.ExecuteRoomOperationReturns([]byte(`{"code":"aborted","error":"changed"}`), nil)
+		_, err = svc.UpdateMetadata(ctx, &service.UpdateMetadataRequest{Room: "testroom", Metadata: "new", Version: &current})
+		terr, ok := err.(twirp.Error)
+		require.True(t, ok)
+		require.Equal(t, twirp.Aborted, terr.Code())
+	})
+
+	t.Run("rooms no one has joined are checked in the store", func(t *testing.T) {
+		svc := newService()
+		svc.router.ExecuteRoomOperationReturns(nil, routing.ErrNotFound)
+		stale := uint32(2)
+		_, err := svc.UpdateMetadata(ctx, &service.UpdateMetadataRequest{Room: "testroom", Metadata: "new", Version: &stale})
+		terr, ok := err.(twirp.Error)
+		require.True(t, ok)
+		require.Equal(t, twirp.Aborted, terr.Code())
+		require.Equal(t, 0, svc.store.StoreRoomCallCount())
+		require.Equal(t, 1, svc.store.UnlockRoomCallCount())
+
+		current := uint32(3)
+		info, err := svc.UpdateMetadata(ctx, &service.UpdateMetadataRequest{Room: "testroom", Metadata: "new", Version: &current})
+		require.NoError(t, err)
+		require.EqualValues(t, 4, info.MetadataVersion)
+		_, room := svc.store.StoreRoomArgsForCall(0)
+		require.Equal(t, "new", room.Metadata)
+		_, _, internal := svc.store.StoreRoomInternalArgsForCall(0)
+		require.EqualValues(t, 4, internal.MetadataVersion)
+	})
+}
+
+func TestTenantRooms(t *testing.T) {
+	svc := newTestRoomService(config.RoomConfig{})
+	grant := &auth.ClaimGrants{
+		Video: &auth.VideoGrant{
+			RoomList: true,
+		},
+	}
+	ctx := service.WithTenant(service.WithGrants(context.Background(), grant), "acme")
+	svc.store.ListRoomsReturns([]*livekit.Room{
+		{Name: "acme/lobby"},
+		{Name: "other/lobby"},
+		{Name: "acmeroom"},
+	}, nil)
+
+	res, err := svc.ListRooms(ctx, &livekit.ListRoomsRequest{})
+	require.NoError(t, err)
+	require.Len(t, res.Rooms, 1)
+	require.Equal(t, "lobby", res.Rooms[0].Name)
+
+	_, err = svc.ListRooms(ctx, &livekit.ListRoomsRequest{Names: []string{"lobby"}})
+	require.NoError(t, err)
+	_, names := svc.store.ListRoomsArgsForCall(1)
+	require.Equal(t, []livekit.RoomName{"acme/lobby"}, names)
+}
+
 func newTestRoomService(conf config.RoomConfig) *TestRoomService {
 	router := &routingfakes.FakeRouter{}
 	allocator := &servicefakes.FakeRoomAllocator{}
-	store := &servicefakes.FakeServiceStore{}
-	svc, err := service.NewRoomService(allocator, store, router, conf)
+	store := &servicefakes.FakeObjectStore{}
+	store.LoadRoomInternalReturns(&types.RoomInternal{}, nil)
+	svc, err := service.NewRoomService(allocator, store, router, nil, conf)
 	if err != nil {
 		panic(err)
 	}
 	return &TestRoomService{
-		RoomService: *svc,
+		RoomService: svc,
 		router:      router,
 		allocator:   allocator,
 		store:       store,
@@ -120,8 +218,8 @@ func newTestRoomService(conf config.RoomConfig) *TestRoomService {
 }
 
 type TestRoomService struct {
-	service.RoomService
+	*service.RoomService
 	router    *routingfakes.FakeRouter
 	allocator *servicefakes.FakeRoomAllocator
-	store     *servicefakes.FakeServiceStore
+	store     *servicefakes.FakeObjectStore
 }
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const (
+	roomOpGetState    = "get_state"
+	roomOpUpdateState = "update_state"
+)
+
+// getStateRequest returns the shared state of a room hosted on the node
+type getStateRequest struct{}
+
+// updateStateRequest updates the shared state of a room hosted on the node, on behalf of a participant or of a room
+// admin when Identity is empty. MaxSize is the configured limit of the state's size, which the room's own limit
+// overrides
+type updateStateRequest struct {
+	Identity livekit.ParticipantIdentity `json:"identity,omitempty"`
+	Set      map[string]*RoomStateValue  `json:"set"`
+	Delete   []string                    `json:"delete"`
+	MaxSize  uint32                      `json:"maxSize"`
+}
+
+type stateResult struct {
+	Entries map[string]*types.RoomStateEntry `json:"entries"`
+}
+
+// GetRoomState returns the shared state of a room on behalf of a room admin
+func (s *RoomService) GetRoomState(ctx context.Context, req *GetRoomStateRequest) (*RoomStateResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	res := &stateResult{}
+	err := executeRoomOperation(ctx, s.router, TenantRoomName(ctx, livekit.RoomName(req.Room)), roomOpGetState, &getStateRequest{}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RoomStateResponse{
+		Room:    req.Room,
+		Entries: res.Entries,
+	}, nil
+}
+
+// UpdateRoomState updates the shared state of a room on behalf of a room admin, who may change any key
+func (s *RoomService) UpdateRoomState(ctx context.Context, req *UpdateRoomStateRequest) (*RoomStateResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+
+	return s.updateRoomState(ctx, livekit.RoomName(req.Room), "", req.Set, req.Delete)
+}
+
+// SetRoomState updates the shared state of the room of the calling participant's token. The participant must be in
+// the room and allowed to publish data
+func (s *RoomService) SetRoomState(ctx context.Context, req *SetRoomStateRequest) (*RoomStateResponse, error) {
+	roomName, err := EnsureJoinPermission(ctx)
+	if err != nil {
+		return nil, twirpAuthError(err)
+	}
+	identity := livekit.ParticipantIdentity(GetGrants(ctx).Identity)
+	if identity == "" {
+		return nil, twirp.InvalidArgumentError("identity", ErrIdentityEmpty.Error())
+	}
+
+	return s.updateRoomState(ctx, roomName, identity, req.Set, req.Delete)
+}
+
+// updateRoomState applies an update to the state of a room on the node hosting it, the name as known to the
+// request's tenant. identity is empty for room admins
+func (s *RoomService) updateRoomState(
+	ctx context.Context,
+	roomName livekit.RoomName,
+	identity livekit.ParticipantIdentity,
+	set map[string]*RoomStateValue,
+	del []string,
+) (*RoomStateResponse, error) {
+	for key, value := range set {
+		if value == nil {
+			return nil, twirp.InvalidArgumentError("set", "missing value of "+key)
+		}
+	}
+
+	res := &stateResult{}
+	err := executeRoomOperation(ctx, s.router, TenantRoomName(ctx, roomName), roomOpUpdateState, &updateStateRequest{
+		Identity: identity,
+		Set:      set,
+		Delete:   del,
+		MaxSize:  s.roomConf().MaxStateSize,
+	}, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RoomStateResponse{
+		Room:    string(roomName),
+		Entries: res.Entries,
+	}, nil
+}
+
+func (r *RoomManager) getState(_ context.Context, room *rtc.Room, _ *getStateRequest) (*stateResult, error) {
+	return &stateResult{Entries: room.State()}, nil
+}
+
+func (r *RoomManager) updateState(_ context.Context, room *rtc.Room, req *updateStateRequest) (*stateResult, error) {
+	if req.Identity != "" {
+		participant := room.GetParticipant(req.Identity)
+		if participant == nil {
+			return nil, twirp.NotFoundError(ErrParticipantNotFound.Error())
+		}
+		if !participant.CanPublishData() {
+			return nil, twirp.NewError(twirp.PermissionDenied, ErrPermissionDenied.Error())
+		}
+	}
+
+	update := &rtc.RoomStateUpdate{
+		Set:    make(map[string]*rtc.RoomStateValue, len(req.Set)),
+		Delete: req.Delete,
+	}
+	for key, value := range req.Set {
+		if value == nil {
+			return nil, twirp.InvalidArgumentError("set", "missing value of "+key)
+		}
+		update.Set[key] = &rtc.RoomStateValue{
+			Value:  value.Value,
+			Shared: value.Shared,
+			TTL:    time.Duration(value.TTL) * time.Second,
+		}
+	}
+	maxSize := req.MaxSize
+	if internal := room.Internal(); internal.MaxStateSize > 0 {
+		maxSize = internal.MaxStateSize
+	}
+
+	entries, err := room.UpdateState(req.Identity, update, maxSize)
+	switch err {
+	case nil:
+	case rtc.ErrStateKeyNotWritable:
+		return nil, twirp.NewError(twirp.PermissionDenied, err.Error())
+	case rtc.ErrStateTooLarge:
+		return nil, twirp.InvalidArgumentError(err.Error(), strconv.Itoa(int(maxSize)))
+	case rtc.ErrEmptyStateKey:
+		return nil, twirp.InvalidArgumentError("key", err.Error())
+	default:
+		return nil, err
+	}
+
+	return &stateResult{Entries: entries}, nil
+}
@@ -7,10 +7,13 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/sebest/xff"
 	"github.com/ua-parser/uap-go/uaparser"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
@@ -19,7 +22,9 @@ import (
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/routing/selector"
 	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
 )
 
 type RTCService struct {
@@ -30,8 +35,12 @@ type RTCService struct {
 	currentNode   routing.LocalNode
 	config        *config.Config
 	isDev         bool
-	limits        config.LimitConfig
 	parser        *uaparser.Parser
+
+	lock       sync.RWMutex
+	limits     config.LimitConfig
+	autoCreate bool
+	roomConfig config.RoomConfig
 }
 
 func NewRTCService(
@@ -49,8 +58,10 @@ func NewRTCService(
 		currentNode:   currentNode,
 		config:        conf,
 		isDev:         conf.Development,
-		limits:        conf.Limit,
 		parser:        uaparser.NewFromSaved(),
+		limits:        conf.Limit,
+		autoCreate:    conf.Room.AutoCreate,
+		roomConfig:    conf.Room,
 	}
 
 	// allow connections from any origin, since script may be hosted anywhere
@@ -62,6 +73,32 @@ func NewRTCService(
 	return s
 }
 
+func (s *RTCService) UpdateConfig(conf *config.Config) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.limits = conf.Limit
+	s.autoCreate = conf.Room.AutoCreate
+	s.roomConfig = conf.Room
+}
+
+func (s *RTCService) getLimits() config.LimitConfig {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.limits
+}
+
+func (s *RTCService) isAutoCreate() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.autoCreate
+}
+
+func (s *RTCService) getRoomConfig() config.RoomConfig {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.roomConfig
+}
+
 func (s *RTCService) Validate(w http.ResponseWriter, r *http.Request) {
 	_, _, code, err := s.validate(r)
 	if err != nil {
@@ -89,6 +126,7 @@ func (s *RTCService) validate(r *http.Request) (livekit.RoomName, routing.Partic
 
 	roomName := livekit.RoomName(r.FormValue("room"))
 	reconnectParam := r.FormValue("reconnect")
+	sidParam := r.FormValue("sid")
 	autoSubParam := r.FormValue("auto_subscribe")
 	publishParam := r.FormValue("publish")
 	adaptiveStreamParam := r.FormValue("adaptive_stream")
@@ -96,6 +134,7 @@ func (s *RTCService) validate(r *http.Request) (livekit.RoomName, routing.Partic
 	if onlyName != "" {
 		roomName = onlyName
 	}
+	roomName = TenantRoomName(r.Context(), roomName)
 
 	// this is new connection for existing participant -  with publish only permissions
 	if publishParam != "" {
@@ -112,20 +151,33 @@ func (s *RTCService) validate(r *http.Request) (livekit.RoomName, routing.Partic
 	if router, ok := s.router.(routing.Router); ok {
 		region = router.GetRegion()
 		if foundNode, err := router.GetNodeForRoom(r.Context(), roomName); err == nil {
-			if selector.LimitsReached(s.limits, foundNode.Stats) {
+			if selector.LimitsReached(s.getLimits(), foundNode.Stats) {
 				return "", routing.ParticipantInit{}, http.StatusServiceUnavailable, rtc.ErrLimitExceeded
 			}
 		}
 	}
 
+	serverClaims := GetServerClaims(r.Context())
 	pi := routing.ParticipantInit{
-		Reconnect:     boolValue(reconnectParam),
-		Identity:      livekit.ParticipantIdentity(claims.Identity),
-		Name:          livekit.ParticipantName(claims.Name),
-		AutoSubscribe: true,
-		Client:        s.ParseClientInfo(r),
-		Grants:        claims,
-		Region:        region,
+		Reconnect:          boolValue(reconnectParam),
+		ParticipantID:      livekit.ParticipantID(sidParam),
+		Identity:           livekit.ParticipantIdentity(claims.Identity),
+		Name:               livekit.ParticipantName(claims.Name),
+		AutoSubscribe:      true,
+		Client:             s.ParseClientInfo(r),
+		Grants:             claims,
+		Region:             region,
+		APIKey:             GetAPIKey(r.Context()),
+		Tenant:             GetTenant(r.Context()),
+		RoomTemplate:       serverClaims.RoomTemplate,
+		MaxSessionDuration: time.Duration(serverClaims.MaxSessionDuration) * time.Second,
+		Role:               serverClaims.Role,
+		DataTopics:         serverClaims.DataTopics,
+		PublishTopics:      serverClaims.PublishTopics,
+		DuplicateIdentity:  serverClaims.DuplicateIdentity,
+	}
+	if !types.DuplicateIdentityPolicy(pi.DuplicateIdentity).IsValid() {
+		return "", routing.ParticipantInit{}, http.StatusBadRequest, ErrInvalidIdentityPolicy
 	}
 
 	if autoSubParam != "" {
@@ -135,6 +187,19 @@ func (s *RTCService) validate(r *http.Request) (livekit.RoomName, routing.Partic
 		pi.AdaptiveStream = boolValue(adaptiveStreamParam)
 	}
 
+	if !pi.Reconnect {
+		if err := checkTenantParticipantLimit(r.Context(), &s.config.Tenancy, s.store); err == ErrTenantLimitExceeded {
+			return "", routing.ParticipantInit{}, http.StatusTooManyRequests, err
+		} else if err != nil {
+			return "", routing.ParticipantInit{}, http.StatusInternalServerError, err
+		}
+		if err := s.checkDuplicateIdentity(r.Context(), roomName, pi); err == rtc.ErrAlreadyJoined {
+			return "", routing.ParticipantInit{}, http.StatusConflict, err
+		} else if err != nil {
+			return "", routing.ParticipantInit{}, http.StatusInternalServerError, err
+		}
+	}
+
 	return roomName, pi, http.StatusOK, nil
 }
 
@@ -146,19 +211,30 @@ func (s *RTCService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// the span covers joining, it's ended once the session has started and the websocket has been established
+	ctx, span := tracing.Start(r.Context(), "RTCService.Join")
+	r = r.WithContext(ctx)
+
 	roomName, pi, code, err := s.validate(r)
 	if err != nil {
+		tracing.EndSpan(span, err)
 		handleError(w, code, err.Error())
 		return
 	}
+	span.SetAttributes(
+		attribute.String("room", string(roomName)),
+		attribute.String("participant", string(pi.Identity)),
+	)
 
 	// when auto create is disabled, we'll check to ensure it's already created
-	if !s.config.Room.AutoCreate {
+	if !s.isAutoCreate() {
 		_, err := s.store.LoadRoom(context.Background(), roomName)
 		if err == ErrRoomNotFound {
+			tracing.EndSpan(span, err)
 			handleError(w, 404, err.Error())
 			return
 		} else if err != nil {
+			tracing.EndSpan(span, err)
 			handleError(w, 500, err.Error())
 			return
 		}
@@ -168,7 +244,14 @@ func (s *RTCService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	rm, err := s.roomAllocator.CreateRoom(r.Context(), &livekit.CreateRoomRequest{Name: string(roomName)})
 	if err != nil {
 		prometheus.ServiceOperationCounter.WithLabelValues("signal_ws", "error", "create_room").Add(1)
-		handleError(w, http.StatusInternalServerError, err.Error())
+		tracing.EndSpan(span, err)
+		if err == ErrTenantLimitExceeded {
+			handleError(w, http.StatusTooManyRequests, err.Error())
+		} else if err == ErrRoomTemplateNotFound {
+			handleError(w, http.StatusBadRequest, err.Error())
+		} else {
+			handleError(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
@@ -176,6 +259,7 @@ func (s *RTCService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	connId, reqSink, resSource, err := s.router.StartParticipantSignal(r.Context(), roomName, pi)
 	if err != nil {
 		prometheus.ServiceOperationCounter.WithLabelValues("signal_ws", "error", "start_signal").Add(1)
+		tracing.EndSpan(span, err)
 		handleError(w, http.StatusInternalServerError, "could not start session: "+err.Error())
 		return
 	}
@@ -199,11 +283,13 @@ func (s *RTCService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	conn, err := s.upgrader.Upgrade(w, r, nil)
 	if err != nil {
 		prometheus.ServiceOperationCounter.WithLabelValues("signal_ws", "error", "upgrade").Add(1)
+		tracing.EndSpan(span, err)
 		pLogger.Warnw("could not upgrade to WS", err)
 		handleError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 	sigConn := NewWSSignalConnection(conn)
+	span.End()
 
 	prometheus.ServiceOperationCounter.WithLabelValues("signal_ws", "success", "").Add(1)
 	pLogger.Infow("new client WS connected", "connID", connId)
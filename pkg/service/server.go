@@ -23,6 +23,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/version"
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
@@ -33,7 +34,10 @@ type LivekitServer struct {
 	config         *config.Config
 	egressService  *EgressService
 	ingressService *IngressService
+	usageService   *UsageService
+	analytics      telemetry.AnalyticsService
 	rtcService     *RTCService
+	configReloader *ConfigReloader
 	httpServer     *http.Server
 	promServer     *http.Server
 	router         routing.Router
@@ -47,20 +51,27 @@ type LivekitServer struct {
 
 func NewLivekitServer(conf *config.Config,
 	roomService livekit.RoomService,
+	roomAPI *RoomAPI,
 	egressService *EgressService,
 	ingressService *IngressService,
+	usageService *UsageService,
+	analytics telemetry.AnalyticsService,
 	rtcService *RTCService,
 	keyProvider auth.KeyProvider,
 	router routing.Router,
 	roomManager *RoomManager,
 	turnServer *turn.Server,
 	currentNode routing.LocalNode,
+	configReloader *ConfigReloader,
 ) (s *LivekitServer, err error) {
 	s = &LivekitServer{
 		config:         conf,
 		egressService:  egressService,
 		ingressService: ingressService,
+		usageService:   usageService,
+		analytics:      analytics,
 		rtcService:     rtcService,
+		configReloader: configReloader,
 		router:         router,
 		roomManager:    roomManager,
 		// turn server starts automatically
@@ -82,6 +93,9 @@ func NewLivekitServer(conf *config.Config,
 	}
 	if keyProvider != nil {
 		middlewares = append(middlewares, NewAPIKeyAuthMiddleware(keyProvider))
+		if conf.Tenancy.Enabled {
+			middlewares = append(middlewares, NewTenantMiddleware(conf.Tenancy))
+		}
 	}
 
 	roomServer := livekit.NewRoomServiceServer(roomService)
@@ -96,10 +110,13 @@ func NewLivekitServer(conf *config.Config,
 		mux.HandleFunc("/debug/rooms", s.debugInfo)
 	}
 	mux.Handle(roomServer.PathPrefix(), roomServer)
+	mux.Handle(roomAPIPrefix, roomAPI)
 	mux.Handle(egressServer.PathPrefix(), egressServer)
 	mux.Handle(ingressServer.PathPrefix(), ingressServer)
+	mux.Handle("/usage", usageService)
 	mux.Handle("/rtc", rtcService)
 	mux.HandleFunc("/rtc/validate", rtcService.Validate)
+	mux.Handle("/admin/reload", configReloader)
 	mux.HandleFunc("/", s.healthCheck)
 
 	s.httpServer = &http.Server{
@@ -131,6 +148,16 @@ func (s *LivekitServer) HTTPPort() int {
 	return int(s.config.Port)
 }
 
+// SetConfigLoader enables configuration reloads, loader re-reads configuration from its sources
+func (s *LivekitServer) SetConfigLoader(loader ConfigLoader) {
+	s.configReloader.SetLoader(loader)
+}
+
+// ReloadConfig applies the reloadable subset of the configuration while running
+func (s *LivekitServer) ReloadConfig() (*ConfigReloadResult, error) {
+	return s.configReloader.Reload()
+}
+
 func (s *LivekitServer) IsRunning() bool {
 	return s.running.Load()
 }
@@ -258,6 +285,9 @@ func (s *LivekitServer) Start() error {
 	s.roomManager.Stop()
 	s.egressService.Stop()
 	s.ingressService.Stop()
+	s.usageService.Stop()
+	// after rooms are closed, so that their final events are written
+	s.analytics.Stop()
 
 	close(s.closedChan)
 	return nil
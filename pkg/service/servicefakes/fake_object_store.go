@@ -6,11 +6,41 @@ import (
 	"sync"
 	"time"
 
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/protocol/livekit"
 )
 
 type FakeObjectStore struct {
+	AppendDataMessageStub        func(context.Context, livekit.RoomName, *types.DataMessage, uint32, uint32) error
+	appendDataMessageMutex       sync.RWMutex
+	appendDataMessageArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 *types.DataMessage
+		arg4 uint32
+		arg5 uint32
+	}
+	appendDataMessageReturns struct {
+		result1 error
+	}
+	appendDataMessageReturnsOnCall map[int]struct {
+		result1 error
+	}
+	CountTenantParticipantsStub        func(context.Context, string) (int, error)
+	countTenantParticipantsMutex       sync.RWMutex
+	countTenantParticipantsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	countTenantParticipantsReturns struct {
+		result1 int
+		result2 error
+	}
+	countTenantParticipantsReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	DeleteParticipantStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) error
 	deleteParticipantMutex       sync.RWMutex
 	deleteParticipantArgsForCall []struct {
@@ -64,6 +94,36 @@ type FakeObjectStore struct {
 		result1 []*livekit.Room
 		result2 error
 	}
+	LoadDataHistoryStub        func(context.Context, livekit.RoomName) ([]*types.DataMessage, error)
+	loadDataHistoryMutex       sync.RWMutex
+	loadDataHistoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	loadDataHistoryReturns struct {
+		result1 []*types.DataMessage
+		result2 error
+	}
+	loadDataHistoryReturnsOnCall map[int]struct {
+		result1 []*types.DataMessage
+		result2 error
+	}
+	LoadOrStoreSessionStartStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, time.Time) (time.Time, error)
+	loadOrStoreSessionStartMutex       sync.RWMutex
+	loadOrStoreSessionStartArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 time.Time
+	}
+	loadOrStoreSessionStartReturns struct {
+		result1 time.Time
+		result2 error
+	}
+	loadOrStoreSessionStartReturnsOnCall map[int]struct {
+		result1 time.Time
+		result2 error
+	}
 	LoadParticipantStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error)
 	loadParticipantMutex       sync.RWMutex
 	loadParticipantArgsForCall []struct {
@@ -93,6 +153,20 @@ type FakeObjectStore struct {
 		result1 *livekit.Room
 		result2 error
 	}
+	LoadRoomInternalStub        func(context.Context, livekit.RoomName) (*types.RoomInternal, error)
+	loadRoomInternalMutex       sync.RWMutex
+	loadRoomInternalArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	loadRoomInternalReturns struct {
+		result1 *types.RoomInternal
+		result2 error
+	}
+	loadRoomInternalReturnsOnCall map[int]struct {
+		result1 *types.RoomInternal
+		result2 error
+	}
 	LockRoomStub        func(context.Context, livekit.RoomName, time.Duration) (string, error)
 	lockRoomMutex       sync.RWMutex
 	lockRoomArgsForCall []struct {
@@ -133,6 +207,19 @@ type FakeObjectStore struct {
 	storeRoomReturnsOnCall map[int]struct {
 		result1 error
 	}
+	StoreRoomInternalStub        func(context.Context, livekit.RoomName, *types.RoomInternal) error
+	storeRoomInternalMutex       sync.RWMutex
+	storeRoomInternalArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 *types.RoomInternal
+	}
+	storeRoomInternalReturns struct {
+		result1 error
+	}
+	storeRoomInternalReturnsOnCall map[int]struct {
+		result1 error
+	}
 	UnlockRoomStub        func(context.Context, livekit.RoomName, string) error
 	unlockRoomMutex       sync.RWMutex
 	unlockRoomArgsForCall []struct {
@@ -150,6 +237,136 @@ type FakeObjectStore struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeObjectStore) AppendDataMessage(arg1 context.Context, arg2 livekit.RoomName, arg3 *types.DataMessage, arg4 uint32, arg5 uint32) error {
+	fake.appendDataMessageMutex.Lock()
+	ret, specificReturn := fake.appendDataMessageReturnsOnCall[len(fake.appendDataMessageArgsForCall)]
+	fake.appendDataMessageArgsForCall = append(fake.appendDataMessageArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 *types.DataMessage
+		arg4 uint32
+		arg5 uint32
+	}{arg1, arg2, arg3, arg4, arg5})
+	stub := fake.AppendDataMessageStub
+	fakeReturns := fake.appendDataMessageReturns
+	fake.recordInvocation("AppendDataMessage", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.appendDataMessageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4, arg5)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) AppendDataMessageCallCount() int {
+	fake.appendDataMessageMutex.RLock()
+	defer fake.appendDataMessageMutex.RUnlock()
+	return len(fake.appendDataMessageArgsForCall)
+}
+
+func (fake *FakeObjectStore) AppendDataMessageCalls(stub func(context.Context, livekit.RoomName, *types.DataMessage, uint32, uint32) error) {
+	fake.appendDataMessageMutex.Lock()
+	defer fake.appendDataMessageMutex.Unlock()
+	fake.AppendDataMessageStub = stub
+}
+
+func (fake *FakeObjectStore) AppendDataMessageArgsForCall(i int) (context.Context, livekit.RoomName, *types.DataMessage, uint32, uint32) {
+	fake.appendDataMessageMutex.RLock()
+	defer fake.appendDataMessageMutex.RUnlock()
+	argsForCall := fake.appendDataMessageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+}
+
+func (fake *FakeObjectStore) AppendDataMessageReturns(result1 error) {
+	fake.appendDataMessageMutex.Lock()
+	defer fake.appendDataMessageMutex.Unlock()
+	fake.AppendDataMessageStub = nil
+	fake.appendDataMessageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) AppendDataMessageReturnsOnCall(i int, result1 error) {
+	fake.appendDataMessageMutex.Lock()
+	defer fake.appendDataMessageMutex.Unlock()
+	fake.AppendDataMessageStub = nil
+	if fake.appendDataMessageReturnsOnCall == nil {
+		fake.appendDataMessageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.appendDataMessageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) CountTenantParticipants(arg1 context.Context, arg2 string) (int, error) {
+	fake.countTenantParticipantsMutex.Lock()
+	ret, specificReturn := fake.countTenantParticipantsReturnsOnCall[len(fake.countTenantParticipantsArgsForCall)]
+	fake.countTenantParticipantsArgsForCall = append(fake.countTenantParticipantsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CountTenantParticipantsStub
+	fakeReturns := fake.countTenantParticipantsReturns
+	fake.recordInvocation("CountTenantParticipants", []interface{}{arg1, arg2})
+	fake.countTenantParticipantsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) CountTenantParticipantsCallCount() int {
+	fake.countTenantParticipantsMutex.RLock()
+	defer fake.countTenantParticipantsMutex.RUnlock()
+	return len(fake.countTenantParticipantsArgsForCall)
+}
+
+func (fake *FakeObjectStore) CountTenantParticipantsCalls(stub func(context.Context, string) (int, error)) {
+	fake.countTenantParticipantsMutex.Lock()
+	defer fake.countTenantParticipantsMutex.Unlock()
+	fake.CountTenantParticipantsStub = stub
+}
+
+func (fake *FakeObjectStore) CountTenantParticipantsArgsForCall(i int) (context.Context, string) {
+	fake.countTenantParticipantsMutex.RLock()
+	defer fake.countTenantParticipantsMutex.RUnlock()
+	argsForCall := fake.countTenantParticipantsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) CountTenantParticipantsReturns(result1 int, result2 error) {
+	fake.countTenantParticipantsMutex.Lock()
+	defer fake.countTenantParticipantsMutex.Unlock()
+	fake.CountTenantParticipantsStub = nil
+	fake.countTenantParticipantsReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) CountTenantParticipantsReturnsOnCall(i int, result1 int, result2 error) {
+	fake.countTenantParticipantsMutex.Lock()
+	defer fake.countTenantParticipantsMutex.Unlock()
+	fake.CountTenantParticipantsStub = nil
+	if fake.countTenantParticipantsReturnsOnCall == nil {
+		fake.countTenantParticipantsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countTenantParticipantsReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) DeleteParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) error {
 	fake.deleteParticipantMutex.Lock()
 	ret, specificReturn := fake.deleteParticipantReturnsOnCall[len(fake.deleteParticipantArgsForCall)]
@@ -410,6 +627,138 @@ func (fake *FakeObjectStore) ListRoomsReturnsOnCall(i int, result1 []*livekit.Ro
 	}{result1, result2}
 }
 
+func (fake *FakeObjectStore) LoadDataHistory(arg1 context.Context, arg2 livekit.RoomName) ([]*types.DataMessage, error) {
+	fake.loadDataHistoryMutex.Lock()
+	ret, specificReturn := fake.loadDataHistoryReturnsOnCall[len(fake.loadDataHistoryArgsForCall)]
+	fake.loadDataHistoryArgsForCall = append(fake.loadDataHistoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.LoadDataHistoryStub
+	fakeReturns := fake.loadDataHistoryReturns
+	fake.recordInvocation("LoadDataHistory", []interface{}{arg1, arg2})
+	fake.loadDataHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryCallCount() int {
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
+	return len(fake.loadDataHistoryArgsForCall)
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryCalls(stub func(context.Context, livekit.RoomName) ([]*types.DataMessage, error)) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = stub
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
+	argsForCall := fake.loadDataHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryReturns(result1 []*types.DataMessage, result2 error) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = nil
+	fake.loadDataHistoryReturns = struct {
+		result1 []*types.DataMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadDataHistoryReturnsOnCall(i int, result1 []*types.DataMessage, result2 error) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = nil
+	if fake.loadDataHistoryReturnsOnCall == nil {
+		fake.loadDataHistoryReturnsOnCall = make(map[int]struct {
+			result1 []*types.DataMessage
+			result2 error
+		})
+	}
+	fake.loadDataHistoryReturnsOnCall[i] = struct {
+		result1 []*types.DataMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadOrStoreSessionStart(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity, arg4 time.Time) (time.Time, error) {
+	fake.loadOrStoreSessionStartMutex.Lock()
+	ret, specificReturn := fake.loadOrStoreSessionStartReturnsOnCall[len(fake.loadOrStoreSessionStartArgsForCall)]
+	fake.loadOrStoreSessionStartArgsForCall = append(fake.loadOrStoreSessionStartArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 livekit.ParticipantIdentity
+		arg4 time.Time
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.LoadOrStoreSessionStartStub
+	fakeReturns := fake.loadOrStoreSessionStartReturns
+	fake.recordInvocation("LoadOrStoreSessionStart", []interface{}{arg1, arg2, arg3, arg4})
+	fake.loadOrStoreSessionStartMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LoadOrStoreSessionStartCallCount() int {
+	fake.loadOrStoreSessionStartMutex.RLock()
+	defer fake.loadOrStoreSessionStartMutex.RUnlock()
+	return len(fake.loadOrStoreSessionStartArgsForCall)
+}
+
+func (fake *FakeObjectStore) LoadOrStoreSessionStartCalls(stub func(context.Context, livekit.RoomName, livekit.ParticipantIdentity, time.Time) (time.Time, error)) {
+	fake.loadOrStoreSessionStartMutex.Lock()
+	defer fake.loadOrStoreSessionStartMutex.Unlock()
+	fake.LoadOrStoreSessionStartStub = stub
+}
+
+func (fake *FakeObjectStore) LoadOrStoreSessionStartArgsForCall(i int) (context.Context, livekit.RoomName, livekit.ParticipantIdentity, time.Time) {
+	fake.loadOrStoreSessionStartMutex.RLock()
+	defer fake.loadOrStoreSessionStartMutex.RUnlock()
+	argsForCall := fake.loadOrStoreSessionStartArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeObjectStore) LoadOrStoreSessionStartReturns(result1 time.Time, result2 error) {
+	fake.loadOrStoreSessionStartMutex.Lock()
+	defer fake.loadOrStoreSessionStartMutex.Unlock()
+	fake.LoadOrStoreSessionStartStub = nil
+	fake.loadOrStoreSessionStartReturns = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadOrStoreSessionStartReturnsOnCall(i int, result1 time.Time, result2 error) {
+	fake.loadOrStoreSessionStartMutex.Lock()
+	defer fake.loadOrStoreSessionStartMutex.Unlock()
+	fake.LoadOrStoreSessionStartStub = nil
+	if fake.loadOrStoreSessionStartReturnsOnCall == nil {
+		fake.loadOrStoreSessionStartReturnsOnCall = make(map[int]struct {
+			result1 time.Time
+			result2 error
+		})
+	}
+	fake.loadOrStoreSessionStartReturnsOnCall[i] = struct {
+		result1 time.Time
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) LoadParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error) {
 	fake.loadParticipantMutex.Lock()
 	ret, specificReturn := fake.loadParticipantReturnsOnCall[len(fake.loadParticipantArgsForCall)]
@@ -541,6 +890,71 @@ func (fake *FakeObjectStore) LoadRoomReturnsOnCall(i int, result1 *livekit.Room,
 	}{result1, result2}
 }
 
+func (fake *FakeObjectStore) LoadRoomInternal(arg1 context.Context, arg2 livekit.RoomName) (*types.RoomInternal, error) {
+	fake.loadRoomInternalMutex.Lock()
+	ret, specificReturn := fake.loadRoomInternalReturnsOnCall[len(fake.loadRoomInternalArgsForCall)]
+	fake.loadRoomInternalArgsForCall = append(fake.loadRoomInternalArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.LoadRoomInternalStub
+	fakeReturns := fake.loadRoomInternalReturns
+	fake.recordInvocation("LoadRoomInternal", []interface{}{arg1, arg2})
+	fake.loadRoomInternalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeObjectStore) LoadRoomInternalCallCount() int {
+	fake.loadRoomInternalMutex.RLock()
+	defer fake.loadRoomInternalMutex.RUnlock()
+	return len(fake.loadRoomInternalArgsForCall)
+}
+
+func (fake *FakeObjectStore) LoadRoomInternalCalls(stub func(context.Context, livekit.RoomName) (*types.RoomInternal, error)) {
+	fake.loadRoomInternalMutex.Lock()
+	defer fake.loadRoomInternalMutex.Unlock()
+	fake.LoadRoomInternalStub = stub
+}
+
+func (fake *FakeObjectStore) LoadRoomInternalArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.loadRoomInternalMutex.RLock()
+	defer fake.loadRoomInternalMutex.RUnlock()
+	argsForCall := fake.loadRoomInternalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeObjectStore) LoadRoomInternalReturns(result1 *types.RoomInternal, result2 error) {
+	fake.loadRoomInternalMutex.Lock()
+	defer fake.loadRoomInternalMutex.Unlock()
+	fake.LoadRoomInternalStub = nil
+	fake.loadRoomInternalReturns = struct {
+		result1 *types.RoomInternal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeObjectStore) LoadRoomInternalReturnsOnCall(i int, result1 *types.RoomInternal, result2 error) {
+	fake.loadRoomInternalMutex.Lock()
+	defer fake.loadRoomInternalMutex.Unlock()
+	fake.LoadRoomInternalStub = nil
+	if fake.loadRoomInternalReturnsOnCall == nil {
+		fake.loadRoomInternalReturnsOnCall = make(map[int]struct {
+			result1 *types.RoomInternal
+			result2 error
+		})
+	}
+	fake.loadRoomInternalReturnsOnCall[i] = struct {
+		result1 *types.RoomInternal
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeObjectStore) LockRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 time.Duration) (string, error) {
 	fake.lockRoomMutex.Lock()
 	ret, specificReturn := fake.lockRoomReturnsOnCall[len(fake.lockRoomArgsForCall)]
@@ -732,6 +1146,69 @@ func (fake *FakeObjectStore) StoreRoomReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeObjectStore) StoreRoomInternal(arg1 context.Context, arg2 livekit.RoomName, arg3 *types.RoomInternal) error {
+	fake.storeRoomInternalMutex.Lock()
+	ret, specificReturn := fake.storeRoomInternalReturnsOnCall[len(fake.storeRoomInternalArgsForCall)]
+	fake.storeRoomInternalArgsForCall = append(fake.storeRoomInternalArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+		arg3 *types.RoomInternal
+	}{arg1, arg2, arg3})
+	stub := fake.StoreRoomInternalStub
+	fakeReturns := fake.storeRoomInternalReturns
+	fake.recordInvocation("StoreRoomInternal", []interface{}{arg1, arg2, arg3})
+	fake.storeRoomInternalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeObjectStore) StoreRoomInternalCallCount() int {
+	fake.storeRoomInternalMutex.RLock()
+	defer fake.storeRoomInternalMutex.RUnlock()
+	return len(fake.storeRoomInternalArgsForCall)
+}
+
+func (fake *FakeObjectStore) StoreRoomInternalCalls(stub func(context.Context, livekit.RoomName, *types.RoomInternal) error) {
+	fake.storeRoomInternalMutex.Lock()
+	defer fake.storeRoomInternalMutex.Unlock()
+	fake.StoreRoomInternalStub = stub
+}
+
+func (fake *FakeObjectStore) StoreRoomInternalArgsForCall(i int) (context.Context, livekit.RoomName, *types.RoomInternal) {
+	fake.storeRoomInternalMutex.RLock()
+	defer fake.storeRoomInternalMutex.RUnlock()
+	argsForCall := fake.storeRoomInternalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeObjectStore) StoreRoomInternalReturns(result1 error) {
+	fake.storeRoomInternalMutex.Lock()
+	defer fake.storeRoomInternalMutex.Unlock()
+	fake.StoreRoomInternalStub = nil
+	fake.storeRoomInternalReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeObjectStore) StoreRoomInternalReturnsOnCall(i int, result1 error) {
+	fake.storeRoomInternalMutex.Lock()
+	defer fake.storeRoomInternalMutex.Unlock()
+	fake.StoreRoomInternalStub = nil
+	if fake.storeRoomInternalReturnsOnCall == nil {
+		fake.storeRoomInternalReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.storeRoomInternalReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeObjectStore) UnlockRoom(arg1 context.Context, arg2 livekit.RoomName, arg3 string) error {
 	fake.unlockRoomMutex.Lock()
 	ret, specificReturn := fake.unlockRoomReturnsOnCall[len(fake.unlockRoomArgsForCall)]
@@ -798,6 +1275,10 @@ func (fake *FakeObjectStore) UnlockRoomReturnsOnCall(i int, result1 error) {
 func (fake *FakeObjectStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.appendDataMessageMutex.RLock()
+	defer fake.appendDataMessageMutex.RUnlock()
+	fake.countTenantParticipantsMutex.RLock()
+	defer fake.countTenantParticipantsMutex.RUnlock()
 	fake.deleteParticipantMutex.RLock()
 	defer fake.deleteParticipantMutex.RUnlock()
 	fake.deleteRoomMutex.RLock()
@@ -806,16 +1287,24 @@ func (fake *FakeObjectStore) Invocations() map[string][][]interface{} {
 	defer fake.listParticipantsMutex.RUnlock()
 	fake.listRoomsMutex.RLock()
 	defer fake.listRoomsMutex.RUnlock()
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
+	fake.loadOrStoreSessionStartMutex.RLock()
+	defer fake.loadOrStoreSessionStartMutex.RUnlock()
 	fake.loadParticipantMutex.RLock()
 	defer fake.loadParticipantMutex.RUnlock()
 	fake.loadRoomMutex.RLock()
 	defer fake.loadRoomMutex.RUnlock()
+	fake.loadRoomInternalMutex.RLock()
+	defer fake.loadRoomInternalMutex.RUnlock()
 	fake.lockRoomMutex.RLock()
 	defer fake.lockRoomMutex.RUnlock()
 	fake.storeParticipantMutex.RLock()
 	defer fake.storeParticipantMutex.RUnlock()
 	fake.storeRoomMutex.RLock()
 	defer fake.storeRoomMutex.RUnlock()
+	fake.storeRoomInternalMutex.RLock()
+	defer fake.storeRoomInternalMutex.RUnlock()
 	fake.unlockRoomMutex.RLock()
 	defer fake.unlockRoomMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -5,11 +5,26 @@ import (
 	"context"
 	"sync"
 
+	"github.com/livekit/livekit-server/pkg/rtc/types"
 	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/protocol/livekit"
 )
 
 type FakeServiceStore struct {
+	CountTenantParticipantsStub        func(context.Context, string) (int, error)
+	countTenantParticipantsMutex       sync.RWMutex
+	countTenantParticipantsArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	countTenantParticipantsReturns struct {
+		result1 int
+		result2 error
+	}
+	countTenantParticipantsReturnsOnCall map[int]struct {
+		result1 int
+		result2 error
+	}
 	ListParticipantsStub        func(context.Context, livekit.RoomName) ([]*livekit.ParticipantInfo, error)
 	listParticipantsMutex       sync.RWMutex
 	listParticipantsArgsForCall []struct {
@@ -38,6 +53,20 @@ type FakeServiceStore struct {
 		result1 []*livekit.Room
 		result2 error
 	}
+	LoadDataHistoryStub        func(context.Context, livekit.RoomName) ([]*types.DataMessage, error)
+	loadDataHistoryMutex       sync.RWMutex
+	loadDataHistoryArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	loadDataHistoryReturns struct {
+		result1 []*types.DataMessage
+		result2 error
+	}
+	loadDataHistoryReturnsOnCall map[int]struct {
+		result1 []*types.DataMessage
+		result2 error
+	}
 	LoadParticipantStub        func(context.Context, livekit.RoomName, livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error)
 	loadParticipantMutex       sync.RWMutex
 	loadParticipantArgsForCall []struct {
@@ -67,10 +96,89 @@ type FakeServiceStore struct {
 		result1 *livekit.Room
 		result2 error
 	}
+	LoadRoomInternalStub        func(context.Context, livekit.RoomName) (*types.RoomInternal, error)
+	loadRoomInternalMutex       sync.RWMutex
+	loadRoomInternalArgsForCall []struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}
+	loadRoomInternalReturns struct {
+		result1 *types.RoomInternal
+		result2 error
+	}
+	loadRoomInternalReturnsOnCall map[int]struct {
+		result1 *types.RoomInternal
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeServiceStore) CountTenantParticipants(arg1 context.Context, arg2 string) (int, error) {
+	fake.countTenantParticipantsMutex.Lock()
+	ret, specificReturn := fake.countTenantParticipantsReturnsOnCall[len(fake.countTenantParticipantsArgsForCall)]
+	fake.countTenantParticipantsArgsForCall = append(fake.countTenantParticipantsArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.CountTenantParticipantsStub
+	fakeReturns := fake.countTenantParticipantsReturns
+	fake.recordInvocation("CountTenantParticipants", []interface{}{arg1, arg2})
+	fake.countTenantParticipantsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceStore) CountTenantParticipantsCallCount() int {
+	fake.countTenantParticipantsMutex.RLock()
+	defer fake.countTenantParticipantsMutex.RUnlock()
+	return len(fake.countTenantParticipantsArgsForCall)
+}
+
+func (fake *FakeServiceStore) CountTenantParticipantsCalls(stub func(context.Context, string) (int, error)) {
+	fake.countTenantParticipantsMutex.Lock()
+	defer fake.countTenantParticipantsMutex.Unlock()
+	fake.CountTenantParticipantsStub = stub
+}
+
+func (fake *FakeServiceStore) CountTenantParticipantsArgsForCall(i int) (context.Context, string) {
+	fake.countTenantParticipantsMutex.RLock()
+	defer fake.countTenantParticipantsMutex.RUnlock()
+	argsForCall := fake.countTenantParticipantsArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceStore) CountTenantParticipantsReturns(result1 int, result2 error) {
+	fake.countTenantParticipantsMutex.Lock()
+	defer fake.countTenantParticipantsMutex.Unlock()
+	fake.CountTenantParticipantsStub = nil
+	fake.countTenantParticipantsReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceStore) CountTenantParticipantsReturnsOnCall(i int, result1 int, result2 error) {
+	fake.countTenantParticipantsMutex.Lock()
+	defer fake.countTenantParticipantsMutex.Unlock()
+	fake.CountTenantParticipantsStub = nil
+	if fake.countTenantParticipantsReturnsOnCall == nil {
+		fake.countTenantParticipantsReturnsOnCall = make(map[int]struct {
+			result1 int
+			result2 error
+		})
+	}
+	fake.countTenantParticipantsReturnsOnCall[i] = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceStore) ListParticipants(arg1 context.Context, arg2 livekit.RoomName) ([]*livekit.ParticipantInfo, error) {
 	fake.listParticipantsMutex.Lock()
 	ret, specificReturn := fake.listParticipantsReturnsOnCall[len(fake.listParticipantsArgsForCall)]
@@ -206,6 +314,71 @@ func (fake *FakeServiceStore) ListRoomsReturnsOnCall(i int, result1 []*livekit.R
 	}{result1, result2}
 }
 
+func (fake *FakeServiceStore) LoadDataHistory(arg1 context.Context, arg2 livekit.RoomName) ([]*types.DataMessage, error) {
+	fake.loadDataHistoryMutex.Lock()
+	ret, specificReturn := fake.loadDataHistoryReturnsOnCall[len(fake.loadDataHistoryArgsForCall)]
+	fake.loadDataHistoryArgsForCall = append(fake.loadDataHistoryArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.LoadDataHistoryStub
+	fakeReturns := fake.loadDataHistoryReturns
+	fake.recordInvocation("LoadDataHistory", []interface{}{arg1, arg2})
+	fake.loadDataHistoryMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceStore) LoadDataHistoryCallCount() int {
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
+	return len(fake.loadDataHistoryArgsForCall)
+}
+
+func (fake *FakeServiceStore) LoadDataHistoryCalls(stub func(context.Context, livekit.RoomName) ([]*types.DataMessage, error)) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = stub
+}
+
+func (fake *FakeServiceStore) LoadDataHistoryArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
+	argsForCall := fake.loadDataHistoryArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceStore) LoadDataHistoryReturns(result1 []*types.DataMessage, result2 error) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = nil
+	fake.loadDataHistoryReturns = struct {
+		result1 []*types.DataMessage
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceStore) LoadDataHistoryReturnsOnCall(i int, result1 []*types.DataMessage, result2 error) {
+	fake.loadDataHistoryMutex.Lock()
+	defer fake.loadDataHistoryMutex.Unlock()
+	fake.LoadDataHistoryStub = nil
+	if fake.loadDataHistoryReturnsOnCall == nil {
+		fake.loadDataHistoryReturnsOnCall = make(map[int]struct {
+			result1 []*types.DataMessage
+			result2 error
+		})
+	}
+	fake.loadDataHistoryReturnsOnCall[i] = struct {
+		result1 []*types.DataMessage
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceStore) LoadParticipant(arg1 context.Context, arg2 livekit.RoomName, arg3 livekit.ParticipantIdentity) (*livekit.ParticipantInfo, error) {
 	fake.loadParticipantMutex.Lock()
 	ret, specificReturn := fake.loadParticipantReturnsOnCall[len(fake.loadParticipantArgsForCall)]
@@ -337,17 +510,88 @@ func (fake *FakeServiceStore) LoadRoomReturnsOnCall(i int, result1 *livekit.Room
 	}{result1, result2}
 }
 
+func (fake *FakeServiceStore) LoadRoomInternal(arg1 context.Context, arg2 livekit.RoomName) (*types.RoomInternal, error) {
+	fake.loadRoomInternalMutex.Lock()
+	ret, specificReturn := fake.loadRoomInternalReturnsOnCall[len(fake.loadRoomInternalArgsForCall)]
+	fake.loadRoomInternalArgsForCall = append(fake.loadRoomInternalArgsForCall, struct {
+		arg1 context.Context
+		arg2 livekit.RoomName
+	}{arg1, arg2})
+	stub := fake.LoadRoomInternalStub
+	fakeReturns := fake.loadRoomInternalReturns
+	fake.recordInvocation("LoadRoomInternal", []interface{}{arg1, arg2})
+	fake.loadRoomInternalMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeServiceStore) LoadRoomInternalCallCount() int {
+	fake.loadRoomInternalMutex.RLock()
+	defer fake.loadRoomInternalMutex.RUnlock()
+	return len(fake.loadRoomInternalArgsForCall)
+}
+
+func (fake *FakeServiceStore) LoadRoomInternalCalls(stub func(context.Context, livekit.RoomName) (*types.RoomInternal, error)) {
+	fake.loadRoomInternalMutex.Lock()
+	defer fake.loadRoomInternalMutex.Unlock()
+	fake.LoadRoomInternalStub = stub
+}
+
+func (fake *FakeServiceStore) LoadRoomInternalArgsForCall(i int) (context.Context, livekit.RoomName) {
+	fake.loadRoomInternalMutex.RLock()
+	defer fake.loadRoomInternalMutex.RUnlock()
+	argsForCall := fake.loadRoomInternalArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeServiceStore) LoadRoomInternalReturns(result1 *types.RoomInternal, result2 error) {
+	fake.loadRoomInternalMutex.Lock()
+	defer fake.loadRoomInternalMutex.Unlock()
+	fake.LoadRoomInternalStub = nil
+	fake.loadRoomInternalReturns = struct {
+		result1 *types.RoomInternal
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeServiceStore) LoadRoomInternalReturnsOnCall(i int, result1 *types.RoomInternal, result2 error) {
+	fake.loadRoomInternalMutex.Lock()
+	defer fake.loadRoomInternalMutex.Unlock()
+	fake.LoadRoomInternalStub = nil
+	if fake.loadRoomInternalReturnsOnCall == nil {
+		fake.loadRoomInternalReturnsOnCall = make(map[int]struct {
+			result1 *types.RoomInternal
+			result2 error
+		})
+	}
+	fake.loadRoomInternalReturnsOnCall[i] = struct {
+		result1 *types.RoomInternal
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeServiceStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.countTenantParticipantsMutex.RLock()
+	defer fake.countTenantParticipantsMutex.RUnlock()
 	fake.listParticipantsMutex.RLock()
 	defer fake.listParticipantsMutex.RUnlock()
 	fake.listRoomsMutex.RLock()
 	defer fake.listRoomsMutex.RUnlock()
+	fake.loadDataHistoryMutex.RLock()
+	defer fake.loadDataHistoryMutex.RUnlock()
 	fake.loadParticipantMutex.RLock()
 	defer fake.loadParticipantMutex.RUnlock()
 	fake.loadRoomMutex.RLock()
 	defer fake.loadRoomMutex.RUnlock()
+	fake.loadRoomInternalMutex.RLock()
+	defer fake.loadRoomInternalMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
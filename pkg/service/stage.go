@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+
+	"github.com/twitchtv/twirp"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+const roomOpStage = "stage"
+
+// stageOperationRequest applies Action to participant Identity of the stage of a room hosted on the node
+type stageOperationRequest struct {
+	Action   StageAction                 `json:"action"`
+	Identity livekit.ParticipantIdentity `json:"identity"`
+}
+
+type stageOperationResult struct {
+	Queue []livekit.ParticipantIdentity `json:"queue"`
+}
+
+// RequestPublish queues a request of the calling participant to be allowed to publish in the room of its token.
+// Withdraw removes the request instead
+func (s *RoomService) RequestPublish(ctx context.Context, req *StageRequest) (*StageResponse, error) {
+	roomName, err := EnsureJoinPermission(ctx)
+	if err != nil {
+		return nil, twirpAuthError(err)
+	}
+	identity := livekit.ParticipantIdentity(GetGrants(ctx).Identity)
+	if identity == "" {
+		return nil, twirp.InvalidArgumentError("identity", ErrIdentityEmpty.Error())
+	}
+
+	action := types.StageActionRequest
+	if req.Withdraw {
+		action = types.StageActionWithdraw
+	}
+	return s.updateStage(ctx, roomName, action, identity)
+}
+
+// ModerateStage lists the room's stage queue, and approves, denies or demotes a participant when requested
+func (s *RoomService) ModerateStage(ctx context.Context, req *ModerateStageRequest) (*StageResponse, error) {
+	if err := EnsureAdminPermission(ctx, livekit.RoomName(req.Room)); err != nil {
+		return nil, twirpAuthError(err)
+	}
+	switch req.Action {
+	case StageActionList, StageActionApprove, StageActionDeny, StageActionDemote:
+	default:
+		return nil, twirp.InvalidArgumentError("action", "must be one of list, approve, deny or demote")
+	}
+	identity := livekit.ParticipantIdentity(req.Identity)
+	if req.Action != StageActionList && identity == "" {
+		return nil, twirp.InvalidArgumentError("identity", ErrIdentityEmpty.Error())
+	}
+
+	return s.updateStage(ctx, livekit.RoomName(req.Room), req.Action, identity)
+}
+
+// updateStage applies action to the stage of a room on the node hosting it, the name as known to the request's tenant
+func (s *RoomService) updateStage(ctx context.Context, roomName livekit.RoomName, action StageAction, identity livekit.ParticipantIdentity) (*StageResponse, error) {
+	result := &stageOperationResult{}
+	err := executeRoomOperation(ctx, s.router, TenantRoomName(ctx, roomName), roomOpStage, &stageOperationRequest{
+		Action:   action,
+		Identity: identity,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &StageResponse{
+		Room:  string(roomName),
+		Queue: make([]string, 0, len(result.Queue)),
+	}
+	for _, queued := range result.Queue {
+		res.Queue = append(res.Queue, string(queued))
+	}
+	return res, nil
+}
+
+func (r *RoomManager) updateStage(_ context.Context, room *rtc.Room, req *stageOperationRequest) (*stageOperationResult, error) {
+	var err error
+	if req.Action != StageActionList {
+		err = room.ApplyStageAction(req.Action, req.Identity)
+	}
+	switch err {
+	case nil:
+	case rtc.ErrInvalidStageAction:
+		return nil, twirp.InvalidArgumentError("action", err.Error())
+	case rtc.ErrParticipantNotFound, rtc.ErrNoPublishRequest:
+		return nil, twirp.NotFoundError(err.Error())
+	case rtc.ErrAlreadyPublisher, rtc.ErrNotPublisher, rtc.ErrMaxPublishersExceeded:
+		return nil, twirp.NewError(twirp.FailedPrecondition, err.Error())
+	default:
+		return nil, err
+	}
+
+	return &stageOperationResult{Queue: room.StageQueue()}, nil
+}
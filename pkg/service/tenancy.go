@@ -0,0 +1,257 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc/types"
+)
+
+// rooms of a tenant are stored and routed as <tenant>/<room>
+const tenantSeparator = "/"
+
+type tenantKey struct{}
+
+// TenantMiddleware resolves the tenant of an authenticated request, it must run after APIKeyAuthMiddleware
+type TenantMiddleware struct {
+	conf       config.TenancyConfig
+	globalKeys map[string]bool
+}
+
+func NewTenantMiddleware(conf config.TenancyConfig) *TenantMiddleware {
+	m := &TenantMiddleware{
+		conf:       conf,
+		globalKeys: make(map[string]bool, len(conf.GlobalKeys)),
+	}
+	for _, key := range conf.GlobalKeys {
+		m.globalKeys[key] = true
+	}
+	return m
+}
+
+func (m *TenantMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+	apiKey := GetAPIKey(r.Context())
+	if apiKey != "" {
+		tenant, err := m.resolveTenant(apiKey, GetServerClaims(r.Context()))
+		if err != nil {
+			handleError(w, http.StatusUnauthorized, err.Error())
+			return
+		}
+		if tenant != "" {
+			r = r.WithContext(WithTenant(r.Context(), tenant))
+		}
+	}
+
+	next.ServeHTTP(w, r)
+}
+
+func (m *TenantMiddleware) resolveTenant(apiKey string, claims *ServerClaims) (string, error) {
+	if m.globalKeys[apiKey] {
+		if strings.Contains(claims.Tenant, tenantSeparator) {
+			return "", ErrInvalidTenant
+		}
+		return claims.Tenant, nil
+	}
+
+	tenant := apiKey
+	if t, ok := m.conf.KeyTenants[apiKey]; ok {
+		tenant = t
+	}
+	if claims.Tenant != "" && claims.Tenant != tenant {
+		return "", ErrPermissionDenied
+	}
+	return tenant, nil
+}
+
+func GetTenant(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// TenantRoomName returns the name a room of the request's tenant is stored and routed under
+func TenantRoomName(ctx context.Context, name livekit.RoomName) livekit.RoomName {
+	tenant := GetTenant(ctx)
+	if tenant == "" || name == "" {
+		return name
+	}
+	return livekit.RoomName(tenantRoomPrefix(tenant) + string(name))
+}
+
+// LocalRoomName is the inverse of TenantRoomName, returning the room name as known to the tenant
+func LocalRoomName(ctx context.Context, name livekit.RoomName) livekit.RoomName {
+	tenant := GetTenant(ctx)
+	if tenant == "" {
+		return name
+	}
+	return livekit.RoomName(strings.TrimPrefix(string(name), tenant+tenantSeparator))
+}
+
+// participantRoomName returns name as known to the tenant of a participant of roomName,
+// as the participant's grants name its room as known to its tenant
+func participantRoomName(participant types.LocalParticipant, roomName livekit.RoomName, name livekit.RoomName) livekit.RoomName {
+	prefix := strings.TrimSuffix(string(roomName), participant.ClaimGrants().Video.Room)
+	return livekit.RoomName(strings.TrimPrefix(string(name), prefix))
+}
+
+// tenantRoomPrefix returns the prefix of the names of the tenant's rooms
+func tenantRoomPrefix(tenant string) string {
+	if tenant == "" {
+		return ""
+	}
+	return tenant + tenantSeparator
+}
+
+// roomTenant returns the tenant a stored room name belongs to, empty when it isn't a tenant's room
+func roomTenant(name livekit.RoomName) string {
+	if i := strings.Index(string(name), tenantSeparator); i > 0 {
+		return string(name)[:i]
+	}
+	return ""
+}
+
+// IsTenantRoom checks if the room belongs to the request's tenant
+func IsTenantRoom(ctx context.Context, name livekit.RoomName) bool {
+	tenant := GetTenant(ctx)
+	if tenant == "" {
+		return true
+	}
+	return strings.HasPrefix(string(name), tenant+tenantSeparator)
+}
+
+// localRoom returns the room as known to the request's tenant
+func localRoom(ctx context.Context, rm *livekit.Room) *livekit.Room {
+	if GetTenant(ctx) == "" {
+		return rm
+	}
+	rm = proto.Clone(rm).(*livekit.Room)
+	rm.Name = string(LocalRoomName(ctx, livekit.RoomName(rm.Name)))
+	return rm
+}
+
+// listTenantRooms returns all rooms that belong to the request's tenant
+func listTenantRooms(ctx context.Context, store ServiceStore) ([]*livekit.Room, error) {
+	rooms, err := store.ListRooms(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*livekit.Room, 0, len(rooms))
+	for _, rm := range rooms {
+		if IsTenantRoom(ctx, livekit.RoomName(rm.Name)) {
+			filtered = append(filtered, rm)
+		}
+	}
+	return filtered, nil
+}
+
+// checkTenantRoomLimit returns ErrTenantLimitExceeded when the tenant cannot create another room
+func checkTenantRoomLimit(ctx context.Context, conf *config.TenancyConfig, store ServiceStore) error {
+	tenant := GetTenant(ctx)
+	if tenant == "" {
+		return nil
+	}
+	limits := conf.Limits(tenant)
+	if limits.MaxRooms <= 0 {
+		return nil
+	}
+
+	rooms, err := listTenantRooms(ctx, store)
+	if err != nil {
+		return err
+	}
+	if len(rooms) >= limits.MaxRooms {
+		return ErrTenantLimitExceeded
+	}
+	return nil
+}
+
+// checkTenantParticipantLimit returns ErrTenantLimitExceeded when the tenant is at capacity
+func checkTenantParticipantLimit(ctx context.Context, conf *config.TenancyConfig, store ServiceStore) error {
+	tenant := GetTenant(ctx)
+	if tenant == "" {
+		return nil
+	}
+	limits := conf.Limits(tenant)
+	if limits.MaxParticipants <= 0 {
+		return nil
+	}
+
+	numParticipants, err := store.CountTenantParticipants(ctx, tenant)
+	if err != nil {
+		return err
+	}
+	if numParticipants >= limits.MaxParticipants {
+		return ErrTenantLimitExceeded
+	}
+	return nil
+}
+
+// localEgressInfo returns the egress as known to the request's tenant
+func localEgressInfo(ctx context.Context, info *livekit.EgressInfo) *livekit.EgressInfo {
+	if GetTenant(ctx) == "" {
+		return info
+	}
+
+	info = proto.Clone(info).(*livekit.EgressInfo)
+	info.RoomName = string(LocalRoomName(ctx, livekit.RoomName(info.RoomName)))
+	switch r := info.Request.(type) {
+	case *livekit.EgressInfo_RoomComposite:
+		r.RoomComposite.RoomName = string(LocalRoomName(ctx, livekit.RoomName(r.RoomComposite.RoomName)))
+	case *livekit.EgressInfo_TrackComposite:
+		r.TrackComposite.RoomName = string(LocalRoomName(ctx, livekit.RoomName(r.TrackComposite.RoomName)))
+	case *livekit.EgressInfo_Track:
+		r.Track.RoomName = string(LocalRoomName(ctx, livekit.RoomName(r.Track.RoomName)))
+	}
+	return info
+}
+
+// localIngressInfo returns the ingress as known to the request's tenant
+func localIngressInfo(ctx context.Context, info *livekit.IngressInfo) *livekit.IngressInfo {
+	if GetTenant(ctx) == "" {
+		return info
+	}
+
+	info = proto.Clone(info).(*livekit.IngressInfo)
+	info.RoomName = string(LocalRoomName(ctx, livekit.RoomName(info.RoomName)))
+	return info
+}
+
+// localWebhookEvent returns the event with rooms named as known to their tenant
+func localWebhookEvent(event *livekit.WebhookEvent) *livekit.WebhookEvent {
+	var roomName string
+	switch {
+	case event.Room != nil:
+		roomName = event.Room.Name
+	case event.EgressInfo != nil:
+		roomName = event.EgressInfo.RoomName
+	case event.IngressInfo != nil:
+		roomName = event.IngressInfo.RoomName
+	}
+	tenant := roomTenant(livekit.RoomName(roomName))
+	if tenant == "" {
+		return event
+	}
+
+	ctx := WithTenant(context.Background(), tenant)
+	event = proto.Clone(event).(*livekit.WebhookEvent)
+	if event.Room != nil {
+		event.Room = localRoom(ctx, event.Room)
+	}
+	if event.EgressInfo != nil {
+		event.EgressInfo = localEgressInfo(ctx, event.EgressInfo)
+	}
+	if event.IngressInfo != nil {
+		event.IngressInfo = localIngressInfo(ctx, event.IngressInfo)
+	}
+	return event
+}
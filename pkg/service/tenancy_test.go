@@ -0,0 +1,102 @@
+package service_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/auth/authfakes"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/service"
+)
+
+func TestTenantMiddleware(t *testing.T) {
+	secret := "somesecretencodedinbase62"
+	provider := &authfakes.FakeKeyProvider{}
+	provider.GetSecretReturns(secret)
+
+	authMiddleware := service.NewAPIKeyAuthMiddleware(provider)
+	tenantMiddleware := service.NewTenantMiddleware(config.TenancyConfig{
+		Enabled:    true,
+		KeyTenants: map[string]string{"APIacme": "acme"},
+		GlobalKeys: []string{"APIglobal"},
+	})
+
+	serve := func(apiKey string, tenantClaim string) (string, int) {
+		var tenant string
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenant = service.GetTenant(r.Context())
+			w.WriteHeader(http.StatusOK)
+		})
+
+		at := auth.NewAccessToken(apiKey, secret).
+			AddGrant(&auth.VideoGrant{Room: "lobby", RoomJoin: true})
+		token, err := at.ToJWT()
+		require.NoError(t, err)
+		if tenantClaim != "" {
+			token = tokenWithTenant(t, apiKey, secret, tenantClaim)
+		}
+
+		r := &http.Request{Header: http.Header{}}
+		w := httptest.NewRecorder()
+		service.SetAuthorizationToken(r, token)
+		authMiddleware.ServeHTTP(w, r, func(w http.ResponseWriter, r *http.Request) {
+			tenantMiddleware.ServeHTTP(w, r, handler)
+		})
+		return tenant, w.Code
+	}
+
+	t.Run("mapped key", func(t *testing.T) {
+		tenant, code := serve("APIacme", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "acme", tenant)
+	})
+
+	t.Run("unmapped key is its own tenant", func(t *testing.T) {
+		tenant, code := serve("APIother", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "APIother", tenant)
+	})
+
+	t.Run("global key", func(t *testing.T) {
+		tenant, code := serve("APIglobal", "")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "", tenant)
+
+		tenant, code = serve("APIglobal", "acme")
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "acme", tenant)
+	})
+
+	t.Run("tenant claim cannot escape key's tenant", func(t *testing.T) {
+		_, code := serve("APIacme", "other")
+		require.Equal(t, http.StatusUnauthorized, code)
+	})
+}
+
+func tokenWithTenant(t *testing.T, apiKey, secret, tenant string) string {
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(secret)},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(sig).
+		Claims(jwt.Claims{
+			Issuer:    apiKey,
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Expiry:    jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).
+		Claims(map[string]interface{}{
+			"video":  &auth.VideoGrant{Room: "lobby", RoomJoin: true},
+			"tenant": tenant,
+		}).
+		CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/telemetry"
+)
+
+type UsageResponse struct {
+	Rooms   map[string]*telemetry.UsageStats `json:"rooms"`
+	APIKeys map[string]*telemetry.UsageStats `json:"api_keys"`
+}
+
+// UsageService exposes usage accumulated across the cluster
+type UsageService struct {
+	store telemetry.UsageStore
+	meter *telemetry.UsageMeter
+}
+
+func NewUsageService(store telemetry.UsageStore, meter *telemetry.UsageMeter) *UsageService {
+	return &UsageService{
+		store: store,
+		meter: meter,
+	}
+}
+
+func (s *UsageService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.meter == nil {
+		handleError(w, http.StatusNotFound, "usage metering is not enabled")
+		return
+	}
+
+	ctx := r.Context()
+	if err := EnsureListPermission(ctx); err != nil {
+		handleError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	rooms, err := s.store.ListUsage(ctx, telemetry.UsageKindRoom)
+	if err != nil {
+		handleError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	apiKeys, err := s.store.ListUsage(ctx, telemetry.UsageKindAPIKey)
+	if err != nil {
+		handleError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	res := &UsageResponse{
+		Rooms:   make(map[string]*telemetry.UsageStats),
+		APIKeys: make(map[string]*telemetry.UsageStats),
+	}
+	roomFilter := livekit.RoomName(r.FormValue("room"))
+	for name, usage := range rooms {
+		roomName := livekit.RoomName(name)
+		if !IsTenantRoom(ctx, roomName) {
+			continue
+		}
+		localName := LocalRoomName(ctx, roomName)
+		if roomFilter != "" && localName != roomFilter {
+			continue
+		}
+		res.Rooms[string(localName)] = usage
+	}
+	for apiKey, usage := range apiKeys {
+		// tenants only see usage of their own key
+		if GetTenant(ctx) != "" && apiKey != GetAPIKey(ctx) {
+			continue
+		}
+		res.APIKeys[apiKey] = usage
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// Stop flushes usage accumulated on this node since the last interval
+func (s *UsageService) Stop() {
+	if s.meter != nil {
+		s.meter.Flush()
+	}
+}
@@ -34,13 +34,18 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		createStore,
 		wire.Bind(new(ServiceStore), new(ObjectStore)),
 		createKeyProvider,
+		wire.Bind(new(auth.KeyProvider), new(*ReloadableKeyProvider)),
 		createWebhookNotifier,
+		wire.Bind(new(webhook.Notifier), new(*ReloadableNotifier)),
 		createClientConfiguration,
 		routing.CreateRouter,
 		getRoomConf,
 		wire.Bind(new(routing.MessageRouter), new(routing.Router)),
 		wire.Bind(new(livekit.RoomService), new(*RoomService)),
 		telemetry.NewAnalyticsService,
+		getUsageStore,
+		telemetry.NewUsageMeter,
+		telemetry.NewRoomMetrics,
 		telemetry.NewTelemetryService,
 		egress.NewRedisRPCClient,
 		getEgressStore,
@@ -48,12 +53,15 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 		ingress.NewRedisRPC,
 		getIngressStore,
 		NewIngressService,
+		NewUsageService,
 		NewRoomAllocator,
 		NewRoomService,
+		NewRoomAPI,
 		NewRTCService,
 		NewLocalRoomManager,
 		newTurnAuthHandler,
 		NewTurnServer,
+		NewConfigReloader,
 		NewLivekitServer,
 	)
 	return &LivekitServer{}, nil
@@ -72,45 +80,43 @@ func getNodeID(currentNode routing.LocalNode) livekit.NodeID {
 	return livekit.NodeID(currentNode.Id)
 }
 
-func createKeyProvider(conf *config.Config) (auth.KeyProvider, error) {
+func createKeyProvider(conf *config.Config) (*ReloadableKeyProvider, error) {
+	if err := loadKeys(conf); err != nil {
+		return nil, err
+	}
+	return NewReloadableKeyProvider(conf.Keys), nil
+}
+
+// loadKeys reads keys from the key file when set
+func loadKeys(conf *config.Config) error {
 	// prefer keyfile if set
 	if conf.KeyFile != "" {
 		if st, err := os.Stat(conf.KeyFile); err != nil {
-			return nil, err
+			return err
 		} else if st.Mode().Perm() != 0600 {
-			return nil, fmt.Errorf("key file must have permission set to 600")
+			return fmt.Errorf("key file must have permission set to 600")
 		}
 		f, err := os.Open(conf.KeyFile)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		defer func() {
 			_ = f.Close()
 		}()
 		decoder := yaml.NewDecoder(f)
 		if err = decoder.Decode(conf.Keys); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	if len(conf.Keys) == 0 {
-		return nil, errors.New("one of key-file or keys must be provided in order to support a secure installation")
+		return errors.New("one of key-file or keys must be provided in order to support a secure installation")
 	}
-
-	return auth.NewFileBasedKeyProviderFromMap(conf.Keys), nil
+	return nil
 }
 
-func createWebhookNotifier(conf *config.Config, provider auth.KeyProvider) (webhook.Notifier, error) {
-	wc := conf.WebHook
-	if len(wc.URLs) == 0 {
-		return nil, nil
-	}
-	secret := provider.GetSecret(wc.APIKey)
-	if secret == "" {
-		return nil, ErrWebHookMissingAPIKey
-	}
-
-	return webhook.NewNotifier(wc.APIKey, secret, wc.URLs), nil
+func createWebhookNotifier(conf *config.Config, provider *ReloadableKeyProvider) (*ReloadableNotifier, error) {
+	return NewReloadableNotifier(conf.WebHook, conf.Tenancy.Enabled, provider)
 }
 
 func createRedisClient(conf *config.Config) (*redis.Client, error) {
@@ -188,6 +194,17 @@ func getIngressStore(s ObjectStore) IngressStore {
 	}
 }
 
+func getUsageStore(s ObjectStore) telemetry.UsageStore {
+	switch store := s.(type) {
+	case *RedisStore:
+		return store
+	case *LocalStore:
+		return store
+	default:
+		return nil
+	}
+}
+
 func createClientConfiguration() clientconfiguration.ClientConfigurationManager {
 	return clientconfiguration.NewStaticClientConfigurationManager(clientconfiguration.StaticConfigurations)
 }
@@ -15,12 +15,10 @@ import (
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
 	"github.com/livekit/livekit-server/pkg/telemetry"
-	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/egress"
 	"github.com/livekit/protocol/ingress"
 	"github.com/livekit/protocol/livekit"
 	"github.com/livekit/protocol/logger"
-	"github.com/livekit/protocol/webhook"
 	"github.com/pkg/errors"
 	"gopkg.in/yaml.v3"
 	"os"
@@ -43,40 +41,49 @@ func InitializeServer(conf *config.Config, currentNode routing.LocalNode) (*Live
 	if err != nil {
 		return nil, err
 	}
-	roomConfig := getRoomConf(conf)
-	roomService, err := NewRoomService(roomAllocator, objectStore, router, roomConfig)
+	reloadableKeyProvider, err := createKeyProvider(conf)
 	if err != nil {
 		return nil, err
 	}
-	nodeID := getNodeID(currentNode)
-	rpcClient := egress.NewRedisRPCClient(nodeID, client)
-	egressStore := getEgressStore(objectStore)
-	keyProvider, err := createKeyProvider(conf)
+	reloadableNotifier, err := createWebhookNotifier(conf, reloadableKeyProvider)
 	if err != nil {
 		return nil, err
 	}
-	notifier, err := createWebhookNotifier(conf, keyProvider)
+	analyticsService, err := telemetry.NewAnalyticsService(conf, currentNode)
 	if err != nil {
 		return nil, err
 	}
-	analyticsService := telemetry.NewAnalyticsService(conf, currentNode)
-	telemetryService := telemetry.NewTelemetryService(notifier, analyticsService)
-	egressService := NewEgressService(rpcClient, objectStore, egressStore, roomService, telemetryService)
-	rpc := ingress.NewRedisRPC(nodeID, client)
-	ingressStore := getIngressStore(objectStore)
-	ingressService := NewIngressService(conf, rpc, ingressStore, roomService, telemetryService)
-	rtcService := NewRTCService(conf, roomAllocator, objectStore, router, currentNode)
+	usageStore := getUsageStore(objectStore)
+	usageMeter := telemetry.NewUsageMeter(conf, currentNode, usageStore)
+	roomMetrics := telemetry.NewRoomMetrics(conf)
+	telemetryService := telemetry.NewTelemetryService(reloadableNotifier, analyticsService, usageMeter, roomMetrics)
 	clientConfigurationManager := createClientConfiguration()
 	roomManager, err := NewLocalRoomManager(conf, objectStore, currentNode, router, telemetryService, clientConfigurationManager)
 	if err != nil {
 		return nil, err
 	}
+	roomConfig := getRoomConf(conf)
+	roomService, err := NewRoomService(roomAllocator, objectStore, router, roomManager, roomConfig)
+	if err != nil {
+		return nil, err
+	}
+	roomAPI := NewRoomAPI(roomService)
+	nodeID := getNodeID(currentNode)
+	rpcClient := egress.NewRedisRPCClient(nodeID, client)
+	egressStore := getEgressStore(objectStore)
+	egressService := NewEgressService(rpcClient, objectStore, egressStore, roomService, telemetryService)
+	rpc := ingress.NewRedisRPC(nodeID, client)
+	ingressStore := getIngressStore(objectStore)
+	ingressService := NewIngressService(conf, rpc, ingressStore, roomService, telemetryService)
+	usageService := NewUsageService(usageStore, usageMeter)
+	rtcService := NewRTCService(conf, roomAllocator, objectStore, router, currentNode)
 	authHandler := newTurnAuthHandler(objectStore)
 	server, err := NewTurnServer(conf, authHandler)
 	if err != nil {
 		return nil, err
 	}
-	livekitServer, err := NewLivekitServer(conf, roomService, egressService, ingressService, rtcService, keyProvider, router, roomManager, server, currentNode)
+	configReloader := NewConfigReloader(conf, reloadableKeyProvider, reloadableNotifier, roomAllocator, roomService, rtcService, roomManager)
+	livekitServer, err := NewLivekitServer(conf, roomService, roomAPI, egressService, ingressService, usageService, analyticsService, rtcService, reloadableKeyProvider, router, roomManager, server, currentNode, configReloader)
 	if err != nil {
 		return nil, err
 	}
@@ -98,45 +105,43 @@ func getNodeID(currentNode routing.LocalNode) livekit.NodeID {
 	return livekit.NodeID(currentNode.Id)
 }
 
-func createKeyProvider(conf *config.Config) (auth.KeyProvider, error) {
+func createKeyProvider(conf *config.Config) (*ReloadableKeyProvider, error) {
+	if err := loadKeys(conf); err != nil {
+		return nil, err
+	}
+	return NewReloadableKeyProvider(conf.Keys), nil
+}
+
+// loadKeys reads keys from the key file when set
+func loadKeys(conf *config.Config) error {
 
 	if conf.KeyFile != "" {
 		if st, err := os.Stat(conf.KeyFile); err != nil {
-			return nil, err
+			return err
 		} else if st.Mode().Perm() != 0600 {
-			return nil, fmt.Errorf("key file must have permission set to 600")
+			return fmt.Errorf("key file must have permission set to 600")
 		}
 		f, err := os.Open(conf.KeyFile)
 		if err != nil {
-			return nil, err
+			return err
 		}
 		defer func() {
 			_ = f.Close()
 		}()
 		decoder := yaml.NewDecoder(f)
 		if err = decoder.Decode(conf.Keys); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
 	if len(conf.Keys) == 0 {
-		return nil, errors.New("one of key-file or keys must be provided in order to support a secure installation")
+		return errors.New("one of key-file or keys must be provided in order to support a secure installation")
 	}
-
-	return auth.NewFileBasedKeyProviderFromMap(conf.Keys), nil
+	return nil
 }
 
-func createWebhookNotifier(conf *config.Config, provider auth.KeyProvider) (webhook.Notifier, error) {
-	wc := conf.WebHook
-	if len(wc.URLs) == 0 {
-		return nil, nil
-	}
-	secret := provider.GetSecret(wc.APIKey)
-	if secret == "" {
-		return nil, ErrWebHookMissingAPIKey
-	}
-
-	return webhook.NewNotifier(wc.APIKey, secret, wc.URLs), nil
+func createWebhookNotifier(conf *config.Config, provider *ReloadableKeyProvider) (*ReloadableNotifier, error) {
+	return NewReloadableNotifier(conf.WebHook, conf.Tenancy.Enabled, provider)
 }
 
 func createRedisClient(conf *config.Config) (*redis.Client, error) {
@@ -214,6 +219,17 @@ func getIngressStore(s ObjectStore) IngressStore {
 	}
 }
 
+func getUsageStore(s ObjectStore) telemetry.UsageStore {
+	switch store := s.(type) {
+	case *RedisStore:
+		return store
+	case *LocalStore:
+		return store
+	default:
+		return nil
+	}
+}
+
 func createClientConfiguration() clientconfiguration.ClientConfigurationManager {
 	return clientconfiguration.NewStaticClientConfigurationManager(clientconfiguration.StaticConfigurations)
 }
@@ -0,0 +1,125 @@
+package telemetry
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// fileAnalyticsSink appends each batch as a line of JSON, rotating the file once it exceeds max size
+type fileAnalyticsSink struct {
+	conf *config.AnalyticsFileConfig
+
+	lock sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newFileAnalyticsSink(conf *config.AnalyticsFileConfig) (*fileAnalyticsSink, error) {
+	s := &fileAnalyticsSink{
+		conf: conf,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileAnalyticsSink) WriteStats(stats *livekit.AnalyticsStats) error {
+	return s.write(stats)
+}
+
+func (s *fileAnalyticsSink) WriteEvents(events *livekit.AnalyticsEvents) error {
+	return s.write(events)
+}
+
+func (s *fileAnalyticsSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *fileAnalyticsSink) write(msg proto.Message) error {
+	line, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.conf.MaxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.conf.MaxSize {
+		if err = s.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileAnalyticsSink) open() error {
+	if err := os.MkdirAll(filepath.Dir(s.conf.Path), 0755); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(s.conf.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+
+	s.file = f
+	s.size = st.Size()
+	return nil
+}
+
+func (s *fileAnalyticsSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		logger.Warnw("could not close analytics file", err, "path", s.conf.Path)
+	}
+
+	rotated := s.conf.Path + "." + time.Now().UTC().Format("20060102T150405.000")
+	if err := os.Rename(s.conf.Path, rotated); err != nil {
+		// keep appending to the current file, rotating is attempted again on the next write
+		logger.Warnw("could not rotate analytics file", err, "path", s.conf.Path)
+	} else {
+		s.removeOldBackups()
+	}
+
+	return s.open()
+}
+
+func (s *fileAnalyticsSink) removeOldBackups() {
+	if s.conf.MaxBackups <= 0 {
+		return
+	}
+
+	backups, err := filepath.Glob(s.conf.Path + ".*")
+	if err != nil || len(backups) <= s.conf.MaxBackups {
+		return
+	}
+
+	// timestamp suffixes sort chronologically
+	sort.Strings(backups)
+	for _, backup := range backups[:len(backups)-s.conf.MaxBackups] {
+		_ = os.Remove(backup)
+	}
+}
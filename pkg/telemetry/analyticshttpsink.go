@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+)
+
+// httpAnalyticsSink POSTs each batch as JSON
+type httpAnalyticsSink struct {
+	conf   *config.AnalyticsHTTPConfig
+	client *http.Client
+}
+
+func newHTTPAnalyticsSink(conf *config.AnalyticsHTTPConfig) (*httpAnalyticsSink, error) {
+	if conf.URL == "" {
+		return nil, fmt.Errorf("analytics http sink requires url")
+	}
+
+	return &httpAnalyticsSink{
+		conf: conf,
+		client: &http.Client{
+			Timeout: conf.Timeout,
+		},
+	}, nil
+}
+
+func (s *httpAnalyticsSink) WriteStats(stats *livekit.AnalyticsStats) error {
+	return s.post(stats)
+}
+
+func (s *httpAnalyticsSink) WriteEvents(events *livekit.AnalyticsEvents) error {
+	return s.post(events)
+}
+
+func (s *httpAnalyticsSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+func (s *httpAnalyticsSink) post(msg proto.Message) error {
+	body, err := protojson.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, s.conf.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.conf.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return fmt.Errorf("analytics endpoint returned %d", res.StatusCode)
+	}
+	return nil
+}
@@ -14,6 +14,8 @@ import (
 type AnalyticsService interface {
 	SendStats(ctx context.Context, stats []*livekit.AnalyticsStat)
 	SendEvent(ctx context.Context, events *livekit.AnalyticsEvent)
+	// Stop flushes stats and events not sent yet
+	Stop()
 }
 
 type analyticsService struct {
@@ -24,11 +26,19 @@ type analyticsService struct {
 	stats  livekit.AnalyticsRecorderService_IngestStatsClient
 }
 
-func NewAnalyticsService(_ *config.Config, currentNode routing.LocalNode) AnalyticsService {
+func NewAnalyticsService(conf *config.Config, currentNode routing.LocalNode) (AnalyticsService, error) {
+	sink, err := createAnalyticsSink(&conf.Analytics)
+	if err != nil {
+		return nil, err
+	}
+	if sink != nil {
+		return NewBatchingAnalyticsService(&conf.Analytics, currentNode.Id, sink), nil
+	}
+
 	return &analyticsService{
 		analyticsKey: "", // TODO: conf.AnalyticsKey
 		nodeID:       currentNode.Id,
-	}
+	}, nil
 }
 
 func (a *analyticsService) SendStats(_ context.Context, stats []*livekit.AnalyticsStat) {
@@ -45,6 +55,8 @@ func (a *analyticsService) SendStats(_ context.Context, stats []*livekit.Analyti
 	}
 }
 
+func (a *analyticsService) Stop() {}
+
 func (a *analyticsService) SendEvent(_ context.Context, event *livekit.AnalyticsEvent) {
 	if a.events == nil {
 		return
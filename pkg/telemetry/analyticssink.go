@@ -0,0 +1,191 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+const (
+	AnalyticsSinkFile = "file"
+	AnalyticsSinkHTTP = "http"
+)
+
+// AnalyticsSink persists batches of stats and events
+//
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . AnalyticsSink
+type AnalyticsSink interface {
+	WriteStats(stats *livekit.AnalyticsStats) error
+	WriteEvents(events *livekit.AnalyticsEvents) error
+	Close() error
+}
+
+func createAnalyticsSink(conf *config.AnalyticsConfig) (AnalyticsSink, error) {
+	switch conf.Sink {
+	case "":
+		return nil, nil
+	case AnalyticsSinkFile:
+		return newFileAnalyticsSink(&conf.File)
+	case AnalyticsSinkHTTP:
+		return newHTTPAnalyticsSink(&conf.HTTP)
+	default:
+		return nil, fmt.Errorf("unknown analytics sink: %s", conf.Sink)
+	}
+}
+
+// batchingAnalyticsService queues stats and events, writing them to a sink in batches.
+// queues are bounded, items are dropped when the sink can't keep up
+type batchingAnalyticsService struct {
+	nodeID        string
+	sink          AnalyticsSink
+	batchSize     int
+	flushInterval time.Duration
+
+	statsQueue  chan *livekit.AnalyticsStat
+	eventsQueue chan *livekit.AnalyticsEvent
+
+	stopOnce sync.Once
+	stopChan chan struct{}
+	doneChan chan struct{}
+}
+
+func NewBatchingAnalyticsService(conf *config.AnalyticsConfig, nodeID string, sink AnalyticsSink) AnalyticsService {
+	a := &batchingAnalyticsService{
+		nodeID:        nodeID,
+		sink:          sink,
+		batchSize:     conf.BatchSize,
+		flushInterval: conf.FlushInterval,
+		statsQueue:    make(chan *livekit.AnalyticsStat, conf.BufferSize),
+		eventsQueue:   make(chan *livekit.AnalyticsEvent, conf.BufferSize),
+		stopChan:      make(chan struct{}),
+		doneChan:      make(chan struct{}),
+	}
+	if a.batchSize <= 0 {
+		a.batchSize = 1
+	}
+	if a.flushInterval <= 0 {
+		a.flushInterval = time.Second
+	}
+
+	go a.worker()
+
+	return a
+}
+
+func (a *batchingAnalyticsService) SendStats(_ context.Context, stats []*livekit.AnalyticsStat) {
+	for i, stat := range stats {
+		stat.Node = a.nodeID
+		select {
+		case a.statsQueue <- stat:
+		default:
+			prometheus.IncrementAnalyticsDropped(prometheus.AnalyticsStats, prometheus.AnalyticsDropQueueFull, len(stats)-i)
+			return
+		}
+	}
+}
+
+func (a *batchingAnalyticsService) SendEvent(_ context.Context, event *livekit.AnalyticsEvent) {
+	select {
+	case a.eventsQueue <- event:
+	default:
+		prometheus.IncrementAnalyticsDropped(prometheus.AnalyticsEvents, prometheus.AnalyticsDropQueueFull, 1)
+	}
+}
+
+// Stop writes stats and events still queued to the sink and closes it, items sent afterwards are discarded
+func (a *batchingAnalyticsService) Stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopChan)
+	})
+	<-a.doneChan
+}
+
+func (a *batchingAnalyticsService) worker() {
+	ticker := time.NewTicker(a.flushInterval)
+	defer ticker.Stop()
+	defer close(a.doneChan)
+
+	stats := make([]*livekit.AnalyticsStat, 0, a.batchSize)
+	events := make([]*livekit.AnalyticsEvent, 0, a.batchSize)
+	for {
+		select {
+		case stat := <-a.statsQueue:
+			stats = append(stats, stat)
+			if len(stats) >= a.batchSize {
+				stats = a.writeStats(stats)
+			}
+		case event := <-a.eventsQueue:
+			events = append(events, event)
+			if len(events) >= a.batchSize {
+				events = a.writeEvents(events)
+			}
+		case <-ticker.C:
+			stats = a.writeStats(stats)
+			events = a.writeEvents(events)
+		case <-a.stopChan:
+			a.flush(stats, events)
+			if err := a.sink.Close(); err != nil {
+				logger.Warnw("failed to close analytics sink", err)
+			}
+			return
+		}
+	}
+}
+
+// flush writes the given batches along with everything still queued, in batches
+func (a *batchingAnalyticsService) flush(stats []*livekit.AnalyticsStat, events []*livekit.AnalyticsEvent) {
+	for {
+		select {
+		case stat := <-a.statsQueue:
+			stats = append(stats, stat)
+			if len(stats) >= a.batchSize {
+				stats = a.writeStats(stats)
+			}
+		case event := <-a.eventsQueue:
+			events = append(events, event)
+			if len(events) >= a.batchSize {
+				events = a.writeEvents(events)
+			}
+		default:
+			a.writeStats(stats)
+			a.writeEvents(events)
+			return
+		}
+	}
+}
+
+// writeStats writes a batch to the sink, returning the emptied batch for reuse
+func (a *batchingAnalyticsService) writeStats(stats []*livekit.AnalyticsStat) []*livekit.AnalyticsStat {
+	if len(stats) == 0 {
+		return stats
+	}
+
+	if err := a.sink.WriteStats(&livekit.AnalyticsStats{Stats: stats}); err != nil {
+		logger.Warnw("failed to write analytics stats", err, "count", len(stats))
+		prometheus.IncrementAnalyticsDropped(prometheus.AnalyticsStats, prometheus.AnalyticsDropSinkError, len(stats))
+	} else {
+		prometheus.IncrementAnalyticsSent(prometheus.AnalyticsStats, len(stats))
+	}
+	return make([]*livekit.AnalyticsStat, 0, a.batchSize)
+}
+
+func (a *batchingAnalyticsService) writeEvents(events []*livekit.AnalyticsEvent) []*livekit.AnalyticsEvent {
+	if len(events) == 0 {
+		return events
+	}
+
+	if err := a.sink.WriteEvents(&livekit.AnalyticsEvents{Events: events}); err != nil {
+		logger.Warnw("failed to write analytics events", err, "count", len(events))
+		prometheus.IncrementAnalyticsDropped(prometheus.AnalyticsEvents, prometheus.AnalyticsDropSinkError, len(events))
+	} else {
+		prometheus.IncrementAnalyticsSent(prometheus.AnalyticsEvents, len(events))
+	}
+	return make([]*livekit.AnalyticsEvent, 0, a.batchSize)
+}
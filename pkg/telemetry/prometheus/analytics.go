@@ -0,0 +1,44 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	AnalyticsStats  = "stats"
+	AnalyticsEvents = "events"
+
+	AnalyticsDropQueueFull = "queue_full"
+	AnalyticsDropSinkError = "sink_error"
+)
+
+var (
+	promAnalyticsSent    *prometheus.CounterVec
+	promAnalyticsDropped *prometheus.CounterVec
+)
+
+func initAnalyticsStats(nodeID string) {
+	promAnalyticsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "analytics",
+		Name:        "sent_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"type"})
+	promAnalyticsDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "analytics",
+		Name:        "dropped_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"type", "reason"})
+
+	prometheus.MustRegister(promAnalyticsSent)
+	prometheus.MustRegister(promAnalyticsDropped)
+}
+
+func IncrementAnalyticsSent(kind string, count int) {
+	promAnalyticsSent.WithLabelValues(kind).Add(float64(count))
+}
+
+func IncrementAnalyticsDropped(kind string, reason string, count int) {
+	promAnalyticsDropped.WithLabelValues(kind, reason).Add(float64(count))
+}
@@ -0,0 +1,50 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	promDataTopicPackets *prometheus.CounterVec
+	promDataTopicBytes   *prometheus.CounterVec
+	promDataTopicDropped *prometheus.CounterVec
+)
+
+func initDataTopicStats(nodeID string) {
+	promDataTopicPackets = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "data_topic",
+		Name:        "packets_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Data packets published to topics, and forwarded to their subscribers.",
+	}, []string{"class", "direction"})
+	promDataTopicBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "data_topic",
+		Name:        "bytes_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Payload bytes of data packets published to topics, and forwarded to their subscribers.",
+	}, []string{"class", "direction"})
+	promDataTopicDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "data_topic",
+		Name:        "dropped_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Data packets dropped because their publisher was not allowed to publish to the topic.",
+	}, []string{"class"})
+
+	prometheus.MustRegister(promDataTopicPackets)
+	prometheus.MustRegister(promDataTopicBytes)
+	prometheus.MustRegister(promDataTopicDropped)
+}
+
+// AddDataTopicPackets counts packets of a class of data topics, incoming as they're published and outgoing as
+// they're forwarded
+func AddDataTopicPackets(class string, direction Direction, packets int, bytes int) {
+	promDataTopicPackets.WithLabelValues(class, string(direction)).Add(float64(packets))
+	promDataTopicBytes.WithLabelValues(class, string(direction)).Add(float64(bytes))
+}
+
+func AddDataTopicDropped(class string) {
+	promDataTopicDropped.WithLabelValues(class).Inc()
+}
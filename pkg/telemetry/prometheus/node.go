@@ -77,6 +77,10 @@ func init() {
 
 	initPacketStats(nodeID)
 	initRoomStats(nodeID)
+	initAnalyticsStats(nodeID)
+	initRoomMetricsStats(nodeID)
+	initDataTopicStats(nodeID)
+	initSignalStats(nodeID)
 }
 
 func GetUpdatedNodeStats(prev *livekit.NodeStats, prevAverage *livekit.NodeStats) (*livekit.NodeStats, bool, error) {
@@ -32,6 +32,7 @@ var (
 	promPliTotal        *prometheus.CounterVec
 	promFirTotal        *prometheus.CounterVec
 	promParticipantJoin *prometheus.CounterVec
+	promDataDropped     *prometheus.CounterVec
 )
 
 func initPacketStats(nodeID string) {
@@ -72,12 +73,21 @@ func initPacketStats(nodeID string) {
 		ConstLabels: prometheus.Labels{"node_id": nodeID},
 	}, nil)
 
+	promDataDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "data_packet",
+		Name:        "dropped_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Data packets of participants dropped for exceeding their size or rate limits.",
+	}, []string{"reason"})
+
 	prometheus.MustRegister(promPacketTotal)
 	prometheus.MustRegister(promPacketBytes)
 	prometheus.MustRegister(promNackTotal)
 	prometheus.MustRegister(promPliTotal)
 	prometheus.MustRegister(promFirTotal)
 	prometheus.MustRegister(promParticipantJoin)
+	prometheus.MustRegister(promDataDropped)
 }
 
 func IncrementPackets(direction Direction, count uint64, retransmit bool) {
@@ -137,3 +147,8 @@ func transmissionLabel(retransmit bool) string {
 		return transmissionRetransmit
 	}
 }
+
+// AddDataPacketDropped counts a participant's data packet dropped for exceeding its limits
+func AddDataPacketDropped(reason string) {
+	promDataDropped.WithLabelValues(reason).Inc()
+}
@@ -0,0 +1,135 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	promRoomParticipants           *prometheus.GaugeVec
+	promRoomTracksPublished        *prometheus.GaugeVec
+	promRoomBytes                  *prometheus.CounterVec
+	promRoomPacketsLost            *prometheus.CounterVec
+	promRoomParticipantBytes       *prometheus.CounterVec
+	promRoomParticipantPacketsLost *prometheus.CounterVec
+	promRoomParticipantRTT         *prometheus.GaugeVec
+	promRoomParticipantJitter      *prometheus.GaugeVec
+	promRoomParticipantScore       *prometheus.GaugeVec
+)
+
+func initRoomMetricsStats(nodeID string) {
+	promRoomParticipants = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room",
+		Name:        "participants",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"room"})
+	promRoomTracksPublished = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room",
+		Name:        "tracks_published",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"room", "kind"})
+	promRoomBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room",
+		Name:        "bytes_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"room", "direction"})
+	promRoomPacketsLost = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room",
+		Name:        "packets_lost_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"room", "direction"})
+	promRoomParticipantBytes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room_participant",
+		Name:        "bytes_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"room", "participant", "direction"})
+	promRoomParticipantPacketsLost = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room_participant",
+		Name:        "packets_lost_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+	}, []string{"room", "participant", "direction"})
+	promRoomParticipantRTT = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room_participant",
+		Name:        "rtt_ms",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Max round trip time of the participant's tracks in the last stats interval.",
+	}, []string{"room", "participant", "direction"})
+	promRoomParticipantJitter = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room_participant",
+		Name:        "jitter",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Max jitter of the participant's tracks in the last stats interval.",
+	}, []string{"room", "participant", "direction"})
+	promRoomParticipantScore = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "room_participant",
+		Name:        "connection_quality",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Connection quality score of the participant's tracks, between 1 and 5.",
+	}, []string{"room", "participant", "direction"})
+
+	prometheus.MustRegister(promRoomParticipants)
+	prometheus.MustRegister(promRoomTracksPublished)
+	prometheus.MustRegister(promRoomBytes)
+	prometheus.MustRegister(promRoomPacketsLost)
+	prometheus.MustRegister(promRoomParticipantBytes)
+	prometheus.MustRegister(promRoomParticipantPacketsLost)
+	prometheus.MustRegister(promRoomParticipantRTT)
+	prometheus.MustRegister(promRoomParticipantJitter)
+	prometheus.MustRegister(promRoomParticipantScore)
+}
+
+func SetRoomParticipants(room string, count int) {
+	promRoomParticipants.WithLabelValues(room).Set(float64(count))
+}
+
+func AddRoomPublishedTrack(room string, kind string, delta int) {
+	promRoomTracksPublished.WithLabelValues(room, kind).Add(float64(delta))
+}
+
+func AddRoomBytes(room string, direction Direction, bytes uint64, packetsLost uint32) {
+	promRoomBytes.WithLabelValues(room, string(direction)).Add(float64(bytes))
+	promRoomPacketsLost.WithLabelValues(room, string(direction)).Add(float64(packetsLost))
+}
+
+func AddRoomParticipantBytes(room string, participant string, direction Direction, bytes uint64, packetsLost uint32) {
+	promRoomParticipantBytes.WithLabelValues(room, participant, string(direction)).Add(float64(bytes))
+	promRoomParticipantPacketsLost.WithLabelValues(room, participant, string(direction)).Add(float64(packetsLost))
+}
+
+func SetRoomParticipantQuality(room string, participant string, direction Direction, rtt uint32, jitter uint32, score float32) {
+	promRoomParticipantRTT.WithLabelValues(room, participant, string(direction)).Set(float64(rtt))
+	promRoomParticipantJitter.WithLabelValues(room, participant, string(direction)).Set(float64(jitter))
+	promRoomParticipantScore.WithLabelValues(room, participant, string(direction)).Set(float64(score))
+}
+
+// DeleteRoomParticipantMetrics removes all series of a participant
+func DeleteRoomParticipantMetrics(room string, participant string) {
+	labels := prometheus.Labels{"room": room, "participant": participant}
+	promRoomParticipantBytes.DeletePartialMatch(labels)
+	promRoomParticipantPacketsLost.DeletePartialMatch(labels)
+	promRoomParticipantRTT.DeletePartialMatch(labels)
+	promRoomParticipantJitter.DeletePartialMatch(labels)
+	promRoomParticipantScore.DeletePartialMatch(labels)
+}
+
+// DeleteRoomMetrics removes all series of a room and its participants
+func DeleteRoomMetrics(room string) {
+	labels := prometheus.Labels{"room": room}
+	promRoomParticipants.DeletePartialMatch(labels)
+	promRoomTracksPublished.DeletePartialMatch(labels)
+	promRoomBytes.DeletePartialMatch(labels)
+	promRoomPacketsLost.DeletePartialMatch(labels)
+	promRoomParticipantBytes.DeletePartialMatch(labels)
+	promRoomParticipantPacketsLost.DeletePartialMatch(labels)
+	promRoomParticipantRTT.DeletePartialMatch(labels)
+	promRoomParticipantJitter.DeletePartialMatch(labels)
+	promRoomParticipantScore.DeletePartialMatch(labels)
+}
@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	promSignalDropped     *prometheus.CounterVec
+	promSignalDisconnects prometheus.Counter
+)
+
+func initSignalStats(nodeID string) {
+	promSignalDropped = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "signal_request",
+		Name:        "dropped_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Signal requests of participants rejected for exceeding their rate or track limits.",
+	}, []string{"type", "reason"})
+	promSignalDisconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace:   livekitNamespace,
+		Subsystem:   "signal_request",
+		Name:        "disconnects_total",
+		ConstLabels: prometheus.Labels{"node_id": nodeID},
+		Help:        "Participants disconnected for repeatedly exceeding their signal limits.",
+	})
+
+	prometheus.MustRegister(promSignalDropped)
+	prometheus.MustRegister(promSignalDisconnects)
+}
+
+// AddSignalRequestDropped counts a participant's signal request rejected for exceeding its limits
+func AddSignalRequestDropped(requestType string, reason string) {
+	promSignalDropped.WithLabelValues(requestType, reason).Inc()
+}
+
+func AddSignalLimitDisconnect() {
+	promSignalDisconnects.Inc()
+}
@@ -0,0 +1,276 @@
+package telemetry
+
+import (
+	"encoding/json"
+	"path"
+	"sync"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry/prometheus"
+)
+
+const (
+	defaultRoomMetricsMaxRooms        = 50
+	defaultRoomMetricsMaxParticipants = 50
+)
+
+type measuredRoom struct {
+	participants int
+	labelled     int
+}
+
+type measuredParticipant struct {
+	roomName livekit.RoomName
+	identity livekit.ParticipantIdentity
+	// participants beyond the per room cap only count towards room series
+	labelled bool
+}
+
+type participantQuality struct {
+	rtt    uint32
+	jitter uint32
+	score  float32
+	tracks int
+}
+
+// RoomMetrics maintains Prometheus series labelled by room and participant for selected rooms.
+// cardinality is bounded by caps on measured rooms and labelled participants per room,
+// series are deleted when the participant leaves or the room closes
+type RoomMetrics struct {
+	roomNames       []string
+	metadataKey     string
+	maxRooms        int
+	maxParticipants int
+
+	lock         sync.Mutex
+	rooms        map[livekit.RoomName]*measuredRoom
+	participants map[livekit.ParticipantID]*measuredParticipant
+}
+
+func NewRoomMetrics(conf *config.Config) *RoomMetrics {
+	if !conf.RoomMetrics.Enabled {
+		return nil
+	}
+
+	m := &RoomMetrics{
+		roomNames:       conf.RoomMetrics.RoomNames,
+		metadataKey:     conf.RoomMetrics.MetadataKey,
+		maxRooms:        conf.RoomMetrics.MaxRooms,
+		maxParticipants: conf.RoomMetrics.MaxParticipantsPerRoom,
+		rooms:           make(map[livekit.RoomName]*measuredRoom),
+		participants:    make(map[livekit.ParticipantID]*measuredParticipant),
+	}
+	if m.maxRooms <= 0 {
+		m.maxRooms = defaultRoomMetricsMaxRooms
+	}
+	if m.maxParticipants <= 0 {
+		m.maxParticipants = defaultRoomMetricsMaxParticipants
+	}
+	return m
+}
+
+func (m *RoomMetrics) RoomStarted(room *livekit.Room) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.getOrAddRoomLocked(room)
+}
+
+func (m *RoomMetrics) RoomEnded(room *livekit.Room) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	roomName := livekit.RoomName(room.Name)
+	if m.rooms[roomName] == nil {
+		return
+	}
+	delete(m.rooms, roomName)
+	for participantID, p := range m.participants {
+		if p.roomName == roomName {
+			delete(m.participants, participantID)
+		}
+	}
+
+	prometheus.DeleteRoomMetrics(room.Name)
+}
+
+func (m *RoomMetrics) ParticipantJoined(room *livekit.Room, participant *livekit.ParticipantInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// rooms may also opt in through metadata updated after they started
+	r := m.getOrAddRoomLocked(room)
+	if r == nil {
+		return
+	}
+
+	p := &measuredParticipant{
+		roomName: livekit.RoomName(room.Name),
+		identity: livekit.ParticipantIdentity(participant.Identity),
+	}
+	if r.labelled < m.maxParticipants {
+		p.labelled = true
+		r.labelled++
+	}
+	m.participants[livekit.ParticipantID(participant.Sid)] = p
+
+	r.participants++
+	prometheus.SetRoomParticipants(room.Name, r.participants)
+}
+
+func (m *RoomMetrics) ParticipantLeft(participantID livekit.ParticipantID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	p := m.participants[participantID]
+	if p == nil {
+		return
+	}
+	delete(m.participants, participantID)
+
+	r := m.rooms[p.roomName]
+	if r == nil {
+		return
+	}
+	r.participants--
+	prometheus.SetRoomParticipants(string(p.roomName), r.participants)
+
+	if p.labelled {
+		r.labelled--
+		// series are keyed by identity, keep them when the participant has already rejoined
+		if !m.isIdentityLabelledLocked(p.roomName, p.identity) {
+			prometheus.DeleteRoomParticipantMetrics(string(p.roomName), string(p.identity))
+		}
+	}
+}
+
+func (m *RoomMetrics) TrackPublished(participantID livekit.ParticipantID, track *livekit.TrackInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if p := m.participants[participantID]; p != nil {
+		prometheus.AddRoomPublishedTrack(string(p.roomName), track.Type.String(), 1)
+	}
+}
+
+func (m *RoomMetrics) TrackUnpublished(participantID livekit.ParticipantID, track *livekit.TrackInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if p := m.participants[participantID]; p != nil {
+		prometheus.AddRoomPublishedTrack(string(p.roomName), track.Type.String(), -1)
+	}
+}
+
+// AddStats records stats of a reporting interval
+func (m *RoomMetrics) AddStats(stats []*livekit.AnalyticsStat) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	type qualityKey struct {
+		participantID livekit.ParticipantID
+		direction     prometheus.Direction
+	}
+	qualities := make(map[qualityKey]*participantQuality)
+	for _, stat := range stats {
+		participantID := livekit.ParticipantID(stat.ParticipantId)
+		p := m.participants[participantID]
+		if p == nil {
+			continue
+		}
+
+		direction := prometheus.Incoming
+		if stat.Kind == livekit.StreamType_DOWNSTREAM {
+			direction = prometheus.Outgoing
+		}
+
+		var bytes uint64
+		var packetsLost, rtt, jitter uint32
+		for _, stream := range stat.Streams {
+			bytes += stream.PrimaryBytes + stream.RetransmitBytes + stream.PaddingBytes
+			packetsLost += stream.PacketsLost
+			if stream.Rtt > rtt {
+				rtt = stream.Rtt
+			}
+			if stream.Jitter > jitter {
+				jitter = stream.Jitter
+			}
+		}
+
+		prometheus.AddRoomBytes(string(p.roomName), direction, bytes, packetsLost)
+		if !p.labelled {
+			continue
+		}
+		prometheus.AddRoomParticipantBytes(string(p.roomName), string(p.identity), direction, bytes, packetsLost)
+
+		key := qualityKey{participantID, direction}
+		q := qualities[key]
+		if q == nil {
+			q = &participantQuality{}
+			qualities[key] = q
+		}
+		if rtt > q.rtt {
+			q.rtt = rtt
+		}
+		if jitter > q.jitter {
+			q.jitter = jitter
+		}
+		q.score += stat.Score
+		q.tracks++
+	}
+
+	for key, q := range qualities {
+		p := m.participants[key.participantID]
+		prometheus.SetRoomParticipantQuality(string(p.roomName), string(p.identity), key.direction, q.rtt, q.jitter, q.score/float32(q.tracks))
+	}
+}
+
+func (m *RoomMetrics) getOrAddRoomLocked(room *livekit.Room) *measuredRoom {
+	roomName := livekit.RoomName(room.Name)
+	if r := m.rooms[roomName]; r != nil {
+		return r
+	}
+
+	if !m.isSelected(room) {
+		return nil
+	}
+	if len(m.rooms) >= m.maxRooms {
+		logger.Debugw("room metrics limit reached, skipping room", "room", room.Name, "limit", m.maxRooms)
+		return nil
+	}
+
+	r := &measuredRoom{}
+	m.rooms[roomName] = r
+	prometheus.SetRoomParticipants(room.Name, 0)
+	return r
+}
+
+func (m *RoomMetrics) isSelected(room *livekit.Room) bool {
+	for _, pattern := range m.roomNames {
+		if matched, _ := path.Match(pattern, room.Name); matched {
+			return true
+		}
+	}
+
+	if m.metadataKey != "" && room.Metadata != "" {
+		metadata := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(room.Metadata), &metadata); err == nil {
+			if optIn, ok := metadata[m.metadataKey].(bool); ok && optIn {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (m *RoomMetrics) isIdentityLabelledLocked(roomName livekit.RoomName, identity livekit.ParticipantIdentity) bool {
+	for _, p := range m.participants {
+		if p.labelled && p.roomName == roomName && p.identity == identity {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,21 @@ import (
 )
 
 type FakeTelemetryService struct {
+	DataThrottledStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, string)
+	dataThrottledMutex       sync.RWMutex
+	dataThrottledArgsForCall []struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+		arg4 string
+	}
+	DurationLimitReachedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo)
+	durationLimitReachedMutex       sync.RWMutex
+	durationLimitReachedArgsForCall []struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}
 	EgressEndedStub        func(context.Context, *livekit.EgressInfo)
 	egressEndedMutex       sync.RWMutex
 	egressEndedArgsForCall []struct {
@@ -30,7 +45,7 @@ type FakeTelemetryService struct {
 		arg3 *livekit.ParticipantInfo
 		arg4 *livekit.AnalyticsClientMeta
 	}
-	ParticipantJoinedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta)
+	ParticipantJoinedStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta, string)
 	participantJoinedMutex       sync.RWMutex
 	participantJoinedArgsForCall []struct {
 		arg1 context.Context
@@ -38,6 +53,7 @@ type FakeTelemetryService struct {
 		arg3 *livekit.ParticipantInfo
 		arg4 *livekit.ClientInfo
 		arg5 *livekit.AnalyticsClientMeta
+		arg6 string
 	}
 	ParticipantLeftStub        func(context.Context, *livekit.Room, *livekit.ParticipantInfo)
 	participantLeftMutex       sync.RWMutex
@@ -118,6 +134,75 @@ type FakeTelemetryService struct {
 	invocationsMutex sync.RWMutex
 }
 
+func (fake *FakeTelemetryService) DataThrottled(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 string) {
+	fake.dataThrottledMutex.Lock()
+	fake.dataThrottledArgsForCall = append(fake.dataThrottledArgsForCall, struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.DataThrottledStub
+	fake.recordInvocation("DataThrottled", []interface{}{arg1, arg2, arg3, arg4})
+	fake.dataThrottledMutex.Unlock()
+	if stub != nil {
+		fake.DataThrottledStub(arg1, arg2, arg3, arg4)
+	}
+}
+
+func (fake *FakeTelemetryService) DataThrottledCallCount() int {
+	fake.dataThrottledMutex.RLock()
+	defer fake.dataThrottledMutex.RUnlock()
+	return len(fake.dataThrottledArgsForCall)
+}
+
+func (fake *FakeTelemetryService) DataThrottledCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo, string)) {
+	fake.dataThrottledMutex.Lock()
+	defer fake.dataThrottledMutex.Unlock()
+	fake.DataThrottledStub = stub
+}
+
+func (fake *FakeTelemetryService) DataThrottledArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo, string) {
+	fake.dataThrottledMutex.RLock()
+	defer fake.dataThrottledMutex.RUnlock()
+	argsForCall := fake.dataThrottledArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *FakeTelemetryService) DurationLimitReached(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo) {
+	fake.durationLimitReachedMutex.Lock()
+	fake.durationLimitReachedArgsForCall = append(fake.durationLimitReachedArgsForCall, struct {
+		arg1 context.Context
+		arg2 *livekit.Room
+		arg3 *livekit.ParticipantInfo
+	}{arg1, arg2, arg3})
+	stub := fake.DurationLimitReachedStub
+	fake.recordInvocation("DurationLimitReached", []interface{}{arg1, arg2, arg3})
+	fake.durationLimitReachedMutex.Unlock()
+	if stub != nil {
+		fake.DurationLimitReachedStub(arg1, arg2, arg3)
+	}
+}
+
+func (fake *FakeTelemetryService) DurationLimitReachedCallCount() int {
+	fake.durationLimitReachedMutex.RLock()
+	defer fake.durationLimitReachedMutex.RUnlock()
+	return len(fake.durationLimitReachedArgsForCall)
+}
+
+func (fake *FakeTelemetryService) DurationLimitReachedCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo)) {
+	fake.durationLimitReachedMutex.Lock()
+	defer fake.durationLimitReachedMutex.Unlock()
+	fake.DurationLimitReachedStub = stub
+}
+
+func (fake *FakeTelemetryService) DurationLimitReachedArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo) {
+	fake.durationLimitReachedMutex.RLock()
+	defer fake.durationLimitReachedMutex.RUnlock()
+	argsForCall := fake.durationLimitReachedArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
 func (fake *FakeTelemetryService) EgressEnded(arg1 context.Context, arg2 *livekit.EgressInfo) {
 	fake.egressEndedMutex.Lock()
 	fake.egressEndedArgsForCall = append(fake.egressEndedArgsForCall, struct {
@@ -219,7 +304,7 @@ func (fake *FakeTelemetryService) ParticipantActiveArgsForCall(i int) (context.C
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
-func (fake *FakeTelemetryService) ParticipantJoined(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 *livekit.ClientInfo, arg5 *livekit.AnalyticsClientMeta) {
+func (fake *FakeTelemetryService) ParticipantJoined(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo, arg4 *livekit.ClientInfo, arg5 *livekit.AnalyticsClientMeta, arg6 string) {
 	fake.participantJoinedMutex.Lock()
 	fake.participantJoinedArgsForCall = append(fake.participantJoinedArgsForCall, struct {
 		arg1 context.Context
@@ -227,12 +312,13 @@ func (fake *FakeTelemetryService) ParticipantJoined(arg1 context.Context, arg2 *
 		arg3 *livekit.ParticipantInfo
 		arg4 *livekit.ClientInfo
 		arg5 *livekit.AnalyticsClientMeta
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg6 string
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.ParticipantJoinedStub
-	fake.recordInvocation("ParticipantJoined", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("ParticipantJoined", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.participantJoinedMutex.Unlock()
 	if stub != nil {
-		fake.ParticipantJoinedStub(arg1, arg2, arg3, arg4, arg5)
+		fake.ParticipantJoinedStub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 }
 
@@ -242,17 +328,17 @@ func (fake *FakeTelemetryService) ParticipantJoinedCallCount() int {
 	return len(fake.participantJoinedArgsForCall)
 }
 
-func (fake *FakeTelemetryService) ParticipantJoinedCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta)) {
+func (fake *FakeTelemetryService) ParticipantJoinedCalls(stub func(context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta, string)) {
 	fake.participantJoinedMutex.Lock()
 	defer fake.participantJoinedMutex.Unlock()
 	fake.ParticipantJoinedStub = stub
 }
 
-func (fake *FakeTelemetryService) ParticipantJoinedArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta) {
+func (fake *FakeTelemetryService) ParticipantJoinedArgsForCall(i int) (context.Context, *livekit.Room, *livekit.ParticipantInfo, *livekit.ClientInfo, *livekit.AnalyticsClientMeta, string) {
 	fake.participantJoinedMutex.RLock()
 	defer fake.participantJoinedMutex.RUnlock()
 	argsForCall := fake.participantJoinedArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeTelemetryService) ParticipantLeft(arg1 context.Context, arg2 *livekit.Room, arg3 *livekit.ParticipantInfo) {
@@ -603,6 +689,10 @@ func (fake *FakeTelemetryService) TrackUnsubscribedArgsForCall(i int) (context.C
 func (fake *FakeTelemetryService) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
+	fake.dataThrottledMutex.RLock()
+	defer fake.dataThrottledMutex.RUnlock()
+	fake.durationLimitReachedMutex.RLock()
+	defer fake.durationLimitReachedMutex.RUnlock()
 	fake.egressEndedMutex.RLock()
 	defer fake.egressEndedMutex.RUnlock()
 	fake.egressStartedMutex.RLock()
@@ -18,7 +18,7 @@ type TelemetryService interface {
 	// events
 	RoomStarted(ctx context.Context, room *livekit.Room)
 	RoomEnded(ctx context.Context, room *livekit.Room)
-	ParticipantJoined(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo, clientInfo *livekit.ClientInfo, clientMeta *livekit.AnalyticsClientMeta)
+	ParticipantJoined(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo, clientInfo *livekit.ClientInfo, clientMeta *livekit.AnalyticsClientMeta, apiKey string)
 	ParticipantActive(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo, clientMeta *livekit.AnalyticsClientMeta)
 	ParticipantLeft(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo)
 	TrackPublished(ctx context.Context, participantID livekit.ParticipantID, identity livekit.ParticipantIdentity, track *livekit.TrackInfo)
@@ -29,19 +29,34 @@ type TelemetryService interface {
 	TrackMaxSubscribedVideoQuality(ctx context.Context, participantID livekit.ParticipantID, track *livekit.TrackInfo, mime string, maxQuality livekit.VideoQuality)
 	EgressStarted(ctx context.Context, info *livekit.EgressInfo)
 	EgressEnded(ctx context.Context, info *livekit.EgressInfo)
-}
+	// DurationLimitReached is called when a room, or a participant when set, reaches its max duration
+	DurationLimitReached(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo)
+	// DataThrottled is called with the reason data packets of a participant start being dropped for exceeding its
+	// limits
+	DataThrottled(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo, reason string)
+}
+
+const (
+	// webhook events sent when duration limits are reached, these aren't defined by the protocol
+	EventRoomDurationExceeded    = "room_duration_exceeded"
+	EventSessionDurationExceeded = "participant_session_duration_exceeded"
+	// webhook event sent when data packets of a participant start being dropped, with the reason in its "reason" field
+	EventDataThrottled = "participant_data_throttled"
+)
 
 type telemetryService struct {
 	internalService TelemetryServiceInternal
+	usage           *UsageMeter
 	jobsChan        chan func()
 }
 
 // queue should be sufficiently large to avoid blocking
 const jobQueueBufferSize = 10000
 
-func NewTelemetryService(notifier webhook.Notifier, analytics AnalyticsService) TelemetryService {
+func NewTelemetryService(notifier webhook.Notifier, analytics AnalyticsService, usage *UsageMeter, roomMetrics *RoomMetrics) TelemetryService {
 	t := &telemetryService{
-		internalService: NewTelemetryServiceInternal(notifier, analytics),
+		internalService: NewTelemetryServiceInternal(notifier, analytics, usage, roomMetrics),
+		usage:           usage,
 		jobsChan:        make(chan func(), jobQueueBufferSize),
 	}
 
@@ -57,12 +72,21 @@ func (t *telemetryService) run() {
 	cleanupTicker := time.NewTicker(time.Minute)
 	defer cleanupTicker.Stop()
 
+	var usageFlush <-chan time.Time
+	if t.usage != nil {
+		usageTicker := time.NewTicker(t.usage.FlushInterval())
+		defer usageTicker.Stop()
+		usageFlush = usageTicker.C
+	}
+
 	for {
 		select {
 		case <-ticker.C:
 			t.internalService.SendAnalytics()
 		case <-cleanupTicker.C:
 			t.internalService.CleanupWorkers()
+		case <-usageFlush:
+			t.usage.Flush()
 		case op := <-t.jobsChan:
 			op()
 		}
@@ -97,9 +121,9 @@ func (t *telemetryService) RoomEnded(ctx context.Context, room *livekit.Room) {
 }
 
 func (t *telemetryService) ParticipantJoined(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo,
-	clientInfo *livekit.ClientInfo, clientMeta *livekit.AnalyticsClientMeta) {
+	clientInfo *livekit.ClientInfo, clientMeta *livekit.AnalyticsClientMeta, apiKey string) {
 	t.enqueue(func() {
-		t.internalService.ParticipantJoined(ctx, room, participant, clientInfo, clientMeta)
+		t.internalService.ParticipantJoined(ctx, room, participant, clientInfo, clientMeta, apiKey)
 	})
 }
 
@@ -162,3 +186,15 @@ func (t *telemetryService) EgressEnded(ctx context.Context, info *livekit.Egress
 		t.internalService.EgressEnded(ctx, info)
 	})
 }
+
+func (t *telemetryService) DurationLimitReached(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo) {
+	t.enqueue(func() {
+		t.internalService.DurationLimitReached(ctx, room, participant)
+	})
+}
+
+func (t *telemetryService) DataThrottled(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo, reason string) {
+	t.enqueue(func() {
+		t.internalService.DataThrottled(ctx, room, participant, reason)
+	})
+}
@@ -38,15 +38,19 @@ type telemetryServiceInternal struct {
 	workers    []*StatsWorker
 	workersIdx map[livekit.ParticipantID]int
 
-	analytics AnalyticsService
+	analytics   AnalyticsService
+	usage       *UsageMeter
+	roomMetrics *RoomMetrics
 }
 
-func NewTelemetryServiceInternal(notifier webhook.Notifier, analytics AnalyticsService) TelemetryServiceInternal {
+func NewTelemetryServiceInternal(notifier webhook.Notifier, analytics AnalyticsService, usage *UsageMeter, roomMetrics *RoomMetrics) TelemetryServiceInternal {
 	return &telemetryServiceInternal{
 		notifier:    notifier,
 		webhookPool: workerpool.New(maxWebhookWorkers),
 		workersIdx:  make(map[livekit.ParticipantID]int),
 		analytics:   analytics,
+		usage:       usage,
+		roomMetrics: roomMetrics,
 	}
 }
 
@@ -94,6 +98,12 @@ func (t *telemetryServiceInternal) TrackStats(streamType livekit.StreamType, par
 }
 
 func (t *telemetryServiceInternal) Report(ctx context.Context, stats []*livekit.AnalyticsStat) {
+	if t.usage != nil {
+		t.usage.AddStats(stats)
+	}
+	if t.roomMetrics != nil {
+		t.roomMetrics.AddStats(stats)
+	}
 	t.analytics.SendStats(ctx, stats)
 }
 
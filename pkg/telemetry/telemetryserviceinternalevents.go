@@ -2,8 +2,10 @@ package telemetry
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
+	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/livekit/protocol/livekit"
@@ -16,6 +18,9 @@ import (
 
 func (t *telemetryServiceInternal) RoomStarted(ctx context.Context, room *livekit.Room) {
 	prometheus.RoomStarted()
+	if t.roomMetrics != nil {
+		t.roomMetrics.RoomStarted(room)
+	}
 
 	t.notifyEvent(ctx, &livekit.WebhookEvent{
 		Event: webhook.EventRoomStarted,
@@ -31,6 +36,9 @@ func (t *telemetryServiceInternal) RoomStarted(ctx context.Context, room *liveki
 
 func (t *telemetryServiceInternal) RoomEnded(ctx context.Context, room *livekit.Room) {
 	prometheus.RoomEnded(time.Unix(room.CreationTime, 0))
+	if t.roomMetrics != nil {
+		t.roomMetrics.RoomEnded(room)
+	}
 
 	t.notifyEvent(ctx, &livekit.WebhookEvent{
 		Event: webhook.EventRoomFinished,
@@ -51,6 +59,7 @@ func (t *telemetryServiceInternal) ParticipantJoined(
 	participant *livekit.ParticipantInfo,
 	clientInfo *livekit.ClientInfo,
 	clientMeta *livekit.AnalyticsClientMeta,
+	apiKey string,
 ) {
 	prometheus.IncrementParticipantJoin(1)
 
@@ -83,6 +92,13 @@ func (t *telemetryServiceInternal) ParticipantJoined(
 
 	prometheus.AddParticipant()
 
+	if t.usage != nil {
+		t.usage.ParticipantJoined(livekit.ParticipantID(participant.Sid), livekit.RoomName(room.Name), apiKey)
+	}
+	if t.roomMetrics != nil {
+		t.roomMetrics.ParticipantJoined(room, participant)
+	}
+
 	t.analytics.SendEvent(ctx, &livekit.AnalyticsEvent{
 		Type:          livekit.AnalyticsEventType_PARTICIPANT_JOINED,
 		Timestamp:     timestamppb.Now(),
@@ -119,6 +135,13 @@ func (t *telemetryServiceInternal) ParticipantLeft(ctx context.Context, room *li
 		w.Close()
 	}
 
+	if t.usage != nil {
+		t.usage.ParticipantLeft(livekit.ParticipantID(participant.Sid))
+	}
+	if t.roomMetrics != nil {
+		t.roomMetrics.ParticipantLeft(livekit.ParticipantID(participant.Sid))
+	}
+
 	prometheus.SubParticipant()
 
 	t.notifyEvent(ctx, &livekit.WebhookEvent{
@@ -139,6 +162,12 @@ func (t *telemetryServiceInternal) ParticipantLeft(ctx context.Context, room *li
 
 func (t *telemetryServiceInternal) TrackPublished(ctx context.Context, participantID livekit.ParticipantID, identity livekit.ParticipantIdentity, track *livekit.TrackInfo) {
 	prometheus.AddPublishedTrack(track.Type.String())
+	if t.usage != nil {
+		t.usage.TrackPublished(participantID, track)
+	}
+	if t.roomMetrics != nil {
+		t.roomMetrics.TrackPublished(participantID, track)
+	}
 
 	roomID, roomName := t.getRoomDetails(participantID)
 	t.notifyEvent(ctx, &livekit.WebhookEvent{
@@ -206,6 +235,12 @@ func (t *telemetryServiceInternal) TrackUnpublished(ctx context.Context, partici
 	}
 
 	prometheus.SubPublishedTrack(track.Type.String())
+	if t.usage != nil {
+		t.usage.TrackUnpublished(participantID, track)
+	}
+	if t.roomMetrics != nil {
+		t.roomMetrics.TrackUnpublished(participantID, track)
+	}
 
 	t.notifyEvent(ctx, &livekit.WebhookEvent{
 		Event: webhook.EventTrackUnpublished,
@@ -233,6 +268,9 @@ func (t *telemetryServiceInternal) TrackUnpublished(ctx context.Context, partici
 func (t *telemetryServiceInternal) TrackSubscribed(ctx context.Context, participantID livekit.ParticipantID, track *livekit.TrackInfo,
 	publisher *livekit.ParticipantInfo) {
 	prometheus.AddSubscribedTrack(track.Type.String())
+	if t.usage != nil {
+		t.usage.TrackSubscribed(participantID, track)
+	}
 
 	roomID, roomName := t.getRoomDetails(participantID)
 	t.analytics.SendEvent(ctx, &livekit.AnalyticsEvent{
@@ -248,6 +286,9 @@ func (t *telemetryServiceInternal) TrackSubscribed(ctx context.Context, particip
 
 func (t *telemetryServiceInternal) TrackUnsubscribed(ctx context.Context, participantID livekit.ParticipantID, track *livekit.TrackInfo) {
 	prometheus.SubSubscribedTrack(track.Type.String())
+	if t.usage != nil {
+		t.usage.TrackUnsubscribed(participantID, track)
+	}
 
 	roomID, roomName := t.getRoomDetails(participantID)
 	t.analytics.SendEvent(ctx, &livekit.AnalyticsEvent{
@@ -268,6 +309,11 @@ func (t *telemetryServiceInternal) getRoomDetails(participantID livekit.Particip
 }
 
 func (t *telemetryServiceInternal) notifyEvent(ctx context.Context, event *livekit.WebhookEvent) {
+	t.notifyPayload(ctx, event, event)
+}
+
+// notifyPayload sends a webhook event encoded as payload, which may add fields the event doesn't define
+func (t *telemetryServiceInternal) notifyPayload(ctx context.Context, event *livekit.WebhookEvent, payload interface{}) {
 	if t.notifier == nil {
 		return
 	}
@@ -276,12 +322,34 @@ func (t *telemetryServiceInternal) notifyEvent(ctx context.Context, event *livek
 	event.Id = utils.NewGuid("EV_")
 
 	t.webhookPool.Submit(func() {
-		if err := t.notifier.Notify(ctx, event); err != nil {
+		if err := t.notifier.Notify(ctx, payload); err != nil {
 			logger.Warnw("failed to notify webhook", err, "event", event.Event)
 		}
 	})
 }
 
+// webhookEventWithReason encodes a webhook event along with a "reason" field, which WebhookEvent doesn't define.
+// Receivers discarding unknown fields decode it as a regular event
+type webhookEventWithReason struct {
+	event  *livekit.WebhookEvent
+	reason string
+}
+
+func (e *webhookEventWithReason) MarshalJSON() ([]byte, error) {
+	encoded, err := protojson.Marshal(e.event)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	if fields["reason"], err = json.Marshal(e.reason); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
 func (t *telemetryServiceInternal) EgressStarted(ctx context.Context, info *livekit.EgressInfo) {
 	t.notifyEvent(ctx, &livekit.WebhookEvent{
 		Event:      webhook.EventEgressStarted,
@@ -309,3 +377,24 @@ func (t *telemetryServiceInternal) EgressEnded(ctx context.Context, info *liveki
 		RoomId:    info.RoomId,
 	})
 }
+
+func (t *telemetryServiceInternal) DurationLimitReached(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo) {
+	event := EventRoomDurationExceeded
+	if participant != nil {
+		event = EventSessionDurationExceeded
+	}
+	t.notifyEvent(ctx, &livekit.WebhookEvent{
+		Event:       event,
+		Room:        room,
+		Participant: participant,
+	})
+}
+
+func (t *telemetryServiceInternal) DataThrottled(ctx context.Context, room *livekit.Room, participant *livekit.ParticipantInfo, reason string) {
+	event := &livekit.WebhookEvent{
+		Event:       EventDataThrottled,
+		Room:        room,
+		Participant: participant,
+	}
+	t.notifyPayload(ctx, event, &webhookEventWithReason{event: event, reason: reason})
+}
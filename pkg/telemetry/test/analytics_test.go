@@ -0,0 +1,89 @@
+package telemetrytest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
+)
+
+func Test_AnalyticsBatchedToSink(t *testing.T) {
+	sink := &telemetryfakes.FakeAnalyticsSink{}
+	conf := &config.AnalyticsConfig{BufferSize: 10, BatchSize: 2, FlushInterval: time.Hour}
+	sut := telemetry.NewBatchingAnalyticsService(conf, "node", sink)
+
+	sut.SendStats(context.Background(), []*livekit.AnalyticsStat{{RoomId: "room"}})
+	sut.SendEvent(context.Background(), &livekit.AnalyticsEvent{RoomId: "room"})
+	require.Never(t, func() bool {
+		return sink.WriteStatsCallCount() > 0 || sink.WriteEventsCallCount() > 0
+	}, 100*time.Millisecond, 10*time.Millisecond)
+
+	sut.SendStats(context.Background(), []*livekit.AnalyticsStat{{RoomId: "room"}})
+	require.Eventually(t, func() bool {
+		return sink.WriteStatsCallCount() == 1
+	}, time.Second, 10*time.Millisecond)
+
+	stats := sink.WriteStatsArgsForCall(0)
+	require.Len(t, stats.Stats, 2)
+	for _, stat := range stats.Stats {
+		require.Equal(t, "node", stat.Node)
+	}
+	require.Zero(t, sink.WriteEventsCallCount())
+}
+
+func Test_AnalyticsFlushedOnStop(t *testing.T) {
+	sink := &telemetryfakes.FakeAnalyticsSink{}
+	conf := &config.AnalyticsConfig{BufferSize: 10, BatchSize: 10, FlushInterval: time.Hour}
+	sut := telemetry.NewBatchingAnalyticsService(conf, "node", sink)
+
+	sut.SendStats(context.Background(), []*livekit.AnalyticsStat{{RoomId: "room"}})
+	sut.SendEvent(context.Background(), &livekit.AnalyticsEvent{RoomId: "room"})
+	sut.Stop()
+
+	require.Equal(t, 1, sink.WriteStatsCallCount())
+	require.Equal(t, 1, sink.WriteEventsCallCount())
+	require.Equal(t, 1, sink.CloseCallCount())
+}
+
+func Test_AnalyticsFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analytics.jsonl")
+	conf := &config.Config{Analytics: config.AnalyticsConfig{
+		Sink:          telemetry.AnalyticsSinkFile,
+		BufferSize:    10,
+		BatchSize:     1,
+		FlushInterval: time.Second,
+		File:          config.AnalyticsFileConfig{Path: path},
+	}}
+	sut, err := telemetry.NewAnalyticsService(conf, &livekit.Node{Id: "node"})
+	require.NoError(t, err)
+
+	sut.SendEvent(context.Background(), &livekit.AnalyticsEvent{RoomId: "room", Type: livekit.AnalyticsEventType_ROOM_CREATED})
+
+	var data []byte
+	require.Eventually(t, func() bool {
+		data, _ = os.ReadFile(path)
+		return len(data) > 0 && strings.HasSuffix(string(data), "\n")
+	}, time.Second, 10*time.Millisecond)
+
+	events := &livekit.AnalyticsEvents{}
+	require.NoError(t, protojson.Unmarshal([]byte(strings.TrimSpace(string(data))), events))
+	require.Len(t, events.Events, 1)
+	require.Equal(t, "room", events.Events[0].RoomId)
+}
+
+func Test_AnalyticsUnknownSink(t *testing.T) {
+	conf := &config.Config{Analytics: config.AnalyticsConfig{Sink: "kafka"}}
+	_, err := telemetry.NewAnalyticsService(conf, &livekit.Node{Id: "node"})
+	require.Error(t, err)
+}
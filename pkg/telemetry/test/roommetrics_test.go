@@ -0,0 +1,118 @@
+package telemetrytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
+)
+
+func Test_RoomMetricsSelectedRooms(t *testing.T) {
+	conf := &config.Config{RoomMetrics: config.RoomMetricsConfig{
+		Enabled:                true,
+		RoomNames:              []string{"measured-*"},
+		MetadataKey:            "metrics",
+		MaxRooms:               2,
+		MaxParticipantsPerRoom: 1,
+	}}
+	metrics := telemetry.NewRoomMetrics(conf)
+	sut := telemetry.NewTelemetryServiceInternal(nil, &telemetryfakes.FakeAnalyticsService{}, nil, metrics)
+
+	measured := &livekit.Room{Sid: "RM_1", Name: "measured-1"}
+	optedIn := &livekit.Room{Sid: "RM_2", Name: "opted-in", Metadata: `{"metrics": true}`}
+	ignored := &livekit.Room{Sid: "RM_3", Name: "ignored"}
+	overCap := &livekit.Room{Sid: "RM_4", Name: "measured-2"}
+	for _, room := range []*livekit.Room{measured, optedIn, ignored, overCap} {
+		sut.RoomStarted(context.Background(), room)
+	}
+	require.Equal(t, []string{"measured-1", "opted-in"}, labelValues(t, "livekit_room_participants", "room"))
+
+	p1 := &livekit.ParticipantInfo{Sid: "PA_1", Identity: "p1"}
+	p2 := &livekit.ParticipantInfo{Sid: "PA_2", Identity: "p2"}
+	sut.ParticipantJoined(context.Background(), measured, p1, nil, nil, "")
+	sut.ParticipantJoined(context.Background(), measured, p2, nil, nil, "")
+	require.Equal(t, float64(2), metricValue(t, "livekit_room_participants", map[string]string{"room": "measured-1"}))
+
+	sut.TrackPublished(context.Background(), "PA_1", "p1", &livekit.TrackInfo{Sid: "TR_1", Type: livekit.TrackType_AUDIO})
+	require.Equal(t, float64(1), metricValue(t, "livekit_room_tracks_published", map[string]string{"room": "measured-1", "kind": "AUDIO"}))
+
+	for _, pID := range []livekit.ParticipantID{"PA_1", "PA_2"} {
+		sut.TrackStats(livekit.StreamType_UPSTREAM, pID, "TR_1", &livekit.AnalyticsStat{
+			Score:   4,
+			Streams: []*livekit.AnalyticsStream{{PrimaryBytes: 100, PacketsLost: 2, Rtt: 30, Jitter: 5}},
+		})
+	}
+	sut.SendAnalytics()
+	require.Equal(t, float64(200), metricValue(t, "livekit_room_bytes_total", map[string]string{"room": "measured-1", "direction": "incoming"}))
+	require.Equal(t, float64(4), metricValue(t, "livekit_room_packets_lost_total", map[string]string{"room": "measured-1", "direction": "incoming"}))
+	// only the first participant is labelled
+	require.Equal(t, []string{"p1"}, labelValues(t, "livekit_room_participant_bytes_total", "participant"))
+	require.Equal(t, float64(30), metricValue(t, "livekit_room_participant_rtt_ms", map[string]string{"participant": "p1"}))
+	require.Equal(t, float64(4), metricValue(t, "livekit_room_participant_connection_quality", map[string]string{"participant": "p1"}))
+
+	sut.ParticipantLeft(context.Background(), measured, p1)
+	require.Empty(t, labelValues(t, "livekit_room_participant_bytes_total", "participant"))
+	require.Equal(t, float64(1), metricValue(t, "livekit_room_participants", map[string]string{"room": "measured-1"}))
+
+	sut.RoomEnded(context.Background(), measured)
+	sut.RoomEnded(context.Background(), optedIn)
+	require.Empty(t, labelValues(t, "livekit_room_participants", "room"))
+	require.Empty(t, labelValues(t, "livekit_room_bytes_total", "room"))
+	require.Empty(t, labelValues(t, "livekit_room_tracks_published", "room"))
+}
+
+func Test_RoomMetricsDisabled(t *testing.T) {
+	require.Nil(t, telemetry.NewRoomMetrics(&config.Config{}))
+}
+
+func labelValues(t *testing.T, name string, label string) []string {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	var values []string
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, lp := range metric.GetLabel() {
+				if lp.GetName() == label {
+					values = append(values, lp.GetValue())
+				}
+			}
+		}
+	}
+	return values
+}
+
+func metricValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+	metrics:
+		for _, metric := range family.GetMetric() {
+			for _, lp := range metric.GetLabel() {
+				if v, ok := labels[lp.GetName()]; ok && v != lp.GetValue() {
+					continue metrics
+				}
+			}
+			if metric.GetGauge() != nil {
+				return metric.GetGauge().GetValue()
+			}
+			return metric.GetCounter().GetValue()
+		}
+	}
+	require.Fail(t, "metric not found", name)
+	return 0
+}
@@ -33,7 +33,7 @@ func Test_OnParticipantJoin_EventIsSent(t *testing.T) {
 	participantInfo := &livekit.ParticipantInfo{Sid: partSID}
 
 	// do
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, clientMeta)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, clientMeta, "")
 
 	// test
 	require.Equal(t, 1, fixture.analytics.SendEventCallCount())
@@ -141,7 +141,7 @@ func Test_OnParticipantActive_EventIsSent(t *testing.T) {
 	participantInfo := &livekit.ParticipantInfo{Sid: partSID}
 
 	// do
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, clientMeta)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, clientMeta, "")
 
 	// test
 	require.Equal(t, 1, fixture.analytics.SendEventCallCount())
@@ -191,7 +191,7 @@ func Test_OnTrackSubscribed_EventIsSent(t *testing.T) {
 	participantInfo := &livekit.ParticipantInfo{Sid: partSID}
 
 	// do
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, clientMeta)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, clientMeta, "")
 
 	// test
 	require.Equal(t, 1, fixture.analytics.SendEventCallCount())
@@ -20,7 +20,7 @@ type telemetryServiceFixture struct {
 func createFixture() *telemetryServiceFixture {
 	fixture := &telemetryServiceFixture{}
 	fixture.analytics = &telemetryfakes.FakeAnalyticsService{}
-	fixture.sut = telemetry.NewTelemetryServiceInternal(nil, fixture.analytics)
+	fixture.sut = telemetry.NewTelemetryServiceInternal(nil, fixture.analytics, nil, nil)
 	return fixture
 }
 
@@ -32,7 +32,7 @@ func Test_ParticipantAndRoomDataAreSentWithAnalytics(t *testing.T) {
 	partSID := livekit.ParticipantID("part1")
 	clientInfo := &livekit.ClientInfo{Sdk: 2}
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, nil, "")
 
 	// do
 	packet := 33
@@ -58,7 +58,7 @@ func Test_OnDownstreamPackets(t *testing.T) {
 	partSID := livekit.ParticipantID("part1")
 	clientInfo := &livekit.ClientInfo{Sdk: 2}
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, nil, "")
 
 	// do
 	packets := []int{33, 23}
@@ -89,7 +89,7 @@ func Test_OnDownstreamPackets_SeveralTracks(t *testing.T) {
 	partSID := livekit.ParticipantID("part1")
 	clientInfo := &livekit.ClientInfo{Sdk: 2}
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, clientInfo, nil, "")
 
 	// do
 	packet1 := 33
@@ -132,7 +132,7 @@ func Test_OnDownStreamStat(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := livekit.ParticipantID("part1")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// do
 	stat1 := &livekit.AnalyticsStat{
@@ -190,7 +190,7 @@ func Test_PacketLostDiffShouldBeSentToTelemetry(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := livekit.ParticipantID("part1")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// do
 	trackID := livekit.TrackID("trackID1")
@@ -238,7 +238,7 @@ func Test_OnDownStreamRTCP_SeveralTracks(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := livekit.ParticipantID("part1")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// do
 	trackID1 := livekit.TrackID("trackID1")
@@ -305,7 +305,7 @@ func Test_OnUpstreamStat(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := livekit.ParticipantID("part1")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// do
 	stat1 := &livekit.AnalyticsStat{
@@ -365,7 +365,7 @@ func Test_OnUpstreamRTCP_SeveralTracks(t *testing.T) {
 	partSID := livekit.ParticipantID("part1")
 	identity := livekit.ParticipantIdentity("part1Identity")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID), Identity: string(identity)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// there should be bytes reported so that stats are sent
 	totalBytes := 1
@@ -446,7 +446,7 @@ func Test_AnalyticsSentWhenParticipantLeaves(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := "part1"
 	participantInfo := &livekit.ParticipantInfo{Sid: partSID}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// do
 	fixture.sut.ParticipantLeft(context.Background(), room, participantInfo)
@@ -462,7 +462,7 @@ func Test_AddUpTrack(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := livekit.ParticipantID("part1")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// do
 	var totalBytes uint64 = 3
@@ -497,7 +497,7 @@ func Test_AddUpTrack_SeveralBuffers_Simulcast(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := livekit.ParticipantID("part1")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 	// do
 	trackID := livekit.TrackID("trackID")
 
@@ -534,7 +534,7 @@ func Test_BothDownstreamAndUpstreamStatsAreSentTogether(t *testing.T) {
 	room := &livekit.Room{}
 	partSID := livekit.ParticipantID("part1")
 	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
-	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil)
+	fixture.sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "")
 
 	// do
 	// upstream bytes
@@ -0,0 +1,92 @@
+package telemetrytest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/livekit/protocol/auth"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/telemetry/tracing"
+)
+
+func Test_TracingExportsSessionAcrossNodes(t *testing.T) {
+	var lock sync.Mutex
+	var spanNames []string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		req := &collectortrace.ExportTraceServiceRequest{}
+		require.NoError(t, proto.Unmarshal(body, req))
+
+		lock.Lock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, span := range ss.Spans {
+					spanNames = append(spanNames, span.Name)
+				}
+			}
+		}
+		lock.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	shutdown, err := tracing.Init(&config.TracingConfig{
+		Enabled:     true,
+		Endpoint:    strings.TrimPrefix(collector.URL, "http://"),
+		Insecure:    true,
+		SampleRatio: 1,
+	}, "node")
+	require.NoError(t, err)
+
+	// signal node
+	ctx, signalSpan := tracing.Start(context.Background(), "signal")
+	pi := routing.ParticipantInit{
+		Identity:     "participant",
+		Grants:       &auth.ClaimGrants{},
+		TraceContext: tracing.Inject(ctx),
+	}
+	ss, err := pi.ToStartSession("room", "connection")
+	require.NoError(t, err)
+	signalSpan.End()
+
+	// rtc node
+	rtcPI, err := routing.ParticipantInitFromStartSession(ss, "")
+	require.NoError(t, err)
+	rtcCtx, rtcSpan := tracing.Start(tracing.Extract(context.Background(), rtcPI.TraceContext), "rtc")
+	require.Equal(t, signalSpan.SpanContext().TraceID(), trace.SpanContextFromContext(rtcCtx).TraceID())
+
+	// spans of long lived objects may outlive their parent
+	_, childSpan := tracing.StartWithParent(rtcSpan.SpanContext(), "child")
+	rtcSpan.End()
+	childSpan.End()
+	require.Equal(t, signalSpan.SpanContext().TraceID(), childSpan.SpanContext().TraceID())
+
+	require.NoError(t, shutdown(context.Background()))
+
+	lock.Lock()
+	defer lock.Unlock()
+	require.ElementsMatch(t, []string{"signal", "rtc", "child"}, spanNames)
+}
+
+func Test_TracingDisabled(t *testing.T) {
+	shutdown, err := tracing.Init(&config.TracingConfig{}, "node")
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+}
@@ -0,0 +1,68 @@
+package telemetrytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/telemetry"
+	"github.com/livekit/livekit-server/pkg/telemetry/telemetryfakes"
+)
+
+func Test_UsageRolledUpPerRoomAndAPIKey(t *testing.T) {
+	store := &telemetryfakes.FakeUsageStore{}
+	conf := &config.Config{Usage: config.UsageConfig{Enabled: true}}
+	meter := telemetry.NewUsageMeter(conf, &livekit.Node{Id: "node"}, store)
+	require.NotNil(t, meter)
+	sut := telemetry.NewTelemetryServiceInternal(nil, &telemetryfakes.FakeAnalyticsService{}, meter, nil)
+
+	room := &livekit.Room{Sid: "RoomSid", Name: "RoomName"}
+	partSID := livekit.ParticipantID("part1")
+	participantInfo := &livekit.ParticipantInfo{Sid: string(partSID)}
+	sut.ParticipantJoined(context.Background(), room, participantInfo, nil, nil, "APIkey")
+
+	track := &livekit.TrackInfo{Sid: "track1", Type: livekit.TrackType_AUDIO}
+	sut.TrackPublished(context.Background(), partSID, "identity", track)
+	sut.TrackStats(livekit.StreamType_UPSTREAM, partSID, "track1", &livekit.AnalyticsStat{
+		Streams: []*livekit.AnalyticsStream{{PrimaryBytes: 100, RetransmitBytes: 20}},
+	})
+	sut.TrackStats(livekit.StreamType_DOWNSTREAM, partSID, "track2", &livekit.AnalyticsStat{
+		Streams: []*livekit.AnalyticsStream{{PrimaryBytes: 50}},
+	})
+	sut.SendAnalytics()
+	sut.ParticipantLeft(context.Background(), room, participantInfo)
+
+	meter.Flush()
+
+	require.Equal(t, 1, store.IncrementUsageCallCount())
+	_, records := store.IncrementUsageArgsForCall(0)
+	require.Len(t, records, 2)
+	for _, record := range records {
+		switch record.Kind {
+		case telemetry.UsageKindRoom:
+			require.Equal(t, "RoomName", record.Key)
+		case telemetry.UsageKindAPIKey:
+			require.Equal(t, "APIkey", record.Key)
+		}
+		require.Equal(t, "node", record.NodeID)
+		require.Equal(t, uint64(120), record.BytesIn)
+		require.Equal(t, uint64(50), record.BytesOut)
+		require.Greater(t, record.ParticipantMinutes, float64(0))
+		require.Greater(t, record.PublishedAudioMinutes, float64(0))
+		require.Zero(t, record.PublishedVideoMinutes)
+	}
+
+	// nothing accumulated since last flush
+	meter.Flush()
+	require.Equal(t, 1, store.IncrementUsageCallCount())
+}
+
+func Test_UsageMeterDisabled(t *testing.T) {
+	var node routing.LocalNode = &livekit.Node{Id: "node"}
+	require.Nil(t, telemetry.NewUsageMeter(&config.Config{}, node, &telemetryfakes.FakeUsageStore{}))
+}
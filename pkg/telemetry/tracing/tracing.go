@@ -0,0 +1,106 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.10.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/version"
+)
+
+const (
+	serviceName = "livekit-server"
+)
+
+var (
+	// spans are no-ops until Init installs a provider
+	tracer     = otel.Tracer(serviceName)
+	propagator = propagation.TraceContext{}
+)
+
+// Init installs a tracer provider exporting spans to an OTLP/HTTP collector.
+// the returned function flushes pending spans and must be called on shutdown
+func Init(conf *config.TracingConfig, nodeID string) (func(context.Context) error, error) {
+	if !conf.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	opts := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(conf.Endpoint),
+	}
+	if conf.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	if len(conf.Headers) != 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(conf.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceVersionKey.String(version.Version),
+			semconv.ServiceInstanceIDKey.String(nodeID),
+		)),
+	)
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Start creates a span as a child of the span in ctx, if any
+func Start(ctx context.Context, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return tracer.Start(ctx, spanName, opts...)
+}
+
+// StartWithParent creates a span as a child of a span that may have already ended,
+// used by long lived objects that outlive the request creating them
+func StartWithParent(parent trace.SpanContext, spanName string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return tracer.Start(trace.ContextWithSpanContext(context.Background(), parent), spanName, opts...)
+}
+
+// EndSpan records err, if any, and ends the span
+func EndSpan(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// RecordError records err, if any, marking the span as failed
+func RecordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// Inject serializes the trace context of ctx, to be carried across nodes
+func Inject(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// Extract returns a context continuing the trace serialized by Inject
+func Extract(ctx context.Context, carrier map[string]string) context.Context {
+	if len(carrier) == 0 {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.MapCarrier(carrier))
+}
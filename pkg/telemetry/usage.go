@@ -0,0 +1,307 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/routing"
+)
+
+const defaultUsageFlushInterval = time.Minute
+
+type UsageKind string
+
+const (
+	UsageKindRoom   UsageKind = "room"
+	UsageKindAPIKey UsageKind = "api_key"
+)
+
+// UsageStats is usage accumulated for a room or an API key
+type UsageStats struct {
+	ParticipantMinutes     float64 `json:"participant_minutes"`
+	PublishedAudioMinutes  float64 `json:"published_audio_minutes"`
+	PublishedVideoMinutes  float64 `json:"published_video_minutes"`
+	SubscribedAudioMinutes float64 `json:"subscribed_audio_minutes"`
+	SubscribedVideoMinutes float64 `json:"subscribed_video_minutes"`
+	BytesIn                uint64  `json:"bytes_in"`
+	BytesOut               uint64  `json:"bytes_out"`
+}
+
+func (u *UsageStats) Add(other *UsageStats) {
+	u.ParticipantMinutes += other.ParticipantMinutes
+	u.PublishedAudioMinutes += other.PublishedAudioMinutes
+	u.PublishedVideoMinutes += other.PublishedVideoMinutes
+	u.SubscribedAudioMinutes += other.SubscribedAudioMinutes
+	u.SubscribedVideoMinutes += other.SubscribedVideoMinutes
+	u.BytesIn += other.BytesIn
+	u.BytesOut += other.BytesOut
+}
+
+// UsageRecord is the usage of a room or an API key during a flush interval
+type UsageRecord struct {
+	Kind   UsageKind `json:"kind"`
+	Key    string    `json:"key"`
+	NodeID string    `json:"node_id"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	UsageStats
+}
+
+//go:generate go run github.com/maxbrunsfeld/counterfeiter/v6 . UsageStore
+type UsageStore interface {
+	// IncrementUsage adds the records to the accumulated usage of their rooms and API keys
+	IncrementUsage(ctx context.Context, records []*UsageRecord) error
+	// ListUsage returns accumulated usage of the given kind, keyed by room name or API key
+	ListUsage(ctx context.Context, kind UsageKind) (map[string]*UsageStats, error)
+}
+
+type trackUsage struct {
+	kind  livekit.TrackType
+	since time.Time
+}
+
+type participantUsage struct {
+	roomName   livekit.RoomName
+	apiKey     string
+	since      time.Time
+	published  map[livekit.TrackID]*trackUsage
+	subscribed map[livekit.TrackID]*trackUsage
+	pending    UsageStats
+}
+
+// UsageMeter accumulates usage of participants on this node, and periodically persists and exports it
+type UsageMeter struct {
+	nodeID        string
+	store         UsageStore
+	exporter      *usageExporter
+	flushInterval time.Duration
+
+	lock         sync.Mutex
+	participants map[livekit.ParticipantID]*participantUsage
+	// usage of participants that have left since last flush
+	leftRooms   map[string]*UsageStats
+	leftAPIKeys map[string]*UsageStats
+	lastFlush   time.Time
+}
+
+func NewUsageMeter(conf *config.Config, currentNode routing.LocalNode, store UsageStore) *UsageMeter {
+	if !conf.Usage.Enabled {
+		return nil
+	}
+
+	m := &UsageMeter{
+		nodeID:        currentNode.Id,
+		store:         store,
+		flushInterval: conf.Usage.FlushInterval,
+		participants:  make(map[livekit.ParticipantID]*participantUsage),
+		leftRooms:     make(map[string]*UsageStats),
+		leftAPIKeys:   make(map[string]*UsageStats),
+		lastFlush:     time.Now(),
+	}
+	if m.flushInterval <= 0 {
+		m.flushInterval = defaultUsageFlushInterval
+	}
+	if conf.Usage.ExportDir != "" {
+		m.exporter = newUsageExporter(conf.Usage.ExportDir, conf.Usage.ExportFormat)
+	}
+	return m
+}
+
+func (m *UsageMeter) FlushInterval() time.Duration {
+	return m.flushInterval
+}
+
+func (m *UsageMeter) ParticipantJoined(participantID livekit.ParticipantID, roomName livekit.RoomName, apiKey string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.participants[participantID] = &participantUsage{
+		roomName:   roomName,
+		apiKey:     apiKey,
+		since:      time.Now(),
+		published:  make(map[livekit.TrackID]*trackUsage),
+		subscribed: make(map[livekit.TrackID]*trackUsage),
+	}
+}
+
+func (m *UsageMeter) ParticipantLeft(participantID livekit.ParticipantID) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	p := m.participants[participantID]
+	if p == nil {
+		return
+	}
+	delete(m.participants, participantID)
+
+	p.accumulate(time.Now())
+	addUsage(m.leftRooms, string(p.roomName), &p.pending)
+	if p.apiKey != "" {
+		addUsage(m.leftAPIKeys, p.apiKey, &p.pending)
+	}
+}
+
+func (m *UsageMeter) TrackPublished(participantID livekit.ParticipantID, track *livekit.TrackInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if p := m.participants[participantID]; p != nil {
+		p.published[livekit.TrackID(track.Sid)] = &trackUsage{kind: track.Type, since: time.Now()}
+	}
+}
+
+func (m *UsageMeter) TrackUnpublished(participantID livekit.ParticipantID, track *livekit.TrackInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if p := m.participants[participantID]; p != nil {
+		trackID := livekit.TrackID(track.Sid)
+		if t := p.published[trackID]; t != nil {
+			p.pending.addTrackMinutes(t, time.Now(), false)
+			delete(p.published, trackID)
+		}
+	}
+}
+
+func (m *UsageMeter) TrackSubscribed(participantID livekit.ParticipantID, track *livekit.TrackInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if p := m.participants[participantID]; p != nil {
+		p.subscribed[livekit.TrackID(track.Sid)] = &trackUsage{kind: track.Type, since: time.Now()}
+	}
+}
+
+func (m *UsageMeter) TrackUnsubscribed(participantID livekit.ParticipantID, track *livekit.TrackInfo) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if p := m.participants[participantID]; p != nil {
+		trackID := livekit.TrackID(track.Sid)
+		if t := p.subscribed[trackID]; t != nil {
+			p.pending.addTrackMinutes(t, time.Now(), true)
+			delete(p.subscribed, trackID)
+		}
+	}
+}
+
+// AddStats accounts bytes of coalesced stats reported by StatsWorker
+func (m *UsageMeter) AddStats(stats []*livekit.AnalyticsStat) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, stat := range stats {
+		p := m.participants[livekit.ParticipantID(stat.ParticipantId)]
+		if p == nil {
+			continue
+		}
+
+		bytes := uint64(0)
+		for _, stream := range stat.Streams {
+			bytes += stream.PrimaryBytes + stream.RetransmitBytes + stream.PaddingBytes
+		}
+		if stat.Kind == livekit.StreamType_UPSTREAM {
+			p.pending.BytesIn += bytes
+		} else {
+			p.pending.BytesOut += bytes
+		}
+	}
+}
+
+// Flush persists and exports usage accumulated since the last flush
+func (m *UsageMeter) Flush() {
+	now := time.Now()
+
+	m.lock.Lock()
+	rooms := m.leftRooms
+	apiKeys := m.leftAPIKeys
+	m.leftRooms = make(map[string]*UsageStats)
+	m.leftAPIKeys = make(map[string]*UsageStats)
+	for _, p := range m.participants {
+		p.accumulate(now)
+		addUsage(rooms, string(p.roomName), &p.pending)
+		if p.apiKey != "" {
+			addUsage(apiKeys, p.apiKey, &p.pending)
+		}
+		p.pending = UsageStats{}
+	}
+	start := m.lastFlush
+	m.lastFlush = now
+	m.lock.Unlock()
+
+	records := make([]*UsageRecord, 0, len(rooms)+len(apiKeys))
+	for name, stats := range rooms {
+		records = append(records, m.newRecord(UsageKindRoom, name, start, now, stats))
+	}
+	for key, stats := range apiKeys {
+		records = append(records, m.newRecord(UsageKindAPIKey, key, start, now, stats))
+	}
+	if len(records) == 0 {
+		return
+	}
+
+	if m.store != nil {
+		if err := m.store.IncrementUsage(context.Background(), records); err != nil {
+			logger.Errorw("could not store usage", err)
+		}
+	}
+	if m.exporter != nil {
+		if err := m.exporter.Export(records); err != nil {
+			logger.Errorw("could not export usage", err)
+		}
+	}
+}
+
+func (m *UsageMeter) newRecord(kind UsageKind, key string, start, end time.Time, stats *UsageStats) *UsageRecord {
+	return &UsageRecord{
+		Kind:       kind,
+		Key:        key,
+		NodeID:     m.nodeID,
+		Start:      start,
+		End:        end,
+		UsageStats: *stats,
+	}
+}
+
+// accumulate moves time spent connected and on tracks up to now into pending usage
+func (p *participantUsage) accumulate(now time.Time) {
+	p.pending.ParticipantMinutes += now.Sub(p.since).Minutes()
+	p.since = now
+
+	for _, t := range p.published {
+		p.pending.addTrackMinutes(t, now, false)
+	}
+	for _, t := range p.subscribed {
+		p.pending.addTrackMinutes(t, now, true)
+	}
+}
+
+func (u *UsageStats) addTrackMinutes(t *trackUsage, now time.Time, subscribed bool) {
+	minutes := now.Sub(t.since).Minutes()
+	t.since = now
+
+	switch {
+	case t.kind == livekit.TrackType_AUDIO && subscribed:
+		u.SubscribedAudioMinutes += minutes
+	case t.kind == livekit.TrackType_AUDIO:
+		u.PublishedAudioMinutes += minutes
+	case t.kind == livekit.TrackType_VIDEO && subscribed:
+		u.SubscribedVideoMinutes += minutes
+	case t.kind == livekit.TrackType_VIDEO:
+		u.PublishedVideoMinutes += minutes
+	}
+}
+
+func addUsage(usage map[string]*UsageStats, key string, stats *UsageStats) {
+	existing := usage[key]
+	if existing == nil {
+		existing = &UsageStats{}
+		usage[key] = existing
+	}
+	existing.Add(stats)
+}
@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+const (
+	UsageExportFormatCSV   = "csv"
+	UsageExportFormatJSONL = "jsonl"
+)
+
+var usageCSVHeader = []string{
+	"start", "end", "node_id", "kind", "key",
+	"participant_minutes",
+	"published_audio_minutes", "published_video_minutes",
+	"subscribed_audio_minutes", "subscribed_video_minutes",
+	"bytes_in", "bytes_out",
+}
+
+// usageExporter appends usage records to a file per day
+type usageExporter struct {
+	dir    string
+	format string
+}
+
+func newUsageExporter(dir string, format string) *usageExporter {
+	if format != UsageExportFormatCSV {
+		format = UsageExportFormatJSONL
+	}
+	return &usageExporter{
+		dir:    dir,
+		format: format,
+	}
+}
+
+func (e *usageExporter) Export(records []*UsageRecord) error {
+	if err := os.MkdirAll(e.dir, 0755); err != nil {
+		return err
+	}
+
+	filename := filepath.Join(e.dir, fmt.Sprintf("usage-%s.%s", time.Now().UTC().Format("20060102"), e.format))
+	st, err := os.Stat(filename)
+	isNew := os.IsNotExist(err) || (err == nil && st.Size() == 0)
+
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	if e.format == UsageExportFormatCSV {
+		return writeUsageCSV(f, records, isNew)
+	}
+
+	encoder := json.NewEncoder(f)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeUsageCSV(f *os.File, records []*UsageRecord, withHeader bool) error {
+	w := csv.NewWriter(f)
+	if withHeader {
+		if err := w.Write(usageCSVHeader); err != nil {
+			return err
+		}
+	}
+
+	formatMinutes := func(minutes float64) string {
+		return strconv.FormatFloat(minutes, 'f', 4, 64)
+	}
+	for _, r := range records {
+		if err := w.Write([]string{
+			r.Start.UTC().Format(time.RFC3339),
+			r.End.UTC().Format(time.RFC3339),
+			r.NodeID,
+			string(r.Kind),
+			r.Key,
+			formatMinutes(r.ParticipantMinutes),
+			formatMinutes(r.PublishedAudioMinutes),
+			formatMinutes(r.PublishedVideoMinutes),
+			formatMinutes(r.SubscribedAudioMinutes),
+			formatMinutes(r.SubscribedVideoMinutes),
+			strconv.FormatUint(r.BytesIn, 10),
+			strconv.FormatUint(r.BytesOut, 10),
+		}); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
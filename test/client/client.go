@@ -48,6 +48,8 @@ type RTCClient struct {
 	subscribedTracks   map[livekit.ParticipantID][]*webrtc.TrackRemote
 	localParticipant   *livekit.ParticipantInfo
 	remoteParticipants map[livekit.ParticipantID]*livekit.ParticipantInfo
+	room               *livekit.Room
+	joinResponse       *livekit.JoinResponse
 
 	reliableDC          *webrtc.DataChannel
 	reliableDCSub       *webrtc.DataChannel
@@ -275,6 +277,8 @@ func (c *RTCClient) Run() error {
 			c.localParticipant = msg.Join.Participant
 			c.id = livekit.ParticipantID(msg.Join.Participant.Sid)
 			c.lock.Lock()
+			c.room = msg.Join.Room
+			c.joinResponse = msg.Join
 			for _, p := range msg.Join.OtherParticipants {
 				c.remoteParticipants[livekit.ParticipantID(p.Sid)] = p
 			}
@@ -331,6 +335,10 @@ func (c *RTCClient) Run() error {
 			c.lock.Lock()
 			c.pendingPublishedTracks[msg.TrackPublished.Cid] = msg.TrackPublished.Track
 			c.lock.Unlock()
+		case *livekit.SignalResponse_RoomUpdate:
+			c.lock.Lock()
+			c.room = msg.RoomUpdate.Room
+			c.lock.Unlock()
 		case *livekit.SignalResponse_RefreshToken:
 			c.lock.Lock()
 			c.refreshToken = msg.RefreshToken
@@ -444,6 +452,20 @@ func (c *RTCClient) RefreshToken() string {
 	return c.refreshToken
 }
 
+// Room returns the room as last received from the server
+func (c *RTCClient) Room() *livekit.Room {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.room
+}
+
+// JoinResponse returns the join response received from the server
+func (c *RTCClient) JoinResponse() *livekit.JoinResponse {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.joinResponse
+}
+
 func (c *RTCClient) PongReceivedAt() int64 {
 	return c.pongReceivedAt.Load()
 }
@@ -478,12 +500,16 @@ func (c *RTCClient) SendIceCandidate(ic *webrtc.ICECandidate, target livekit.Sig
 }
 
 func (c *RTCClient) AddTrack(track *webrtc.TrackLocalStaticSample, path string) (writer *TrackWriter, err error) {
+	return c.addTrack(track, path, livekit.TrackSource_UNKNOWN)
+}
+
+func (c *RTCClient) addTrack(track *webrtc.TrackLocalStaticSample, path string, source livekit.TrackSource) (writer *TrackWriter, err error) {
 	trackType := livekit.TrackType_AUDIO
 	if track.Kind() == webrtc.RTPCodecTypeVideo {
 		trackType = livekit.TrackType_VIDEO
 	}
 
-	if err = c.SendAddTrack(track.ID(), track.StreamID(), trackType); err != nil {
+	if err = c.sendAddTrack(track.ID(), track.StreamID(), trackType, source); err != nil {
 		return
 	}
 
@@ -534,6 +560,15 @@ func (c *RTCClient) AddStaticTrack(mime string, id string, label string) (writer
 	return c.AddStaticTrackWithCodec(webrtc.RTPCodecCapability{MimeType: mime}, id, label)
 }
 
+func (c *RTCClient) AddStaticTrackWithSource(mime string, id string, label string, source livekit.TrackSource) (writer *TrackWriter, err error) {
+	track, err := webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{MimeType: mime}, id, label)
+	if err != nil {
+		return
+	}
+
+	return c.addTrack(track, "", source)
+}
+
 func (c *RTCClient) AddStaticTrackWithCodec(codec webrtc.RTPCodecCapability, id string, label string) (writer *TrackWriter, err error) {
 	track, err := webrtc.NewTrackLocalStaticSample(codec, id, label)
 	if err != nil {
@@ -568,18 +603,28 @@ func (c *RTCClient) AddFileTrack(path string, id string, label string) (writer *
 
 // send AddTrack command to server to initiate server-side negotiation
 func (c *RTCClient) SendAddTrack(cid string, name string, trackType livekit.TrackType) error {
+	return c.sendAddTrack(cid, name, trackType, livekit.TrackSource_UNKNOWN)
+}
+
+func (c *RTCClient) sendAddTrack(cid string, name string, trackType livekit.TrackType, source livekit.TrackSource) error {
 	return c.SendRequest(&livekit.SignalRequest{
 		Message: &livekit.SignalRequest_AddTrack{
 			AddTrack: &livekit.AddTrackRequest{
-				Cid:  cid,
-				Name: name,
-				Type: trackType,
+				Cid:    cid,
+				Name:   name,
+				Type:   trackType,
+				Source: source,
 			},
 		},
 	})
 }
 
 func (c *RTCClient) PublishData(data []byte, kind livekit.DataPacket_Kind) error {
+	return c.PublishDataTo(data, kind, nil)
+}
+
+// PublishDataTo publishes data to the given destination sids, which may include a topic
+func (c *RTCClient) PublishDataTo(data []byte, kind livekit.DataPacket_Kind, destinationSids []string) error {
 	if err := c.ensurePublisherConnected(); err != nil {
 		return err
 	}
@@ -587,7 +632,7 @@ func (c *RTCClient) PublishData(data []byte, kind livekit.DataPacket_Kind) error
 	dp := &livekit.DataPacket{
 		Kind: kind,
 		Value: &livekit.DataPacket_User{
-			User: &livekit.UserPacket{Payload: data},
+			User: &livekit.UserPacket{Payload: data, DestinationSids: destinationSids},
 		},
 	}
 	payload, err := proto.Marshal(dp)
@@ -602,6 +647,31 @@ func (c *RTCClient) PublishData(data []byte, kind livekit.DataPacket_Kind) error
 	}
 }
 
+// PublishDataToTopic publishes data to the subscribers of a data topic
+func (c *RTCClient) PublishDataToTopic(data []byte, topic string) error {
+	if err := c.ensurePublisherConnected(); err != nil {
+		return err
+	}
+
+	up := &livekit.UserPacket{Payload: data}
+	rtc.SetUserPacketTopic(up, topic)
+	payload, err := proto.Marshal(&livekit.DataPacket{
+		Kind:  livekit.DataPacket_RELIABLE,
+		Value: &livekit.DataPacket_User{User: up},
+	})
+	if err != nil {
+		return err
+	}
+	return c.reliableDC.Send(payload)
+}
+
+// UpdateDataTopics changes the data topics the client is subscribed to
+func (c *RTCClient) UpdateDataTopics(subscribe []string, unsubscribe []string) error {
+	req := &livekit.SignalRequest{}
+	rtc.SetSignalDataTopics(req, subscribe, unsubscribe)
+	return c.SendRequest(req)
+}
+
 func (c *RTCClient) GetPublishedTrackIDs() []string {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/service"
+)
+
+func TestConfigReload(t *testing.T) {
+	s, finish := setupSingleNodeTest("TestConfigReload")
+	defer finish()
+
+	var loaded *config.Config
+	s.SetConfigLoader(func() (*config.Config, error) {
+		return loaded, nil
+	})
+	newConfig := func(updater func(conf *config.Config)) *config.Config {
+		conf, err := config.NewConfig("", nil)
+		require.NoError(t, err)
+		conf.Keys = map[string]string{testApiKey: testApiSecret, "newkey": "newsecret"}
+		updater(conf)
+		return conf
+	}
+
+	// key is not known before the reload
+	code, _ := reloadConfig(t, "newkey", "newsecret")
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	loaded = newConfig(func(conf *config.Config) {
+		conf.Room.MaxMetadataSize = 10
+		conf.RTC.TCPPort = 7981
+	})
+	code, res := reloadConfig(t, testApiKey, testApiSecret)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"keys", "room.max_metadata_size"}, res.Applied)
+	require.Equal(t, []string{"rtc.tcp_port"}, res.RestartRequired)
+
+	// new limits and keys are live
+	_, err := roomClient.CreateRoom(contextWithToken(createRoomToken()), &livekit.CreateRoomRequest{
+		Name:     "reloaded",
+		Metadata: "metadata longer than the limit",
+	})
+	require.NoError(t, err)
+	_, err = roomClient.UpdateRoomMetadata(contextWithToken(adminRoomToken("reloaded")), &livekit.UpdateRoomMetadataRequest{
+		Room:     "reloaded",
+		Metadata: "metadata longer than the limit",
+	})
+	require.Error(t, err)
+	code, res = reloadConfig(t, "newkey", "newsecret")
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, res.Applied)
+	require.Equal(t, []string{"rtc.tcp_port"}, res.RestartRequired)
+
+	// invalid configuration isn't applied
+	loaded = newConfig(func(conf *config.Config) {
+		conf.Keys = map[string]string{"otherkey": "othersecret"}
+		conf.WebHook.URLs = []string{"http://localhost:7890"}
+		conf.WebHook.APIKey = "missing"
+	})
+	code, _ = reloadConfig(t, testApiKey, testApiSecret)
+	require.Equal(t, http.StatusBadRequest, code)
+	// still authorized by the previous keys
+	code, _ = reloadConfig(t, "newkey", "newsecret")
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
+func reloadConfig(t *testing.T, apiKey string, apiSecret string) (int, *service.ConfigReloadResult) {
+	token, err := auth.NewAccessToken(apiKey, apiSecret).
+		AddGrant(&auth.VideoGrant{RoomAdmin: true}).
+		ToJWT()
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%d/admin/reload", defaultServerPort), nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+	res := &service.ConfigReloadResult{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	return resp.StatusCode, res
+}
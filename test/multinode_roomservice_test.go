@@ -2,12 +2,14 @@ package test
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/livekit/protocol/livekit"
 
+	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/livekit-server/pkg/testutils"
 )
 
@@ -158,3 +160,51 @@ func TestMultiNodeMutePublishedTrack(t *testing.T) {
 	require.Equal(t, trackIDs[0], res.Track.Sid)
 	require.True(t, res.Track.Muted)
 }
+
+// moderation is applied by the node hosting the room, whichever node serves the request
+func TestMultiNodeRoomAPIModeration(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+	_, _, finish := setupMultiNodeTest("TestMultiNodeRoomAPIModeration")
+	defer finish()
+
+	c1 := createRTCClient("c1", secondServerPort, nil)
+	c2 := createRTCClient("c2", secondServerPort, nil)
+	waitUntilConnected(t, c1, c2)
+	defer stopClients(c1, c2)
+
+	res := &service.ModerationResponse{}
+	code := roomAPIRequest(t, "remove_participants", adminRoomToken(testRoom), &service.RemoveParticipantsRequest{
+		Room:     testRoom,
+		Identity: "^c1$",
+	}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"c1"}, res.Participants)
+}
+
+// participants are moved by the node hosting their room, whichever node serves the request
+func TestMultiNodeRoomAPIMove(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+	_, _, finish := setupMultiNodeTest("TestMultiNodeRoomAPIMove")
+	defer finish()
+
+	_, err := roomClient.CreateRoom(contextWithToken(createRoomToken()), &livekit.CreateRoomRequest{Name: "destination"})
+	require.NoError(t, err)
+	c1 := createRTCClient("c1", secondServerPort, nil)
+	waitUntilConnected(t, c1)
+	defer c1.Stop()
+
+	res := &service.MoveParticipantResponse{}
+	code := roomAPIRequest(t, "move", adminRoomToken(testRoom), &service.MoveParticipantRequest{
+		Room:        testRoom,
+		Identity:    "c1",
+		Destination: "destination",
+	}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "destination", res.Room)
+}
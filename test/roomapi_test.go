@@ -0,0 +1,965 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
+
+	"github.com/livekit/protocol/auth"
+	"github.com/livekit/protocol/livekit"
+	"github.com/livekit/protocol/logger"
+
+	"github.com/livekit/livekit-server/pkg/config"
+	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/livekit-server/pkg/testutils"
+	testclient "github.com/livekit/livekit-server/test/client"
+)
+
+func TestRoomAPILock(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPILock")
+	defer finish()
+
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	waitUntilConnected(t, c1)
+	defer c1.Stop()
+
+	// only room admins may lock
+	code := roomAPIRequest(t, "lock", joinToken(testRoom, "c1"), &service.LockRoomRequest{Room: testRoom, Locked: true}, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+	code = roomAPIRequest(t, "lock", adminRoomToken("unknown"), &service.LockRoomRequest{Room: "unknown", Locked: true}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	res := &service.LockRoomResponse{}
+	code = roomAPIRequest(t, "lock", adminRoomToken(testRoom), &service.LockRoomRequest{Room: testRoom, Locked: true}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.True(t, res.Locked)
+
+	// listed as locked, and the room's participants are told
+	rooms := &service.ListRoomsResponse{}
+	code = roomAPIRequest(t, "list", listRoomToken(), &service.ListRoomsRequest{Names: []string{testRoom}}, rooms)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, rooms.Rooms, 1)
+	require.True(t, rooms.Rooms[0].Locked)
+	protoClient := livekit.NewRoomServiceProtobufClient(fmt.Sprintf("http://localhost:%d", defaultServerPort), &http.Client{})
+	listed, err := protoClient.ListRooms(contextWithToken(listRoomToken()), &livekit.ListRoomsRequest{Names: []string{testRoom}})
+	require.NoError(t, err)
+	require.Len(t, listed.Rooms, 1)
+	require.True(t, rtc.RoomLocked(listed.Rooms[0]))
+	testutils.WithTimeout(t, func() string {
+		if room := c1.Room(); room == nil || !rtc.RoomLocked(room) {
+			return "c1 did not receive the locked room"
+		}
+		return ""
+	})
+
+	// new identities are refused
+	ws, err := testclient.NewWebSocketConn(fmt.Sprintf("ws://localhost:%d", defaultServerPort), joinToken(testRoom, "c2"), nil)
+	require.NoError(t, err)
+	c2, err := testclient.NewRTCClient(ws)
+	require.NoError(t, err)
+	msg, err := c2.ReadResponse()
+	require.NoError(t, err)
+	require.Equal(t, livekit.DisconnectReason_JOIN_FAILURE, msg.GetLeave().GetReason())
+	c2.Stop()
+
+	// identities already in the room may join again
+	c1Again := createRTCClient("c1", defaultServerPort, nil)
+	waitUntilConnected(t, c1Again)
+	defer c1Again.Stop()
+
+	code = roomAPIRequest(t, "lock", adminRoomToken(testRoom), &service.LockRoomRequest{Room: testRoom, Locked: false}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, res.Locked)
+	c3 := createRTCClient("c3", defaultServerPort, nil)
+	waitUntilConnected(t, c3)
+	defer c3.Stop()
+}
+
+func TestRoomAPIMove(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIMove")
+	defer finish()
+
+	breakoutRoom := "breakout"
+	_, err := roomClient.CreateRoom(contextWithToken(createRoomToken()), &livekit.CreateRoomRequest{Name: breakoutRoom})
+	require.NoError(t, err)
+
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	c2 := createRTCClient("c2", defaultServerPort, nil)
+	c3 := createRTCClientWithToken(joinToken(breakoutRoom, "c3"), defaultServerPort, nil)
+	waitUntilConnected(t, c1, c2, c3)
+	defer stopClients(c1, c2, c3)
+
+	t1, err := c1.AddStaticTrack("audio/opus", "audio", "webcam")
+	require.NoError(t, err)
+	defer t1.Stop()
+	testutils.WithTimeout(t, func() string {
+		if len(c2.SubscribedTracks()[c1.ID()]) != 1 {
+			return "c2 didn't subscribe to c1's track"
+		}
+		return ""
+	})
+
+	req := &service.MoveParticipantRequest{Room: testRoom, Identity: "c1", Destination: breakoutRoom}
+	// admin permission on the room alone isn't enough
+	code := roomAPIRequest(t, "move", adminRoomToken(testRoom), req, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	at := auth.NewAccessToken(testApiKey, testApiSecret).
+		AddGrant(&auth.VideoGrant{RoomAdmin: true, RoomCreate: true, Room: testRoom})
+	token, err := at.ToJWT()
+	require.NoError(t, err)
+	code = roomAPIRequest(t, "move", token, &service.MoveParticipantRequest{Room: testRoom, Identity: "c1", Destination: "unknown"}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	res := &service.MoveParticipantResponse{}
+	code = roomAPIRequest(t, "move", token, req, res)
+	require.Equal(t, http.StatusOK, code)
+	require.False(t, res.Reconnect)
+
+	// c1 kept its connection, and is now only seen in the breakout room, along with its track
+	testutils.WithTimeout(t, func() string {
+		if len(c2.RemoteParticipants()) != 0 {
+			return "c2 still sees c1"
+		}
+		if remote := c1.RemoteParticipants(); len(remote) != 1 || remote[0].Identity != "c3" {
+			return "c1 doesn't see only c3"
+		}
+		if len(c3.SubscribedTracks()[c1.ID()]) != 1 {
+			return "c3 didn't subscribe to c1's track"
+		}
+		return ""
+	})
+
+	participants, err := roomClient.ListParticipants(contextWithToken(adminRoomToken(breakoutRoom)), &livekit.ListParticipantsRequest{Room: breakoutRoom})
+	require.NoError(t, err)
+	require.Len(t, participants.Participants, 2)
+	_, err = roomClient.GetParticipant(contextWithToken(adminRoomToken(testRoom)), &livekit.RoomParticipantIdentity{Room: testRoom, Identity: "c1"})
+	require.Error(t, err)
+
+	// the client's token is refreshed for the breakout room
+	testutils.WithTimeout(t, func() string {
+		grants, err := auth.ParseAPIToken(c1.RefreshToken())
+		if err != nil {
+			return err.Error()
+		}
+		if claims, err := grants.Verify(testApiSecret); err != nil || claims.Video.Room != breakoutRoom {
+			return "token wasn't refreshed for the breakout room"
+		}
+		return ""
+	})
+}
+
+func TestRoomAPIBreakouts(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIBreakouts")
+	defer finish()
+
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	c2 := createRTCClient("c2", defaultServerPort, nil)
+	waitUntilConnected(t, c1, c2)
+	defer stopClients(c1, c2)
+	// only active participants can be moved
+	testutils.WithTimeout(t, func() string {
+		participants, err := roomClient.ListParticipants(contextWithToken(adminRoomToken(testRoom)), &livekit.ListParticipantsRequest{Room: testRoom})
+		if err != nil {
+			return err.Error()
+		}
+		for _, pi := range participants.Participants {
+			if pi.State != livekit.ParticipantInfo_ACTIVE {
+				return "participants aren't active"
+			}
+		}
+		return ""
+	})
+
+	at := auth.NewAccessToken(testApiKey, testApiSecret).
+		AddGrant(&auth.VideoGrant{RoomAdmin: true, RoomCreate: true, RoomList: true, Room: testRoom})
+	token, err := at.ToJWT()
+	require.NoError(t, err)
+
+	breakoutRoom := "breakout"
+	req := &service.CreateBreakoutRoomRequest{Room: testRoom, Name: breakoutRoom, Participants: []string{"c1"}}
+	code := roomAPIRequest(t, "create_breakout", adminRoomToken(testRoom), req, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	info := &service.RoomInfo{}
+	code = roomAPIRequest(t, "create_breakout", token, req, info)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, breakoutRoom, info.Name)
+	require.Equal(t, testRoom, info.Parent)
+
+	// breakout rooms can't be nested
+	code = roomAPIRequest(t, "create_breakout", token, &service.CreateBreakoutRoomRequest{Room: breakoutRoom, Name: "nested"}, nil)
+	require.NotEqual(t, http.StatusOK, code)
+
+	testutils.WithTimeout(t, func() string {
+		if len(c2.RemoteParticipants()) != 0 {
+			return "c2 still sees c1"
+		}
+		return ""
+	})
+
+	// links are listed with the rooms
+	rooms := &service.ListRoomsResponse{}
+	code = roomAPIRequest(t, "list", token, &service.ListRoomsRequest{Names: []string{testRoom, breakoutRoom}}, rooms)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, rooms.Rooms, 2)
+	for _, rm := range rooms.Rooms {
+		switch rm.Name {
+		case testRoom:
+			require.Equal(t, []string{breakoutRoom}, rm.Breakouts)
+		case breakoutRoom:
+			require.Equal(t, testRoom, rm.Parent)
+		}
+	}
+	protoClient := livekit.NewRoomServiceProtobufClient(fmt.Sprintf("http://localhost:%d", defaultServerPort), &http.Client{})
+	listed, err := protoClient.ListRooms(contextWithToken(listRoomToken()), &livekit.ListRoomsRequest{Names: []string{testRoom, breakoutRoom}})
+	require.NoError(t, err)
+	require.Len(t, listed.Rooms, 2)
+	for _, rm := range listed.Rooms {
+		parent, breakouts := rtc.RoomBreakoutLinks(rm)
+		switch rm.Name {
+		case testRoom:
+			require.Equal(t, []string{breakoutRoom}, breakouts)
+		case breakoutRoom:
+			require.Equal(t, testRoom, parent)
+		}
+	}
+
+	payload := "back in five"
+	received := atomic.NewBool(false)
+	c1.OnDataReceived = func(data []byte, sid string) {
+		if string(data) == payload {
+			received.Store(true)
+		}
+	}
+	res := &service.BreakoutsResponse{}
+	code = roomAPIRequest(t, "broadcast_breakouts", token, &service.BroadcastBreakoutsRequest{Room: testRoom, Data: []byte(payload)}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{breakoutRoom}, res.Breakouts)
+	testutils.WithTimeout(t, func() string {
+		if !received.Load() {
+			return "c1 did not receive broadcast data"
+		}
+		return ""
+	})
+
+	code = roomAPIRequest(t, "close_breakouts", token, &service.CloseBreakoutsRequest{Room: testRoom}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{breakoutRoom}, res.Breakouts)
+
+	// participants are back in the parent, and the breakout room is gone
+	testutils.WithTimeout(t, func() string {
+		if len(c2.RemoteParticipants()) != 1 {
+			return "c2 doesn't see c1"
+		}
+		rooms := &service.ListRoomsResponse{}
+		code := roomAPIRequest(t, "list", token, &service.ListRoomsRequest{}, rooms)
+		if code != http.StatusOK || len(rooms.Rooms) != 1 || len(rooms.Rooms[0].Breakouts) != 0 {
+			return "breakout room wasn't closed"
+		}
+		return ""
+	})
+
+	// breakout rooms with a duration close back into the parent at their deadline
+	req = &service.CreateBreakoutRoomRequest{Room: testRoom, Name: breakoutRoom, Duration: 2, Participants: []string{"c2"}}
+	code = roomAPIRequest(t, "create_breakout", token, req, info)
+	require.Equal(t, http.StatusOK, code)
+	require.NotZero(t, info.CloseAt)
+	testutils.WithTimeout(t, func() string {
+		if len(c1.RemoteParticipants()) != 0 {
+			return "c1 still sees c2"
+		}
+		return ""
+	})
+	testutils.WithTimeout(t, func() string {
+		if len(c1.RemoteParticipants()) != 1 {
+			return "c2 wasn't moved back"
+		}
+		rooms := &service.ListRoomsResponse{}
+		code := roomAPIRequest(t, "list", token, &service.ListRoomsRequest{}, rooms)
+		if code != http.StatusOK || len(rooms.Rooms) != 1 || len(rooms.Rooms[0].Breakouts) != 0 {
+			return "breakout room wasn't closed"
+		}
+		return ""
+	})
+}
+
+// roomAPIRequest posts an operation to the room API, decoding the response into res when successful
+func TestRoomAPIBridgeTrack(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIBridgeTrack")
+	defer finish()
+
+	stageRoom := "stage"
+	_, err := roomClient.CreateRoom(contextWithToken(createRoomToken()), &livekit.CreateRoomRequest{Name: stageRoom})
+	require.NoError(t, err)
+
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	c2 := createRTCClientWithToken(joinToken(stageRoom, "c2"), defaultServerPort, nil)
+	waitUntilConnected(t, c1, c2)
+	defer stopClients(c1, c2)
+
+	t1, err := c1.AddStaticTrack("audio/opus", "audio", "webcam")
+	require.NoError(t, err)
+	defer t1.Stop()
+	var trackSid string
+	testutils.WithTimeout(t, func() string {
+		pi, err := roomClient.GetParticipant(contextWithToken(adminRoomToken(testRoom)), &livekit.RoomParticipantIdentity{Room: testRoom, Identity: "c1"})
+		if err != nil {
+			return err.Error()
+		}
+		if len(pi.Tracks) != 1 {
+			return "c1 didn't publish its track"
+		}
+		trackSid = pi.Tracks[0].Sid
+		return ""
+	})
+
+	req := &service.BridgeTrackRequest{Room: testRoom, Identity: "c1", TrackSid: trackSid, Destination: stageRoom, BridgeIdentity: "bridge"}
+	// admin permission on the room alone isn't enough
+	code := roomAPIRequest(t, "bridge_track", adminRoomToken(testRoom), req, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	at := auth.NewAccessToken(testApiKey, testApiSecret).
+		AddGrant(&auth.VideoGrant{RoomAdmin: true, RoomCreate: true, Room: testRoom})
+	token, err := at.ToJWT()
+	require.NoError(t, err)
+	code = roomAPIRequest(t, "bridge_track", token, &service.BridgeTrackRequest{Room: testRoom, Identity: "c1", TrackSid: "unknown", Destination: stageRoom, BridgeIdentity: "bridge"}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	res := &service.BridgeTrackResponse{}
+	code = roomAPIRequest(t, "bridge_track", token, req, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "bridge", res.Identity)
+	require.NotEqual(t, trackSid, res.TrackSid)
+
+	// c2 sees the bridge participant publishing the track, and receives media from it
+	testutils.WithTimeout(t, func() string {
+		remote := c2.RemoteParticipants()
+		if len(remote) != 1 || remote[0].Identity != "bridge" {
+			return "c2 doesn't see the bridge participant"
+		}
+		if len(remote[0].Tracks) != 1 || remote[0].Tracks[0].Sid != res.TrackSid {
+			return "bridge participant doesn't publish the bridged track"
+		}
+		if len(c2.SubscribedTracks()[livekit.ParticipantID(remote[0].Sid)]) != 1 {
+			return "c2 didn't subscribe to the bridged track"
+		}
+		if c2.BytesReceived() == 0 {
+			return "c2 didn't receive media"
+		}
+		return ""
+	})
+
+	// the bridge stops along with the source track
+	c1.Stop()
+	testutils.WithTimeout(t, func() string {
+		if len(c2.RemoteParticipants()) != 0 {
+			return "c2 still sees the bridge participant"
+		}
+		return ""
+	})
+	participants, err := roomClient.ListParticipants(contextWithToken(adminRoomToken(stageRoom)), &livekit.ListParticipantsRequest{Room: stageRoom})
+	require.NoError(t, err)
+	require.Len(t, participants.Participants, 1)
+}
+
+func TestRoomAPIStage(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIStage")
+	defer finish()
+
+	audienceGrant := &auth.VideoGrant{RoomJoin: true, Room: testRoom}
+	audienceGrant.SetCanPublish(false)
+	audienceToken := joinTokenWithGrant("c1", audienceGrant)
+	c1 := createRTCClientWithToken(audienceToken, defaultServerPort, nil)
+	moderator := createRTCClientWithToken(joinTokenWithGrant("moderator", &auth.VideoGrant{RoomJoin: true, RoomAdmin: true, Room: testRoom}), defaultServerPort, nil)
+	waitUntilConnected(t, c1, moderator)
+	defer stopClients(c1, moderator)
+
+	// the last stage message each client received
+	stageMessage := func(c *testclient.RTCClient) *atomic.String {
+		received := atomic.NewString("")
+		c.OnDataReceived = func(data []byte, sid string) {
+			msg := rtc.StageMessage{}
+			if json.Unmarshal(data, &msg) == nil && msg.Type == "stage" {
+				received.Store(fmt.Sprintf("%s %s %v", msg.Event, msg.Identity, msg.Queue))
+			}
+		}
+		return received
+	}
+	c1Message, moderatorMessage := stageMessage(c1), stageMessage(moderator)
+	waitForMessage := func(received *atomic.String, expected string) {
+		testutils.WithTimeout(t, func() string {
+			if msg := received.Load(); msg != expected {
+				return fmt.Sprintf("expected stage message %q, got %q", expected, msg)
+			}
+			return ""
+		})
+	}
+	canPublish := func() bool {
+		pi, err := roomClient.GetParticipant(contextWithToken(adminRoomToken(testRoom)), &livekit.RoomParticipantIdentity{Room: testRoom, Identity: "c1"})
+		require.NoError(t, err)
+		return pi.Permission.CanPublish
+	}
+	testutils.WithTimeout(t, func() string {
+		participants, err := roomClient.ListParticipants(contextWithToken(adminRoomToken(testRoom)), &livekit.ListParticipantsRequest{Room: testRoom})
+		require.NoError(t, err)
+		for _, pi := range participants.Participants {
+			if pi.State != livekit.ParticipantInfo_ACTIVE {
+				return "participants are not active yet"
+			}
+		}
+		return ""
+	})
+
+	res := &service.StageResponse{}
+	code := roomAPIRequest(t, "stage_request", audienceToken, &service.StageRequest{}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"c1"}, res.Queue)
+	waitForMessage(moderatorMessage, "requested c1 [c1]")
+
+	// participants cannot moderate
+	code = roomAPIRequest(t, "stage", audienceToken, &service.ModerateStageRequest{Room: testRoom, Action: service.StageActionApprove, Identity: "c1"}, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	token := adminRoomToken(testRoom)
+	res = &service.StageResponse{}
+	code = roomAPIRequest(t, "stage", token, &service.ModerateStageRequest{Room: testRoom, Action: service.StageActionDeny, Identity: "c1"}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Empty(t, res.Queue)
+	waitForMessage(c1Message, "denied c1 []")
+	require.False(t, canPublish())
+	code = roomAPIRequest(t, "stage", token, &service.ModerateStageRequest{Room: testRoom, Action: service.StageActionApprove, Identity: "c1"}, nil)
+	require.Equal(t, http.StatusNotFound, code)
+
+	// approval lets the participant publish right away
+	code = roomAPIRequest(t, "stage_request", audienceToken, &service.StageRequest{}, nil)
+	require.Equal(t, http.StatusOK, code)
+	code = roomAPIRequest(t, "stage", token, &service.ModerateStageRequest{Room: testRoom, Action: service.StageActionApprove, Identity: "c1"}, nil)
+	require.Equal(t, http.StatusOK, code)
+	waitForMessage(c1Message, "approved c1 []")
+	waitForMessage(moderatorMessage, "approved c1 []")
+	require.True(t, canPublish())
+	code = roomAPIRequest(t, "stage_request", audienceToken, &service.StageRequest{}, nil)
+	require.Equal(t, http.StatusPreconditionFailed, code)
+
+	t1, err := c1.AddStaticTrack("audio/opus", "audio", "webcam")
+	require.NoError(t, err)
+	defer t1.Stop()
+	testutils.WithTimeout(t, func() string {
+		if len(moderator.SubscribedTracks()[c1.ID()]) != 1 {
+			return "moderator didn't subscribe to c1's track"
+		}
+		return ""
+	})
+
+	// demotion unpublishes the participant's tracks
+	code = roomAPIRequest(t, "stage", token, &service.ModerateStageRequest{Room: testRoom, Action: service.StageActionDemote, Identity: "c1"}, nil)
+	require.Equal(t, http.StatusOK, code)
+	waitForMessage(c1Message, "demoted c1 []")
+	require.False(t, canPublish())
+	testutils.WithTimeout(t, func() string {
+		pi, err := roomClient.GetParticipant(contextWithToken(token), &livekit.RoomParticipantIdentity{Room: testRoom, Identity: "c1"})
+		require.NoError(t, err)
+		if len(pi.Tracks) != 0 {
+			return "c1's track is still published"
+		}
+		return ""
+	})
+
+	// requests of participants leaving the room are withdrawn
+	code = roomAPIRequest(t, "stage_request", audienceToken, &service.StageRequest{}, nil)
+	require.Equal(t, http.StatusOK, code)
+	c1.Stop()
+	waitForMessage(moderatorMessage, "withdrawn c1 []")
+}
+
+func TestRoomAPIModeration(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIModeration")
+	defer finish()
+
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	c2 := createRTCClient("c2", defaultServerPort, nil)
+	waitUntilConnected(t, c1, c2)
+	defer stopClients(c1, c2)
+
+	for _, c := range []*testclient.RTCClient{c1, c2} {
+		track, err := c.AddStaticTrackWithSource("audio/opus", "audio", "microphone", livekit.TrackSource_MICROPHONE)
+		require.NoError(t, err)
+		defer track.Stop()
+	}
+	token := adminRoomToken(testRoom)
+	trackMuted := func(identity string) (bool, string) {
+		pi, err := roomClient.GetParticipant(contextWithToken(token), &livekit.RoomParticipantIdentity{Room: testRoom, Identity: identity})
+		if err != nil {
+			return false, err.Error()
+		}
+		if len(pi.Tracks) != 1 {
+			return false, identity + " doesn't publish one track"
+		}
+		return pi.Tracks[0].Muted, ""
+	}
+	testutils.WithTimeout(t, func() string {
+		for _, identity := range []string{"c1", "c2"} {
+			if _, msg := trackMuted(identity); msg != "" {
+				return msg
+			}
+		}
+		return ""
+	})
+
+	code := roomAPIRequest(t, "moderate_tracks", token, &service.ModerateTracksRequest{Room: testRoom, Sources: []string{"whiteboard"}}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+
+	res := &service.ModerationResponse{}
+	code = roomAPIRequest(t, "moderate_tracks", token, &service.ModerateTracksRequest{
+		Room:        testRoom,
+		Sources:     []string{"microphone"},
+		Except:      []string{"c2"},
+		MuteOnEntry: 30,
+	}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, testRoom, res.Room)
+	require.Equal(t, 1, res.Tracks)
+	testutils.WithTimeout(t, func() string {
+		if muted, msg := trackMuted("c1"); msg != "" || !muted {
+			return "c1's microphone isn't muted " + msg
+		}
+		return ""
+	})
+	muted, msg := trackMuted("c2")
+	require.Empty(t, msg)
+	require.False(t, muted)
+
+	// late joiners are muted on entry
+	c3 := createRTCClient("c3", defaultServerPort, nil)
+	waitUntilConnected(t, c3)
+	defer c3.Stop()
+	t3, err := c3.AddStaticTrackWithSource("audio/opus", "audio", "microphone", livekit.TrackSource_MICROPHONE)
+	require.NoError(t, err)
+	defer t3.Stop()
+	testutils.WithTimeout(t, func() string {
+		if muted, msg := trackMuted("c3"); msg != "" || !muted {
+			return "c3's microphone isn't muted " + msg
+		}
+		return ""
+	})
+
+	code = roomAPIRequest(t, "remove_participants", token, &service.RemoveParticipantsRequest{Room: testRoom, Identity: "("}, nil)
+	require.Equal(t, http.StatusBadRequest, code)
+	res = &service.ModerationResponse{}
+	code = roomAPIRequest(t, "remove_participants", token, &service.RemoveParticipantsRequest{Room: testRoom, Identity: "^c", Except: []string{"c2"}}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.ElementsMatch(t, []string{"c1", "c3"}, res.Participants)
+	testutils.WithTimeout(t, func() string {
+		participants, err := roomClient.ListParticipants(contextWithToken(token), &livekit.ListParticipantsRequest{Room: testRoom})
+		if err != nil {
+			return err.Error()
+		}
+		if len(participants.Participants) != 1 || participants.Participants[0].Identity != "c2" {
+			return "only c2 should be left in the room"
+		}
+		return ""
+	})
+}
+
+func TestRoomAPIAttributes(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIAttributes")
+	defer finish()
+
+	// waits until a client sees attributes of c1 at version, in the last participant update
+	waitForAttributes := func(c *testclient.RTCClient, expected string) {
+		testutils.WithTimeout(t, func() string {
+			received := ""
+			for _, pi := range c.RemoteParticipants() {
+				if pi.Identity == "c1" {
+					attributes, version := rtc.ParticipantAttributes(pi)
+					received = fmt.Sprintf("%d %v", version, attributes)
+				}
+			}
+			if received != expected {
+				return fmt.Sprintf("expected attributes %q, got %q", expected, received)
+			}
+			return ""
+		})
+	}
+
+	c1Token := joinToken(testRoom, "c1")
+	c1 := createRTCClientWithToken(c1Token, defaultServerPort, nil)
+	c2 := createRTCClient("c2", defaultServerPort, nil)
+	waitUntilConnected(t, c1, c2)
+	defer stopClients(c1, c2)
+	testutils.WithTimeout(t, func() string {
+		participants, err := roomClient.ListParticipants(contextWithToken(adminRoomToken(testRoom)), &livekit.ListParticipantsRequest{Room: testRoom})
+		require.NoError(t, err)
+		for _, pi := range participants.Participants {
+			if pi.State != livekit.ParticipantInfo_ACTIVE {
+				return "participants are not active yet"
+			}
+		}
+		return ""
+	})
+
+	res := &service.AttributesResponse{}
+	code := roomAPIRequest(t, "set_attributes", c1Token, &service.SetAttributesRequest{
+		Attributes: map[string]string{"hand": "raised", "status": "away"},
+	}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.EqualValues(t, 1, res.Version)
+	waitForAttributes(c2, "1 map[hand:raised status:away]")
+
+	// updates conditioned on a stale version are rejected
+	token := adminRoomToken(testRoom)
+	stale := uint32(0)
+	code = roomAPIRequest(t, "update_attributes", token, &service.UpdateAttributesRequest{
+		Room:       testRoom,
+		Identity:   "c1",
+		Attributes: map[string]string{"hand": ""},
+		Version:    &stale,
+	}, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	res = &service.AttributesResponse{}
+	current := uint32(1)
+	code = roomAPIRequest(t, "update_attributes", token, &service.UpdateAttributesRequest{
+		Room:       testRoom,
+		Identity:   "c1",
+		Attributes: map[string]string{"hand": ""},
+		Version:    &current,
+	}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, map[string]string{"status": "away"}, res.Attributes)
+	require.EqualValues(t, 2, res.Version)
+	// updates only carry the keys that changed
+	waitForAttributes(c2, "2 map[hand:]")
+
+	// participants cannot update others' attributes
+	code = roomAPIRequest(t, "update_attributes", c1Token, &service.UpdateAttributesRequest{
+		Room:       testRoom,
+		Identity:   "c2",
+		Attributes: map[string]string{"hand": "raised"},
+	}, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	// participants joining later receive all attributes
+	c3 := createRTCClient("c3", defaultServerPort, nil)
+	waitUntilConnected(t, c3)
+	defer c3.Stop()
+	waitForAttributes(c3, "2 map[status:away]")
+
+	// and so does the room service, to clients using protobuf
+	protoClient := livekit.NewRoomServiceProtobufClient(fmt.Sprintf("http://localhost:%d", defaultServerPort), &http.Client{})
+	participant, err := protoClient.GetParticipant(contextWithToken(token), &livekit.RoomParticipantIdentity{Room: testRoom, Identity: "c1"})
+	require.NoError(t, err)
+	attributes, version := rtc.ParticipantAttributes(participant)
+	require.Equal(t, map[string]string{"status": "away"}, attributes)
+	require.EqualValues(t, 2, version)
+}
+
+func TestRoomAPIUpdateMetadata(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIUpdateMetadata")
+	defer finish()
+
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	waitUntilConnected(t, c1)
+	defer c1.Stop()
+
+	token := adminRoomToken(testRoom)
+	info := &service.RoomInfo{}
+	code := roomAPIRequest(t, "update_metadata", token, &service.UpdateMetadataRequest{Room: testRoom, Metadata: "first"}, info)
+	require.Equal(t, http.StatusOK, code)
+	version := info.MetadataVersion
+	require.NotZero(t, version)
+
+	// a writer holding the previous version fails once metadata changed
+	stale := version
+	info = &service.RoomInfo{}
+	code = roomAPIRequest(t, "update_metadata", token, &service.UpdateMetadataRequest{Room: testRoom, Metadata: "second", Version: &version}, info)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, stale+1, info.MetadataVersion)
+	code = roomAPIRequest(t, "update_metadata", token, &service.UpdateMetadataRequest{Room: testRoom, Metadata: "third", Version: &stale}, nil)
+	require.Equal(t, http.StatusConflict, code)
+
+	list := &service.ListRoomsResponse{}
+	code = roomAPIRequest(t, "list", listRoomToken(), &service.ListRoomsRequest{Names: []string{testRoom}}, list)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, list.Rooms, 1)
+	require.Equal(t, "second", list.Rooms[0].Metadata)
+	require.Equal(t, stale+1, list.Rooms[0].MetadataVersion)
+	testutils.WithTimeout(t, func() string {
+		if room := c1.Room(); room == nil || room.Metadata != "second" {
+			return "c1 did not receive the room's metadata"
+		}
+		return ""
+	})
+
+	// unconditional updates increment the version too
+	_, err := roomClient.UpdateRoomMetadata(contextWithToken(token), &livekit.UpdateRoomMetadataRequest{Room: testRoom, Metadata: "fourth"})
+	require.NoError(t, err)
+	code = roomAPIRequest(t, "update_metadata", token, &service.UpdateMetadataRequest{Room: testRoom, Metadata: "fifth", Version: &info.MetadataVersion}, nil)
+	require.Equal(t, http.StatusConflict, code)
+}
+
+func TestRoomAPIState(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIState")
+	defer finish()
+
+	// room state as received by a client
+	receivedState := func(c *testclient.RTCClient) *atomic.String {
+		received := atomic.NewString("")
+		state := map[string]string{}
+		c.OnDataReceived = func(data []byte, sid string) {
+			msg := rtc.RoomStateMessage{}
+			if json.Unmarshal(data, &msg) != nil || msg.Type != "room_state" {
+				return
+			}
+			if msg.Snapshot {
+				state = map[string]string{}
+			}
+			for key, entry := range msg.Entries {
+				if entry == nil {
+					delete(state, key)
+				} else {
+					state[key] = entry.Value
+				}
+			}
+			received.Store(fmt.Sprint(state))
+		}
+		return received
+	}
+	waitForState := func(received *atomic.String, expected string) {
+		testutils.WithTimeout(t, func() string {
+			if state := received.Load(); state != expected {
+				return fmt.Sprintf("expected room state %q, got %q", expected, state)
+			}
+			return ""
+		})
+	}
+
+	c1Token := joinToken(testRoom, "c1")
+	c1 := createRTCClientWithToken(c1Token, defaultServerPort, nil)
+	c2Token := joinToken(testRoom, "c2")
+	c2 := createRTCClientWithToken(c2Token, defaultServerPort, nil)
+	c2State := receivedState(c2)
+	waitUntilConnected(t, c1, c2)
+	defer stopClients(c1, c2)
+	testutils.WithTimeout(t, func() string {
+		participants, err := roomClient.ListParticipants(contextWithToken(adminRoomToken(testRoom)), &livekit.ListParticipantsRequest{Room: testRoom})
+		require.NoError(t, err)
+		for _, pi := range participants.Participants {
+			if pi.State != livekit.ParticipantInfo_ACTIVE {
+				return "participants are not active yet"
+			}
+		}
+		return ""
+	})
+
+	res := &service.RoomStateResponse{}
+	code := roomAPIRequest(t, "set_state", c1Token, &service.SetRoomStateRequest{
+		Set: map[string]*service.RoomStateValue{"poll": {Value: "open"}, "pointer": {Value: "1,2", Shared: true}},
+	}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Entries, 2)
+	require.Equal(t, "c1", res.Entries["poll"].Owner)
+	waitForState(c2State, "map[pointer:1,2 poll:open]")
+
+	// keys are owned by the participant that created them, unless shared
+	code = roomAPIRequest(t, "set_state", c2Token, &service.SetRoomStateRequest{Delete: []string{"poll"}}, nil)
+	require.Equal(t, http.StatusForbidden, code)
+	code = roomAPIRequest(t, "set_state", c2Token, &service.SetRoomStateRequest{
+		Set: map[string]*service.RoomStateValue{"pointer": {Value: "3,4"}},
+	}, nil)
+	require.Equal(t, http.StatusOK, code)
+	waitForState(c2State, "map[pointer:3,4 poll:open]")
+
+	// admins may change any key
+	token := adminRoomToken(testRoom)
+	code = roomAPIRequest(t, "update_state", token, &service.UpdateRoomStateRequest{Room: testRoom, Delete: []string{"poll"}}, nil)
+	require.Equal(t, http.StatusOK, code)
+	waitForState(c2State, "map[pointer:3,4]")
+	res = &service.RoomStateResponse{}
+	code = roomAPIRequest(t, "get_state", token, &service.GetRoomStateRequest{Room: testRoom}, res)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, res.Entries, 1)
+	require.Equal(t, "3,4", res.Entries["pointer"].Value)
+
+	// participants joining later receive the whole state in their join response
+	c3 := createRTCClient("c3", defaultServerPort, nil)
+	waitUntilConnected(t, c3)
+	defer c3.Stop()
+	state := rtc.JoinRoomState(c3.JoinResponse())
+	require.Len(t, state, 1)
+	require.Equal(t, "3,4", state["pointer"].Value)
+	require.Equal(t, "c1", state["pointer"].Owner)
+}
+
+func TestRoomAPIDataHistory(t *testing.T) {
+	s := createSingleNodeServer(func(conf *config.Config) {
+		conf.Room.DataHistory = config.DataHistoryConfig{MaxMessages: 10, Persist: true}
+	})
+	go func() {
+		if err := s.Start(); err != nil {
+			logger.Errorw("server returned error", err)
+		}
+	}()
+	defer s.Stop(true)
+	waitForServerToStart(s)
+
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	waitUntilConnected(t, c1)
+	defer c1.Stop()
+
+	require.NoError(t, c1.PublishData([]byte("hello"), livekit.DataPacket_RELIABLE))
+	require.NoError(t, c1.PublishData([]byte("lossy"), livekit.DataPacket_LOSSY))
+	_, err := roomClient.SendData(contextWithToken(adminRoomToken(testRoom)), &livekit.SendDataRequest{
+		Room: testRoom,
+		Data: []byte("announcement"),
+		Kind: livekit.DataPacket_RELIABLE,
+	})
+	require.NoError(t, err)
+
+	// only room admins may read the history
+	code := roomAPIRequest(t, "data_history", joinToken(testRoom, "c1"), &service.DataHistoryRequest{Room: testRoom}, nil)
+	require.Equal(t, http.StatusUnauthorized, code)
+
+	res := &service.DataHistoryResponse{}
+	testutils.WithTimeout(t, func() string {
+		code := roomAPIRequest(t, "data_history", adminRoomToken(testRoom), &service.DataHistoryRequest{Room: testRoom}, res)
+		if code != http.StatusOK {
+			return fmt.Sprintf("unexpected status %d", code)
+		}
+		if len(res.Messages) != 2 {
+			return fmt.Sprintf("expected 2 retained messages, got %d", len(res.Messages))
+		}
+		return ""
+	})
+	require.Equal(t, "c1", res.Messages[0].Sender)
+	require.Equal(t, []byte("hello"), res.Messages[0].Payload)
+	require.Empty(t, res.Messages[1].Sender)
+	require.Equal(t, []byte("announcement"), res.Messages[1].Payload)
+
+	// participants joining later are replayed the history
+	received := atomic.NewString("")
+	c2 := createRTCClient("c2", defaultServerPort, nil)
+	c2.OnDataReceived = func(data []byte, sid string) {
+		received.Store(received.Load() + string(data) + ";")
+	}
+	waitUntilConnected(t, c2)
+	defer c2.Stop()
+	testutils.WithTimeout(t, func() string {
+		if history := received.Load(); history != "hello;announcement;" {
+			return fmt.Sprintf("expected history to be replayed, got %q", history)
+		}
+		return ""
+	})
+}
+
+func TestRoomAPIDataTopics(t *testing.T) {
+	_, finish := setupSingleNodeTest("TestRoomAPIDataTopics")
+	defer finish()
+
+	// data received by a client, apart from probes of which only the last one is kept
+	receivedData := func(c *testclient.RTCClient, probe *atomic.String) *atomic.String {
+		received := atomic.NewString("")
+		c.OnDataReceived = func(data []byte, sid string) {
+			if strings.HasPrefix(string(data), "probe:") {
+				probe.Store(string(data))
+				return
+			}
+			received.Store(received.Load() + string(data) + ";")
+		}
+		return received
+	}
+
+	// c1 may only publish to chat topics, c2 subscribes to them on join
+	c1 := createRTCClientWithToken(tokenWithClaims(t, "c1", map[string]interface{}{
+		"publishTopics": []string{"chat/*"},
+	}), defaultServerPort, nil)
+	c2 := createRTCClientWithToken(tokenWithClaims(t, "c2", map[string]interface{}{
+		"dataTopics": []string{"chat/*"},
+	}), defaultServerPort, nil)
+	c3 := createRTCClient("c3", defaultServerPort, nil)
+	c3Probe := atomic.NewString("")
+	c2Data := receivedData(c2, atomic.NewString(""))
+	c3Data := receivedData(c3, c3Probe)
+	waitUntilConnected(t, c1, c2, c3)
+	defer stopClients(c1, c2, c3)
+
+	// waits until c3 receives probes published to a topic, the signal path not acknowledging topic changes
+	waitForTopic := func(topic string) {
+		testutils.WithTimeout(t, func() string {
+			require.NoError(t, c1.PublishDataToTopic([]byte("probe:"+topic), topic))
+			if c3Probe.Load() != "probe:"+topic {
+				return "c3 did not subscribe to " + topic
+			}
+			return ""
+		})
+	}
+
+	require.NoError(t, c3.UpdateDataTopics([]string{"chat/lobby"}, nil))
+	waitForTopic("chat/lobby")
+	require.NoError(t, c3.UpdateDataTopics([]string{"chat/other"}, []string{"chat/lobby"}))
+	waitForTopic("chat/other")
+
+	require.NoError(t, c1.PublishDataToTopic([]byte("lobby"), "chat/lobby"))
+	// not allowed by c1's token, dropped
+	require.NoError(t, c1.PublishDataToTopic([]byte("cursor"), "cursor"))
+	require.NoError(t, c1.PublishData([]byte("everyone"), livekit.DataPacket_RELIABLE))
+	require.NoError(t, c1.PublishDataToTopic([]byte("other"), "chat/other"))
+
+	testutils.WithTimeout(t, func() string {
+		if data := c2Data.Load(); data != "lobby;everyone;other;" {
+			return fmt.Sprintf("c2 received unexpected data %q", data)
+		}
+		if data := c3Data.Load(); data != "everyone;other;" {
+			return fmt.Sprintf("c3 received unexpected data %q", data)
+		}
+		return ""
+	})
+}
+
+func roomAPIRequest(t *testing.T, operation string, token string, req interface{}, res interface{}) int {
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+	httpReq, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%d/room/%s", defaultServerPort, operation), bytes.NewReader(body))
+	require.NoError(t, err)
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := http.DefaultClient.Do(httpReq)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK && res != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(res))
+	}
+	return resp.StatusCode
+}
+
+// tokenWithClaims creates a token joining testRoom, with server side claims alongside its grants
+func tokenWithClaims(t *testing.T, identity string, claims map[string]interface{}) string {
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(testApiSecret)},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+
+	claims["video"] = &auth.VideoGrant{Room: testRoom, RoomJoin: true}
+	token, err := jwt.Signed(sig).
+		Claims(jwt.Claims{
+			Issuer:    testApiKey,
+			Subject:   identity,
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Expiry:    jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).
+		Claims(claims).
+		CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pion/webrtc/v3"
 	"github.com/stretchr/testify/require"
 	"github.com/thoas/go-funk"
+	"gopkg.in/square/go-jose.v2/jwt"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
@@ -18,6 +19,7 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/rtc"
+	"github.com/livekit/livekit-server/pkg/service"
 	"github.com/livekit/livekit-server/pkg/testutils"
 	testclient "github.com/livekit/livekit-server/test/client"
 )
@@ -117,6 +119,127 @@ func TestClientConnectDuplicate(t *testing.T) {
 	})
 }
 
+func TestClientConnectDuplicatePolicy(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+
+	_, finish := setupSingleNodeTest("TestClientConnectDuplicatePolicy")
+	defer finish()
+
+	listIdentities := func() []string {
+		res, err := roomClient.ListParticipants(contextWithToken(adminRoomToken(testRoom)), &livekit.ListParticipantsRequest{Room: testRoom})
+		require.NoError(t, err)
+		identities := make([]string, 0, len(res.Participants))
+		for _, p := range res.Participants {
+			identities = append(identities, p.Identity)
+		}
+		return identities
+	}
+
+	// newcomers are refused with a specific error when duplicates are rejected
+	rejectToken := tokenWithClaims(t, "c1", map[string]interface{}{"duplicateIdentity": "reject"})
+	c1 := createRTCClientWithToken(rejectToken, defaultServerPort, nil)
+	waitUntilConnected(t, c1)
+	defer stopClients(c1)
+
+	_, err := testclient.NewWebSocketConn(fmt.Sprintf("ws://localhost:%d", defaultServerPort), rejectToken, nil)
+	require.Error(t, err)
+	req, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:%d/rtc/validate", defaultServerPort), nil)
+	require.NoError(t, err)
+	testclient.SetAuthorizationToken(req.Header, rejectToken)
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = res.Body.Close()
+	require.Equal(t, http.StatusConflict, res.StatusCode)
+
+	// concurrent sessions join under suffixed identities when allowed
+	allowToken := tokenWithClaims(t, "c2", map[string]interface{}{"duplicateIdentity": "allow"})
+	c2 := createRTCClientWithToken(allowToken, defaultServerPort, nil)
+	waitUntilConnected(t, c2)
+	c2Dup := createRTCClientWithToken(allowToken, defaultServerPort, nil)
+	waitUntilConnected(t, c2Dup)
+	defer stopClients(c2, c2Dup)
+	require.Equal(t, c2.ID()+"#2", c2Dup.ID())
+	require.ElementsMatch(t, []string{"c1", "c2"}, listIdentities())
+	testutils.WithTimeout(t, func() string {
+		for _, sid := range []livekit.ParticipantID{c2.ID(), c2Dup.ID()} {
+			if p := c1.GetRemoteParticipant(sid); p == nil || p.Identity != "c2" {
+				return fmt.Sprintf("c1 did not see session %s of c2", sid)
+			}
+		}
+		return ""
+	})
+
+	// removing the identity removes each of its sessions
+	_, err = roomClient.RemoveParticipant(contextWithToken(adminRoomToken(testRoom)), &livekit.RoomParticipantIdentity{
+		Room:     testRoom,
+		Identity: "c2",
+	})
+	require.NoError(t, err)
+	testutils.WithTimeout(t, func() string {
+		if len(c1.RemoteParticipants()) != 0 {
+			return "c1 still sees sessions of c2"
+		}
+		return ""
+	})
+	require.Equal(t, []string{"c1"}, listIdentities())
+
+	// invalid policies are refused
+	_, err = testclient.NewWebSocketConn(fmt.Sprintf("ws://localhost:%d", defaultServerPort),
+		tokenWithClaims(t, "c3", map[string]interface{}{"duplicateIdentity": "merge"}), nil)
+	require.Error(t, err)
+}
+
+// refreshed tokens keep the server claims of the token the participant joined with
+func TestRefreshTokenServerClaims(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+		return
+	}
+
+	_, finish := setupSingleNodeTest("TestRefreshTokenServerClaims")
+	defer finish()
+
+	c1 := createRTCClientWithToken(tokenWithClaims(t, "c1", map[string]interface{}{
+		"maxSessionDuration": 3600,
+		"role":               "viewer",
+		"dataTopics":         []string{"chat"},
+		"publishTopics":      []string{"chat/*"},
+		"duplicateIdentity":  "reject",
+	}), defaultServerPort, nil)
+	waitUntilConnected(t, c1)
+	defer stopClients(c1)
+
+	var token string
+	testutils.WithTimeout(t, func() string {
+		if token = c1.RefreshToken(); token == "" {
+			return "did not receive refresh token"
+		}
+		return ""
+	})
+
+	verifier, err := auth.ParseAPIToken(token)
+	require.NoError(t, err)
+	require.Equal(t, testApiKey, verifier.APIKey())
+	grants, err := verifier.Verify(testApiSecret)
+	require.NoError(t, err)
+	require.Equal(t, testRoom, grants.Video.Room)
+
+	parsed, err := jwt.ParseSigned(token)
+	require.NoError(t, err)
+	claims := &service.ServerClaims{}
+	require.NoError(t, parsed.UnsafeClaimsWithoutVerification(claims))
+	require.Equal(t, &service.ServerClaims{
+		MaxSessionDuration: 3600,
+		Role:               "viewer",
+		DataTopics:         []string{"chat"},
+		PublishTopics:      []string{"chat/*"},
+		DuplicateIdentity:  "reject",
+	}, claims)
+}
+
 func TestSinglePublisher(t *testing.T) {
 	if testing.Short() {
 		t.SkipNow()
@@ -2,6 +2,7 @@ package test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net"
@@ -11,7 +12,10 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
 	"google.golang.org/protobuf/encoding/protojson"
+	"gopkg.in/square/go-jose.v2"
+	"gopkg.in/square/go-jose.v2/jwt"
 
 	"github.com/livekit/protocol/auth"
 	"github.com/livekit/protocol/livekit"
@@ -21,8 +25,11 @@ import (
 
 	"github.com/livekit/livekit-server/pkg/config"
 	"github.com/livekit/livekit-server/pkg/routing"
+	"github.com/livekit/livekit-server/pkg/rtc"
 	"github.com/livekit/livekit-server/pkg/service"
+	"github.com/livekit/livekit-server/pkg/telemetry"
 	"github.com/livekit/livekit-server/pkg/testutils"
+	testclient "github.com/livekit/livekit-server/test/client"
 )
 
 func TestWebhooks(t *testing.T) {
@@ -104,7 +111,116 @@ func TestWebhooks(t *testing.T) {
 	require.Equal(t, testRoom, ts.GetEvent(webhook.EventRoomFinished).Room.Name)
 }
 
-func setupServerWithWebhook() (server *service.LivekitServer, testServer *webhookTestServer, finishFunc func(), err error) {
+func TestDurationLimitWebhooks(t *testing.T) {
+	_, ts, finish, err := setupServerWithWebhook()
+	require.NoError(t, err)
+	defer finish()
+
+	// participant session is limited by the token
+	c1Token := sessionLimitedToken(t, "c1", 2)
+	c1 := createRTCClientWithToken(c1Token, defaultServerPort, nil)
+	waitUntilConnected(t, c1)
+	defer c1.Stop()
+	testutils.WithTimeout(t, func() string {
+		if ts.GetEvent(telemetry.EventSessionDurationExceeded) == nil {
+			return "did not receive SessionDurationExceeded"
+		}
+		return ""
+	})
+	require.Equal(t, "c1", ts.GetEvent(telemetry.EventSessionDurationExceeded).Participant.Identity)
+	require.Nil(t, ts.GetEvent(telemetry.EventRoomDurationExceeded))
+
+	// rejoining doesn't restart the session time
+	ts.ClearEvents()
+	c1Rejoined := createRTCClientWithToken(c1Token, defaultServerPort, nil)
+	defer c1Rejoined.Stop()
+	require.Eventually(t, func() bool {
+		return ts.GetEvent(telemetry.EventSessionDurationExceeded) != nil
+	}, time.Second, 10*time.Millisecond)
+
+	// room duration is requested when creating the room
+	rc := livekit.NewRoomServiceJSONClient(fmt.Sprintf("http://localhost:%d", defaultServerPort), &http.Client{})
+	_, err = rc.CreateRoom(contextWithToken(createRoomToken()), &livekit.CreateRoomRequest{
+		Name:     "timeboxed",
+		Metadata: `{"maxDuration": 2}`,
+	})
+	require.NoError(t, err)
+	c2 := createRTCClientWithToken(joinToken("timeboxed", "c2"), defaultServerPort, nil)
+	waitUntilConnected(t, c2)
+	defer c2.Stop()
+	testutils.WithTimeout(t, func() string {
+		if ts.GetEvent(telemetry.EventRoomDurationExceeded) == nil {
+			return "did not receive RoomDurationExceeded"
+		}
+		if ts.GetEvent(webhook.EventRoomFinished) == nil {
+			return "did not receive RoomFinished"
+		}
+		return ""
+	})
+	require.Equal(t, "timeboxed", ts.GetEvent(telemetry.EventRoomDurationExceeded).Room.Name)
+}
+
+func sessionLimitedToken(t *testing.T, identity string, maxSessionDuration uint32) string {
+	sig, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.HS256, Key: []byte(testApiSecret)},
+		(&jose.SignerOptions{}).WithType("JWT"))
+	require.NoError(t, err)
+
+	token, err := jwt.Signed(sig).
+		Claims(jwt.Claims{
+			Issuer:    testApiKey,
+			Subject:   identity,
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Expiry:    jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		}).
+		Claims(map[string]interface{}{
+			"video":              &auth.VideoGrant{Room: testRoom, RoomJoin: true},
+			"maxSessionDuration": maxSessionDuration,
+		}).
+		CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func TestDataLimitWebhooks(t *testing.T) {
+	_, ts, finish, err := setupServerWithWebhook(func(conf *config.Config) {
+		conf.Room.DataLimits = config.DataLimitsConfig{MaxPacketSize: 8, Action: "warn"}
+	})
+	require.NoError(t, err)
+	defer finish()
+
+	received := func(c *testclient.RTCClient) *atomic.String {
+		data := atomic.NewString("")
+		c.OnDataReceived = func(payload []byte, sid string) {
+			data.Store(data.Load() + string(payload) + ";")
+		}
+		return data
+	}
+	c1 := createRTCClient("c1", defaultServerPort, nil)
+	c2 := createRTCClient("c2", defaultServerPort, nil)
+	c1Data := received(c1)
+	c2Data := received(c2)
+	waitUntilConnected(t, c1, c2)
+	defer stopClients(c1, c2)
+
+	require.NoError(t, c1.PublishData([]byte("too large to send"), livekit.DataPacket_RELIABLE))
+	require.NoError(t, c1.PublishData([]byte("small"), livekit.DataPacket_RELIABLE))
+	testutils.WithTimeout(t, func() string {
+		if ts.GetEvent(telemetry.EventDataThrottled) == nil {
+			return "did not receive DataThrottled"
+		}
+		if data := c2Data.Load(); data != "small;" {
+			return fmt.Sprintf("c2 received unexpected data %q", data)
+		}
+		if data := c1Data.Load(); data != `{"type":"data_throttled","reason":"size"};` {
+			return fmt.Sprintf("c1 received unexpected data %q", data)
+		}
+		return ""
+	})
+	require.Equal(t, "c1", ts.GetEvent(telemetry.EventDataThrottled).Participant.Identity)
+	require.Equal(t, rtc.DataLimitReasonSize, ts.GetEventReason(telemetry.EventDataThrottled))
+}
+
+func setupServerWithWebhook(configUpdaters ...func(*config.Config)) (server *service.LivekitServer, testServer *webhookTestServer, finishFunc func(), err error) {
 	conf, err := config.NewConfig("", nil)
 	if err != nil {
 		panic(fmt.Sprintf("could not create config: %v", err))
@@ -112,6 +228,9 @@ func setupServerWithWebhook() (server *service.LivekitServer, testServer *webhoo
 	conf.WebHook.URLs = []string{"http://localhost:7890"}
 	conf.WebHook.APIKey = testApiKey
 	conf.Keys = map[string]string{testApiKey: testApiSecret}
+	for _, updater := range configUpdaters {
+		updater(conf)
+	}
 
 	testServer = newTestServer(":7890")
 	if err = testServer.Start(); err != nil {
@@ -145,8 +264,10 @@ func setupServerWithWebhook() (server *service.LivekitServer, testServer *webhoo
 }
 
 type webhookTestServer struct {
-	server   *http.Server
-	events   map[string]*livekit.WebhookEvent
+	server *http.Server
+	events map[string]*livekit.WebhookEvent
+	// reasons of events carrying one, which WebhookEvent doesn't define
+	reasons  map[string]string
 	lock     sync.Mutex
 	provider auth.KeyProvider
 }
@@ -154,6 +275,7 @@ type webhookTestServer struct {
 func newTestServer(addr string) *webhookTestServer {
 	s := &webhookTestServer{
 		events:   make(map[string]*livekit.WebhookEvent),
+		reasons:  make(map[string]string),
 		provider: auth.NewFileBasedKeyProviderFromMap(map[string]string{testApiKey: testApiSecret}),
 	}
 	s.server = &http.Server{
@@ -171,13 +293,21 @@ func (s *webhookTestServer) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
 	}
 
 	event := livekit.WebhookEvent{}
-	if err = protojson.Unmarshal(data, &event); err != nil {
+	if err = (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, &event); err != nil {
+		logger.Errorw("could not unmarshal event", err)
+		return
+	}
+	extra := struct {
+		Reason string `json:"reason"`
+	}{}
+	if err = json.Unmarshal(data, &extra); err != nil {
 		logger.Errorw("could not unmarshal event", err)
 		return
 	}
 
 	s.lock.Lock()
 	s.events[event.Event] = &event
+	s.reasons[event.Event] = extra.Reason
 	s.lock.Unlock()
 }
 
@@ -187,9 +317,17 @@ func (s *webhookTestServer) GetEvent(name string) *livekit.WebhookEvent {
 	return s.events[name]
 }
 
+// GetEventReason returns the reason of the last event with the given name, empty when it has none
+func (s *webhookTestServer) GetEventReason(name string) string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.reasons[name]
+}
+
 func (s *webhookTestServer) ClearEvents() {
 	s.lock.Lock()
 	s.events = make(map[string]*livekit.WebhookEvent)
+	s.reasons = make(map[string]string)
 	s.lock.Unlock()
 }
 